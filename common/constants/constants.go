@@ -9,6 +9,8 @@ const (
 	OFFICE_DOC_TYPE    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
 	CORE_PROP_TYPE     = "http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties"
 	EXTENDED_PROP_TYPE = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties"
+	CUSTOM_PROP_TYPE   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/custom-properties"
+	THUMBNAIL_TYPE     = "http://schemas.openxmlformats.org/package/2006/relationships/metadata/thumbnail"
 	StylesType         = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"
 )
 
@@ -72,6 +74,14 @@ const (
 	SourceRelationshipImage            = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
 	SourceRelationshipOfficeDocument   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
 	SourceRelationshipHyperLink        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink"
+	SourceRelationshipHeader           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/header"
+	SourceRelationshipFooter           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer"
+	SourceRelationshipFootnotes        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footnotes"
+	SourceRelationshipEndnotes         = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/endnotes"
+	SourceRelationshipNumbering        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering"
+	SourceRelationshipSettings         = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings"
+	SourceRelationshipCustomXML        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXml"
+	SourceRelationshipPrinterSettings  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/printerSettings"
 )
 
 const (
@@ -81,4 +91,8 @@ const (
 
 const MediaPath = "word/media/"
 
+const CommentsContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.comments+xml"
+
+const NumberingContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"
+
 const ConentTypeFileIdx = "[Content_Types].xml"
@@ -88,3 +88,17 @@ func (f FillModeProps) MarshalXML(e *xml.Encoder, start xml.StartElement) error
 
 	return nil
 }
+
+// UnmarshalXML decodes the fill mode element it is given, which is any element of the blip
+// fill not matched by another field.
+func (f *FillModeProps) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name.Local {
+	case "stretch":
+		f.Stretch = &shapes.Stretch{}
+		return d.DecodeElement(f.Stretch, &start)
+	case "tile":
+		f.Tile = &shapes.Tile{}
+		return d.DecodeElement(f.Tile, &start)
+	}
+	return d.Skip()
+}
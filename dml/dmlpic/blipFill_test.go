@@ -1 +1,27 @@
 package dmlpic
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestBlipFill_RoundTrip(t *testing.T) {
+	input := `<pic:blipFill><a:blip r:embed="rId5"></a:blip><a:stretch><a:fillRect></a:fillRect></a:stretch></pic:blipFill>`
+
+	var fill BlipFill
+	if err := xml.Unmarshal([]byte(input), &fill); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if fill.FillModeProps.Stretch == nil || fill.FillModeProps.Stretch.FillRect == nil {
+		t.Fatalf("expected the stretch fill mode with its fill rectangle, got %+v", fill.FillModeProps)
+	}
+
+	output, err := xml.Marshal(fill)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(output), `<a:stretch><a:fillRect></a:fillRect></a:stretch>`) {
+		t.Errorf("expected the stretch fill mode to be written back, got %s", output)
+	}
+}
@@ -0,0 +1,532 @@
+package docspec
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// twipsPerInch is the number of twentieths of a point in an inch.
+const twipsPerInch = 1440
+
+// Build validates the spec and creates a new document from it, based on the default template.
+//
+// Validation problems are returned as Errors; problems found while building, such as an
+// unknown style or a missing image, are returned as an *Error.
+func Build(spec *Spec) (*docx.RootDoc, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	rd, err := godocx.NewDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	b := &builder{rd: rd, baseDir: spec.BaseDir}
+	if spec.Page != nil {
+		b.page(spec.Page)
+	}
+
+	for i := range spec.Styles {
+		b.style(&spec.Styles[i])
+	}
+
+	for i := range spec.Content {
+		if err := b.block(&spec.Content[i], fmt.Sprintf("content[%d]", i)); err != nil {
+			return nil, err
+		}
+	}
+
+	return rd, nil
+}
+
+// BuildFile reads a spec file and builds the document it describes.
+func BuildFile(path string) (*docx.RootDoc, error) {
+	spec, err := ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Build(spec)
+}
+
+type builder struct {
+	rd      *docx.RootDoc
+	baseDir string
+}
+
+// twips returns a validated length in twips.
+func twips(l Length) int {
+	v, _ := l.Twips()
+	return v
+}
+
+func (b *builder) page(p *Page) {
+	body := b.rd.Document.Body
+	if body.SectPr == nil {
+		body.SectPr = ctypes.NewSectionProper()
+	}
+	sectPr := body.SectPr
+
+	if p.Size != "" || p.Width != "" || p.Height != "" || p.Orientation != "" {
+		if sectPr.PageSize == nil {
+			sectPr.PageSize = &ctypes.PageSize{}
+		}
+		ps := sectPr.PageSize
+
+		if size, ok := pageSizes[strings.ToLower(p.Size)]; ok {
+			ps.Width = internal.ToPtr(uint64(size[0]))
+			ps.Height = internal.ToPtr(uint64(size[1]))
+		}
+		if p.Width != "" {
+			ps.Width = internal.ToPtr(uint64(twips(p.Width)))
+		}
+		if p.Height != "" {
+			ps.Height = internal.ToPtr(uint64(twips(p.Height)))
+		}
+
+		if p.Orientation != "" {
+			ps.Orient, _ = stypes.PageOrientFromStr(p.Orientation)
+
+			// Word expects the page dimensions to match the orientation
+			if ps.Width != nil && ps.Height != nil {
+				landscape := ps.Orient == stypes.PageOrientLandscape
+				if landscape != (*ps.Width > *ps.Height) {
+					ps.Width, ps.Height = ps.Height, ps.Width
+				}
+			}
+		}
+	}
+
+	if m := p.Margins; m != nil {
+		if sectPr.PageMargin == nil {
+			sectPr.PageMargin = &ctypes.PageMargin{}
+		}
+		pm := sectPr.PageMargin
+
+		margins := []struct {
+			val  Length
+			dest **int
+		}{
+			{m.Top, &pm.Top},
+			{m.Right, &pm.Right},
+			{m.Bottom, &pm.Bottom},
+			{m.Left, &pm.Left},
+			{m.Header, &pm.Header},
+			{m.Footer, &pm.Footer},
+		}
+		for _, margin := range margins {
+			if margin.val != "" {
+				*margin.dest = internal.ToPtr(twips(margin.val))
+			}
+		}
+	}
+}
+
+func (b *builder) style(st *Style) {
+	styleType := stypes.StyleTypeParagraph
+	if st.Type == "character" {
+		styleType = stypes.StyleTypeCharacter
+	}
+
+	name := st.Name
+	if name == "" {
+		name = st.ID
+	}
+
+	style := ctypes.Style{
+		ID:          internal.ToPtr(st.ID),
+		Type:        &styleType,
+		Name:        ctypes.NewCTString(name),
+		CustomStyle: internal.ToPtr(stypes.OnOffOne),
+		QFormat:     &ctypes.OnOff{},
+	}
+	if st.BasedOn != "" {
+		style.BasedOn = ctypes.NewCTString(st.BasedOn)
+	}
+	if st.Next != "" {
+		style.Next = ctypes.NewCTString(st.Next)
+	}
+
+	rp := &ctypes.RunProperty{}
+	if st.Font != "" {
+		rp.Fonts = &ctypes.RunFonts{Ascii: st.Font, HAnsi: st.Font}
+	}
+	if st.Size != 0 {
+		rp.Size = ctypes.NewFontSize(uint64(st.Size) * 2)
+	}
+	if st.Bold {
+		rp.Bold = ctypes.OnOffFromBool(true)
+	}
+	if st.Italic {
+		rp.Italic = ctypes.OnOffFromBool(true)
+	}
+	if st.Underline {
+		rp.Underline = ctypes.NewGenSingleStrVal(stypes.UnderlineSingle)
+	}
+	if st.Color != "" {
+		rp.Color = ctypes.NewColor(st.Color)
+	}
+	if *rp != (ctypes.RunProperty{}) {
+		style.RunProp = rp
+	}
+
+	if styleType == stypes.StyleTypeParagraph && (st.Align != "" || st.SpaceBefore != "" || st.SpaceAfter != "") {
+		pPr := &ctypes.ParagraphProp{}
+		if st.Align != "" {
+			jc, _ := stypes.JustificationFromStr(st.Align)
+			pPr.Justification = ctypes.NewGenSingleStrVal(jc)
+		}
+		if st.SpaceBefore != "" || st.SpaceAfter != "" {
+			pPr.Spacing = &ctypes.Spacing{}
+			if st.SpaceBefore != "" {
+				pPr.Spacing.Before = internal.ToPtr(uint64(twips(st.SpaceBefore)))
+			}
+			if st.SpaceAfter != "" {
+				pPr.Spacing.After = internal.ToPtr(uint64(twips(st.SpaceAfter)))
+			}
+		}
+		style.ParaProp = pPr
+	}
+
+	b.rd.SetStyle(style)
+}
+
+// checkStyle reports an error if the document has no style with the given ID and type.
+func (b *builder) checkStyle(id string, styleType stypes.StyleType, path string) error {
+	if id == "" || b.rd.GetStyleByID(id, styleType) != nil {
+		return nil
+	}
+	return &Error{Path: path, Err: fmt.Errorf("unknown %s style %q", styleType, id)}
+}
+
+func (b *builder) block(blk *Block, path string) error {
+	switch {
+	case blk.Title != "":
+		_, err := b.rd.AddHeading(blk.Title, 0)
+		return err
+	case blk.Heading != nil:
+		level := blk.Heading.Level
+		if level == 0 {
+			level = 1
+		}
+		_, err := b.rd.AddHeading(blk.Heading.Text, level)
+		return err
+	case blk.Paragraph != nil:
+		return b.paragraph(blk.Paragraph, path+".paragraph")
+	case blk.List != nil:
+		abstractNum := 2
+		if blk.List.Ordered {
+			abstractNum = 1
+		}
+		numID := b.rd.NewListInstance(abstractNum)
+		return b.listItems(blk.List.Items, numID, 0, path+".list.items")
+	case blk.Table != nil:
+		return b.table(blk.Table, path+".table")
+	case blk.Image != nil:
+		return b.image(blk.Image, path+".image")
+	case blk.PageBreak:
+		b.rd.AddPageBreak()
+	case blk.TOC != nil:
+		b.toc(blk.TOC)
+	}
+	return nil
+}
+
+func (b *builder) paragraph(para *Paragraph, path string) error {
+	if err := b.checkStyle(para.Style, stypes.StyleTypeParagraph, path+".style"); err != nil {
+		return err
+	}
+
+	p := b.rd.AddEmptyParagraph()
+	if para.Style != "" {
+		p.Style(para.Style)
+	}
+	if para.Align != "" {
+		jc, _ := stypes.JustificationFromStr(para.Align)
+		p.Justification(jc)
+	}
+	return b.runs(p, para.Text, para.Runs, path)
+}
+
+// runs adds content given either as text with inline marks or as runs to a paragraph.
+func (b *builder) runs(p *docx.Paragraph, text string, runs []Run, path string) error {
+	if len(runs) == 0 {
+		runs, _ = parseMarks(text)
+	}
+
+	for i, r := range runs {
+		if err := b.checkStyle(r.Style, stypes.StyleTypeCharacter, fmt.Sprintf("%s.runs[%d].style", path, i)); err != nil {
+			return err
+		}
+
+		if r.Link != "" {
+			p.AddLink(r.Text, r.Link)
+			continue
+		}
+
+		run := p.AddText(r.Text)
+		if r.Style != "" {
+			run.Style(r.Style)
+		}
+		if r.Bold {
+			run.Bold(true)
+		}
+		if r.Italic {
+			run.Italic(true)
+		}
+		if r.Underline {
+			run.Underline(stypes.UnderlineSingle)
+		}
+		if r.Strike {
+			run.Strike(true)
+		}
+		if r.Color != "" {
+			run.Color(r.Color)
+		}
+		if r.Size != 0 {
+			run.Size(uint64(r.Size))
+		}
+		if r.Font != "" {
+			run.Font(r.Font)
+		}
+	}
+	return nil
+}
+
+func (b *builder) listItems(items []ListItem, numID, level int, path string) error {
+	for i := range items {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+
+		p := b.rd.AddEmptyParagraph()
+		p.Style("ListParagraph")
+		p.Numbering(numID, level)
+		if err := b.runs(p, items[i].Text, items[i].Runs, itemPath); err != nil {
+			return err
+		}
+
+		if err := b.listItems(items[i].Items, numID, level+1, itemPath+".items"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gridCell is a cell of a table laid out on the table grid. Cell is nil for the cells
+// continuing a vertically merged region.
+type gridCell struct {
+	cell  *Cell
+	path  string
+	span  int
+	merge stypes.MergeCell
+}
+
+// layoutTable places the cells of a table on its grid, adding the cells that continue vertically
+// merged regions. It returns an error if a row does not fill the grid exactly.
+func layoutTable(t *Table) ([][]gridCell, *Error) {
+	cols := len(t.Widths)
+
+	// Remaining rows and column span of the vertically merged region starting at each column
+	var (
+		mergeRows []int
+		mergeSpan []int
+	)
+	grow := func(col int) {
+		for len(mergeRows) <= col {
+			mergeRows = append(mergeRows, 0)
+			mergeSpan = append(mergeSpan, 0)
+		}
+	}
+
+	grid := make([][]gridCell, len(t.Rows))
+	for i, row := range t.Rows {
+		col, next := 0, 0
+		for {
+			grow(col)
+			if mergeRows[col] > 0 {
+				grid[i] = append(grid[i], gridCell{span: mergeSpan[col], merge: stypes.MergeCellContinue})
+				mergeRows[col]--
+				col += mergeSpan[col]
+				continue
+			}
+
+			if next == len(row) {
+				break
+			}
+
+			c := &row[next]
+			gc := gridCell{cell: c, path: fmt.Sprintf(".rows[%d][%d]", i, next), span: 1}
+			if c.ColSpan > 1 {
+				gc.span = c.ColSpan
+			}
+			if c.RowSpan > 1 {
+				gc.merge = stypes.MergeCellRestart
+				mergeRows[col] = c.RowSpan - 1
+				mergeSpan[col] = gc.span
+			}
+			grid[i] = append(grid[i], gc)
+			col += gc.span
+			next++
+		}
+
+		if cols == 0 {
+			cols = col
+		}
+		if col != cols {
+			return nil, &Error{Path: fmt.Sprintf(".rows[%d]", i), Err: fmt.Errorf("row spans %d columns, expected %d", col, cols)}
+		}
+	}
+
+	return grid, nil
+}
+
+func (b *builder) table(t *Table, path string) error {
+	if err := b.checkStyle(t.Style, stypes.StyleTypeTable, path+".style"); err != nil {
+		return err
+	}
+
+	grid, _ := layoutTable(t)
+
+	tbl := b.rd.AddTable()
+	if t.Style != "" {
+		tbl.Style(t.Style)
+	}
+
+	widths := make([]uint64, len(t.Widths))
+	for i, w := range t.Widths {
+		widths[i] = uint64(twips(w))
+	}
+	tbl.Grid(widths...)
+
+	for _, gridRow := range grid {
+		row := tbl.AddRow()
+		col := 0
+		for _, gc := range gridRow {
+			cell := row.AddCell()
+			if gc.span > 1 {
+				cell.ColSpan(gc.span)
+			}
+			if gc.merge != "" {
+				cell.VerticalMerge(gc.merge)
+			}
+			if width := spanWidth(widths, col, gc.span); width > 0 {
+				cell.Width(width, stypes.TableWidthDxa)
+			}
+			col += gc.span
+
+			if gc.cell == nil {
+				cell.AddEmptyPara()
+				continue
+			}
+
+			if gc.cell.Fill != "" {
+				cell.BackgroundColor(gc.cell.Fill)
+			}
+			if gc.cell.VAlign != "" {
+				cell.VerticalAlign(gc.cell.VAlign)
+			}
+			if err := b.runs(cell.AddEmptyPara(), gc.cell.Text, gc.cell.Runs, path+gc.path); err != nil {
+				return err
+			}
+		}
+	}
+
+	if t.Header {
+		rows := tbl.GetCT().RowContents
+		rows[0].Row.Property.Header = &ctypes.OnOff{}
+	}
+
+	return nil
+}
+
+// spanWidth returns the width of a cell covering span grid columns from col, or zero if the
+// table has no column widths.
+func spanWidth(widths []uint64, col, span int) int {
+	var width uint64
+	for i := col; i < col+span && i < len(widths); i++ {
+		width += widths[i]
+	}
+	return int(width)
+}
+
+func (b *builder) image(img *Image, path string) error {
+	imgPath := img.Path
+	if !filepath.IsAbs(imgPath) && b.baseDir != "" {
+		imgPath = filepath.Join(b.baseDir, imgPath)
+	}
+
+	width, height, err := imageSize(img, imgPath)
+	if err != nil {
+		return &Error{Path: path + ".path", Err: err}
+	}
+
+	pic, err := b.rd.AddPicture(imgPath, width, height)
+	if err != nil {
+		return &Error{Path: path + ".path", Err: err}
+	}
+
+	pic.Inline.DocProp.Description = img.Alt
+	if img.Align != "" {
+		jc, _ := stypes.JustificationFromStr(img.Align)
+		pic.Para.Justification(jc)
+	}
+	return nil
+}
+
+// imageSize returns the display size of an image, filling in missing dimensions from the
+// pixel size of the image file.
+func imageSize(img *Image, imgPath string) (units.Inch, units.Inch, error) {
+	width := float64(twips(img.Width)) / twipsPerInch
+	height := float64(twips(img.Height)) / twipsPerInch
+	if width > 0 && height > 0 {
+		return units.Inch(width), units.Inch(height), nil
+	}
+
+	f, err := os.Open(imgPath)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading image size: %w", err)
+	}
+	if cfg.Width == 0 || cfg.Height == 0 {
+		return 0, 0, errors.New("image has no size")
+	}
+
+	ratio := float64(cfg.Height) / float64(cfg.Width)
+	switch {
+	case width > 0:
+		height = width * ratio
+	case height > 0:
+		width = height / ratio
+	default:
+		width, height = float64(cfg.Width)/96, float64(cfg.Height)/96
+	}
+	return units.Inch(width), units.Inch(height), nil
+}
+
+func (b *builder) toc(toc *TOC) {
+	if toc.Title != "" {
+		b.rd.AddParagraph(toc.Title).Style("TOCHeading")
+	}
+
+	from, to, _ := tocLevels(toc.Levels)
+	p := b.rd.AddEmptyParagraph()
+	p.AddField(fmt.Sprintf(`TOC \o "%d-%d" \h \z \u`, from, to), "Update the field to show the table of contents.")
+}
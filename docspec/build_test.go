@@ -0,0 +1,123 @@
+package docspec_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gomutex/godocx/docspec"
+	"github.com/gomutex/godocx/packager"
+	"github.com/gomutex/godocx/wml/stypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reportSpec = `
+page:
+  size: A4
+  orientation: landscape
+  margins: {top: 1cm}
+styles:
+  - {id: Note, basedOn: Normal, italic: true, size: 9}
+content:
+  - title: Report
+  - toc: {title: Contents}
+  - heading: Summary
+  - paragraph: {text: "Growth of **12%**, see [site](https://example.com).", style: Note, align: center}
+  - list:
+      items: [One, {text: Two, items: [Two A]}]
+  - table:
+      widths: [1in, 1in, 1in]
+      header: true
+      rows:
+        - [{text: Merged, rowSpan: 2, fill: D9D9D9}, {text: Wide, colSpan: 2}]
+        - [B, C]
+  - image: {path: godocx.png, width: 2in, alt: Logo}
+  - pageBreak: true
+`
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	png, err := os.ReadFile("../godocx.png")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "godocx.png"), png, 0o644))
+	specPath := filepath.Join(dir, "report.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(reportSpec), 0o644))
+
+	rd, err := docspec.BuildFile(specPath)
+	require.NoError(t, err)
+
+	ps := rd.Document.Body.SectPr.PageSize
+	assert.Equal(t, uint64(16838), *ps.Width)
+	assert.Equal(t, uint64(11906), *ps.Height)
+	assert.Equal(t, 567, *rd.Document.Body.SectPr.PageMargin.Top)
+
+	note := rd.GetStyleByID("Note", stypes.StyleTypeParagraph)
+	require.NotNil(t, note)
+	assert.Equal(t, uint64(18), note.RunProp.Size.Value)
+
+	body := rd.Document.Body.Children
+	require.Len(t, body, 11)
+
+	toc := body[2].Para.GetCT()
+	require.Len(t, toc.Children, 5)
+	assert.Contains(t, toc.Children[1].Run.Children[0].InstrText.Text, `TOC \o "1-3"`)
+
+	para := body[4].Para.GetCT()
+	assert.Equal(t, "Note", para.Property.Style.Val)
+	require.Len(t, para.Children, 5)
+	assert.NotNil(t, para.Children[1].Run.Property.Bold)
+	assert.NotNil(t, para.Children[3].Link)
+
+	item := body[7].Para.GetCT()
+	assert.Equal(t, 1, item.Property.NumProp.ILvl.Val)
+
+	tbl := body[8].Table.GetCT()
+	assert.NotNil(t, tbl.RowContents[0].Row.Property.Header)
+	second := tbl.RowContents[1].Row.Contents
+	require.Len(t, second, 3)
+	assert.Equal(t, stypes.MergeCellContinue, *second[0].Cell.Property.VMerge.Val)
+	assert.Equal(t, 2, tbl.RowContents[0].Row.Contents[1].Cell.Property.GridSpan.Val)
+
+	var buf bytes.Buffer
+	require.NoError(t, rd.Write(&buf))
+	data := buf.Bytes()
+	_, err = packager.Unpack(&data)
+	require.NoError(t, err)
+}
+
+func TestBuild_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{
+			name: "validation",
+			spec: `content: [{paragraph: {text: "*open"}}]`,
+			err:  `content[0].paragraph.text: unclosed * in "*open"`,
+		},
+		{
+			name: "unknown style",
+			spec: `content: [{paragraph: {text: x, style: Missing}}]`,
+			err:  `content[0].paragraph.style: unknown paragraph style "Missing"`,
+		},
+		{
+			name: "missing image",
+			spec: `content: [{image: {path: missing.png, width: 1in, height: 1in}}]`,
+			err:  `content[0].image.path: `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := docspec.Parse([]byte(tt.spec))
+			require.NoError(t, err)
+
+			_, err = docspec.Build(spec)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
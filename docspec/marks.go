@@ -0,0 +1,68 @@
+package docspec
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var linkMarkRe = regexp.MustCompile(`^\[([^\]]*)\]\(([^)\s]+)\)`)
+
+// parseMarks splits text with inline marks into runs. It supports **bold**, *italic*,
+// ~~strike~~ and [text](url); a backslash escapes the character that follows it.
+func parseMarks(text string) ([]Run, error) {
+	var (
+		runs                 []Run
+		sb                   strings.Builder
+		bold, italic, strike bool
+	)
+
+	flush := func() {
+		if sb.Len() > 0 {
+			runs = append(runs, Run{Text: sb.String(), Bold: bold, Italic: italic, Strike: strike})
+			sb.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1:
+			_, size := utf8.DecodeRuneInString(rest[1:])
+			sb.WriteString(rest[1 : 1+size])
+			i += 1 + size
+		case strings.HasPrefix(rest, "**"):
+			flush()
+			bold = !bold
+			i += 2
+		case strings.HasPrefix(rest, "~~"):
+			flush()
+			strike = !strike
+			i += 2
+		case rest[0] == '*':
+			flush()
+			italic = !italic
+			i++
+		case rest[0] == '[' && linkMarkRe.MatchString(rest):
+			m := linkMarkRe.FindStringSubmatch(rest)
+			flush()
+			runs = append(runs, Run{Text: m[1], Link: m[2], Bold: bold, Italic: italic, Strike: strike})
+			i += len(m[0])
+		default:
+			sb.WriteByte(rest[0])
+			i++
+		}
+	}
+	flush()
+
+	switch {
+	case bold:
+		return nil, fmt.Errorf("unclosed ** in %q", text)
+	case italic:
+		return nil, fmt.Errorf("unclosed * in %q", text)
+	case strike:
+		return nil, fmt.Errorf("unclosed ~~ in %q", text)
+	}
+	return runs, nil
+}
@@ -0,0 +1,33 @@
+package docspec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMarks(t *testing.T) {
+	runs, err := parseMarks(`Revenue **grew *sharply*** by ~~10~~ 12\*2% — see [report](https://example.com/r).`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Run{
+		{Text: "Revenue "},
+		{Text: "grew ", Bold: true},
+		{Text: "sharply", Bold: true, Italic: true},
+		{Text: " by "},
+		{Text: "10", Strike: true},
+		{Text: " 12*2% — see "},
+		{Text: "report", Link: "https://example.com/r"},
+		{Text: "."},
+	}, runs)
+}
+
+func TestParseMarks_Unclosed(t *testing.T) {
+	_, err := parseMarks("a **bold start")
+	assert.EqualError(t, err, `unclosed ** in "a **bold start"`)
+
+	runs, err := parseMarks("[not a link] (x)")
+	require.NoError(t, err)
+	assert.Equal(t, []Run{{Text: "[not a link] (x)"}}, runs)
+}
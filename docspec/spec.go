@@ -0,0 +1,345 @@
+// Package docspec builds documents from a declarative spec written in YAML or JSON.
+//
+// A spec describes the page setup, additional styles and the content of a document as a list
+// of blocks:
+//
+//	page:
+//	  size: A4
+//	  margins: {top: 2cm, bottom: 2cm, left: 2.5cm, right: 2.5cm}
+//	styles:
+//	  - id: Note
+//	    basedOn: Normal
+//	    italic: true
+//	content:
+//	  - title: Quarterly report
+//	  - toc: {levels: 1-2}
+//	  - heading: Summary
+//	  - paragraph: Revenue grew by **12%** compared to [last year](https://example.com/2023).
+//	  - list:
+//	      items: [Sales, {text: Services, items: [Consulting, Training]}]
+//	  - table:
+//	      widths: [4cm, 3cm]
+//	      header: true
+//	      rows:
+//	        - [Region, Revenue]
+//	        - [North, 1.2M]
+//	  - image: {path: chart.png, width: 12cm}
+//	  - pageBreak: true
+//
+// Paragraph, list item and cell text may contain the inline marks **bold**, *italic*,
+// ~~strike~~ and [text](url); a backslash escapes the character that follows it.
+// Lengths are numbers with one of the units pt, in, cm or mm; numbers without a unit are points.
+package docspec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is a declarative description of a document.
+type Spec struct {
+	Page    *Page   `yaml:"page"`
+	Styles  []Style `yaml:"styles"`
+	Content []Block `yaml:"content"`
+
+	// BaseDir is the directory relative image paths are resolved against.
+	// ParseFile sets it to the directory of the spec file.
+	BaseDir string `yaml:"-"`
+}
+
+// Page is the page setup of the document.
+type Page struct {
+	Size        string   `yaml:"size"` // A3, A4, A5, Letter or Legal
+	Width       Length   `yaml:"width"`
+	Height      Length   `yaml:"height"`
+	Orientation string   `yaml:"orientation"` // portrait or landscape
+	Margins     *Margins `yaml:"margins"`
+}
+
+// Margins are the page margins of the document.
+type Margins struct {
+	Top    Length `yaml:"top"`
+	Right  Length `yaml:"right"`
+	Bottom Length `yaml:"bottom"`
+	Left   Length `yaml:"left"`
+	Header Length `yaml:"header"`
+	Footer Length `yaml:"footer"`
+}
+
+// Style defines a paragraph or character style. A style with the ID of a style of the
+// default template replaces it.
+type Style struct {
+	ID          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type"` // paragraph (default) or character
+	BasedOn     string `yaml:"basedOn"`
+	Next        string `yaml:"next"`
+	Font        string `yaml:"font"`
+	Size        uint   `yaml:"size"` // Size in points.
+	Bold        bool   `yaml:"bold"`
+	Italic      bool   `yaml:"italic"`
+	Underline   bool   `yaml:"underline"`
+	Color       string `yaml:"color"`
+	Align       string `yaml:"align"`
+	SpaceBefore Length `yaml:"spaceBefore"`
+	SpaceAfter  Length `yaml:"spaceAfter"`
+}
+
+// Block is an element of the document content. Exactly one of its fields must be set.
+type Block struct {
+	Title     string     `yaml:"title"`
+	Heading   *Heading   `yaml:"heading"`
+	Paragraph *Paragraph `yaml:"paragraph"`
+	List      *List      `yaml:"list"`
+	Table     *Table     `yaml:"table"`
+	Image     *Image     `yaml:"image"`
+	PageBreak bool       `yaml:"pageBreak"`
+	TOC       *TOC       `yaml:"toc"`
+}
+
+// Heading is a heading paragraph. It may be written as a plain string for a level 1 heading.
+type Heading struct {
+	Text  string `yaml:"text"`
+	Level uint   `yaml:"level"` // 1 to 9, defaults to 1
+}
+
+// Paragraph is a body paragraph. It may be written as a plain string holding its text.
+type Paragraph struct {
+	Text  string `yaml:"text"` // Text with inline marks.
+	Runs  []Run  `yaml:"runs"` // Runs is an alternative to Text.
+	Style string `yaml:"style"`
+	Align string `yaml:"align"`
+}
+
+// Run is a piece of text sharing the same formatting.
+type Run struct {
+	Text      string `yaml:"text"`
+	Bold      bool   `yaml:"bold"`
+	Italic    bool   `yaml:"italic"`
+	Underline bool   `yaml:"underline"`
+	Strike    bool   `yaml:"strike"`
+	Color     string `yaml:"color"`
+	Size      uint   `yaml:"size"` // Size in points.
+	Font      string `yaml:"font"`
+	Style     string `yaml:"style"` // Character style.
+	Link      string `yaml:"link"`  // URL the text links to.
+}
+
+// List is a bulleted or numbered list.
+type List struct {
+	Ordered bool       `yaml:"ordered"`
+	Items   []ListItem `yaml:"items"`
+}
+
+// ListItem is an entry of a list, with optional nested items. It may be written as a plain
+// string holding its text.
+type ListItem struct {
+	Text  string     `yaml:"text"`
+	Runs  []Run      `yaml:"runs"`
+	Items []ListItem `yaml:"items"`
+}
+
+// Table is a table. The cells of a row that lie below a cell spanning several rows are left out.
+type Table struct {
+	Style  string   `yaml:"style"`
+	Widths []Length `yaml:"widths"`
+	Header bool     `yaml:"header"` // Repeat the first row on every page.
+	Rows   [][]Cell `yaml:"rows"`
+}
+
+// Cell is a table cell. It may be written as a plain string holding its text.
+type Cell struct {
+	Text    string `yaml:"text"`
+	Runs    []Run  `yaml:"runs"`
+	ColSpan int    `yaml:"colSpan"`
+	RowSpan int    `yaml:"rowSpan"`
+	Fill    string `yaml:"fill"`   // Background color, for example "D9D9D9".
+	VAlign  string `yaml:"vAlign"` // top, center or bottom
+}
+
+// Image is a picture in a paragraph of its own. When only one of Width and Height is given,
+// the other follows from the aspect ratio of the picture; when neither is given, the picture
+// is shown at 96 DPI.
+type Image struct {
+	Path   string `yaml:"path"`
+	Width  Length `yaml:"width"`
+	Height Length `yaml:"height"`
+	Alt    string `yaml:"alt"`
+	Align  string `yaml:"align"`
+}
+
+// TOC is a table of contents field, updated by Word when the document is opened and the
+// user confirms the update.
+type TOC struct {
+	Title  string `yaml:"title"`
+	Levels string `yaml:"levels"` // Heading levels to include, for example "1-3" (the default).
+}
+
+// Length is a distance such as "2.5cm", "1in", "12pt" or "20mm". A number without unit is in points.
+type Length string
+
+// lengthUnits are the number of twentieths of a point per unit.
+var lengthUnits = map[string]float64{
+	"pt": 20,
+	"in": 1440,
+	"cm": 1440 / 2.54,
+	"mm": 144 / 2.54,
+}
+
+// Twips returns the length in twentieths of a point.
+func (l Length) Twips() (int, error) {
+	s := strings.TrimSpace(string(l))
+	factor := lengthUnits["pt"]
+	for unit, f := range lengthUnits {
+		if strings.HasSuffix(s, unit) {
+			s, factor = strings.TrimSpace(strings.TrimSuffix(s, unit)), f
+			break
+		}
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid length %q", string(l))
+	}
+	return int(v*factor + 0.5), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface, accepting a plain string as the heading text.
+func (h *Heading) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		h.Text = value.Value
+		return nil
+	}
+	type plain Heading
+	return value.Decode((*plain)(h))
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface, accepting a plain string as the paragraph text.
+func (p *Paragraph) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		p.Text = value.Value
+		return nil
+	}
+	type plain Paragraph
+	return value.Decode((*plain)(p))
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface, accepting a plain string as the item text.
+func (li *ListItem) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		li.Text = value.Value
+		return nil
+	}
+	type plain ListItem
+	return value.Decode((*plain)(li))
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface, accepting a plain string as the cell text.
+func (c *Cell) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		c.Text = value.Value
+		return nil
+	}
+	type plain Cell
+	return value.Decode((*plain)(c))
+}
+
+// Parse decodes a spec written in YAML or JSON. Unknown fields are reported by their spec path.
+func Parse(data []byte) (*Spec, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	var errs Errors
+	checkFields(&root, reflect.TypeOf(Spec{}), "", &errs)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	spec := &Spec{}
+	if root.Kind == 0 {
+		return spec, nil
+	}
+	if err := root.Decode(spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// ParseFile reads and decodes a spec file.
+func ParseFile(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	spec.BaseDir = filepath.Dir(path)
+	return spec, nil
+}
+
+// checkFields reports mapping keys that do not correspond to a field of the type they decode into.
+func checkFields(node *yaml.Node, t reflect.Type, path string, errs *Errors) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if node.Kind == yaml.DocumentNode {
+		for _, c := range node.Content {
+			checkFields(c, t, path, errs)
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			field, ok := fieldByTag(t, key)
+			if !ok {
+				errs.add(joinPath(path, key), "unknown field")
+				continue
+			}
+			checkFields(node.Content[i+1], field.Type, joinPath(path, key), errs)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, c := range node.Content {
+			checkFields(c, t.Elem(), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// fieldByTag returns the struct field with the given yaml name.
+func fieldByTag(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if tag != "" && tag != "-" && tag == name {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,136 @@
+package docspec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+page:
+  size: A4
+  orientation: landscape
+  margins: {top: 2cm, left: 1in}
+styles:
+  - id: Note
+    italic: true
+    size: 9
+content:
+  - title: Report
+  - heading: Summary
+  - heading: {text: Details, level: 2}
+  - paragraph: Plain text with **bold**.
+  - paragraph:
+      style: Note
+      runs:
+        - {text: Red, color: FF0000}
+  - list:
+      ordered: true
+      items: [One, {text: Two, items: [Two A]}]
+  - table:
+      widths: [2cm, 2cm, 2cm]
+      rows:
+        - [{text: Merged, rowSpan: 2}, {text: Wide, colSpan: 2}]
+        - [B, C]
+  - pageBreak: true
+  - toc: {levels: 1-2}
+`
+
+func TestParse(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	require.NoError(t, err)
+	require.NoError(t, spec.Validate())
+
+	assert.Equal(t, Length("2cm"), spec.Page.Margins.Top)
+	assert.Equal(t, "Note", spec.Styles[0].ID)
+	require.Len(t, spec.Content, 9)
+	assert.Equal(t, "Summary", spec.Content[1].Heading.Text)
+	assert.Equal(t, uint(2), spec.Content[2].Heading.Level)
+	assert.Equal(t, "Plain text with **bold**.", spec.Content[3].Paragraph.Text)
+	assert.Equal(t, "FF0000", spec.Content[4].Paragraph.Runs[0].Color)
+	assert.Equal(t, "Two A", spec.Content[5].List.Items[1].Items[0].Text)
+	assert.Equal(t, 2, spec.Content[6].Table.Rows[0][0].RowSpan)
+	assert.Equal(t, "B", spec.Content[6].Table.Rows[1][0].Text)
+	assert.True(t, spec.Content[7].PageBreak)
+}
+
+func TestParse_JSON(t *testing.T) {
+	spec, err := Parse([]byte(`{"content": [{"paragraph": "Hello"}, {"image": {"path": "a.png", "width": "3cm"}}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", spec.Content[0].Paragraph.Text)
+	assert.Equal(t, Length("3cm"), spec.Content[1].Image.Width)
+}
+
+func TestParse_UnknownFields(t *testing.T) {
+	_, err := Parse([]byte(`
+content:
+  - paragraph: {text: x, bolt: true}
+  - table:
+      rows: [[{text: a, colspan: 2}]]
+`))
+	require.Error(t, err)
+	assert.Equal(t, "content[0].paragraph.bolt: unknown field\ncontent[1].table.rows[0][0].colspan: unknown field", err.Error())
+}
+
+func TestLength_Twips(t *testing.T) {
+	tests := map[Length]int{
+		"12":     240,
+		"12pt":   240,
+		"1in":    1440,
+		"2.54cm": 1440,
+		"10 mm":  567,
+	}
+	for l, want := range tests {
+		got, err := l.Twips()
+		require.NoError(t, err, l)
+		assert.Equal(t, want, got, l)
+	}
+
+	_, err := Length("3 furlongs").Twips()
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	spec, err := Parse([]byte(`
+page: {size: B7, orientation: sideways}
+styles:
+  - {id: X, type: table}
+  - {id: X}
+content:
+  - {}
+  - {heading: A, paragraph: B}
+  - paragraph: {text: "**open", align: middle}
+  - table:
+      rows:
+        - [a, b, c]
+        - [{text: a, colSpan: 2}]
+        - [{text: a, rowSpan: 3}, b, c]
+  - image: {width: 2 parsecs}
+  - toc: {levels: 3-1}
+`))
+	require.NoError(t, err)
+
+	err = spec.Validate()
+	require.Error(t, err)
+
+	var paths []string
+	for _, e := range err.(Errors) {
+		paths = append(paths, e.Error())
+	}
+	assert.Equal(t, []string{
+		`page.size: unknown page size "B7"`,
+		`page.orientation: invalid orientation "sideways"`,
+		`styles[0].type: style type must be paragraph or character, not "table"`,
+		`styles[1].id: duplicate style "X"`,
+		`content[0]: block must be one of title, heading, paragraph, list, table, image, pageBreak or toc`,
+		`content[1]: block can only be one of heading, paragraph`,
+		`content[2].paragraph.align: invalid alignment "middle"`,
+		`content[2].paragraph.text: unclosed ** in "**open"`,
+		`content[3].table.rows[2][0].rowSpan: cell spans past the last row`,
+		`content[3].table.rows[1]: row spans 2 columns, expected 3`,
+		`content[4].image.path: image has no path`,
+		`content[4].image.width: invalid length "2 parsecs"`,
+		`content[5].toc.levels: levels "3-1" are in the wrong order`,
+	}, paths)
+}
@@ -0,0 +1,297 @@
+package docspec
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// Error is a problem with a spec, along with its location such as "content[2].table.rows[1]".
+type Error struct {
+	Path string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Errors lists all problems found in a spec.
+type Errors []*Error
+
+func (errs Errors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (errs *Errors) add(path string, format string, args ...any) {
+	*errs = append(*errs, &Error{Path: path, Err: fmt.Errorf(format, args...)})
+}
+
+// pageSizes are the width and height in twips of the supported named page sizes.
+var pageSizes = map[string][2]int{
+	"a3":     {16838, 23811},
+	"a4":     {11906, 16838},
+	"a5":     {8391, 11906},
+	"letter": {12240, 15840},
+	"legal":  {12240, 20160},
+}
+
+// maxListDepth is the number of list levels supported by the numbering definitions.
+const maxListDepth = 9
+
+var tocLevelsRe = regexp.MustCompile(`^([1-9])-([1-9])$`)
+
+// Validate checks the spec and returns Errors listing every problem found, or nil if there are none.
+// Styles referred to by the content are checked when the document is built.
+func (s *Spec) Validate() error {
+	var errs Errors
+
+	if s.Page != nil {
+		validatePage(s.Page, &errs)
+	}
+
+	ids := make(map[string]bool)
+	for i := range s.Styles {
+		path := fmt.Sprintf("styles[%d]", i)
+		validateStyle(&s.Styles[i], path, &errs)
+		if id := s.Styles[i].ID; ids[id] {
+			errs.add(path+".id", "duplicate style %q", id)
+		}
+		ids[s.Styles[i].ID] = true
+	}
+
+	for i := range s.Content {
+		validateBlock(&s.Content[i], fmt.Sprintf("content[%d]", i), &errs)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func validateLength(l Length, path string, errs *Errors) {
+	if l == "" {
+		return
+	}
+	if _, err := l.Twips(); err != nil {
+		errs.add(path, "%v", err)
+	}
+}
+
+func validateAlign(align, path string, errs *Errors) {
+	if align == "" {
+		return
+	}
+	if _, err := stypes.JustificationFromStr(align); err != nil {
+		errs.add(path, "invalid alignment %q", align)
+	}
+}
+
+func validatePage(p *Page, errs *Errors) {
+	if p.Size != "" {
+		if _, ok := pageSizes[strings.ToLower(p.Size)]; !ok {
+			errs.add("page.size", "unknown page size %q", p.Size)
+		}
+		if p.Width != "" || p.Height != "" {
+			errs.add("page.size", "size cannot be combined with width and height")
+		}
+	}
+
+	validateLength(p.Width, "page.width", errs)
+	validateLength(p.Height, "page.height", errs)
+
+	if p.Orientation != "" {
+		if _, err := stypes.PageOrientFromStr(p.Orientation); err != nil {
+			errs.add("page.orientation", "invalid orientation %q", p.Orientation)
+		}
+	}
+
+	if m := p.Margins; m != nil {
+		validateLength(m.Top, "page.margins.top", errs)
+		validateLength(m.Right, "page.margins.right", errs)
+		validateLength(m.Bottom, "page.margins.bottom", errs)
+		validateLength(m.Left, "page.margins.left", errs)
+		validateLength(m.Header, "page.margins.header", errs)
+		validateLength(m.Footer, "page.margins.footer", errs)
+	}
+}
+
+func validateStyle(st *Style, path string, errs *Errors) {
+	if st.ID == "" {
+		errs.add(path+".id", "style has no id")
+	}
+
+	switch st.Type {
+	case "", "paragraph":
+	case "character":
+		if st.Align != "" || st.SpaceBefore != "" || st.SpaceAfter != "" {
+			errs.add(path, "character styles cannot have paragraph formatting")
+		}
+	default:
+		errs.add(path+".type", "style type must be paragraph or character, not %q", st.Type)
+	}
+
+	validateAlign(st.Align, path+".align", errs)
+	validateLength(st.SpaceBefore, path+".spaceBefore", errs)
+	validateLength(st.SpaceAfter, path+".spaceAfter", errs)
+}
+
+func validateBlock(b *Block, path string, errs *Errors) {
+	var kinds []string
+	if b.Title != "" {
+		kinds = append(kinds, "title")
+	}
+	if b.Heading != nil {
+		kinds = append(kinds, "heading")
+	}
+	if b.Paragraph != nil {
+		kinds = append(kinds, "paragraph")
+	}
+	if b.List != nil {
+		kinds = append(kinds, "list")
+	}
+	if b.Table != nil {
+		kinds = append(kinds, "table")
+	}
+	if b.Image != nil {
+		kinds = append(kinds, "image")
+	}
+	if b.PageBreak {
+		kinds = append(kinds, "pageBreak")
+	}
+	if b.TOC != nil {
+		kinds = append(kinds, "toc")
+	}
+
+	switch len(kinds) {
+	case 0:
+		errs.add(path, "block must be one of title, heading, paragraph, list, table, image, pageBreak or toc")
+		return
+	case 1:
+	default:
+		errs.add(path, "block can only be one of %s", strings.Join(kinds, ", "))
+		return
+	}
+
+	switch {
+	case b.Heading != nil:
+		if b.Heading.Text == "" {
+			errs.add(path+".heading.text", "heading has no text")
+		}
+		if b.Heading.Level > 9 {
+			errs.add(path+".heading.level", "heading level must be between 1 and 9")
+		}
+	case b.Paragraph != nil:
+		validateAlign(b.Paragraph.Align, path+".paragraph.align", errs)
+		validateText(b.Paragraph.Text, b.Paragraph.Runs, path+".paragraph", errs)
+	case b.List != nil:
+		if len(b.List.Items) == 0 {
+			errs.add(path+".list.items", "list has no items")
+		}
+		validateListItems(b.List.Items, 0, path+".list.items", errs)
+	case b.Table != nil:
+		validateTable(b.Table, path+".table", errs)
+	case b.Image != nil:
+		if b.Image.Path == "" {
+			errs.add(path+".image.path", "image has no path")
+		}
+		validateLength(b.Image.Width, path+".image.width", errs)
+		validateLength(b.Image.Height, path+".image.height", errs)
+		validateAlign(b.Image.Align, path+".image.align", errs)
+	case b.TOC != nil:
+		if _, _, err := tocLevels(b.TOC.Levels); err != nil {
+			errs.add(path+".toc.levels", "%v", err)
+		}
+	}
+}
+
+// validateText checks content given either as text with inline marks or as runs.
+func validateText(text string, runs []Run, path string, errs *Errors) {
+	if text != "" && len(runs) > 0 {
+		errs.add(path, "text and runs cannot be combined")
+		return
+	}
+
+	if _, err := parseMarks(text); err != nil {
+		errs.add(path+".text", "%v", err)
+	}
+}
+
+func validateListItems(items []ListItem, depth int, path string, errs *Errors) {
+	if depth >= maxListDepth {
+		errs.add(path, "lists can be nested at most %d levels deep", maxListDepth)
+		return
+	}
+
+	for i := range items {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		validateText(items[i].Text, items[i].Runs, itemPath, errs)
+		validateListItems(items[i].Items, depth+1, itemPath+".items", errs)
+	}
+}
+
+func validateTable(t *Table, path string, errs *Errors) {
+	if len(t.Rows) == 0 {
+		errs.add(path+".rows", "table has no rows")
+		return
+	}
+
+	for i, w := range t.Widths {
+		validateLength(w, fmt.Sprintf("%s.widths[%d]", path, i), errs)
+	}
+
+	for i, row := range t.Rows {
+		for j := range row {
+			cellPath := fmt.Sprintf("%s.rows[%d][%d]", path, i, j)
+			c := &row[j]
+			validateText(c.Text, c.Runs, cellPath, errs)
+			if c.ColSpan < 0 {
+				errs.add(cellPath+".colSpan", "column span cannot be negative")
+			}
+			if c.RowSpan < 0 {
+				errs.add(cellPath+".rowSpan", "row span cannot be negative")
+			}
+			if i+c.RowSpan > len(t.Rows) {
+				errs.add(cellPath+".rowSpan", "cell spans past the last row")
+			}
+			if c.VAlign != "" && c.VAlign != "top" && c.VAlign != "center" && c.VAlign != "bottom" {
+				errs.add(cellPath+".vAlign", "vertical alignment must be top, center or bottom")
+			}
+		}
+	}
+
+	if _, err := layoutTable(t); err != nil {
+		errs.add(path+err.Path, "%v", err.Err)
+	}
+}
+
+// tocLevels returns the range of heading levels of a table of contents.
+func tocLevels(levels string) (int, int, error) {
+	if levels == "" {
+		return 1, 3, nil
+	}
+
+	m := tocLevelsRe.FindStringSubmatch(levels)
+	if m == nil {
+		return 0, 0, fmt.Errorf("levels must look like 1-3, not %q", levels)
+	}
+
+	from, _ := strconv.Atoi(m[1])
+	to, _ := strconv.Atoi(m[2])
+	if from > to {
+		return 0, 0, fmt.Errorf("levels %q are in the wrong order", levels)
+	}
+	return from, to, nil
+}
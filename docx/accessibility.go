@@ -0,0 +1,347 @@
+package docx
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// AccessibilityRule is the rule broken by an issue found by CheckAccessibility.
+type AccessibilityRule string
+
+const (
+	RuleImageAltText     AccessibilityRule = "image-alt-text"    // An image has no alternative text.
+	RuleHeadingOrder     AccessibilityRule = "heading-order"     // A heading skips levels.
+	RuleTableHeader      AccessibilityRule = "table-header"      // A table has no repeating header row, or merged header cells.
+	RuleContrast         AccessibilityRule = "contrast"          // Text has too little contrast with its background.
+	RuleEmptyParagraph   AccessibilityRule = "empty-paragraph"   // An empty paragraph is used for spacing.
+	RuleDocumentTitle    AccessibilityRule = "document-title"    // The core properties have no title.
+	RuleDocumentLanguage AccessibilityRule = "document-language" // The core properties have no language.
+	RuleLinkText         AccessibilityRule = "link-text"         // The text of a link does not tell where it leads.
+)
+
+// AccessibilityIssue is a problem found by CheckAccessibility.
+type AccessibilityIssue struct {
+	Rule AccessibilityRule `json:"rule"`
+
+	// Location is where the issue is in the body, as for Change, and is empty for issues with
+	// the document as a whole.
+	Location string `json:"location,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (i AccessibilityIssue) String() string {
+	if i.Location == "" {
+		return fmt.Sprintf("%s: %s", i.Rule, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Rule, i.Location, i.Message)
+}
+
+// Contrast ratios required by WCAG 2 level AA, for normal text and for large text, which is
+// text of 18 points or more, or bold text of 14 points or more.
+const (
+	minContrast      = 4.5
+	minLargeContrast = 3.0
+)
+
+// nonDescriptiveLinks are link texts that do not tell where a link leads, in lower case.
+var nonDescriptiveLinks = map[string]bool{
+	"click here": true, "click": true, "here": true, "link": true, "this link": true,
+	"more": true, "read more": true, "learn more": true, "more info": true, "details": true,
+	"this": true, "go": true,
+}
+
+// highlightColors are the RGB colors of the highlight color names.
+var highlightColors = map[string]string{
+	stypes.ColorIndexBlack:       "000000",
+	stypes.ColorIndexBlue:        "0000FF",
+	stypes.ColorIndexBrightGreen: "00FF00",
+	stypes.ColorIndexDarkBlue:    "000080",
+	stypes.ColorIndexDarkRed:     "800000",
+	stypes.ColorIndexDarkYellow:  "808000",
+	stypes.ColorIndexGray25:      "C0C0C0",
+	stypes.ColorIndexGray50:      "808080",
+	stypes.ColorIndexGreen:       "008000",
+	stypes.ColorIndexMagenta:     "FF00FF",
+	stypes.ColorIndexRed:         "FF0000",
+	stypes.ColorIndexDarkCyan:    "008080",
+	stypes.ColorIndexCyan:        "00FFFF",
+	stypes.ColorIndexDarkMagenta: "800080",
+	stypes.ColorIndexWhite:       "FFFFFF",
+	stypes.ColorIndexYellow:      "FFFF00",
+}
+
+// CheckAccessibility checks the document for what keeps it from being read with assistive
+// technology, along the lines of WCAG 2 and PDF/UA:
+//
+//   - images without alternative text, the description of their drawing properties;
+//   - headings that skip levels, such as a Heading3 after a Heading1;
+//   - tables whose first row is not marked to repeat as a header row, or whose header rows
+//     have merged cells;
+//   - text whose color has less contrast with its background than WCAG level AA requires,
+//     the background being the highlight or shading of the run, paragraph or table cell;
+//   - empty paragraphs used for spacing instead of paragraph spacing;
+//   - core properties without a title or a language;
+//   - links whose text, such as "click here" or a bare URL, does not tell where they lead.
+//
+// Only direct formatting is taken into account for contrast, and only text with an explicit
+// color; "auto" colors are chosen by Word to contrast with the background. The body is
+// checked; headers, footers and notes are left out.
+func (rd *RootDoc) CheckAccessibility() ([]AccessibilityIssue, error) {
+	var issues []AccessibilityIssue
+
+	core, err := rd.CoreProperties()
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(core.Title) == "" {
+		issues = append(issues, AccessibilityIssue{Rule: RuleDocumentTitle, Message: "the document has no title"})
+	}
+	if strings.TrimSpace(core.Language) == "" {
+		issues = append(issues, AccessibilityIssue{Rule: RuleDocumentLanguage, Message: "the document has no language"})
+	}
+
+	a := &accessibilityChecker{issues: issues}
+	a.blocks(bodyRefs(rd.Document.Body), "", "")
+	return a.issues, nil
+}
+
+type accessibilityChecker struct {
+	issues  []AccessibilityIssue
+	heading uint // heading is the level of the last heading, 0 before the first.
+}
+
+func (a *accessibilityChecker) add(rule AccessibilityRule, loc, format string, args ...interface{}) {
+	a.issues = append(a.issues, AccessibilityIssue{Rule: rule, Location: loc, Message: fmt.Sprintf(format, args...)})
+}
+
+// blocks checks a list of blocks. loc is the location of the list, ending with ", " when not
+// empty, and fill is the background color of the table cell holding it, if any.
+func (a *accessibilityChecker) blocks(refs []blockRef, loc, fill string) {
+	var paras, tables, controls int
+	for i, ref := range refs {
+		switch {
+		case ref.para != nil:
+			paras++
+			pLoc := fmt.Sprintf("%sparagraph %d", loc, paras)
+			// Word needs a paragraph in each cell and after each table, which may be empty.
+			required := len(refs) == 1 || i > 0 && refs[i-1].tbl != nil
+			if !required && emptyParagraph(ref.para) {
+				a.add(RuleEmptyParagraph, pLoc, "the paragraph is empty; use paragraph spacing instead")
+			}
+			a.paragraph(ref.para, pLoc, fill)
+		case ref.tbl != nil:
+			tables++
+			a.table(ref.tbl, fmt.Sprintf("%stable %d", loc, tables), fill)
+		case ref.sdt != nil:
+			controls++
+			a.blocks(contentRefs(ref.sdt.Contents), fmt.Sprintf("%scontent control %d, ", loc, controls), fill)
+		}
+	}
+}
+
+// emptyParagraph reports whether a paragraph shows nothing but blank lines.
+func emptyParagraph(p *ctypes.Paragraph) bool {
+	if p.Property != nil && p.Property.SectPr != nil {
+		// The paragraph ends a section.
+		return false
+	}
+	if strings.TrimSpace(paragraphText(p)) != "" {
+		return false
+	}
+	for _, tr := range paragraphRuns(p) {
+		for _, child := range tr.run.Children {
+			if child.Drawing != nil || child.Pict != nil || child.Sym != nil || child.FldChar != nil {
+				return false
+			}
+			if child.Break != nil && child.Break.BreakType != nil && *child.Break.BreakType != stypes.BreakTypeTextWrapping {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (a *accessibilityChecker) paragraph(p *ctypes.Paragraph, loc, fill string) {
+	if level, ok := headingLevel(p); ok {
+		if level > a.heading+1 {
+			if a.heading == 0 {
+				a.add(RuleHeadingOrder, loc, "the first heading is level %d; start with level 1", level)
+			} else {
+				a.add(RuleHeadingOrder, loc, "heading level %d follows level %d; levels should not be skipped", level, a.heading)
+			}
+		}
+		a.heading = level
+	}
+
+	if p.Property != nil {
+		if f := shadingFill(p.Property.Shading); f != "" {
+			fill = f
+		}
+	}
+
+	var link *ctypes.Hyperlink
+	var linkText strings.Builder
+	checkLink := func() {
+		if link != nil {
+			a.link(linkText.String(), loc)
+		}
+		link = nil
+		linkText.Reset()
+	}
+	for _, tr := range paragraphRuns(p) {
+		if tr.link != link {
+			checkLink()
+			link = tr.link
+		}
+		if link != nil {
+			linkText.WriteString(runText(tr.run))
+		}
+
+		for _, child := range tr.run.Children {
+			for _, img := range drawingImages(child.Drawing) {
+				if strings.TrimSpace(img.Description) == "" {
+					a.add(RuleImageAltText, loc, "the image has no alternative text")
+				}
+			}
+		}
+		a.contrast(tr.run, loc, fill)
+	}
+	checkLink()
+}
+
+// link checks the text of a link.
+func (a *accessibilityChecker) link(text, loc string) {
+	text = strings.TrimSpace(text)
+	lower := strings.ToLower(strings.TrimRight(text, ".!:"))
+	switch {
+	case text == "":
+		a.add(RuleLinkText, loc, "the link has no text")
+	case nonDescriptiveLinks[lower]:
+		a.add(RuleLinkText, loc, "the link text %q does not tell where the link leads", text)
+	case strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "www."):
+		a.add(RuleLinkText, loc, "the link text is the address %q; describe where the link leads", text)
+	}
+}
+
+// contrast checks the contrast between the color of the text of a run and its background.
+func (a *accessibilityChecker) contrast(r *ctypes.Run, loc, fill string) {
+	rp := r.Property
+	if rp == nil || rp.Color == nil {
+		return
+	}
+	text := strings.TrimSpace(runText(r))
+	if text == "" {
+		return
+	}
+
+	fg, ok := parseRGB(rp.Color.Val)
+	if !ok {
+		return
+	}
+	if f := shadingFill(rp.Shading); f != "" {
+		fill = f
+	}
+	if rp.Highlight != nil {
+		if f, ok := highlightColors[rp.Highlight.Val]; ok {
+			fill = f
+		}
+	}
+	bg, ok := parseRGB(fill)
+	if !ok {
+		bg = [3]float64{1, 1, 1}
+	}
+
+	limit := minContrast
+	if rp.Size != nil && (rp.Size.Value >= 36 || rp.Size.Value >= 28 && isOn(rp.Bold)) {
+		limit = minLargeContrast
+	}
+	if ratio := contrastRatio(fg, bg); ratio < limit {
+		a.add(RuleContrast, loc, "the text %q has a contrast ratio of %.1f:1 with its background, below %.1f:1", text, ratio, limit)
+	}
+}
+
+// table checks that a table has a header row repeated on each page without merged cells,
+// and then checks its cells.
+func (a *accessibilityChecker) table(t *ctypes.Table, loc, fill string) {
+	rows := tableRows(t)
+	if len(rows) > 0 && (rows[0].Property == nil || !isOn(rows[0].Property.Header)) {
+		a.add(RuleTableHeader, loc, "the first row is not marked as a header row repeated on each page")
+	}
+
+	header := true
+	for i, row := range rows {
+		header = header && row.Property != nil && isOn(row.Property.Header)
+		cells := 0
+		for _, cc := range row.Contents {
+			if cc.Cell == nil {
+				continue
+			}
+			cells++
+			cellLoc := fmt.Sprintf("%s, row %d, cell %d", loc, i+1, cells)
+
+			cellFill := fill
+			if cp := cc.Cell.Property; cp != nil {
+				if header && (cp.GridSpan != nil && cp.GridSpan.Val > 1 || cp.VMerge != nil || cp.HMerge != nil || cp.CellMerge != nil) {
+					a.add(RuleTableHeader, cellLoc, "the header cell is merged")
+				}
+				if f := shadingFill(cp.Shading); f != "" {
+					cellFill = f
+				}
+			}
+			a.blocks(contentRefs(cc.Cell.Contents), cellLoc+", ", cellFill)
+		}
+	}
+}
+
+// shadingFill returns the fill color of shading, or "" when there is none.
+func shadingFill(shd *ctypes.Shading) string {
+	if shd == nil || shd.Fill == nil || *shd.Fill == "auto" {
+		return ""
+	}
+	return *shd.Fill
+}
+
+// parseRGB parses a hex color such as "FF0000" into its red, green and blue components
+// between 0 and 1.
+func parseRGB(hex string) ([3]float64, bool) {
+	var rgb [3]float64
+	if len(hex) != 6 {
+		return rgb, false
+	}
+	for i := range rgb {
+		v, err := strconv.ParseUint(hex[2*i:2*i+2], 16, 8)
+		if err != nil {
+			return rgb, false
+		}
+		rgb[i] = float64(v) / 255
+	}
+	return rgb, true
+}
+
+// contrastRatio returns the WCAG contrast ratio between two colors, from 1 to 21.
+func contrastRatio(a, b [3]float64) float64 {
+	la, lb := luminance(a), luminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// luminance returns the relative luminance of an sRGB color.
+func luminance(rgb [3]float64) float64 {
+	var linear [3]float64
+	for i, c := range rgb {
+		if c <= 0.03928 {
+			linear[i] = c / 12.92
+		} else {
+			linear[i] = math.Pow((c+0.055)/1.055, 2.4)
+		}
+	}
+	return 0.2126*linear[0] + 0.7152*linear[1] + 0.0722*linear[2]
+}
@@ -0,0 +1,68 @@
+package docx_test
+
+import (
+	"bytes"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/wml/stypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAccessibility(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+
+	_, err = rd.AddHeading("Report", 1)
+	require.NoError(t, err)
+	_, err = rd.AddHeading("Details", 3)
+	require.NoError(t, err)
+	rd.AddEmptyParagraph()
+	p := rd.AddParagraph("For the figures, ")
+	p.AddLink("click here", "https://example.com/figures")
+	p.AddText(". ")
+	p.AddText("Draft").Color("CCCCCC")
+	p.AddText(" and ")
+	p.AddText("final").Color("FFFFFF").Shading(stypes.ShdClear, "auto", "003366")
+	_, err = rd.AddPicture("../godocx.png", units.Inch(1), units.Inch(1))
+	require.NoError(t, err)
+
+	tbl := rd.AddTable().Grid(2000, 2000)
+	row := tbl.AddRow()
+	row.AddCell().ColSpan(2).AddParagraph("Region and revenue")
+	row = tbl.AddRow()
+	row.AddCell().AddParagraph("North")
+	row.AddCell().AddParagraph("1.2M")
+
+	issues, err := rd.CheckAccessibility()
+	require.NoError(t, err)
+	assert.Equal(t, []docx.AccessibilityIssue{
+		{Rule: docx.RuleDocumentTitle, Message: "the document has no title"},
+		{Rule: docx.RuleDocumentLanguage, Message: "the document has no language"},
+		{Rule: docx.RuleHeadingOrder, Location: "paragraph 2", Message: "heading level 3 follows level 1; levels should not be skipped"},
+		{Rule: docx.RuleEmptyParagraph, Location: "paragraph 3", Message: "the paragraph is empty; use paragraph spacing instead"},
+		{Rule: docx.RuleLinkText, Location: "paragraph 4", Message: `the link text "click here" does not tell where the link leads`},
+		{Rule: docx.RuleContrast, Location: "paragraph 4", Message: `the text "Draft" has a contrast ratio of 1.6:1 with its background, below 4.5:1`},
+		{Rule: docx.RuleImageAltText, Location: "paragraph 5", Message: "the image has no alternative text"},
+		{Rule: docx.RuleTableHeader, Location: "table 1", Message: "the first row is not marked as a header row repeated on each page"},
+	}, issues)
+
+	tbl.Rows()[0].Header()
+	issues, err = rd.CheckAccessibility()
+	require.NoError(t, err)
+	assert.Contains(t, issues, docx.AccessibilityIssue{Rule: docx.RuleTableHeader, Location: "table 1, row 1, cell 1", Message: "the header cell is merged"})
+
+	rd = rewrite(t, rd, func(files map[string][]byte) {
+		core := files["docProps/core.xml"]
+		core = bytes.Replace(core, []byte("<dc:title/>"), []byte("<dc:title>Report</dc:title><dc:language>en-GB</dc:language>"), 1)
+		files["docProps/core.xml"] = core
+	})
+	issues, err = rd.CheckAccessibility()
+	require.NoError(t, err)
+	for _, issue := range issues {
+		assert.NotContains(t, []docx.AccessibilityRule{docx.RuleDocumentTitle, docx.RuleDocumentLanguage}, issue.Rule)
+	}
+}
@@ -0,0 +1,481 @@
+package docx
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+)
+
+// StyleConflict tells AppendDocument what to do with a style that both documents define
+// differently.
+type StyleConflict int
+
+const (
+	// UseDestinationStyles gives the appended content the definition of the style in this
+	// document, as Word does when pasting with destination styles.
+	UseDestinationStyles StyleConflict = iota
+
+	// KeepSourceStyles copies the style of the other document under a new ID and name, such
+	// as "Heading1_1" named "heading 1 (1)", so the appended content keeps its look.
+	KeepSourceStyles
+)
+
+// AppendOptions configures RootDoc.AppendDocument.
+type AppendOptions struct {
+	// NewSection starts the appended content in a new section, beginning on a new page with
+	// the page setup of the other document. The headers and footers of this document carry
+	// on into the new section.
+	NewSection bool
+
+	// Styles tells what to do with styles defined differently in both documents.
+	Styles StyleConflict
+}
+
+// AppendDocument appends the body of another document to this document.
+//
+// The images and hyperlinks of the appended content are copied along with new relationship
+// IDs and media file names. Styles used by the content that this document lacks are copied,
+// and conflicting styles are handled as set by opts. Lists of the appended content get new
+// numbering definitions, so they are numbered on their own instead of continuing lists of
+// this document. The other document is left unchanged, apart from numbering instances it
+// created being written to its numbering part.
+//
+// Headers, footers, notes and comments of the other document are not copied.
+func (rd *RootDoc) AppendDocument(other *RootDoc, opts *AppendOptions) error {
+	if other == nil || other == rd {
+		return errors.New("append: a document cannot be appended to itself")
+	}
+	if opts == nil {
+		opts = &AppendOptions{}
+	}
+
+	im := newImporter(other, rd)
+	blocks := make([]DocumentChild, 0, len(other.Document.Body.Children))
+	for _, child := range other.Document.Body.Children {
+		block, err := im.block(child)
+		if err != nil {
+			return fmt.Errorf("append: %w", err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	var tables []*ctypes.Table
+	eachBlock(blocks, func(child DocumentChild) bool {
+		if child.Table != nil {
+			tables = append(tables, child.Table.ct)
+		}
+		return false
+	})
+	paras := blockParagraphs(blocks)
+
+	renamed, styles := rd.mergeStyles(other, usedStyles(paras, tables), opts.Styles)
+	renameStyles(paras, tables, renamed)
+
+	numIDs, err := rd.mergeNumbering(other, usedNumbering(paras, styles))
+	if err != nil {
+		return fmt.Errorf("append: %w", err)
+	}
+	renumber(paras, styles, numIDs)
+
+	body := rd.Document.Body
+	if opts.NewSection {
+		// The section of the content so far ends with a paragraph holding its properties.
+		end := newParagraph(rd)
+		end.ensureProp()
+		end.ct.Property.SectPr = ctypes.NewSectionProper()
+		if body.SectPr != nil {
+			sectPr := internal.DeepCopy(*body.SectPr)
+			end.ct.Property.SectPr = &sectPr
+		}
+		body.Children = append(body.Children, DocumentChild{Para: end})
+
+		if src := other.Document.Body.SectPr; src != nil {
+			sectPr := internal.DeepCopy(*src)
+			sectPr.HeaderReference, sectPr.FooterReference, sectPr.Type = nil, nil, nil
+			body.SectPr = &sectPr
+		}
+	}
+	body.Children = append(body.Children, blocks...)
+	return nil
+}
+
+// usedStyles returns the IDs of the styles used by paragraphs, their runs and tables.
+func usedStyles(paras []*ctypes.Paragraph, tables []*ctypes.Table) map[string]bool {
+	used := make(map[string]bool)
+	for _, p := range paras {
+		if p.Property != nil && p.Property.Style != nil {
+			used[p.Property.Style.Val] = true
+		}
+		for _, tr := range paragraphRuns(p) {
+			if tr.run.Property != nil && tr.run.Property.Style != nil {
+				used[tr.run.Property.Style.Val] = true
+			}
+		}
+	}
+	for _, t := range tables {
+		if t.TableProp.Style != nil {
+			used[t.TableProp.Style.Val] = true
+		}
+	}
+	return used
+}
+
+// renameStyles applies the style IDs renamed by mergeStyles to paragraphs, runs and tables.
+func renameStyles(paras []*ctypes.Paragraph, tables []*ctypes.Table, renamed map[string]string) {
+	rename := func(s *ctypes.CTString) {
+		if s == nil {
+			return
+		}
+		if id, ok := renamed[s.Val]; ok {
+			s.Val = id
+		}
+	}
+
+	for _, p := range paras {
+		if p.Property != nil {
+			rename(p.Property.Style)
+		}
+		for _, tr := range paragraphRuns(p) {
+			if tr.run.Property != nil {
+				rename(tr.run.Property.Style)
+			}
+		}
+	}
+	for _, t := range tables {
+		rename(t.TableProp.Style)
+	}
+}
+
+// mergeStyles copies the styles used from the other document into this one, along with the
+// styles they are based on, linked to or followed by. It returns the styles renamed to keep
+// the source definition, and the copied styles.
+func (rd *RootDoc) mergeStyles(other *RootDoc, used map[string]bool, conflict StyleConflict) (map[string]string, []*ctypes.Style) {
+	renamed := make(map[string]string)
+	if other.DocStyles == nil || len(used) == 0 {
+		return renamed, nil
+	}
+	if rd.DocStyles == nil {
+		rd.DocStyles = &ctypes.Styles{}
+	}
+
+	// Styles the used ones depend on are needed too.
+	srcStyles := make(map[string]*ctypes.Style)
+	for i := range other.DocStyles.StyleList {
+		if s := &other.DocStyles.StyleList[i]; s.ID != nil {
+			srcStyles[*s.ID] = s
+		}
+	}
+	needed := make(map[string]bool)
+	var need func(id string)
+	need = func(id string) {
+		s, ok := srcStyles[id]
+		if !ok || needed[id] {
+			return
+		}
+		needed[id] = true
+		for _, dep := range []*ctypes.CTString{s.BasedOn, s.Link, s.Next} {
+			if dep != nil {
+				need(dep.Val)
+			}
+		}
+	}
+	for id := range used {
+		need(id)
+	}
+
+	dstStyles := make(map[string]*ctypes.Style)
+	names := make(map[string]bool)
+	for i := range rd.DocStyles.StyleList {
+		s := &rd.DocStyles.StyleList[i]
+		if s.ID != nil {
+			dstStyles[*s.ID] = s
+		}
+		if s.Name != nil {
+			names[strings.ToLower(s.Name.Val)] = true
+		}
+	}
+
+	var copied []ctypes.Style
+	for _, src := range other.DocStyles.StyleList {
+		if src.ID == nil || !needed[*src.ID] {
+			continue
+		}
+
+		dst, exists := dstStyles[*src.ID]
+		if exists && (conflict == UseDestinationStyles || reflect.DeepEqual(*dst, src)) {
+			continue
+		}
+
+		s := internal.DeepCopy(src)
+		s.Default = nil
+		if exists {
+			id, n := *src.ID, 1
+			newID := fmt.Sprintf("%s_%d", id, n)
+			for dstStyles[newID] != nil || srcStyles[newID] != nil {
+				n++
+				newID = fmt.Sprintf("%s_%d", id, n)
+			}
+			s.ID = &newID
+			renamed[id] = newID
+			if s.Name != nil {
+				name := fmt.Sprintf("%s (%d)", s.Name.Val, n)
+				for k := n + 1; names[strings.ToLower(name)]; k++ {
+					name = fmt.Sprintf("%s (%d)", s.Name.Val, k)
+				}
+				s.Name = ctypes.NewCTString(name)
+				names[strings.ToLower(name)] = true
+			}
+			dstStyles[newID] = &s
+		}
+		copied = append(copied, s)
+	}
+
+	start := len(rd.DocStyles.StyleList)
+	for _, s := range copied {
+		for _, dep := range []*ctypes.CTString{s.BasedOn, s.Link, s.Next} {
+			if dep != nil {
+				if id, ok := renamed[dep.Val]; ok {
+					dep.Val = id
+				}
+			}
+		}
+		rd.DocStyles.StyleList = append(rd.DocStyles.StyleList, s)
+	}
+
+	styles := make([]*ctypes.Style, 0, len(copied))
+	for i := start; i < len(rd.DocStyles.StyleList); i++ {
+		styles = append(styles, &rd.DocStyles.StyleList[i])
+	}
+	return renamed, styles
+}
+
+// usedNumbering returns the numbering instance IDs used by paragraphs and styles.
+func usedNumbering(paras []*ctypes.Paragraph, styles []*ctypes.Style) map[int]bool {
+	used := make(map[int]bool)
+	add := func(pp *ctypes.ParagraphProp) {
+		if pp != nil && pp.NumProp != nil && pp.NumProp.NumID != nil && pp.NumProp.NumID.Val != 0 {
+			used[pp.NumProp.NumID.Val] = true
+		}
+	}
+	for _, p := range paras {
+		add(p.Property)
+	}
+	for _, s := range styles {
+		add(s.ParaProp)
+	}
+	return used
+}
+
+// renumber applies the numbering instance IDs remapped by mergeNumbering.
+func renumber(paras []*ctypes.Paragraph, styles []*ctypes.Style, numIDs map[int]int) {
+	set := func(pp *ctypes.ParagraphProp) {
+		if pp == nil || pp.NumProp == nil || pp.NumProp.NumID == nil {
+			return
+		}
+		if id, ok := numIDs[pp.NumProp.NumID.Val]; ok {
+			pp.NumProp.NumID.Val = id
+		}
+	}
+	for _, p := range paras {
+		set(p.Property)
+	}
+	for _, s := range styles {
+		set(s.ParaProp)
+	}
+}
+
+const numberingPath = "word/numbering.xml"
+
+// numberingDef is an abstract numbering definition or a numbering instance read from a
+// numbering part, with its XML as read.
+type numberingDef struct {
+	id       int
+	abstract int // abstract is the abstract numbering ID used by an instance.
+	raw      string
+}
+
+// readNumbering returns the abstract numbering definitions and the numbering instances of a
+// numbering part.
+func readNumbering(content []byte) (abstracts, nums []numberingDef, err error) {
+	d := xml.NewDecoder(strings.NewReader(string(content)))
+	inRoot := false
+	for {
+		pos := d.InputOffset()
+		token, err := d.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			if !inRoot {
+				inRoot = true
+				continue
+			}
+
+			var def struct {
+				AbstractNumID string `xml:"abstractNumId,attr"`
+				NumID         string `xml:"numId,attr"`
+				Abstract      struct {
+					Val int `xml:"val,attr"`
+				} `xml:"abstractNumId"`
+			}
+			if err := d.DecodeElement(&def, &elem); err != nil {
+				return nil, nil, err
+			}
+			raw := string(content[pos:d.InputOffset()])
+
+			switch elem.Name.Local {
+			case "abstractNum":
+				id, _ := strconv.Atoi(def.AbstractNumID)
+				abstracts = append(abstracts, numberingDef{id: id, raw: raw})
+			case "num":
+				id, _ := strconv.Atoi(def.NumID)
+				nums = append(nums, numberingDef{id: id, abstract: def.Abstract.Val, raw: raw})
+			}
+		case xml.EndElement:
+			return abstracts, nums, nil
+		}
+	}
+}
+
+var (
+	abstractNumIDAttr = regexp.MustCompile(`abstractNumId="\d+"`)
+	numIDAttr         = regexp.MustCompile(`numId="\d+"`)
+	abstractNumIDVal  = regexp.MustCompile(`(abstractNumId\s+\w+:val=")\d+"`)
+	nsidVal           = regexp.MustCompile(`(nsid\s+\w+:val=")[0-9A-Fa-f]+"`)
+)
+
+// mergeNumbering copies the numbering instances used from the other document into this one,
+// with their abstract numbering definitions, under new IDs. It returns the new instance IDs.
+func (rd *RootDoc) mergeNumbering(other *RootDoc, used map[int]bool) (map[int]int, error) {
+	numIDs := make(map[int]int)
+	if len(used) == 0 {
+		return numIDs, nil
+	}
+
+	if other.Numbering != nil {
+		if err := other.Numbering.applyToFileMap(); err != nil {
+			return nil, err
+		}
+	}
+	srcContent, ok := other.FileMap.Load(numberingPath)
+	if !ok {
+		return numIDs, nil
+	}
+	srcAbstracts, srcNums, err := readNumbering(srcContent.([]byte))
+	if err != nil {
+		return nil, err
+	}
+
+	if rd.Numbering != nil {
+		if err := rd.Numbering.applyToFileMap(); err != nil {
+			return nil, err
+		}
+	}
+	var dstContent string
+	if content, ok := rd.FileMap.Load(numberingPath); ok {
+		dstContent = string(content.([]byte))
+	} else {
+		dstContent = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<w:numbering xmlns:w="` + constants.WMLNamespace + `"></w:numbering>`
+		rd.Document.addRelation(constants.SourceRelationshipNumbering, "numbering.xml")
+		if err := rd.ContentType.AddOverride("/"+numberingPath, constants.NumberingContentType); err != nil {
+			return nil, err
+		}
+	}
+	dstAbstracts, dstNums, err := readNumbering([]byte(dstContent))
+	if err != nil {
+		return nil, err
+	}
+
+	nextAbstract, nextNum := 0, 0
+	for _, a := range dstAbstracts {
+		if a.id >= nextAbstract {
+			nextAbstract = a.id + 1
+		}
+	}
+	for _, n := range dstNums {
+		if n.id >= nextNum {
+			nextNum = n.id + 1
+		}
+	}
+	if nextNum == 0 {
+		nextNum = 1
+	}
+
+	ids := make([]int, 0, len(used))
+	for id := range used {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	abstractIDs := make(map[int]int)
+	var abstractsXML, numsXML strings.Builder
+	for _, id := range ids {
+		var num *numberingDef
+		for i := range srcNums {
+			if srcNums[i].id == id {
+				num = &srcNums[i]
+			}
+		}
+		if num == nil {
+			continue
+		}
+
+		newAbstract, ok := abstractIDs[num.abstract]
+		if !ok {
+			for _, a := range srcAbstracts {
+				if a.id != num.abstract {
+					continue
+				}
+				// 201 and 202 are kept for the lists created by NumberingManager.
+				for nextAbstract == 201 || nextAbstract == 202 {
+					nextAbstract++
+				}
+				newAbstract = nextAbstract
+				nextAbstract++
+				abstractIDs[num.abstract] = newAbstract
+
+				raw := abstractNumIDAttr.ReplaceAllString(a.raw, fmt.Sprintf(`abstractNumId="%d"`, newAbstract))
+				// The list identifier makes Word join lists sharing it, so it is made unique too.
+				raw = nsidVal.ReplaceAllString(raw, fmt.Sprintf(`${1}%08X"`, 0xD0C00000+newAbstract))
+				abstractsXML.WriteString(raw)
+			}
+		}
+
+		raw := numIDAttr.ReplaceAllString(num.raw, fmt.Sprintf(`numId="%d"`, nextNum))
+		raw = abstractNumIDVal.ReplaceAllString(raw, fmt.Sprintf(`${1}%d"`, newAbstract))
+		numsXML.WriteString(raw)
+		numIDs[id] = nextNum
+		nextNum++
+	}
+
+	// Abstract definitions come before all instances, and instances before the cleanup list.
+	content := dstContent
+	at := strings.Index(content, "<w:num ")
+	if at < 0 {
+		at = strings.LastIndex(content, "</w:numbering>")
+	}
+	content = content[:at] + abstractsXML.String() + content[at:]
+
+	at = strings.LastIndex(content, "</w:num>")
+	if at >= 0 {
+		at += len("</w:num>")
+	} else if at = strings.Index(content, "<w:numIdMacAtCleanup"); at < 0 {
+		at = strings.LastIndex(content, "</w:numbering>")
+	}
+	content = content[:at] + numsXML.String() + content[at:]
+
+	rd.FileMap.Store(numberingPath, []byte(content))
+	return numIDs, nil
+}
@@ -0,0 +1,126 @@
+package docx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clauseStyle(color string) ctypes.Style {
+	return ctypes.Style{
+		ID:      internal.ToPtr("Clause"),
+		Type:    internal.ToPtr(stypes.StyleTypeParagraph),
+		Name:    ctypes.NewCTString("Clause"),
+		RunProp: &ctypes.RunProperty{Color: ctypes.NewColor(color)},
+	}
+}
+
+func setupAppendDocs(t *testing.T) (*docx.RootDoc, *docx.RootDoc) {
+	t.Helper()
+
+	dst, err := godocx.NewDocument()
+	require.NoError(t, err)
+	dst.SetStyle(clauseStyle("000000"))
+	list := dst.NewListInstance(1)
+	dst.AddParagraph("Proposal").Numbering(list, 0)
+	_, err = dst.AddPicture("../godocx.png", units.Inch(1), units.Inch(1))
+	require.NoError(t, err)
+
+	src, err := godocx.NewDocument()
+	require.NoError(t, err)
+	src.SetStyle(clauseStyle("FF0000"))
+	p := src.AddParagraph("Clause 1")
+	p.Style("Clause")
+	p.Numbering(src.NewListInstance(1), 0)
+	src.AddParagraph("See ").AddLink("the terms", "https://example.com/terms")
+	_, err = src.AddPicture("../godocx.png", units.Inch(1), units.Inch(1))
+	require.NoError(t, err)
+
+	return dst, src
+}
+
+func TestAppendDocument(t *testing.T) {
+	dst, src := setupAppendDocs(t)
+	before := len(dst.Document.Body.Children)
+
+	require.NoError(t, dst.AppendDocument(src, &docx.AppendOptions{Styles: docx.KeepSourceStyles, NewSection: true}))
+
+	children := dst.Document.Body.Children
+	require.Len(t, children, before+1+len(src.Document.Body.Children))
+	assert.NotNil(t, children[before].Para.GetCT().Property.SectPr, "the first section ends before the appended content")
+
+	clause := children[before+1].Para
+	assert.Equal(t, "Clause 1", clause.Text())
+	assert.Equal(t, "Clause_1", clause.StyleID(), "the conflicting style is renamed")
+	assert.NotEqual(t, children[0].Para.NumberingID(), clause.NumberingID(), "the list gets a numbering instance of its own")
+	assert.Equal(t, "Clause", src.Document.Body.Children[0].Para.StyleID(), "the source is left unchanged")
+
+	renamed := dst.GetStyleByID("Clause_1", stypes.StyleTypeParagraph)
+	require.NotNil(t, renamed)
+	assert.Equal(t, "Clause (1)", renamed.Name.Val)
+	assert.Equal(t, "FF0000", renamed.RunProp.Color.Val)
+
+	link := children[before+2].Para.Hyperlinks()[0]
+	assert.Equal(t, "https://example.com/terms", link.URL())
+
+	var buf bytes.Buffer
+	require.NoError(t, dst.Write(&buf))
+	files := readZip(t, buf.Bytes())
+	assert.Contains(t, files, "word/media/image2.png", "appended images get new names")
+
+	numbering := string(files["word/numbering.xml"])
+	assert.Contains(t, numbering, fmt.Sprintf(`<w:num w:numId="%d"><w:abstractNumId w:val="203"/>`, clause.NumberingID()))
+	assert.Contains(t, numbering, `<w:abstractNum w:abstractNumId="203">`, "the list definition is copied under a new ID")
+}
+
+func TestAppendDocument_DestinationStyles(t *testing.T) {
+	dst, src := setupAppendDocs(t)
+	styles := len(dst.DocStyles.StyleList)
+
+	require.NoError(t, dst.AppendDocument(src, nil))
+
+	last := dst.Document.Body.Children
+	assert.Equal(t, "Clause", last[len(last)-3].Para.StyleID())
+	assert.Len(t, dst.DocStyles.StyleList, styles, "no style is copied")
+	assert.Error(t, dst.AppendDocument(dst, nil))
+}
+
+func TestAppendDocument_Reopen(t *testing.T) {
+	dst, src := setupAppendDocs(t)
+	require.NoError(t, dst.AppendDocument(src, &docx.AppendOptions{Styles: docx.KeepSourceStyles}))
+
+	path := t.TempDir() + "/appended.docx"
+	require.NoError(t, dst.SaveTo(path))
+	reopened, err := godocx.OpenDocument(path)
+	require.NoError(t, err)
+	assert.Contains(t, reopened.Text(), "Clause 1\nSee the terms")
+}
+
+func readZip(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[f.Name] = content
+	}
+	return files
+}
@@ -0,0 +1,399 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gomutex/godocx/wml/ctypes"
+)
+
+// blockList is the list of paragraphs and tables holding a block: the document body, a story,
+// a table cell or a content control. Exactly one of doc and cell is set; cell also holds the
+// contents of content controls, which are marked by control.
+type blockList struct {
+	doc     *[]DocumentChild
+	cell    *[]ctypes.TCBlockContent
+	control bool
+}
+
+// errNotInDocument is returned when editing a block or run that is not part of the document.
+var errNotInDocument = errors.New("element is not part of the document")
+
+// blockRef identifies a paragraph, table or content control by its element. One field is set.
+type blockRef struct {
+	para *ctypes.Paragraph
+	tbl  *ctypes.Table
+	sdt  *ctypes.SDT
+}
+
+func (ref blockRef) isChild(child DocumentChild) bool {
+	return (ref.para != nil && child.Para != nil && child.Para.ct == ref.para) ||
+		(ref.tbl != nil && child.Table != nil && child.Table.ct == ref.tbl) ||
+		(ref.sdt != nil && child.Control != nil && child.Control.ct == ref.sdt)
+}
+
+func (ref blockRef) isContent(content ctypes.TCBlockContent) bool {
+	return (ref.para != nil && content.Paragraph == ref.para) ||
+		(ref.tbl != nil && content.Table == ref.tbl) ||
+		(ref.sdt != nil && content.SDT == ref.sdt)
+}
+
+// findBlock returns the list holding a paragraph or table and its index in that list.
+// Blocks are found by identity, so wrappers stay usable after other blocks are moved.
+func (rd *RootDoc) findBlock(para *ctypes.Paragraph, tbl *ctypes.Table) (blockList, int, bool) {
+	return rd.findRef(blockRef{para: para, tbl: tbl})
+}
+
+// findRef returns the list holding a block and its index in that list.
+func (rd *RootDoc) findRef(ref blockRef) (blockList, int, bool) {
+	lists := []*[]DocumentChild{&rd.Document.Body.Children}
+	if rd.storiesLoaded {
+		for _, part := range rd.storyParts {
+			for _, s := range part.stories {
+				lists = append(lists, &s.Children)
+			}
+		}
+	}
+
+	for _, list := range lists {
+		for i, child := range *list {
+			if ref.isChild(child) {
+				return blockList{doc: list}, i, true
+			}
+			if child.Table != nil {
+				if bl, idx, ok := findInTable(child.Table.ct, ref); ok {
+					return bl, idx, true
+				}
+			}
+			if child.Control != nil {
+				if bl, idx, ok := findInContents(&child.Control.ct.Contents, true, ref); ok {
+					return bl, idx, true
+				}
+			}
+		}
+	}
+	return blockList{}, 0, false
+}
+
+func findInTable(t *ctypes.Table, ref blockRef) (blockList, int, bool) {
+	for _, rc := range t.RowContents {
+		if rc.Row == nil {
+			continue
+		}
+
+		for _, cc := range rc.Row.Contents {
+			if cc.Cell == nil {
+				continue
+			}
+
+			if bl, idx, ok := findInContents(&cc.Cell.Contents, false, ref); ok {
+				return bl, idx, true
+			}
+		}
+	}
+	return blockList{}, 0, false
+}
+
+// findInContents looks for a block in the contents of a cell or content control.
+func findInContents(contents *[]ctypes.TCBlockContent, control bool, ref blockRef) (blockList, int, bool) {
+	for i, content := range *contents {
+		if ref.isContent(content) {
+			return blockList{cell: contents, control: control}, i, true
+		}
+		if content.Table != nil {
+			if bl, idx, ok := findInTable(content.Table, ref); ok {
+				return bl, idx, true
+			}
+		}
+		if content.SDT != nil {
+			if bl, idx, ok := findInContents(&content.SDT.Contents, true, ref); ok {
+				return bl, idx, true
+			}
+		}
+	}
+	return blockList{}, 0, false
+}
+
+func (bl blockList) len() int {
+	if bl.doc != nil {
+		return len(*bl.doc)
+	}
+	return len(*bl.cell)
+}
+
+// insert adds a paragraph, table or content control at an index of the list.
+func (bl blockList) insert(index int, child DocumentChild) {
+	if bl.doc != nil {
+		*bl.doc = append(*bl.doc, DocumentChild{})
+		copy((*bl.doc)[index+1:], (*bl.doc)[index:])
+		(*bl.doc)[index] = child
+		return
+	}
+
+	content := ctypes.TCBlockContent{}
+	if para := child.Para; para != nil {
+		content.Paragraph = para.ct
+	}
+	if tbl := child.Table; tbl != nil {
+		content.Table = tbl.ct
+	}
+	if ctl := child.Control; ctl != nil {
+		content.SDT = ctl.ct
+	}
+	*bl.cell = append(*bl.cell, ctypes.TCBlockContent{})
+	copy((*bl.cell)[index+1:], (*bl.cell)[index:])
+	(*bl.cell)[index] = content
+}
+
+// remove takes the block at an index out of the list. The last paragraph of a table cell
+// cannot be removed, as a cell must end with a paragraph.
+func (bl blockList) remove(index int) error {
+	if bl.doc != nil {
+		*bl.doc = append((*bl.doc)[:index], (*bl.doc)[index+1:]...)
+		return nil
+	}
+
+	contents := append([]ctypes.TCBlockContent{}, (*bl.cell)[:index]...)
+	contents = append(contents, (*bl.cell)[index+1:]...)
+	if err := bl.checkContents(contents); err != nil {
+		return err
+	}
+	*bl.cell = contents
+	return nil
+}
+
+// move places the block at index from at index to.
+func (bl blockList) move(from, to int) error {
+	if to < 0 || to >= bl.len() {
+		return fmt.Errorf("index %d is out of range for %d elements", to, bl.len())
+	}
+
+	if bl.doc != nil {
+		child := (*bl.doc)[from]
+		*bl.doc = append((*bl.doc)[:from], (*bl.doc)[from+1:]...)
+		*bl.doc = append((*bl.doc)[:to], append([]DocumentChild{child}, (*bl.doc)[to:]...)...)
+		return nil
+	}
+
+	content := (*bl.cell)[from]
+	contents := append([]ctypes.TCBlockContent{}, (*bl.cell)[:from]...)
+	contents = append(contents, (*bl.cell)[from+1:]...)
+	contents = append(contents[:to], append([]ctypes.TCBlockContent{content}, contents[to:]...)...)
+	if err := bl.checkContents(contents); err != nil {
+		return err
+	}
+	*bl.cell = contents
+	return nil
+}
+
+// checkContents makes sure that edited cell contents still end with a paragraph. Content
+// controls have no such rule.
+func (bl blockList) checkContents(contents []ctypes.TCBlockContent) error {
+	if bl.control {
+		return nil
+	}
+	if len(contents) == 0 || contents[len(contents)-1].Paragraph == nil {
+		return errors.New("a table cell must end with a paragraph")
+	}
+	return nil
+}
+
+// insertBlock adds a paragraph or table next to an existing block.
+func (rd *RootDoc) insertBlock(at *ctypes.Paragraph, atTbl *ctypes.Table, after bool, para *Paragraph, tbl *Table) error {
+	bl, index, ok := rd.findBlock(at, atTbl)
+	if !ok {
+		return errNotInDocument
+	}
+
+	if after {
+		index++
+	}
+	bl.insert(index, DocumentChild{Para: para, Table: tbl})
+	return nil
+}
+
+// InsertParagraphBefore adds a paragraph with the given text just before this paragraph, in
+// the body, story or table cell holding it.
+func (p *Paragraph) InsertParagraphBefore(text string) (*Paragraph, error) {
+	para := newParagraph(p.root, paraWithText(text))
+	return para, p.root.insertBlock(p.ct, nil, false, para, nil)
+}
+
+// InsertParagraphAfter adds a paragraph with the given text just after this paragraph, in the
+// body, story or table cell holding it.
+func (p *Paragraph) InsertParagraphAfter(text string) (*Paragraph, error) {
+	para := newParagraph(p.root, paraWithText(text))
+	return para, p.root.insertBlock(p.ct, nil, true, para, nil)
+}
+
+// InsertTableBefore adds an empty table just before this paragraph.
+func (p *Paragraph) InsertTableBefore() (*Table, error) {
+	tbl := &Table{root: p.root, ct: ctypes.DefaultTable()}
+	return tbl, p.root.insertBlock(p.ct, nil, false, nil, tbl)
+}
+
+// InsertTableAfter adds an empty table just after this paragraph.
+func (p *Paragraph) InsertTableAfter() (*Table, error) {
+	tbl := &Table{root: p.root, ct: ctypes.DefaultTable()}
+	return tbl, p.root.insertBlock(p.ct, nil, true, nil, tbl)
+}
+
+// Remove takes the paragraph out of the body, story or table cell holding it.
+func (p *Paragraph) Remove() error {
+	bl, index, ok := p.root.findBlock(p.ct, nil)
+	if !ok {
+		return errNotInDocument
+	}
+	return bl.remove(index)
+}
+
+// MoveTo moves the paragraph to an index of the body, story or table cell holding it.
+// Index counts paragraphs and tables, and is the position of the paragraph after the move.
+func (p *Paragraph) MoveTo(index int) error {
+	bl, from, ok := p.root.findBlock(p.ct, nil)
+	if !ok {
+		return errNotInDocument
+	}
+	return bl.move(from, index)
+}
+
+// InsertParagraphBefore adds a paragraph with the given text just before this table.
+func (t *Table) InsertParagraphBefore(text string) (*Paragraph, error) {
+	para := newParagraph(t.root, paraWithText(text))
+	return para, t.root.insertBlock(nil, t.ct, false, para, nil)
+}
+
+// InsertParagraphAfter adds a paragraph with the given text just after this table.
+func (t *Table) InsertParagraphAfter(text string) (*Paragraph, error) {
+	para := newParagraph(t.root, paraWithText(text))
+	return para, t.root.insertBlock(nil, t.ct, true, para, nil)
+}
+
+// InsertTableAfter adds an empty table just after this table.
+func (t *Table) InsertTableAfter() (*Table, error) {
+	tbl := &Table{root: t.root, ct: ctypes.DefaultTable()}
+	return tbl, t.root.insertBlock(nil, t.ct, true, nil, tbl)
+}
+
+// Remove takes the table out of the body, story or table cell holding it.
+func (t *Table) Remove() error {
+	bl, index, ok := t.root.findBlock(nil, t.ct)
+	if !ok {
+		return errNotInDocument
+	}
+	return bl.remove(index)
+}
+
+// MoveTo moves the table to an index of the body, story or table cell holding it.
+// Index counts paragraphs and tables, and is the position of the table after the move.
+func (t *Table) MoveTo(index int) error {
+	bl, from, ok := t.root.findBlock(nil, t.ct)
+	if !ok {
+		return errNotInDocument
+	}
+	return bl.move(from, index)
+}
+
+// Runs returns the runs of the paragraph in text order, including the runs of hyperlinks and
+// tracked insertions.
+func (p *Paragraph) Runs() []*Run {
+	var runs []*Run
+	for _, tr := range paragraphRuns(p.ct) {
+		r := newRun(p.root, tr.run)
+		r.para = p.ct
+		runs = append(runs, r)
+	}
+	return runs
+}
+
+// locate finds the run in its paragraph.
+func (r *Run) locate() (*ctypes.Paragraph, textRun, bool) {
+	paras := []*ctypes.Paragraph{r.para}
+	if r.para == nil && r.root != nil {
+		paras = blockParagraphs(r.root.Document.Body.Children)
+		if r.root.storiesLoaded {
+			for _, part := range r.root.storyParts {
+				for _, s := range part.stories {
+					paras = append(paras, blockParagraphs(s.Children)...)
+				}
+			}
+		}
+	}
+
+	for _, p := range paras {
+		if p == nil {
+			continue
+		}
+		for _, tr := range paragraphRuns(p) {
+			if tr.run == r.ct {
+				return p, tr, true
+			}
+		}
+	}
+	return nil, textRun{}, false
+}
+
+// InsertRunBefore adds a run with the given text just before this run, inside the same
+// hyperlink if any. The new run has no formatting of its own.
+func (r *Run) InsertRunBefore(text string) (*Run, error) {
+	p, tr, ok := r.locate()
+	if !ok {
+		return nil, errNotInDocument
+	}
+
+	run := &ctypes.Run{Children: []ctypes.RunChild{{Text: ctypes.TextFromString(text)}}}
+	tr.insertBefore(run)
+	return &Run{root: r.root, ct: run, para: p}, nil
+}
+
+// InsertRunAfter adds a run with the given text just after this run, inside the same
+// hyperlink if any. The new run has no formatting of its own.
+func (r *Run) InsertRunAfter(text string) (*Run, error) {
+	p, tr, ok := r.locate()
+	if !ok {
+		return nil, errNotInDocument
+	}
+
+	run := &ctypes.Run{Children: []ctypes.RunChild{{Text: ctypes.TextFromString(text)}}}
+	tr.insertAfter(run)
+	return &Run{root: r.root, ct: run, para: p}, nil
+}
+
+// Remove takes the run out of its paragraph. A hyperlink left without runs is removed too.
+func (r *Run) Remove() error {
+	p, tr, ok := r.locate()
+	if !ok {
+		return errNotInDocument
+	}
+
+	tr.remove()
+	removeEmptyLinks(&p.Children)
+	return nil
+}
+
+// MoveTo moves the run so that it becomes the run at index of Paragraph.Runs. A run moved
+// next to a hyperlink run joins that hyperlink.
+func (r *Run) MoveTo(index int) error {
+	p, tr, ok := r.locate()
+	if !ok {
+		return errNotInDocument
+	}
+
+	runs := paragraphRuns(p)
+	if index < 0 || index >= len(runs) {
+		return fmt.Errorf("index %d is out of range for %d runs", index, len(runs))
+	}
+
+	tr.remove()
+	runs = paragraphRuns(p)
+	switch {
+	case index < len(runs):
+		runs[index].insertBefore(r.ct)
+	case len(runs) > 0:
+		runs[len(runs)-1].insertAfter(r.ct)
+	default:
+		p.Children = append(p.Children, ctypes.ParagraphChild{Run: r.ct})
+	}
+	removeEmptyLinks(&p.Children)
+	return nil
+}
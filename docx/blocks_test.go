@@ -0,0 +1,109 @@
+package docx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bodyTexts(rd *RootDoc) []string {
+	var texts []string
+	for _, child := range rd.Document.Body.Children {
+		if child.Para != nil {
+			texts = append(texts, child.Para.Text())
+		} else {
+			texts = append(texts, "<table>")
+		}
+	}
+	return texts
+}
+
+func TestParagraph_InsertRemoveMove(t *testing.T) {
+	rd := setupRootDoc(t)
+	intro := rd.AddParagraph("Intro")
+	scope := rd.AddParagraph("Scope")
+	optional := rd.AddParagraph("Optional")
+
+	clause, err := scope.InsertParagraphAfter("Clause")
+	require.NoError(t, err)
+	_, err = scope.InsertParagraphBefore("Heading")
+	require.NoError(t, err)
+	tbl, err := clause.InsertTableAfter()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Intro", "Heading", "Scope", "Clause", "<table>", "Optional"}, bodyTexts(rd))
+
+	require.NoError(t, optional.Remove())
+	require.NoError(t, intro.MoveTo(4))
+	assert.Equal(t, []string{"Heading", "Scope", "Clause", "<table>", "Intro"}, bodyTexts(rd))
+
+	// Wrappers keep working after other blocks have moved.
+	_, err = tbl.InsertParagraphBefore("Before table")
+	require.NoError(t, err)
+	require.NoError(t, tbl.MoveTo(0))
+	_, err = clause.InsertParagraphAfter("After clause")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"<table>", "Heading", "Scope", "Clause", "After clause", "Before table", "Intro"}, bodyTexts(rd))
+
+	assert.Error(t, intro.MoveTo(7))
+	assert.ErrorIs(t, optional.Remove(), errNotInDocument)
+}
+
+func TestCellContent_Edit(t *testing.T) {
+	rd := setupRootDoc(t)
+	cell := rd.AddTable().AddRow().AddCell()
+	first := cell.AddParagraph("first")
+	second := cell.AddParagraph("second")
+
+	middle, err := first.InsertParagraphAfter("middle")
+	require.NoError(t, err)
+	nested, err := first.InsertTableBefore()
+	require.NoError(t, err)
+
+	contents := cell.ct.Contents
+	require.Len(t, contents, 4)
+	assert.Same(t, nested.ct, contents[0].Table)
+	assert.Equal(t, "middle", paragraphText(contents[2].Paragraph))
+
+	require.NoError(t, second.MoveTo(1))
+	assert.Equal(t, "second", paragraphText(cell.ct.Contents[1].Paragraph))
+	assert.Error(t, nested.MoveTo(3), "a cell must end with a paragraph")
+
+	require.NoError(t, nested.Remove())
+	require.NoError(t, second.Remove())
+	require.NoError(t, first.Remove())
+	require.Len(t, cell.ct.Contents, 1)
+	assert.Error(t, middle.Remove(), "the last paragraph of a cell stays")
+	assert.Len(t, cell.ct.Contents, 1)
+}
+
+func TestRun_Edit(t *testing.T) {
+	rd := setupRootDoc(t)
+	p := rd.AddParagraph("one ")
+	p.AddText("two ")
+	p.AddLink("three", "https://example.com")
+
+	runs := p.Runs()
+	require.Len(t, runs, 3)
+
+	require.NoError(t, runs[1].Remove())
+	assert.Equal(t, "one three", p.Text())
+
+	_, err := runs[0].InsertRunAfter("and ")
+	require.NoError(t, err)
+	_, err = runs[2].InsertRunBefore("then ")
+	require.NoError(t, err)
+	assert.Equal(t, "one and then three", p.Text())
+	assert.Equal(t, "then ", runText(p.GetCT().Children[2].Link.Run), "a run inserted next to a link run joins the link")
+
+	require.NoError(t, runs[0].MoveTo(3))
+	assert.Equal(t, "and then threeone ", p.Text())
+
+	require.NoError(t, runs[2].Remove())
+	require.NoError(t, p.Runs()[1].Remove())
+	assert.Equal(t, "and one ", p.Text())
+	assert.Len(t, p.GetCT().Children, 2, "the emptied hyperlink is removed")
+
+	assert.ErrorIs(t, runs[1].Remove(), errNotInDocument)
+	assert.Error(t, runs[0].MoveTo(2))
+}
@@ -2,6 +2,7 @@ package docx
 
 import (
 	"encoding/xml"
+	"fmt"
 
 	"github.com/gomutex/godocx/wml/ctypes"
 )
@@ -14,10 +15,12 @@ type Body struct {
 	SectPr   *ctypes.SectionProp
 }
 
-// DocumentChild represents a child element within a Word document, which can be a Paragraph or a Table.
+// DocumentChild represents a child element within a Word document, which can be a Paragraph,
+// a Table or a block-level ContentControl.
 type DocumentChild struct {
-	Para  *Paragraph
-	Table *Table
+	Para    *Paragraph
+	Table   *Table
+	Control *ContentControl
 }
 
 // Use this function to initialize a new Body before adding content to it.
@@ -27,6 +30,23 @@ func NewBody(root *RootDoc) *Body {
 	}
 }
 
+// AddBlocks appends paragraphs, tables and content controls of the document to the body, in
+// the order given. Blocks made with NewParagraph and NewTable can be built in several
+// goroutines and added once all of them are done, so that the body follows the order of the
+// blocks rather than the order in which they were finished.
+//
+// Blocks of other documents are not added; see InsertAt.
+func (rd *RootDoc) AddBlocks(blocks ...DocumentChild) error {
+	for i, child := range blocks {
+		if childRoot(child) != rd {
+			return fmt.Errorf("add blocks: block %d is not a block of the document", i)
+		}
+	}
+
+	rd.Document.Body.Children = append(rd.Document.Body.Children, blocks...)
+	return nil
+}
+
 // MarshalXML implements the xml.Marshaler interface for the Body type.
 // It encodes the Body to its corresponding XML representation.
 func (b Body) MarshalXML(e *xml.Encoder, start xml.StartElement) (err error) {
@@ -50,6 +70,12 @@ func (b Body) MarshalXML(e *xml.Encoder, start xml.StartElement) (err error) {
 					return err
 				}
 			}
+
+			if child.Control != nil {
+				if err = child.Control.ct.MarshalXML(e, xml.StartElement{}); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -87,6 +113,12 @@ func (body *Body) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err erro
 					return err
 				}
 				body.Children = append(body.Children, DocumentChild{Table: tbl})
+			case "sdt":
+				ctl := NewContentControl(body.root)
+				if err := d.DecodeElement(ctl.ct, &elem); err != nil {
+					return err
+				}
+				body.Children = append(body.Children, DocumentChild{Control: ctl})
 			case "sectPr":
 				body.SectPr = ctypes.NewSectionProper()
 				if err := d.DecodeElement(body.SectPr, &elem); err != nil {
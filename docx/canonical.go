@@ -0,0 +1,296 @@
+package docx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// EqualOptions configures Equal.
+type EqualOptions struct {
+	// IgnoreFormatting compares the text and the structure of the documents only. Paragraph,
+	// run, table and section properties are left out, as are styles, numbering definitions,
+	// themes, fonts and settings.
+	IgnoreFormatting bool
+
+	// Properties compares the document properties too, such as the title, the author and the
+	// dates of creation and modification, which are left out by default.
+	Properties bool
+}
+
+// CanonicalHash returns the SHA-256 hash of the content and formatting of the document, as
+// hex digits. Documents that mean the same have the same hash, however they were written.
+//
+// The hash covers the parts of the package reachable through relationships, such as the
+// body, styles, numbering, headers, footers, notes and images, but not the document
+// properties. It leaves out what Word and other writers vary between saves of the same
+// content:
+//
+//   - revision save IDs (rsid attributes and the list of them in the settings) and the paragraph
+//     IDs of Word 2010 and later;
+//   - zip metadata, such as the order, dates and compression of the files;
+//   - the numbering of relationship IDs, which stand for what they point at: the content of
+//     the part, or the address of a hyperlink;
+//   - the names of parts and media files, images being compared by their bytes;
+//   - the order of attributes, namespace prefixes and whitespace between elements.
+func (rd *RootDoc) CanonicalHash() (string, error) {
+	return rd.canonicalHash(&EqualOptions{})
+}
+
+// Equal reports whether two documents have the same content and formatting, ignoring the
+// differences that RootDoc.CanonicalHash ignores.
+func Equal(a, b *RootDoc, opts *EqualOptions) (bool, error) {
+	if opts == nil {
+		opts = &EqualOptions{}
+	}
+
+	ha, err := a.canonicalHash(opts)
+	if err != nil {
+		return false, err
+	}
+	hb, err := b.canonicalHash(opts)
+	if err != nil {
+		return false, err
+	}
+	return ha == hb, nil
+}
+
+func (rd *RootDoc) canonicalHash(opts *EqualOptions) (string, error) {
+	files, err := rd.snapshot()
+	if err != nil {
+		return "", fmt.Errorf("canonical hash: %w", err)
+	}
+
+	c := &canonicalizer{files: files, opts: opts, digests: make(map[string]string), visiting: make(map[string]bool)}
+	digest, err := c.part("")
+	if err != nil {
+		return "", fmt.Errorf("canonical hash: %w", err)
+	}
+	return digest, nil
+}
+
+// relationshipsNS are the namespaces of the attributes holding relationship IDs.
+var relationshipsNS = map[string]bool{
+	"http://schemas.openxmlformats.org/officeDocument/2006/relationships": true,
+	"http://purl.oclc.org/ooxml/officeDocument/relationships":             true,
+}
+
+// formattingElements are the elements left out when formatting is ignored.
+var formattingElements = map[string]bool{
+	"pPr": true, "rPr": true, "tblPr": true, "tblPrEx": true, "tblGrid": true,
+	"trPr": true, "tcPr": true, "sectPr": true,
+}
+
+// formattingRels are the last segments of the types of the relationships left out when
+// formatting is ignored.
+var formattingRels = map[string]bool{
+	"styles": true, "stylesWithEffects": true, "numbering": true, "theme": true,
+	"fontTable": true, "settings": true, "webSettings": true,
+}
+
+// propertyRels are the last segments of the types of the relationships to document
+// properties.
+var propertyRels = map[string]bool{
+	"core-properties": true, "extended-properties": true, "extendedProperties": true,
+	"custom-properties": true, "customProperties": true, "thumbnail": true,
+}
+
+// textElements are the elements whose whitespace is content.
+var textElements = map[string]bool{"t": true, "delText": true, "instrText": true, "delInstrText": true}
+
+// canonicalizer computes the digests of the parts of a package.
+type canonicalizer struct {
+	files    map[string][]byte
+	opts     *EqualOptions
+	digests  map[string]string
+	visiting map[string]bool
+}
+
+// part returns the digest of a part, which covers its content and the parts it is related
+// to. The empty path stands for the package, which has relationships only.
+func (c *canonicalizer) part(p string) (string, error) {
+	if digest, ok := c.digests[p]; ok {
+		return digest, nil
+	}
+	if c.visiting[p] {
+		// Parts related to one another in a cycle stand for one another by their type.
+		return "cycle", nil
+	}
+	c.visiting[p] = true
+	defer delete(c.visiting, p)
+
+	rels, err := c.relations(p)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	if p != "" {
+		content, ok := c.files[p]
+		if !ok {
+			return "missing", nil
+		}
+		if isXMLContent(content) {
+			if err := c.writeXML(h, content, rels); err != nil {
+				return "", fmt.Errorf("%s: %w", p, err)
+			}
+		} else {
+			h.Write(content)
+		}
+	}
+
+	list := make([]string, 0, len(rels))
+	for _, rel := range rels {
+		list = append(list, rel)
+	}
+	sort.Strings(list)
+	for _, rel := range list {
+		writeField(h, 'R', rel)
+	}
+
+	digest := hex.EncodeToString(h.Sum(nil))
+	c.digests[p] = digest
+	return digest, nil
+}
+
+// relations returns the canonical form of the relationships of a part by ID: their type along
+// with the address they point at or the digest of the part they point at.
+func (c *canonicalizer) relations(p string) (map[string]string, error) {
+	relsPath := path.Join(path.Dir(p), "_rels", path.Base(p)+".rels")
+	if p == "" {
+		relsPath = "_rels/.rels"
+	}
+	content, ok := c.files[relsPath]
+	if !ok {
+		return nil, nil
+	}
+
+	var rels Relationships
+	if err := xml.Unmarshal(content, &rels); err != nil {
+		return nil, fmt.Errorf("%s: %w", relsPath, err)
+	}
+
+	out := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		kind := rel.Type[strings.LastIndex(rel.Type, "/")+1:]
+		if propertyRels[kind] && !c.opts.Properties || formattingRels[kind] && c.opts.IgnoreFormatting {
+			continue
+		}
+
+		if rel.TargetMode == "External" {
+			out[rel.ID] = kind + " " + rel.Target
+			continue
+		}
+		target := strings.TrimPrefix(rel.Target, "/")
+		if !strings.HasPrefix(rel.Target, "/") {
+			target = path.Join(path.Dir(p), rel.Target)
+		}
+		digest, err := c.part(target)
+		if err != nil {
+			return nil, err
+		}
+		out[rel.ID] = kind + " " + digest
+	}
+	return out, nil
+}
+
+// isXMLContent reports whether the content of a part is XML.
+func isXMLContent(content []byte) bool {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	return len(bytes.TrimSpace(content)) > 0 && bytes.TrimSpace(content)[0] == '<'
+}
+
+// writeXML writes the canonical form of an XML part to h. rels are the canonical forms of
+// the relationships of the part, which replace the relationship IDs.
+func (c *canonicalizer) writeXML(h hash.Hash, content []byte, rels map[string]string) error {
+	d := xml.NewDecoder(bytes.NewReader(content))
+
+	var (
+		names []string // names are the local names of the open elements.
+		skip  int      // skip is the depth of the element being left out, or 0.
+		text  strings.Builder
+	)
+	flush := func() {
+		s := text.String()
+		text.Reset()
+		if s == "" || strings.TrimSpace(s) == "" && (len(names) == 0 || !textElements[names[len(names)-1]]) {
+			return
+		}
+		writeField(h, 'T', s)
+	}
+
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if skip > 0 {
+				skip++
+				continue
+			}
+			if t.Name.Local == "rsids" || c.opts.IgnoreFormatting && formattingElements[t.Name.Local] {
+				skip = 1
+				continue
+			}
+			flush()
+			names = append(names, t.Name.Local)
+
+			var attrs []string
+			for _, a := range t.Attr {
+				switch {
+				case a.Name.Space == "xmlns" || a.Name.Space == "" && a.Name.Local == "xmlns":
+					continue
+				case strings.HasPrefix(a.Name.Local, "rsid") || a.Name.Local == "paraId" || a.Name.Local == "textId":
+					continue
+				}
+				value := a.Value
+				if relationshipsNS[a.Name.Space] {
+					if rel, ok := rels[value]; ok {
+						value = rel
+					}
+				}
+				attrs = append(attrs, a.Name.Space+" "+a.Name.Local+"="+value)
+			}
+			sort.Strings(attrs)
+
+			writeField(h, 'S', t.Name.Space+" "+t.Name.Local)
+			for _, a := range attrs {
+				writeField(h, 'A', a)
+			}
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+			flush()
+			names = names[:len(names)-1]
+			writeField(h, 'E', "")
+		case xml.CharData:
+			if skip == 0 {
+				text.Write(t)
+			}
+		}
+	}
+	return nil
+}
+
+// writeField writes a tagged string to h, ended by a character XML content cannot hold.
+func writeField(h hash.Hash, tag byte, s string) {
+	h.Write([]byte{tag})
+	io.WriteString(h, s)
+	h.Write([]byte{0})
+}
@@ -0,0 +1,136 @@
+package docx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/packager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildCanonicalDoc(t *testing.T) *docx.RootDoc {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+	p := rd.AddParagraph("See ")
+	p.AddLink("the site", "https://example.com")
+	p.AddText(" for details.").Bold(true)
+	_, err = rd.AddPicture("../godocx.png", units.Inch(1), units.Inch(1))
+	require.NoError(t, err)
+	return rd
+}
+
+// rewrite writes a document, changes the files of the package with edit and reads it back.
+func rewrite(t *testing.T, rd *docx.RootDoc, edit func(files map[string][]byte)) *docx.RootDoc {
+	var buf bytes.Buffer
+	require.NoError(t, rd.Write(&buf))
+	files := readZip(t, buf.Bytes())
+	edit(files)
+
+	var out bytes.Buffer
+	zw := zip.NewWriter(&out)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	data := out.Bytes()
+	doc, err := packager.Unpack(&data)
+	require.NoError(t, err)
+	return doc
+}
+
+func TestCanonicalHash(t *testing.T) {
+	rd := buildCanonicalDoc(t)
+	hash, err := rd.CanonicalHash()
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	again, err := buildCanonicalDoc(t).CanonicalHash()
+	require.NoError(t, err)
+	assert.Equal(t, hash, again)
+
+	// What Word varies between saves does not change the hash.
+	resaved := rewrite(t, rd, func(files map[string][]byte) {
+		rels, doc := "word/_rels/document.xml.rels", "word/document.xml"
+		for _, name := range []string{rels, doc} {
+			files[name] = bytes.ReplaceAll(files[name], []byte(`"rId`), []byte(`"rIdNew`))
+		}
+		files[doc] = bytes.ReplaceAll(files[doc], []byte("<w:p>"), []byte(`<w:p w:rsidR="00A1B2C3" w14:paraId="1A2B3C4D">`))
+		files["word/settings.xml"] = bytes.Replace(files["word/settings.xml"], []byte("</w:settings>"),
+			[]byte(`<w:rsids><w:rsidRoot w:val="00A1B2C3"/><w:rsid w:val="00D4E5F6"/></w:rsids></w:settings>`), 1)
+
+		for name, content := range files {
+			if strings.HasPrefix(name, "word/media/image") {
+				delete(files, name)
+				files[strings.Replace(name, "image", "picture", 1)] = content
+			}
+		}
+		files[rels] = bytes.ReplaceAll(files[rels], []byte("media/image"), []byte("media/picture"))
+	})
+	resavedHash, err := resaved.CanonicalHash()
+	require.NoError(t, err)
+	assert.Equal(t, hash, resavedHash)
+
+	changed := buildCanonicalDoc(t)
+	changed.AddParagraph("More text")
+	changedHash, err := changed.CanonicalHash()
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
+
+func TestEqual(t *testing.T) {
+	golden := buildCanonicalDoc(t)
+
+	rd := buildCanonicalDoc(t)
+	rd.Document.Body.Children[0].Para.AddText(" ")
+	equal, err := docx.Equal(golden, rd, nil)
+	require.NoError(t, err)
+	assert.False(t, equal, "whitespace in text counts")
+
+	rd = buildCanonicalDoc(t)
+	rd.Document.Body.Children[0].Para.Style("Title")
+	equal, err = docx.Equal(golden, rd, nil)
+	require.NoError(t, err)
+	assert.False(t, equal)
+	equal, err = docx.Equal(golden, rd, &docx.EqualOptions{IgnoreFormatting: true})
+	require.NoError(t, err)
+	assert.True(t, equal)
+
+	// Document properties are compared on request only.
+	rd = rewrite(t, buildCanonicalDoc(t), func(files map[string][]byte) {
+		files["docProps/core.xml"] = bytes.ReplaceAll(files["docProps/core.xml"], []byte("</dc:creator>"), []byte("someone</dc:creator>"))
+	})
+	equal, err = docx.Equal(golden, rd, nil)
+	require.NoError(t, err)
+	assert.True(t, equal)
+	equal, err = docx.Equal(golden, rd, &docx.EqualOptions{Properties: true})
+	require.NoError(t, err)
+	assert.False(t, equal)
+}
+
+func TestCanonicalHash_LeavesFileMap(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+	rd.AddParagraph("First").Numbering(rd.NewListInstance(1), 0)
+	before, ok := rd.FileMap.Load("word/numbering.xml")
+	require.True(t, ok)
+
+	_, err = rd.CanonicalHash()
+	require.NoError(t, err)
+
+	after, _ := rd.FileMap.Load("word/numbering.xml")
+	assert.Equal(t, before, after, "hashing does not store the generated numbering part")
+
+	var buf bytes.Buffer
+	require.NoError(t, rd.Write(&buf))
+	assert.Contains(t, string(readZip(t, buf.Bytes())["word/numbering.xml"]), `<w:num w:numId=`)
+}
@@ -0,0 +1,79 @@
+package docx
+
+import "github.com/gomutex/godocx/internal"
+
+// copyPackage returns a copy of the document with an empty body. The parts of the package,
+// its relationships, content types, styles and numbering instances are copied, and so are
+// the changes made to headers, footers, notes and comments, which the copy parses again on
+// first use.
+func (rd *RootDoc) copyPackage() (*RootDoc, error) {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+
+	c := &RootDoc{
+		Path:             rd.Path,
+		RootRels:         internal.DeepCopy(rd.RootRels),
+		ContentType:      internal.DeepCopy(rd.ContentType),
+		rID:              rd.rID,
+		ImageCount:       rd.ImageCount,
+		bookmarkID:       rd.bookmarkID,
+		bookmarksScanned: rd.bookmarksScanned,
+	}
+
+	rd.FileMap.Range(func(path, content any) bool {
+		c.FileMap.Store(path, append([]byte(nil), content.([]byte)...))
+		return true
+	})
+	for _, part := range rd.storyParts {
+		content, err := marshal(part)
+		if err != nil {
+			return nil, err
+		}
+		c.FileMap.Store(part.path, content)
+	}
+
+	if rd.DocStyles != nil {
+		styles := internal.DeepCopy(*rd.DocStyles)
+		c.DocStyles = &styles
+	}
+	if rd.Numbering != nil {
+		c.Numbering = rd.Numbering.copyFor(c)
+	} else {
+		c.Numbering = NewNumberingManager(c)
+	}
+
+	doc := rd.Document
+	c.Document = &Document{
+		Root:         c,
+		Background:   internal.DeepCopy(doc.Background),
+		Body:         NewBody(c),
+		DocRels:      internal.DeepCopy(doc.DocRels),
+		RID:          doc.RID,
+		relativePath: doc.relativePath,
+	}
+	return c, nil
+}
+
+// Clone returns an independent copy of the document, which can be changed and saved without
+// affecting the document. Parsing a template once and cloning it for each document made from
+// it saves reading the package again.
+//
+// Clone only reads the document, so several goroutines may clone a document at the same time
+// as long as none of them changes it.
+func (rd *RootDoc) Clone() (*RootDoc, error) {
+	c, err := rd.copyPackage()
+	if err != nil {
+		return nil, err
+	}
+
+	body := rd.Document.Body
+	for _, child := range body.Children {
+		block, _ := copyBlock(c, child)
+		c.Document.Body.Children = append(c.Document.Body.Children, block)
+	}
+	if body.SectPr != nil {
+		sectPr := internal.DeepCopy(*body.SectPr)
+		c.Document.Body.SectPr = &sectPr
+	}
+	return c, nil
+}
@@ -0,0 +1,75 @@
+package docx_test
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+	rd.AddParagraph("Dear {{name}},")
+	tbl := rd.AddTable()
+	tbl.AddRow().AddCell().AddParagraph("cell")
+	list := rd.NewListInstance(1)
+
+	c, err := rd.Clone()
+	require.NoError(t, err)
+
+	c.Document.Body.Children[0].Para.AddText(" welcome")
+	c.Document.Body.Children[1].Table.Rows()[0].Cells()[0].AddParagraph("more")
+	c.AddParagraph("Only in the clone").Numbering(c.NewListInstance(1), 0)
+	c.SetStyle(ctypes.Style{ID: internal.ToPtr("Clone"), Type: internal.ToPtr(stypes.StyleTypeParagraph)})
+	c.FileMap.Store("word/extra.xml", []byte("<extra/>"))
+
+	assert.Equal(t, "Dear {{name}},", rd.Document.Body.Children[0].Para.Text())
+	assert.Len(t, rd.Document.Body.Children, 2)
+	assert.Len(t, rd.Document.Body.Children[1].Table.Rows()[0].Cells()[0].Paragraphs(), 1)
+	assert.Nil(t, rd.GetStyleByID("Clone", stypes.StyleTypeParagraph))
+	_, ok := rd.FileMap.Load("word/extra.xml")
+	assert.False(t, ok)
+	assert.Equal(t, list+1, rd.NewListInstance(1), "numbering instances of the clone are its own")
+
+	var buf bytes.Buffer
+	require.NoError(t, c.Write(&buf))
+	files := readZip(t, buf.Bytes())
+	assert.Contains(t, string(files["word/document.xml"]), "Only in the clone")
+	assert.Contains(t, string(files["word/numbering.xml"]), fmt.Sprintf(`w:numId="%d"`, list+1))
+}
+
+func TestClone_Concurrent(t *testing.T) {
+	rd, err := godocx.OpenDocument("../testdata/test.docx")
+	require.NoError(t, err)
+	_, err = rd.Stories()
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c, err := rd.Clone()
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			c.AddParagraph(fmt.Sprintf("Document %d", i))
+			errs[i] = c.Write(&bytes.Buffer{})
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+}
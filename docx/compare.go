@@ -0,0 +1,945 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+)
+
+// CompareOptions configures Compare.
+type CompareOptions struct {
+	// Author is the author of the revisions of the redlined document. It defaults to "godocx".
+	Author string
+
+	// Date is the date of the revisions. Revisions have no date if it is zero.
+	Date time.Time
+}
+
+// ChangeKind is the kind of a difference found by Compare.
+type ChangeKind string
+
+const (
+	ChangeInserted  ChangeKind = "inserted"
+	ChangeDeleted   ChangeKind = "deleted"
+	ChangeReplaced  ChangeKind = "replaced"
+	ChangeFormatted ChangeKind = "formatted"
+)
+
+// Change is a difference between two documents found by Compare.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+
+	// Location is where the change is in the redlined document, such as "paragraph 4" or
+	// "table 1, row 2, cell 3, paragraph 1", counting from 1.
+	Location string `json:"location"`
+
+	Original string `json:"original,omitempty"` // Original is the text deleted or reformatted.
+	Revised  string `json:"revised,omitempty"`  // Revised is the text inserted or reformatted.
+}
+
+// Compare compares two documents and returns a redlined document, which is the revised
+// document with the differences from the original recorded as tracked changes, along with
+// the list of the differences.
+//
+// Paragraphs and table rows of both documents are aligned, and aligned paragraphs are
+// compared word by word. Text that was removed or added becomes a deletion or an insertion,
+// and text whose character formatting changed gets a formatting revision; paragraphs and
+// rows without a counterpart are deleted or inserted as a whole. Changes of paragraph
+// formatting, such as of the style, are listed but not tracked in the redlined document.
+//
+// Deleted content is copied from the original document with the images and hyperlinks it
+// uses. Bookmarks and comment ranges are kept in paragraphs whose text is unchanged only.
+// Neither document is changed.
+func Compare(original, revised *RootDoc, opts *CompareOptions) (*RootDoc, []Change, error) {
+	if opts == nil {
+		opts = &CompareOptions{}
+	}
+
+	redline, err := revised.copyPackage()
+	if err != nil {
+		return nil, nil, fmt.Errorf("compare: %w", err)
+	}
+
+	c := newComparer(original, redline, opts.Author, opts.Date, original, revised)
+	c.im = newImporter(original, redline)
+
+	blocks, err := c.blocks(bodyRefs(original.Document.Body), bodyRefs(revised.Document.Body), "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("compare: %w", err)
+	}
+
+	body := redline.Document.Body
+	for _, ref := range blocks {
+		switch {
+		case ref.para != nil:
+			p := newParagraph(redline)
+			p.ct = ref.para
+			body.Children = append(body.Children, DocumentChild{Para: p})
+		case ref.tbl != nil:
+			t := NewTable(redline)
+			t.ct = ref.tbl
+			body.Children = append(body.Children, DocumentChild{Table: t})
+		case ref.sdt != nil:
+			ctl := &ContentControl{root: redline, ct: ref.sdt}
+			body.Children = append(body.Children, DocumentChild{Control: ctl})
+		}
+	}
+	if sectPr := revised.Document.Body.SectPr; sectPr != nil {
+		sp := internal.DeepCopy(*sectPr)
+		body.SectPr = &sp
+	}
+
+	redline.bookmarkID, redline.bookmarksScanned = c.lastID, true
+	return redline, c.changes, nil
+}
+
+// bodyRefs returns the blocks of a body.
+func bodyRefs(body *Body) []blockRef {
+	refs := make([]blockRef, 0, len(body.Children))
+	for _, child := range body.Children {
+		switch {
+		case child.Para != nil:
+			refs = append(refs, blockRef{para: child.Para.ct})
+		case child.Table != nil:
+			refs = append(refs, blockRef{tbl: child.Table.ct})
+		case child.Control != nil:
+			refs = append(refs, blockRef{sdt: child.Control.ct})
+		}
+	}
+	return refs
+}
+
+// contentRefs returns the blocks of the content of a table cell or content control.
+func contentRefs(contents []ctypes.TCBlockContent) []blockRef {
+	refs := make([]blockRef, 0, len(contents))
+	for _, content := range contents {
+		switch {
+		case content.Paragraph != nil:
+			refs = append(refs, blockRef{para: content.Paragraph})
+		case content.Table != nil:
+			refs = append(refs, blockRef{tbl: content.Table})
+		case content.SDT != nil:
+			refs = append(refs, blockRef{sdt: content.SDT})
+		}
+	}
+	return refs
+}
+
+// refContents returns blocks as the content of a table cell or content control.
+func refContents(refs []blockRef) []ctypes.TCBlockContent {
+	contents := make([]ctypes.TCBlockContent, 0, len(refs))
+	for _, ref := range refs {
+		contents = append(contents, ctypes.TCBlockContent{Paragraph: ref.para, Table: ref.tbl, SDT: ref.sdt})
+	}
+	return contents
+}
+
+// comparer builds a redlined document out of two documents.
+type comparer struct {
+	orig, rev *RootDoc // orig and rev are the documents compared content belongs to.
+
+	// im copies deleted content of the original into the redlined document. Content is not
+	// copied when im is nil, as it is when the original content belongs to rev already.
+	im *importer
+
+	author  string
+	date    *string
+	lastID  int // lastID is the last ID given to a revision.
+	quiet   int // quiet is non-zero while the content of a table or row listed as a whole is compared.
+	changes []Change
+}
+
+// newComparer returns a comparer of content of orig and rev making revisions by author.
+// Revision IDs do not collide with the IDs of the bookmarks of docs.
+func newComparer(orig, rev *RootDoc, author string, date time.Time, docs ...*RootDoc) *comparer {
+	c := &comparer{orig: orig, rev: rev, author: author}
+	if c.author == "" {
+		c.author = "godocx"
+	}
+	if !date.IsZero() {
+		d := date.UTC().Format(time.RFC3339)
+		c.date = &d
+	}
+
+	for _, rd := range docs {
+		for _, p := range blockParagraphs(rd.Document.Body.Children) {
+			for _, child := range p.Children {
+				if child.BmkStart != nil && child.BmkStart.ID > c.lastID {
+					c.lastID = child.BmkStart.ID
+				}
+			}
+		}
+	}
+	return c
+}
+
+// track returns the attributes of a new revision.
+func (c *comparer) track() ctypes.TrackChange {
+	c.lastID++
+	return ctypes.TrackChange{ID: c.lastID, Author: c.author, Date: c.date}
+}
+
+func (c *comparer) record(change Change) {
+	if c.quiet == 0 {
+		c.changes = append(c.changes, change)
+	}
+}
+
+// blocks compares two lists of blocks and returns the redlined list. loc is the location of
+// the list, such as "table 1, row 2, cell 1, ", or "" for the body.
+func (c *comparer) blocks(orig, rev []blockRef, loc string) ([]blockRef, error) {
+	origKeys, revKeys := blockKeys(orig), blockKeys(rev)
+	origWords, revWords := blockWords(orig), blockWords(rev)
+
+	ops := diffSequences(len(orig), len(rev),
+		func(i, j int) bool { return origKeys[i] == revKeys[j] },
+		func(i, j int) bool {
+			a, b := orig[i], rev[j]
+			if (a.para != nil) != (b.para != nil) || (a.tbl != nil) != (b.tbl != nil) {
+				return false
+			}
+			if a.sdt != nil {
+				return b.sdt != nil && sdtTag(a.sdt) == sdtTag(b.sdt)
+			}
+			return similar(origWords[i], revWords[j])
+		})
+
+	var out []blockRef
+	paras, tables, controls := 0, 0, 0
+	for _, op := range ops {
+		var a, b blockRef
+		if op.a >= 0 {
+			a = orig[op.a]
+		}
+		if op.b >= 0 {
+			b = rev[op.b]
+		}
+
+		switch {
+		case a.para != nil || b.para != nil:
+			paras++
+			p, err := c.paragraph(a.para, b.para, fmt.Sprintf("%sparagraph %d", loc, paras))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, blockRef{para: p})
+		case a.tbl != nil || b.tbl != nil:
+			tables++
+			t, err := c.table(a.tbl, b.tbl, fmt.Sprintf("%stable %d", loc, tables))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, blockRef{tbl: t})
+		default:
+			controls++
+			sdt, err := c.control(a.sdt, b.sdt, fmt.Sprintf("%scontent control %d, ", loc, controls))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, blockRef{sdt: sdt})
+		}
+	}
+	return out, nil
+}
+
+// control compares two content controls, either of which may be nil.
+func (c *comparer) control(orig, rev *ctypes.SDT, loc string) (*ctypes.SDT, error) {
+	var out ctypes.SDT
+	var origContents, revContents []blockRef
+	if orig != nil {
+		out = ctypes.SDT{Property: internal.DeepCopy(orig.Property), EndProperty: internal.DeepCopy(orig.EndProperty)}
+		origContents = contentRefs(orig.Contents)
+	}
+	if rev != nil {
+		out = ctypes.SDT{Property: internal.DeepCopy(rev.Property), EndProperty: internal.DeepCopy(rev.EndProperty)}
+		revContents = contentRefs(rev.Contents)
+	}
+
+	contents, err := c.blocks(origContents, revContents, loc)
+	if err != nil {
+		return nil, err
+	}
+	out.Contents = refContents(contents)
+	return &out, nil
+}
+
+// table compares two tables, either of which may be nil, aligning their rows.
+func (c *comparer) table(orig, rev *ctypes.Table, loc string) (*ctypes.Table, error) {
+	if orig == nil || rev == nil {
+		// A table inserted or deleted as a whole is listed once.
+		if orig == nil {
+			c.record(Change{Kind: ChangeInserted, Location: loc, Revised: tableText(rev)})
+		} else {
+			c.record(Change{Kind: ChangeDeleted, Location: loc, Original: tableText(orig)})
+		}
+		c.quiet++
+		defer func() { c.quiet-- }()
+	}
+
+	var out ctypes.Table
+	var origRows, revRows []*ctypes.Row
+	if orig != nil {
+		out.TableProp, out.Grid = internal.DeepCopy(orig.TableProp), internal.DeepCopy(orig.Grid)
+		origRows = tableRows(orig)
+	}
+	if rev != nil {
+		out.TableProp, out.Grid = internal.DeepCopy(rev.TableProp), internal.DeepCopy(rev.Grid)
+		revRows = tableRows(rev)
+	}
+
+	origKeys, revKeys := make([]string, len(origRows)), make([]string, len(revRows))
+	origWords, revWords := make([][]string, len(origRows)), make([][]string, len(revRows))
+	for i, row := range origRows {
+		origKeys[i] = rowText(row)
+		origWords[i] = words(origKeys[i])
+	}
+	for j, row := range revRows {
+		revKeys[j] = rowText(row)
+		revWords[j] = words(revKeys[j])
+	}
+
+	ops := diffSequences(len(origRows), len(revRows),
+		func(i, j int) bool { return origKeys[i] == revKeys[j] },
+		func(i, j int) bool {
+			return len(origRows[i].Contents) == len(revRows[j].Contents) && similar(origWords[i], revWords[j])
+		})
+
+	for _, op := range ops {
+		var a, b *ctypes.Row
+		if op.a >= 0 {
+			a = origRows[op.a]
+		}
+		if op.b >= 0 {
+			b = revRows[op.b]
+		}
+
+		// Rows whose cells do not match up are replaced.
+		if a != nil && b != nil && len(a.Contents) != len(b.Contents) {
+			for _, pair := range [][2]*ctypes.Row{{a, nil}, {nil, b}} {
+				row, err := c.row(pair[0], pair[1], fmt.Sprintf("%s, row %d", loc, len(out.RowContents)+1))
+				if err != nil {
+					return nil, err
+				}
+				out.RowContents = append(out.RowContents, ctypes.RowContent{Row: row})
+			}
+			continue
+		}
+
+		row, err := c.row(a, b, fmt.Sprintf("%s, row %d", loc, len(out.RowContents)+1))
+		if err != nil {
+			return nil, err
+		}
+		out.RowContents = append(out.RowContents, ctypes.RowContent{Row: row})
+	}
+	return &out, nil
+}
+
+// row compares two rows with as many cells, either of which may be nil.
+func (c *comparer) row(orig, rev *ctypes.Row, loc string) (*ctypes.Row, error) {
+	src := rev
+	if src == nil {
+		src = orig
+	}
+	out := &ctypes.Row{PropException: internal.DeepCopy(src.PropException), Property: internal.DeepCopy(src.Property)}
+
+	if orig == nil || rev == nil {
+		if out.Property == nil {
+			out.Property = ctypes.DefaultRowProperty()
+		}
+		track := c.track()
+		if orig == nil {
+			out.Property.Ins = &track
+			c.record(Change{Kind: ChangeInserted, Location: loc, Revised: rowText(rev)})
+		} else {
+			out.Property.Del = &track
+			c.record(Change{Kind: ChangeDeleted, Location: loc, Original: rowText(orig)})
+		}
+		c.quiet++
+		defer func() { c.quiet-- }()
+	}
+
+	for i, cc := range src.Contents {
+		if cc.Cell == nil {
+			continue
+		}
+
+		var origContents, revContents []blockRef
+		if orig != nil && orig.Contents[i].Cell != nil {
+			origContents = contentRefs(orig.Contents[i].Cell.Contents)
+		}
+		if rev != nil && rev.Contents[i].Cell != nil {
+			revContents = contentRefs(rev.Contents[i].Cell.Contents)
+		}
+
+		contents, err := c.blocks(origContents, revContents, fmt.Sprintf("%s, cell %d, ", loc, i+1))
+		if err != nil {
+			return nil, err
+		}
+		cell := &ctypes.Cell{Property: internal.DeepCopy(cc.Cell.Property), Contents: refContents(contents)}
+		out.Contents = append(out.Contents, ctypes.TRCellContent{Cell: cell})
+	}
+	return out, nil
+}
+
+// diffToken is a word, a run of spaces, a punctuation mark, a piece of non-text run content
+// such as a tab or a picture, or a whole hyperlink.
+type diffToken struct {
+	key   string              // key is what tokens are compared by.
+	text  string              // text is the text of the token.
+	prop  *ctypes.RunProperty // prop is the formatting of the run holding the token.
+	child *ctypes.RunChild    // child is non-text run content.
+	link  *ctypes.Hyperlink   // link is the hyperlink the token stands for.
+}
+
+var tokenRegexp = regexp.MustCompile(`\s+|[\p{L}\p{N}_]+|[^\s\p{L}\p{N}_]`)
+
+// paragraphTokens splits the content of a paragraph of rd into tokens. Inserted runs count
+// as plain runs and deleted runs are left out, so the paragraph is compared as if its
+// revisions were accepted.
+func paragraphTokens(rd *RootDoc, p *ctypes.Paragraph) []diffToken {
+	if p == nil {
+		return nil
+	}
+
+	var tokens []diffToken
+	addRun := func(r *ctypes.Run) {
+		for i := range r.Children {
+			child := &r.Children[i]
+			if child.Text != nil {
+				for _, word := range tokenRegexp.FindAllString(child.Text.Text, -1) {
+					tokens = append(tokens, diffToken{key: word, text: word, prop: r.Property})
+				}
+				continue
+			}
+
+			key, err := xml.Marshal(ctypes.Run{Children: []ctypes.RunChild{*child}})
+			if err != nil {
+				continue
+			}
+			tokens = append(tokens, diffToken{key: "\x00" + string(key), text: runChildText(*child), prop: r.Property, child: child})
+		}
+	}
+
+	for _, child := range p.Children {
+		switch {
+		case child.Run != nil:
+			addRun(child.Run)
+		case child.Ins != nil:
+			for _, r := range child.Ins.Runs {
+				addRun(r)
+			}
+		case child.Link != nil:
+			// Links are compared by their targets, as relationship IDs differ between documents.
+			target := child.Link.ID
+			if rel := rd.Document.relationByID(child.Link.ID); rel != nil {
+				target = rel.Target
+			}
+			text := linkText(child.Link)
+			tokens = append(tokens, diffToken{key: "\x01" + target + "#" + child.Link.Anchor + "\x00" + text, text: text, link: child.Link})
+		}
+	}
+	return tokens
+}
+
+func linkText(link *ctypes.Hyperlink) string {
+	var sb strings.Builder
+	writeChildrenText(&sb, []ctypes.ParagraphChild{{Link: link}})
+	return sb.String()
+}
+
+// tokenState tells how a token of a redlined paragraph differs from the original.
+type tokenState int
+
+const (
+	tokenEqual tokenState = iota
+	tokenInserted
+	tokenDeleted
+	tokenFormatted
+)
+
+// stateToken is a token of a redlined paragraph.
+type stateToken struct {
+	diffToken
+	state tokenState
+	old   *ctypes.RunProperty // old is the formatting a formatted token had.
+}
+
+// paragraph compares two paragraphs, either of which may be nil, word by word.
+func (c *comparer) paragraph(orig, rev *ctypes.Paragraph, loc string) (*ctypes.Paragraph, error) {
+	a, b := paragraphTokens(c.orig, orig), paragraphTokens(c.rev, rev)
+
+	switch {
+	case orig == nil:
+		c.record(Change{Kind: ChangeInserted, Location: loc, Revised: paragraphText(rev)})
+	case rev == nil:
+		c.record(Change{Kind: ChangeDeleted, Location: loc, Original: paragraphText(orig)})
+	default:
+		if !reflect.DeepEqual(paragraphFormat(orig), paragraphFormat(rev)) {
+			c.record(Change{Kind: ChangeFormatted, Location: loc, Original: paragraphText(orig), Revised: paragraphText(rev)})
+		}
+	}
+
+	ops := diffSequences(len(a), len(b), func(i, j int) bool { return a[i].key == b[j].key }, nil)
+
+	var tokens []stateToken
+	unchanged := orig != nil && rev != nil
+	for _, op := range ops {
+		switch {
+		case op.a < 0:
+			tokens = append(tokens, stateToken{diffToken: b[op.b], state: tokenInserted})
+			unchanged = false
+		case op.b < 0:
+			tokens = append(tokens, stateToken{diffToken: a[op.a], state: tokenDeleted})
+			unchanged = false
+		case b[op.b].link == nil && !sameFormat(a[op.a].prop, b[op.b].prop):
+			tokens = append(tokens, stateToken{diffToken: b[op.b], state: tokenFormatted, old: a[op.a].prop})
+			unchanged = false
+		default:
+			tokens = append(tokens, stateToken{diffToken: b[op.b], state: tokenEqual})
+		}
+	}
+
+	// Paragraphs with the same content keep everything they hold, such as bookmarks.
+	if unchanged {
+		return rev.Clone(), nil
+	}
+	if orig != nil && rev != nil {
+		c.recordTokens(tokens, loc)
+	}
+
+	var out ctypes.Paragraph
+	if rev != nil {
+		out.Property = internal.DeepCopy(rev.Property)
+	} else {
+		out.Property = internal.DeepCopy(orig.Property)
+	}
+	if orig == nil || rev == nil {
+		if out.Property == nil {
+			out.Property = ctypes.DefaultParaProperty()
+		}
+		if out.Property.RunProperty == nil {
+			out.Property.RunProperty = &ctypes.RunProperty{}
+		}
+		track := c.track()
+		if orig == nil {
+			out.Property.RunProperty.Ins = &track
+		} else {
+			out.Property.RunProperty.Del = &track
+		}
+	}
+
+	for start := 0; start < len(tokens); {
+		end := start + 1
+		for end < len(tokens) && tokens[end].link == nil && tokens[start].link == nil &&
+			tokens[end].state == tokens[start].state && sameFormat(tokens[end].prop, tokens[start].prop) &&
+			sameFormat(tokens[end].old, tokens[start].old) {
+			end++
+		}
+
+		children, err := c.tokenChildren(tokens[start:end])
+		if err != nil {
+			return nil, err
+		}
+		out.Children = append(out.Children, children...)
+		start = end
+	}
+	return &out, nil
+}
+
+// tokenChildren returns the paragraph content for tokens of the same state and formatting,
+// or for a hyperlink.
+func (c *comparer) tokenChildren(tokens []stateToken) ([]ctypes.ParagraphChild, error) {
+	state := tokens[0].state
+	var children []ctypes.ParagraphChild
+
+	if link := tokens[0].link; link != nil {
+		children = []ctypes.ParagraphChild{{Link: internal.DeepCopy(link)}}
+	} else {
+		run := &ctypes.Run{Property: internal.DeepCopy(tokens[0].prop)}
+		var text strings.Builder
+		flush := func() {
+			if text.Len() > 0 {
+				run.Children = append(run.Children, ctypes.RunChild{Text: ctypes.TextFromString(text.String())})
+				text.Reset()
+			}
+		}
+		for _, t := range tokens {
+			if t.child != nil {
+				flush()
+				run.Children = append(run.Children, internal.DeepCopy(*t.child))
+			} else {
+				text.WriteString(t.text)
+			}
+		}
+		flush()
+
+		if state == tokenFormatted {
+			if run.Property == nil {
+				run.Property = &ctypes.RunProperty{}
+			}
+			old := internal.DeepCopy(tokens[0].old)
+			if old != nil {
+				old.Ins, old.Del, old.Change = nil, nil, nil
+			}
+			run.Property.Change = &ctypes.RunPropChange{TrackChange: c.track(), Property: old}
+		}
+		children = []ctypes.ParagraphChild{{Run: run}}
+	}
+
+	switch state {
+	case tokenInserted:
+		return wrapRevision(children, func(runs []*ctypes.Run) ctypes.ParagraphChild {
+			return ctypes.ParagraphChild{Ins: &ctypes.RunTrackChange{TrackChange: c.track(), Runs: runs}}
+		}), nil
+	case tokenDeleted:
+		// Deleted content comes from the original document.
+		if c.im != nil {
+			if err := c.im.paragraph(&ctypes.Paragraph{Children: children}); err != nil {
+				return nil, err
+			}
+		}
+		return wrapRevision(children, func(runs []*ctypes.Run) ctypes.ParagraphChild {
+			for _, r := range runs {
+				for i := range r.Children {
+					child := &r.Children[i]
+					if child.Text != nil {
+						child.DelText, child.Text = child.Text, nil
+					}
+					if child.InstrText != nil {
+						child.DelInstrText, child.InstrText = child.InstrText, nil
+					}
+				}
+			}
+			return ctypes.ParagraphChild{Del: &ctypes.RunTrackChange{TrackChange: c.track(), Runs: runs}}
+		}), nil
+	}
+	return children, nil
+}
+
+// wrapRevision puts the runs of children in insertions or deletions made by wrap. The runs of
+// a hyperlink are wrapped within the hyperlink.
+func wrapRevision(children []ctypes.ParagraphChild, wrap func(runs []*ctypes.Run) ctypes.ParagraphChild) []ctypes.ParagraphChild {
+	var out []ctypes.ParagraphChild
+	for _, child := range children {
+		switch {
+		case child.Run != nil:
+			out = append(out, wrap([]*ctypes.Run{child.Run}))
+		case child.Link != nil:
+			var runs []*ctypes.Run
+			for _, tr := range paragraphRuns(&ctypes.Paragraph{Children: []ctypes.ParagraphChild{child}}) {
+				runs = append(runs, tr.run)
+			}
+			link := child.Link
+			link.Run, link.Children = nil, []ctypes.ParagraphChild{wrap(runs)}
+			out = append(out, ctypes.ParagraphChild{Link: link})
+		}
+	}
+	return out
+}
+
+// recordTokens lists the changes of a paragraph present in both documents. Deletions and
+// insertions separated by spaces only are listed as one change.
+func (c *comparer) recordTokens(tokens []stateToken, loc string) {
+	if c.quiet > 0 {
+		return
+	}
+
+	for i := 0; i < len(tokens); {
+		state := tokens[i].state
+		if state == tokenEqual {
+			i++
+			continue
+		}
+
+		var orig, rev strings.Builder
+		end := i
+		if state == tokenFormatted {
+			for end < len(tokens) && tokens[end].state == tokenFormatted {
+				rev.WriteString(tokens[end].text)
+				end++
+			}
+			text := strings.TrimSpace(rev.String())
+			c.record(Change{Kind: ChangeFormatted, Location: loc, Original: text, Revised: text})
+			i = end
+			continue
+		}
+
+		deleted, inserted := false, false
+		for end < len(tokens) {
+			t := tokens[end]
+			if t.state == tokenEqual || t.state == tokenFormatted {
+				// Spaces between changes are part of the change.
+				next := end
+				for next < len(tokens) && (tokens[next].state == tokenEqual || tokens[next].state == tokenFormatted) &&
+					strings.TrimSpace(tokens[next].text) == "" && tokens[next].child == nil && tokens[next].link == nil {
+					next++
+				}
+				if next == end || next == len(tokens) || tokens[next].state == tokenEqual || tokens[next].state == tokenFormatted {
+					break
+				}
+				for ; end < next; end++ {
+					orig.WriteString(tokens[end].text)
+					rev.WriteString(tokens[end].text)
+				}
+				continue
+			}
+
+			if t.state == tokenDeleted {
+				deleted = true
+				orig.WriteString(t.text)
+			} else {
+				inserted = true
+				rev.WriteString(t.text)
+			}
+			end++
+		}
+
+		change := Change{Location: loc, Original: strings.TrimSpace(orig.String()), Revised: strings.TrimSpace(rev.String())}
+		switch {
+		case deleted && inserted:
+			change.Kind = ChangeReplaced
+		case deleted:
+			change.Kind, change.Revised = ChangeDeleted, ""
+		default:
+			change.Kind, change.Original = ChangeInserted, ""
+		}
+		c.record(change)
+		i = end
+	}
+}
+
+// sameFormat reports whether two run properties format text the same way.
+func sameFormat(a, b *ctypes.RunProperty) bool {
+	empty := ctypes.RunProperty{}
+	if a == nil {
+		a = &empty
+	}
+	if b == nil {
+		b = &empty
+	}
+	if a.Ins != nil || a.Del != nil || a.Change != nil || b.Ins != nil || b.Del != nil || b.Change != nil {
+		x, y := *a, *b
+		x.Ins, x.Del, x.Change = nil, nil, nil
+		y.Ins, y.Del, y.Change = nil, nil, nil
+		return reflect.DeepEqual(x, y)
+	}
+	return reflect.DeepEqual(*a, *b)
+}
+
+// paragraphFormat returns the paragraph properties that tell how a paragraph looks.
+func paragraphFormat(p *ctypes.Paragraph) ctypes.ParagraphProp {
+	if p.Property == nil {
+		return ctypes.ParagraphProp{}
+	}
+	prop := *p.Property
+	prop.RunProperty, prop.SectPr, prop.PPrChange = nil, nil, nil
+	return prop
+}
+
+// blockKeys returns the keys blocks are aligned by when they are the same.
+func blockKeys(refs []blockRef) []string {
+	keys := make([]string, len(refs))
+	for i, ref := range refs {
+		switch {
+		case ref.para != nil:
+			keys[i] = "p\x00" + paragraphText(ref.para)
+		case ref.tbl != nil:
+			keys[i] = "tbl\x00" + tableText(ref.tbl)
+		case ref.sdt != nil:
+			keys[i] = "sdt\x00" + sdtTag(ref.sdt) + "\x00" + contentsText(ref.sdt.Contents)
+		}
+	}
+	return keys
+}
+
+// blockWords returns the words of blocks, which tell how similar blocks are.
+func blockWords(refs []blockRef) [][]string {
+	out := make([][]string, len(refs))
+	for i, ref := range refs {
+		switch {
+		case ref.para != nil:
+			out[i] = words(paragraphText(ref.para))
+		case ref.tbl != nil:
+			out[i] = words(tableText(ref.tbl))
+		}
+	}
+	return out
+}
+
+func sdtTag(sdt *ctypes.SDT) string {
+	if sdt.Property == nil {
+		return ""
+	}
+	return sdt.Property.Tag
+}
+
+func tableRows(t *ctypes.Table) []*ctypes.Row {
+	var rows []*ctypes.Row
+	for _, rc := range t.RowContents {
+		if rc.Row != nil {
+			rows = append(rows, rc.Row)
+		}
+	}
+	return rows
+}
+
+// rowText returns the text of a row, with cells separated by tabs.
+func rowText(row *ctypes.Row) string {
+	var cells []string
+	for _, cc := range row.Contents {
+		if cc.Cell != nil {
+			cells = append(cells, contentsText(cc.Cell.Contents))
+		}
+	}
+	return strings.Join(cells, "\t")
+}
+
+// tableText returns the text of a table, with rows separated by newlines.
+func tableText(t *ctypes.Table) string {
+	var rows []string
+	for _, row := range tableRows(t) {
+		rows = append(rows, rowText(row))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func contentsText(contents []ctypes.TCBlockContent) string {
+	var parts []string
+	for _, p := range contentParagraphs(nil, contents) {
+		parts = append(parts, paragraphText(p))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// words returns the words and punctuation marks of text.
+func words(text string) []string {
+	var out []string
+	for _, token := range tokenRegexp.FindAllString(text, -1) {
+		if strings.TrimSpace(token) != "" {
+			out = append(out, token)
+		}
+	}
+	return out
+}
+
+// similar reports whether two lists of words have at least half of their words in common, so
+// that the blocks holding them are taken for versions of one another.
+func similar(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	common := len(lcsPairs(a, b))
+	return 2*common >= (len(a)+len(b))/2
+}
+
+func lcsPairs(a, b []string) [][2]int {
+	return lcs(len(a), len(b), func(i, j int) bool { return a[i] == b[j] })
+}
+
+// alignOp is a step of the alignment of two sequences: a pair of matching items, an item of
+// the first sequence only (b is -1) or an item of the second sequence only (a is -1).
+type alignOp struct {
+	a, b int
+}
+
+// diffSequences aligns two sequences. Items for which same returns true are matched first;
+// in the stretches between them, items for which similar returns true are paired up. Items
+// of the first sequence come before those of the second in each stretch of unmatched items.
+func diffSequences(n, m int, same, similarFn func(i, j int) bool) []alignOp {
+	var ops []alignOp
+	stretch := func(i0, i1, j0, j1 int) {
+		var pairs [][2]int
+		if similarFn != nil && i1 > i0 && j1 > j0 {
+			pairs = lcs(i1-i0, j1-j0, func(i, j int) bool { return similarFn(i0+i, j0+j) })
+		}
+		i, j := i0, j0
+		for _, pair := range pairs {
+			for ; i < i0+pair[0]; i++ {
+				ops = append(ops, alignOp{a: i, b: -1})
+			}
+			for ; j < j0+pair[1]; j++ {
+				ops = append(ops, alignOp{a: -1, b: j})
+			}
+			ops = append(ops, alignOp{a: i, b: j})
+			i, j = i+1, j+1
+		}
+		for ; i < i1; i++ {
+			ops = append(ops, alignOp{a: i, b: -1})
+		}
+		for ; j < j1; j++ {
+			ops = append(ops, alignOp{a: -1, b: j})
+		}
+	}
+
+	i, j := 0, 0
+	for _, pair := range lcs(n, m, same) {
+		stretch(i, pair[0], j, pair[1])
+		ops = append(ops, alignOp{a: pair[0], b: pair[1]})
+		i, j = pair[0]+1, pair[1]+1
+	}
+	stretch(i, n, j, m)
+	return ops
+}
+
+// lcsLimit bounds the size of the table used to find a longest common subsequence. Beyond it,
+// the parts of the sequences that differ are not aligned.
+const lcsLimit = 1 << 22
+
+// lcs returns the pairs of indexes of a longest common subsequence of two sequences whose
+// items match when match returns true.
+func lcs(n, m int, match func(i, j int) bool) [][2]int {
+	// Common ends are matched first, which keeps the table small for sequences that differ
+	// in a few places.
+	var head, tail [][2]int
+	for len(head) < n && len(head) < m && match(len(head), len(head)) {
+		head = append(head, [2]int{len(head), len(head)})
+	}
+	h := len(head)
+	for n-len(tail) > h && m-len(tail) > h && match(n-len(tail)-1, m-len(tail)-1) {
+		tail = append(tail, [2]int{n - len(tail) - 1, m - len(tail) - 1})
+	}
+	rows, cols := n-h-len(tail), m-h-len(tail)
+
+	pairs := head
+	if rows > 0 && cols > 0 && (rows+1)*(cols+1) <= lcsLimit {
+		// table[i*(cols+1)+j] is the length of a longest common subsequence of the items
+		// from i and from j of the middle parts.
+		table := make([]int32, (rows+1)*(cols+1))
+		at := func(i, j int) int { return i*(cols+1) + j }
+		for i := rows - 1; i >= 0; i-- {
+			for j := cols - 1; j >= 0; j-- {
+				switch {
+				case match(h+i, h+j):
+					table[at(i, j)] = table[at(i+1, j+1)] + 1
+				case table[at(i+1, j)] >= table[at(i, j+1)]:
+					table[at(i, j)] = table[at(i+1, j)]
+				default:
+					table[at(i, j)] = table[at(i, j+1)]
+				}
+			}
+		}
+
+		for i, j := 0, 0; i < rows && j < cols; {
+			switch {
+			case match(h+i, h+j):
+				pairs = append(pairs, [2]int{h + i, h + j})
+				i, j = i+1, j+1
+			case table[at(i+1, j)] >= table[at(i, j+1)]:
+				i++
+			default:
+				j++
+			}
+		}
+	}
+
+	for k := len(tail) - 1; k >= 0; k-- {
+		pairs = append(pairs, tail[k])
+	}
+	return pairs
+}
@@ -0,0 +1,113 @@
+package docx_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/packager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCompareDocs(t *testing.T) (*docx.RootDoc, *docx.RootDoc) {
+	original, err := godocx.NewDocument()
+	require.NoError(t, err)
+	original.AddParagraph("Payment is due within 30 days.")
+	original.AddParagraph("This clause is removed.")
+	original.AddParagraph("Terms apply.")
+	tbl := original.AddTable()
+	for _, text := range []string{"Fee", "Term"} {
+		row := tbl.AddRow()
+		row.AddCell().AddParagraph(text)
+		row.AddCell().AddParagraph("one year")
+	}
+
+	revised, err := godocx.NewDocument()
+	require.NoError(t, err)
+	revised.AddParagraph("Payment is due within 45 business days.")
+	p := revised.AddParagraph("")
+	p.AddText("Terms ")
+	p.AddText("apply").Bold(true)
+	p.AddText(".")
+	revised.AddParagraph("A new clause.")
+	tbl = revised.AddTable()
+	for _, text := range []string{"Fee", "Term"} {
+		row := tbl.AddRow()
+		row.AddCell().AddParagraph(text)
+		row.AddCell().AddParagraph("one year")
+	}
+	row := tbl.AddRow()
+	row.AddCell().AddParagraph("Notice")
+	row.AddCell().AddParagraph("30 days")
+	return original, revised
+}
+
+func TestCompare(t *testing.T) {
+	original, revised := setupCompareDocs(t)
+
+	redline, changes, err := docx.Compare(original, revised, &docx.CompareOptions{
+		Author: "Legal",
+		Date:   time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []docx.Change{
+		{Kind: docx.ChangeReplaced, Location: "paragraph 1", Original: "30", Revised: "45 business"},
+		{Kind: docx.ChangeDeleted, Location: "paragraph 2", Original: "This clause is removed."},
+		{Kind: docx.ChangeFormatted, Location: "paragraph 3", Original: "apply", Revised: "apply"},
+		{Kind: docx.ChangeInserted, Location: "paragraph 4", Revised: "A new clause."},
+		{Kind: docx.ChangeInserted, Location: "table 1, row 3", Revised: "Notice\t30 days"},
+	}, changes)
+
+	var buf bytes.Buffer
+	require.NoError(t, redline.Write(&buf))
+	doc := string(readZip(t, buf.Bytes())["word/document.xml"])
+	assert.Contains(t, doc, `<w:del w:id="`)
+	assert.Contains(t, doc, `w:author="Legal" w:date="2024-05-01T12:00:00Z"><w:r><w:delText>30</w:delText></w:r></w:del>`)
+	assert.Contains(t, doc, `<w:ins w:id="2" w:author="Legal" w:date="2024-05-01T12:00:00Z"><w:r><w:t>45 business</w:t></w:r></w:ins>`)
+	assert.Contains(t, doc, `<w:rPrChange`)
+	assert.Contains(t, doc, `<w:trPr><w:ins w:id="`)
+	assert.Equal(t, 1, strings.Count(doc, "This clause is removed."))
+
+	// The redlined document reads as the revised one.
+	assert.Equal(t, "Payment is due within 45 business days.", redline.Document.Body.Children[0].Para.Text())
+	assert.Equal(t, "", redline.Document.Body.Children[1].Para.Text())
+
+	// Reading the redlined document back keeps the revisions.
+	data := buf.Bytes()
+	reopened, err := packager.Unpack(&data)
+	require.NoError(t, err)
+	assert.Equal(t, "A new clause.", reopened.Document.Body.Children[3].Para.Text())
+	var again bytes.Buffer
+	require.NoError(t, reopened.Write(&again))
+	assert.Equal(t, doc, string(readZip(t, again.Bytes())["word/document.xml"]))
+}
+
+func TestCompare_Same(t *testing.T) {
+	original, revised := setupCompareDocs(t)
+
+	redline, changes, err := docx.Compare(revised, revised, nil)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	var buf bytes.Buffer
+	require.NoError(t, redline.Write(&buf))
+	doc := string(readZip(t, buf.Bytes())["word/document.xml"])
+	assert.NotContains(t, doc, "<w:ins")
+	assert.NotContains(t, doc, "<w:del")
+
+	_, changes, err = docx.Compare(original, revised, nil)
+	require.NoError(t, err)
+	assert.Len(t, changes, 5)
+	assert.Len(t, original.Document.Body.Children, 4, "the documents are left unchanged")
+
+	rd, err := godocx.OpenDocument("../testdata/test.docx")
+	require.NoError(t, err)
+	_, changes, err = docx.Compare(rd, rd, nil)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,83 @@
+package docx_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/docx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildBlocksConcurrently(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+	img, err := os.ReadFile("../godocx.png")
+	require.NoError(t, err)
+
+	const n = 8
+	blocks := make([][]docx.DocumentChild, n)
+	bookmarks := make([]int, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			p := docx.NewParagraph(rd)
+			p.AddText(fmt.Sprintf("Section %d ", i))
+			p.AddLink("source", fmt.Sprintf("https://example.com/%d", i))
+			p.Numbering(rd.NewListInstance(1), 0)
+			bookmarks[i] = p.AddBookmark(fmt.Sprintf("section%d", i))
+			if _, err := p.AddPictureFromBytes(img, "png", units.Inch(1), units.Inch(1)); err != nil {
+				errs[i] = err
+				return
+			}
+
+			tbl := docx.NewTable(rd)
+			tbl.AddRow().AddCell().AddParagraph(fmt.Sprintf("cell %d", i))
+			blocks[i] = []docx.DocumentChild{{Para: p}, {Table: tbl}}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, errs[i])
+		require.NoError(t, rd.AddBlocks(blocks[i]...))
+	}
+
+	children := rd.Document.Body.Children
+	require.Len(t, children, 2*n)
+	for i := 0; i < n; i++ {
+		assert.Equal(t, fmt.Sprintf("Section %d source", i), children[2*i].Para.Text(), "blocks are added in order")
+	}
+
+	ids := make(map[string]bool)
+	for _, rel := range rd.Document.DocRels.Relationships {
+		assert.False(t, ids[rel.ID], "relationship ID %s is given out once", rel.ID)
+		ids[rel.ID] = true
+	}
+	seen := make(map[int]bool)
+	for _, id := range bookmarks {
+		assert.False(t, seen[id], "bookmark ID %d is given out once", id)
+		seen[id] = true
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, rd.Write(&buf))
+	files := readZip(t, buf.Bytes())
+	for i := 1; i <= n; i++ {
+		assert.Contains(t, files, fmt.Sprintf("word/media/image%d.png", i))
+	}
+
+	other, err := godocx.NewDocument()
+	require.NoError(t, err)
+	assert.Error(t, rd.AddBlocks(docx.DocumentChild{Para: docx.NewParagraph(other)}))
+}
@@ -0,0 +1,55 @@
+package docx
+
+import (
+	"github.com/gomutex/godocx/wml/ctypes"
+)
+
+// ContentControl is a block-level content control, such as a rich text control or a
+// repeating section, holding paragraphs, tables and further content controls.
+type ContentControl struct {
+	root *RootDoc
+	ct   *ctypes.SDT
+}
+
+// NewContentControl returns an empty content control of the document. Its properties and
+// content are set through GetCT.
+func NewContentControl(root *RootDoc) *ContentControl {
+	return &ContentControl{root: root, ct: &ctypes.SDT{}}
+}
+
+// GetCT returns a pointer to the underlying ctypes.SDT instance.
+func (c *ContentControl) GetCT() *ctypes.SDT {
+	return c.ct
+}
+
+// Tag returns the tag of the content control, or an empty string if it has none.
+func (c *ContentControl) Tag() string {
+	if c.ct.Property == nil {
+		return ""
+	}
+	return c.ct.Property.Tag
+}
+
+// Alias returns the friendly name of the content control, or an empty string if it has none.
+func (c *ContentControl) Alias() string {
+	if c.ct.Property == nil {
+		return ""
+	}
+	return c.ct.Property.Alias
+}
+
+// Contents returns the paragraphs, tables and content controls inside the content control.
+func (c *ContentControl) Contents() []DocumentChild {
+	return wrapBlockContents(c.root, c.ct.Contents)
+}
+
+// Paragraphs returns the paragraphs directly inside the content control.
+func (c *ContentControl) Paragraphs() []*Paragraph {
+	var paras []*Paragraph
+	for _, child := range c.Contents() {
+		if child.Para != nil {
+			paras = append(paras, child.Para)
+		}
+	}
+	return paras
+}
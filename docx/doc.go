@@ -8,4 +8,18 @@
 // // The RootDoc structure is initialized from the main godocx package,
 // which provides methods for creating a new document from a default template or
 // opening an existing document.
+//
+// # Concurrency
+//
+// A RootDoc and its content must not be changed by several goroutines at the same time,
+// except for building separate blocks. Paragraphs and tables made with NewParagraph and
+// NewTable may be built in parallel, including adding hyperlinks, pictures, bookmarks and
+// list instances to them, as the IDs and parts these need are handed out safely. Once built,
+// the blocks are added to the body from a single goroutine with RootDoc.AddBlocks, in the
+// order they must appear in. Changing the body, styles or stories, and saving, must not
+// happen while blocks are being built.
+//
+// Reading a document from several goroutines is safe as long as none of them changes it. To
+// render many documents from one template in parallel, give each goroutine its own copy made
+// with RootDoc.Clone.
 package docx
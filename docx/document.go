@@ -36,15 +36,32 @@ type Document struct {
 	DocRels      Relationships // DocRels represents relationships specific to the document.
 	RID          int
 	relativePath string
+	textBoxes    []string // textBoxes are the texts of the text boxes dropped when the part was read.
 }
 
 // IncRelationID increments the relation ID of the document and returns the new ID.
 // This method is used to generate unique IDs for relationships within the document.
+// It is safe for concurrent use.
 func (doc *Document) IncRelationID() int {
+	defer doc.lock()()
+	return doc.incRelationID()
+}
+
+func (doc *Document) incRelationID() int {
 	doc.RID += 1
 	return doc.RID
 }
 
+// lock locks the state the document shares with content built concurrently and returns the
+// function unlocking it.
+func (doc *Document) lock() func() {
+	if doc.Root == nil {
+		return func() {}
+	}
+	doc.Root.mu.Lock()
+	return doc.Root.mu.Unlock
+}
+
 // MarshalXML implements the xml.Marshaler interface for the Document type.
 func (doc Document) MarshalXML(e *xml.Encoder, start xml.StartElement) (err error) {
 	start.Name.Local = "w:document"
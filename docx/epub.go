@@ -0,0 +1,858 @@
+package docx
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// EPUBOptions configures the EPUB 3 export performed by RootDoc.WriteEPUB.
+type EPUBOptions struct {
+	// SplitLevel is the deepest heading level that starts a new chapter.
+	// The Title style and headings at levels 1 to SplitLevel each begin a new chapter.
+	// Defaults to 1.
+	SplitLevel uint
+
+	// TOCLevel is the deepest heading level listed in the navigation document and the NCX.
+	// Defaults to 3.
+	TOCLevel uint
+
+	// Identifier is the unique identifier of the publication. When empty, the identifier
+	// from the core properties is used, or one is derived from the document title.
+	Identifier string
+
+	// Title is used when the core properties do not define a title.
+	Title string
+
+	// Language is used when the core properties do not define a language. Defaults to "en".
+	Language string
+}
+
+const (
+	epubContentDir = "OEBPS"
+	epubMimeType   = "application/epub+zip"
+	epubXHTMLType  = "application/xhtml+xml"
+)
+
+// WriteEPUB writes the document as an EPUB 3 publication to w.
+//
+// The body is split into chapters at the heading levels configured in opts, and the
+// navigation document and the NCX are generated from the document headings. Images
+// referenced from the body are packaged along with the chapters, and the title, creator
+// and language from the core properties are mapped into the package metadata.
+//
+// Parameters:
+//   - w: The writer to which the EPUB archive is written.
+//   - opts: Export options. A nil value uses the defaults.
+//
+// Example:
+//
+//	f, _ := os.Create("manual.epub")
+//	defer f.Close()
+//	err := document.WriteEPUB(f, &docx.EPUBOptions{SplitLevel: 2})
+func (rd *RootDoc) WriteEPUB(w io.Writer, opts *EPUBOptions) error {
+	if rd.Document == nil || rd.Document.Body == nil {
+		return fmt.Errorf("document has no body")
+	}
+
+	ew, err := newEPUBWriter(rd, opts)
+	if err != nil {
+		return err
+	}
+
+	ew.renderBody(rd.Document.Body.Children)
+
+	zw := zip.NewWriter(w)
+	if err := ew.writeTo(zw); err != nil {
+		_ = zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+// epubChapter holds the rendered XHTML body of a single chapter.
+type epubChapter struct {
+	id       string
+	fileName string
+	title    string
+	body     strings.Builder
+	hasText  bool
+}
+
+// epubNavPoint is a heading listed in the navigation document.
+type epubNavPoint struct {
+	level    uint
+	title    string
+	href     string
+	children []*epubNavPoint
+}
+
+// epubImage is an image part copied into the publication.
+type epubImage struct {
+	id        string
+	href      string
+	mediaType string
+	content   []byte
+}
+
+// epubBlock tracks the list nesting of the block currently being rendered.
+type epubBlock struct {
+	sb     *strings.Builder
+	liOpen []bool
+}
+
+type epubWriter struct {
+	rd   *RootDoc
+	opts EPUBOptions
+
+	identifier string
+	title      string
+	creator    string
+	language   string
+	modified   string
+
+	chapters []*epubChapter
+	nav      []*epubNavPoint
+	headings int
+
+	images     map[string]*epubImage
+	imageOrder []*epubImage
+}
+
+func newEPUBWriter(rd *RootDoc, opts *EPUBOptions) (*epubWriter, error) {
+	ew := &epubWriter{
+		rd:     rd,
+		images: make(map[string]*epubImage),
+	}
+
+	if opts != nil {
+		ew.opts = *opts
+	}
+	if ew.opts.SplitLevel == 0 {
+		ew.opts.SplitLevel = 1
+	}
+	if ew.opts.TOCLevel == 0 {
+		ew.opts.TOCLevel = 3
+	}
+
+	core, err := rd.CoreProperties()
+	if err != nil {
+		return nil, err
+	}
+
+	ew.title = firstNonEmpty(core.Title, ew.opts.Title, "Untitled")
+	ew.creator = core.Creator
+	ew.language = firstNonEmpty(core.Language, ew.opts.Language, "en")
+	ew.identifier = firstNonEmpty(ew.opts.Identifier, core.Identifier)
+	if ew.identifier == "" {
+		ew.identifier = nameBasedUUID(ew.title + "\x00" + ew.creator)
+	}
+
+	modified := time.Unix(0, 0).UTC()
+	if t, err := time.Parse(time.RFC3339, core.Modified); err == nil {
+		modified = t.UTC()
+	}
+	ew.modified = modified.Format("2006-01-02T15:04:05Z")
+
+	return ew, nil
+}
+
+// renderBody distributes the body content over chapters and renders it.
+func (ew *epubWriter) renderBody(children []DocumentChild) {
+	chapter := ew.newChapter()
+	block := &epubBlock{sb: &chapter.body}
+
+	// The content of content controls is rendered as if it were written in their place.
+	var render func(children []DocumentChild)
+	render = func(children []DocumentChild) {
+		for _, child := range children {
+			if child.Para != nil {
+				para := child.Para.ct
+				if level, ok := headingLevel(para); ok && level <= ew.opts.SplitLevel && chapter.hasText {
+					block.closeLists()
+					chapter = ew.newChapter()
+					block = &epubBlock{sb: &chapter.body}
+				}
+
+				if ew.writeParagraph(block, para, chapter) {
+					chapter.hasText = true
+				}
+			}
+
+			if child.Table != nil {
+				block.closeLists()
+				ew.writeTable(block, child.Table.ct)
+				chapter.hasText = true
+			}
+
+			if child.Control != nil {
+				render(child.Control.Contents())
+			}
+		}
+	}
+	render(children)
+	block.closeLists()
+}
+
+func (ew *epubWriter) newChapter() *epubChapter {
+	n := len(ew.chapters) + 1
+	chapter := &epubChapter{
+		id:       fmt.Sprintf("chapter%d", n),
+		fileName: fmt.Sprintf("chapter%d.xhtml", n),
+	}
+	ew.chapters = append(ew.chapters, chapter)
+	return chapter
+}
+
+// writeParagraph renders a paragraph and reports whether it produced any visible content.
+func (ew *epubWriter) writeParagraph(block *epubBlock, p *ctypes.Paragraph, chapter *epubChapter) bool {
+	var inline strings.Builder
+	ew.writeInline(&inline, p.Children)
+	content := inline.String()
+
+	if level, ok := headingLevel(p); ok {
+		block.closeLists()
+
+		ew.headings++
+		anchor := fmt.Sprintf("h%d", ew.headings)
+		tag := fmt.Sprintf("h%d", epubClamp(level, 1, 6))
+		title := strings.TrimSpace(paragraphText(p))
+
+		block.sb.WriteString(fmt.Sprintf("<%s id=\"%s\">%s</%s>\n", tag, anchor, content, tag))
+
+		if chapter != nil && title != "" {
+			if chapter.title == "" {
+				chapter.title = title
+			}
+			if epubClamp(level, 1, 9) <= ew.opts.TOCLevel {
+				ew.nav = append(ew.nav, &epubNavPoint{
+					level: epubClamp(level, 1, 9),
+					title: title,
+					href:  chapter.fileName + "#" + anchor,
+				})
+			}
+		}
+		return true
+	}
+
+	if p.Property != nil && p.Property.NumProp != nil && p.Property.NumProp.NumID != nil && p.Property.NumProp.NumID.Val != 0 {
+		level := uint(1)
+		if p.Property.NumProp.ILvl != nil && p.Property.NumProp.ILvl.Val > 0 {
+			level = uint(p.Property.NumProp.ILvl.Val) + 1
+		}
+		block.openItem(level)
+		block.sb.WriteString(content)
+		return true
+	}
+
+	block.closeLists()
+	if strings.TrimSpace(content) == "" {
+		return false
+	}
+
+	block.sb.WriteString("<p")
+	if p.Property != nil && p.Property.Justification != nil {
+		switch p.Property.Justification.Val {
+		case stypes.JustificationCenter:
+			block.sb.WriteString(` style="text-align: center"`)
+		case stypes.JustificationRight:
+			block.sb.WriteString(` style="text-align: right"`)
+		case stypes.JustificationBoth, stypes.JustificationDistribute:
+			block.sb.WriteString(` style="text-align: justify"`)
+		}
+	}
+	block.sb.WriteString(">")
+	block.sb.WriteString(content)
+	block.sb.WriteString("</p>\n")
+	return true
+}
+
+func (ew *epubWriter) writeInline(sb *strings.Builder, children []ctypes.ParagraphChild) {
+	for _, child := range children {
+		if child.Run != nil {
+			ew.writeRun(sb, child.Run)
+		}
+
+		if child.Link != nil {
+			href := ""
+			if rel := ew.rd.Document.relationByID(child.Link.ID); rel != nil && rel.TargetMode == "External" {
+				href = rel.Target
+			}
+
+			if href != "" {
+				sb.WriteString(fmt.Sprintf("<a href=\"%s\">", html.EscapeString(href)))
+			}
+			if child.Link.Run != nil {
+				ew.writeRun(sb, child.Link.Run)
+			}
+			ew.writeInline(sb, child.Link.Children)
+			if href != "" {
+				sb.WriteString("</a>")
+			}
+		}
+	}
+}
+
+func (ew *epubWriter) writeRun(sb *strings.Builder, r *ctypes.Run) {
+	var tags []string
+	if prop := r.Property; prop != nil {
+		if isOn(prop.Bold) {
+			tags = append(tags, "strong")
+		}
+		if isOn(prop.Italic) {
+			tags = append(tags, "em")
+		}
+		if prop.Underline != nil && prop.Underline.Val != stypes.UnderlineNone {
+			tags = append(tags, "u")
+		}
+		if isOn(prop.Strike) || isOn(prop.DoubleStrike) {
+			tags = append(tags, "s")
+		}
+		if prop.VertAlign != nil {
+			switch prop.VertAlign.Val {
+			case stypes.VerticalAlignRunSuperscript:
+				tags = append(tags, "sup")
+			case stypes.VerticalAlignRunSubscript:
+				tags = append(tags, "sub")
+			}
+		}
+	}
+
+	for _, tag := range tags {
+		sb.WriteString("<" + tag + ">")
+	}
+
+	for _, child := range r.Children {
+		switch {
+		case child.Text != nil:
+			sb.WriteString(html.EscapeString(child.Text.Text))
+		case child.Tab != nil:
+			sb.WriteString(" ")
+		case child.CarrRtn != nil:
+			sb.WriteString("<br/>")
+		case child.Break != nil:
+			if child.Break.BreakType == nil || *child.Break.BreakType == stypes.BreakTypeTextWrapping {
+				sb.WriteString("<br/>")
+			}
+		case child.Drawing != nil:
+			for _, img := range drawingImages(child.Drawing) {
+				if item := ew.addImage(img.RelID); item != nil {
+					sb.WriteString(fmt.Sprintf("<img src=\"%s\" alt=\"%s\"/>", html.EscapeString(item.href), html.EscapeString(img.Description)))
+				}
+			}
+		}
+	}
+
+	for i := len(tags) - 1; i >= 0; i-- {
+		sb.WriteString("</" + tags[i] + ">")
+	}
+}
+
+func (ew *epubWriter) writeTable(block *epubBlock, tbl *ctypes.Table) {
+	rows := make([]*ctypes.Row, 0, len(tbl.RowContents))
+	for _, rc := range tbl.RowContents {
+		if rc.Row != nil {
+			rows = append(rows, rc.Row)
+		}
+	}
+
+	block.sb.WriteString("<table>\n")
+	for rowIdx, row := range rows {
+		block.sb.WriteString("<tr>")
+
+		col := 0
+		for _, cc := range row.Contents {
+			cell := cc.Cell
+			if cell == nil {
+				continue
+			}
+
+			span := cellGridSpan(cell)
+			if cellMergeContinues(cell) {
+				col += span
+				continue
+			}
+
+			block.sb.WriteString("<td")
+			if span > 1 {
+				block.sb.WriteString(fmt.Sprintf(" colspan=\"%d\"", span))
+			}
+			if rowSpan := verticalSpan(rows, rowIdx, col); rowSpan > 1 {
+				block.sb.WriteString(fmt.Sprintf(" rowspan=\"%d\"", rowSpan))
+			}
+			block.sb.WriteString(">")
+
+			cellBlock := &epubBlock{sb: block.sb}
+			ew.writeCellContents(cellBlock, cell.Contents)
+			cellBlock.closeLists()
+
+			block.sb.WriteString("</td>")
+			col += span
+		}
+
+		block.sb.WriteString("</tr>\n")
+	}
+	block.sb.WriteString("</table>\n")
+}
+
+// writeCellContents renders the paragraphs and tables of a table cell, including those inside
+// content controls.
+func (ew *epubWriter) writeCellContents(block *epubBlock, contents []ctypes.TCBlockContent) {
+	for _, content := range contents {
+		if content.Paragraph != nil {
+			ew.writeParagraph(block, content.Paragraph, nil)
+		}
+		if content.Table != nil {
+			block.closeLists()
+			ew.writeTable(block, content.Table)
+		}
+		if content.SDT != nil {
+			ew.writeCellContents(block, content.SDT.Contents)
+		}
+	}
+}
+
+// addImage registers the image referenced by rID in the manifest and returns it.
+func (ew *epubWriter) addImage(rID string) *epubImage {
+	partPath, content, ok := ew.rd.imagePart(rID)
+	if !ok {
+		return nil
+	}
+
+	if item, ok := ew.images[partPath]; ok {
+		return item
+	}
+
+	ext := strings.TrimPrefix(path.Ext(partPath), ".")
+	mediaType, err := MIMEFromExt(strings.ToLower(ext))
+	if err != nil {
+		return nil
+	}
+
+	item := &epubImage{
+		id:        fmt.Sprintf("img%d", len(ew.imageOrder)+1),
+		href:      fmt.Sprintf("images/image%d.%s", len(ew.imageOrder)+1, strings.ToLower(ext)),
+		mediaType: mediaType,
+		content:   content,
+	}
+	ew.images[partPath] = item
+	ew.imageOrder = append(ew.imageOrder, item)
+	return item
+}
+
+// openItem starts a new list item at the given nesting level, opening or closing lists as needed.
+func (b *epubBlock) openItem(level uint) {
+	depth := uint(len(b.liOpen))
+
+	for depth > level {
+		b.closeList()
+		depth--
+	}
+
+	if depth == level && b.liOpen[depth-1] {
+		b.sb.WriteString("</li>\n")
+		b.liOpen[depth-1] = false
+	}
+
+	for depth < level {
+		if depth > 0 && !b.liOpen[depth-1] {
+			b.sb.WriteString("<li>")
+			b.liOpen[depth-1] = true
+		}
+		b.sb.WriteString("<ul>\n")
+		b.liOpen = append(b.liOpen, false)
+		depth++
+	}
+
+	b.sb.WriteString("<li>")
+	b.liOpen[depth-1] = true
+}
+
+func (b *epubBlock) closeList() {
+	depth := len(b.liOpen)
+	if b.liOpen[depth-1] {
+		b.sb.WriteString("</li>\n")
+	}
+	b.sb.WriteString("</ul>\n")
+	b.liOpen = b.liOpen[:depth-1]
+}
+
+func (b *epubBlock) closeLists() {
+	for len(b.liOpen) > 0 {
+		b.closeList()
+	}
+}
+
+func (ew *epubWriter) writeTo(zw *zip.Writer) error {
+	// The mimetype file must be the first entry of the archive and must not be compressed.
+	if err := writeEPUBEntry(zw, "mimetype", []byte(epubMimeType), zip.Store); err != nil {
+		return err
+	}
+
+	container := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">` +
+		`<rootfiles><rootfile full-path="` + epubContentDir + `/content.opf" media-type="application/oebps-package+xml"/></rootfiles>` +
+		`</container>`
+	if err := writeEPUBEntry(zw, "META-INF/container.xml", []byte(container), zip.Deflate); err != nil {
+		return err
+	}
+
+	opf, err := ew.packageDocument()
+	if err != nil {
+		return err
+	}
+	if err := writeEPUBEntry(zw, epubContentDir+"/content.opf", opf, zip.Deflate); err != nil {
+		return err
+	}
+
+	if err := writeEPUBEntry(zw, epubContentDir+"/nav.xhtml", ew.navDocument(), zip.Deflate); err != nil {
+		return err
+	}
+
+	ncx, err := ew.ncxDocument()
+	if err != nil {
+		return err
+	}
+	if err := writeEPUBEntry(zw, epubContentDir+"/toc.ncx", ncx, zip.Deflate); err != nil {
+		return err
+	}
+
+	if err := writeEPUBEntry(zw, epubContentDir+"/style.css", []byte(epubStyleSheet), zip.Deflate); err != nil {
+		return err
+	}
+
+	for _, chapter := range ew.chapters {
+		title := firstNonEmpty(chapter.title, ew.title)
+		if err := writeEPUBEntry(zw, epubContentDir+"/"+chapter.fileName, ew.xhtmlDocument(title, chapter.body.String()), zip.Deflate); err != nil {
+			return err
+		}
+	}
+
+	for _, img := range ew.imageOrder {
+		if err := writeEPUBEntry(zw, epubContentDir+"/"+img.href, img.content, zip.Deflate); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeEPUBEntry(zw *zip.Writer, name string, content []byte, method uint16) error {
+	fw, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   method,
+		Modified: time.Unix(0, 0).UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fw.Write(content)
+	return err
+}
+
+const epubStyleSheet = `body { font-family: serif; }
+h1, h2, h3, h4, h5, h6 { font-family: sans-serif; }
+table { border-collapse: collapse; }
+td { border: 1px solid #999; padding: 0.2em 0.4em; vertical-align: top; }
+img { max-width: 100%; }
+`
+
+func (ew *epubWriter) xhtmlDocument(title string, body string) []byte {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString("<!DOCTYPE html>\n")
+	sb.WriteString(fmt.Sprintf(`<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="%s" lang="%s">`+"\n",
+		html.EscapeString(ew.language), html.EscapeString(ew.language)))
+	sb.WriteString("<head>\n<meta charset=\"UTF-8\"/>\n")
+	sb.WriteString("<title>" + html.EscapeString(title) + "</title>\n")
+	sb.WriteString("<link rel=\"stylesheet\" type=\"text/css\" href=\"style.css\"/>\n</head>\n<body>\n")
+	sb.WriteString(body)
+	sb.WriteString("</body>\n</html>\n")
+	return []byte(sb.String())
+}
+
+// navTree arranges the flat list of headings into a tree following the heading levels.
+// If the document has no headings, every chapter is listed instead.
+func (ew *epubWriter) navTree() []*epubNavPoint {
+	points := ew.nav
+	if len(points) == 0 {
+		for i, chapter := range ew.chapters {
+			title := chapter.title
+			if title == "" {
+				title = ew.title
+				if len(ew.chapters) > 1 {
+					title = fmt.Sprintf("%s (%d)", ew.title, i+1)
+				}
+			}
+			points = append(points, &epubNavPoint{level: 1, title: title, href: chapter.fileName})
+		}
+	}
+
+	var roots, stack []*epubNavPoint
+	for _, point := range points {
+		point.children = nil
+		for len(stack) > 0 && stack[len(stack)-1].level >= point.level {
+			stack = stack[:len(stack)-1]
+		}
+
+		if len(stack) == 0 {
+			roots = append(roots, point)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, point)
+		}
+		stack = append(stack, point)
+	}
+	return roots
+}
+
+func (ew *epubWriter) navDocument() []byte {
+	var sb strings.Builder
+	sb.WriteString(`<nav epub:type="toc" id="toc">` + "\n")
+	sb.WriteString("<h1>" + html.EscapeString(ew.title) + "</h1>\n")
+
+	var writeList func(points []*epubNavPoint)
+	writeList = func(points []*epubNavPoint) {
+		sb.WriteString("<ol>\n")
+		for _, point := range points {
+			sb.WriteString(fmt.Sprintf("<li><a href=\"%s\">%s</a>", html.EscapeString(point.href), html.EscapeString(point.title)))
+			if len(point.children) > 0 {
+				sb.WriteString("\n")
+				writeList(point.children)
+			}
+			sb.WriteString("</li>\n")
+		}
+		sb.WriteString("</ol>\n")
+	}
+	writeList(ew.navTree())
+
+	sb.WriteString("</nav>\n")
+	return ew.xhtmlDocument(ew.title, sb.String())
+}
+
+type opfPackage struct {
+	XMLName          xml.Name    `xml:"http://www.idpf.org/2007/opf package"`
+	Version          string      `xml:"version,attr"`
+	UniqueIdentifier string      `xml:"unique-identifier,attr"`
+	Lang             string      `xml:"xml:lang,attr"`
+	Metadata         opfMetadata `xml:"metadata"`
+	Manifest         []opfItem   `xml:"manifest>item"`
+	Spine            opfSpine    `xml:"spine"`
+}
+
+type opfMetadata struct {
+	DC         string    `xml:"xmlns:dc,attr"`
+	Identifier opfIDElem `xml:"dc:identifier"`
+	Title      string    `xml:"dc:title"`
+	Language   string    `xml:"dc:language"`
+	Creator    string    `xml:"dc:creator,omitempty"`
+	Meta       []opfMeta `xml:"meta"`
+}
+
+type opfIDElem struct {
+	ID    string `xml:"id,attr"`
+	Value string `xml:",chardata"`
+}
+
+type opfMeta struct {
+	Property string `xml:"property,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type opfItem struct {
+	ID         string `xml:"id,attr"`
+	Href       string `xml:"href,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Properties string `xml:"properties,attr,omitempty"`
+}
+
+type opfSpine struct {
+	Toc      string       `xml:"toc,attr"`
+	ItemRefs []opfItemRef `xml:"itemref"`
+}
+
+type opfItemRef struct {
+	IDRef string `xml:"idref,attr"`
+}
+
+func (ew *epubWriter) packageDocument() ([]byte, error) {
+	pkg := opfPackage{
+		Version:          "3.0",
+		UniqueIdentifier: "pub-id",
+		Lang:             ew.language,
+		Metadata: opfMetadata{
+			DC:         constants.NameSpaceDublinCore,
+			Identifier: opfIDElem{ID: "pub-id", Value: ew.identifier},
+			Title:      ew.title,
+			Language:   ew.language,
+			Creator:    ew.creator,
+			Meta:       []opfMeta{{Property: "dcterms:modified", Value: ew.modified}},
+		},
+		Manifest: []opfItem{
+			{ID: "nav", Href: "nav.xhtml", MediaType: epubXHTMLType, Properties: "nav"},
+			{ID: "ncx", Href: "toc.ncx", MediaType: "application/x-dtbncx+xml"},
+			{ID: "css", Href: "style.css", MediaType: "text/css"},
+		},
+		Spine: opfSpine{Toc: "ncx"},
+	}
+
+	for _, chapter := range ew.chapters {
+		pkg.Manifest = append(pkg.Manifest, opfItem{ID: chapter.id, Href: chapter.fileName, MediaType: epubXHTMLType})
+		pkg.Spine.ItemRefs = append(pkg.Spine.ItemRefs, opfItemRef{IDRef: chapter.id})
+	}
+
+	for _, img := range ew.imageOrder {
+		pkg.Manifest = append(pkg.Manifest, opfItem{ID: img.id, Href: img.href, MediaType: img.mediaType})
+	}
+
+	return marshal(pkg)
+}
+
+type ncxDocument struct {
+	XMLName  xml.Name      `xml:"http://www.daisy.org/z3986/2005/ncx/ ncx"`
+	Version  string        `xml:"version,attr"`
+	Meta     []ncxMeta     `xml:"head>meta"`
+	DocTitle string        `xml:"docTitle>text"`
+	NavMap   []ncxNavPoint `xml:"navMap>navPoint"`
+}
+
+type ncxMeta struct {
+	Name    string `xml:"name,attr"`
+	Content string `xml:"content,attr"`
+}
+
+type ncxNavPoint struct {
+	ID        string        `xml:"id,attr"`
+	PlayOrder int           `xml:"playOrder,attr"`
+	Label     string        `xml:"navLabel>text"`
+	Content   ncxContent    `xml:"content"`
+	Children  []ncxNavPoint `xml:"navPoint"`
+}
+
+type ncxContent struct {
+	Src string `xml:"src,attr"`
+}
+
+func (ew *epubWriter) ncxDocument() ([]byte, error) {
+	playOrder := 0
+	maxDepth := 0
+
+	var convert func(points []*epubNavPoint, depth int) []ncxNavPoint
+	convert = func(points []*epubNavPoint, depth int) []ncxNavPoint {
+		if len(points) > 0 && depth > maxDepth {
+			maxDepth = depth
+		}
+
+		navPoints := make([]ncxNavPoint, 0, len(points))
+		for _, point := range points {
+			playOrder++
+			np := ncxNavPoint{
+				ID:        fmt.Sprintf("navPoint%d", playOrder),
+				PlayOrder: playOrder,
+				Label:     point.title,
+				Content:   ncxContent{Src: point.href},
+			}
+			np.Children = convert(point.children, depth+1)
+			navPoints = append(navPoints, np)
+		}
+		return navPoints
+	}
+
+	doc := ncxDocument{
+		Version:  "2005-1",
+		DocTitle: ew.title,
+		NavMap:   convert(ew.navTree(), 1),
+	}
+	doc.Meta = []ncxMeta{
+		{Name: "dtb:uid", Content: ew.identifier},
+		{Name: "dtb:depth", Content: fmt.Sprintf("%d", maxDepth)},
+		{Name: "dtb:totalPageCount", Content: "0"},
+		{Name: "dtb:maxPageNumber", Content: "0"},
+	}
+
+	return marshal(doc)
+}
+
+// cellGridSpan returns the number of grid columns spanned by a cell.
+func cellGridSpan(cell *ctypes.Cell) int {
+	if cell.Property != nil && cell.Property.GridSpan != nil && cell.Property.GridSpan.Val > 1 {
+		return cell.Property.GridSpan.Val
+	}
+	return 1
+}
+
+// cellMergeContinues reports whether a cell continues a vertically merged region started above it.
+func cellMergeContinues(cell *ctypes.Cell) bool {
+	if cell.Property == nil || cell.Property.VMerge == nil {
+		return false
+	}
+	return cell.Property.VMerge.Val == nil || *cell.Property.VMerge.Val == stypes.MergeCellContinue
+}
+
+// cellAtGridColumn returns the cell of a row that starts at the given grid column.
+func cellAtGridColumn(row *ctypes.Row, gridCol int) *ctypes.Cell {
+	col := 0
+	for _, cc := range row.Contents {
+		if cc.Cell == nil {
+			continue
+		}
+		if col == gridCol {
+			return cc.Cell
+		}
+		col += cellGridSpan(cc.Cell)
+		if col > gridCol {
+			return nil
+		}
+	}
+	return nil
+}
+
+// verticalSpan returns the number of rows covered by the cell starting at gridCol in rows[rowIdx].
+func verticalSpan(rows []*ctypes.Row, rowIdx int, gridCol int) int {
+	span := 1
+	for i := rowIdx + 1; i < len(rows); i++ {
+		cell := cellAtGridColumn(rows[i], gridCol)
+		if cell == nil || !cellMergeContinues(cell) {
+			break
+		}
+		span++
+	}
+	return span
+}
+
+func epubClamp(v uint, lo uint, hi uint) uint {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// nameBasedUUID derives a stable URN UUID (version 5 layout) from the given name.
+func nameBasedUUID(name string) string {
+	sum := sha1.Sum([]byte(name))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
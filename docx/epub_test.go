@@ -0,0 +1,155 @@
+package docx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readEPUB(t *testing.T, content []byte) (*zip.Reader, map[string]string) {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[f.Name] = string(data)
+	}
+	return zr, files
+}
+
+func TestWriteEPUB(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+
+	rd.AddParagraph("Preface text")
+
+	_, err = rd.AddHeading("Installation", 1)
+	require.NoError(t, err)
+	p := rd.AddParagraph("Run the ")
+	p.AddText("installer").Bold(true)
+	p.AddText(" & reboot.")
+	p.AddLink("site", "https://example.com/?a=1&b=2")
+
+	_, err = rd.AddHeading("Requirements", 2)
+	require.NoError(t, err)
+	list := rd.NewListInstance(2)
+	rd.AddParagraph("Disk").Numbering(list, 0)
+	rd.AddParagraph("SSD").Numbering(list, 1)
+	rd.AddParagraph("Memory").Numbering(list, 0)
+
+	tbl := rd.AddTable()
+	row := tbl.AddRow()
+	row.AddCell().AddParagraph("Qty")
+	row.AddCell().AddParagraph("Item")
+
+	_, err = rd.AddHeading("Usage", 1)
+	require.NoError(t, err)
+	_, err = rd.AddPicture("../godocx.png", units.Inch(1), units.Inch(1))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, rd.WriteEPUB(&buf, &docx.EPUBOptions{Title: "Manual", Language: "de"}))
+
+	zr, files := readEPUB(t, buf.Bytes())
+
+	require.NotEmpty(t, zr.File)
+	assert.Equal(t, "mimetype", zr.File[0].Name)
+	assert.Equal(t, zip.Store, zr.File[0].Method)
+	assert.Equal(t, "application/epub+zip", files["mimetype"])
+	assert.Contains(t, files["META-INF/container.xml"], `full-path="OEBPS/content.opf"`)
+
+	opf := files["OEBPS/content.opf"]
+	assert.Contains(t, opf, "<dc:title>Manual</dc:title>")
+	assert.Contains(t, opf, "<dc:language>de</dc:language>")
+	assert.Contains(t, opf, "<dc:creator>gomutex</dc:creator>")
+	assert.Contains(t, opf, `properties="nav"`)
+	assert.Contains(t, opf, `media-type="image/png"`)
+	assert.Contains(t, opf, `<itemref idref="chapter3"></itemref>`)
+	assert.NotContains(t, opf, `chapter4`)
+
+	// Preface, Installation (with its level 2 subsection) and Usage
+	assert.Contains(t, files["OEBPS/chapter1.xhtml"], "<p>Preface text</p>")
+
+	ch2 := files["OEBPS/chapter2.xhtml"]
+	assert.Contains(t, ch2, `<h1 id="h1">Installation</h1>`)
+	assert.Contains(t, ch2, `<h2 id="h2">Requirements</h2>`)
+	assert.Contains(t, ch2, "<strong>installer</strong> &amp; reboot.")
+	assert.Contains(t, ch2, `<a href="https://example.com/?a=1&amp;b=2">site</a>`)
+	assert.Contains(t, ch2, "<ul>\n<li>Disk<ul>\n<li>SSD</li>\n</ul>\n</li>\n<li>Memory</li>\n</ul>")
+	assert.Contains(t, ch2, "<td><p>Qty</p>\n</td>")
+
+	ch3 := files["OEBPS/chapter3.xhtml"]
+	assert.Contains(t, ch3, `<img src="images/image1.png"`)
+	assert.NotEmpty(t, files["OEBPS/images/image1.png"])
+
+	nav := files["OEBPS/nav.xhtml"]
+	assert.Contains(t, nav, `<a href="chapter2.xhtml#h1">Installation</a>`)
+	assert.Contains(t, nav, `<a href="chapter2.xhtml#h2">Requirements</a>`)
+	assert.Contains(t, nav, `<a href="chapter3.xhtml#h3">Usage</a>`)
+
+	ncx := files["OEBPS/toc.ncx"]
+	assert.Contains(t, ncx, `<content src="chapter2.xhtml#h2"></content>`)
+	assert.Contains(t, ncx, `<meta name="dtb:depth" content="2"></meta>`)
+}
+
+func TestWriteEPUB_SplitLevel(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+
+	for _, title := range []string{"One", "Two"} {
+		_, err = rd.AddHeading(title, 1)
+		require.NoError(t, err)
+		_, err = rd.AddHeading(title+" detail", 2)
+		require.NoError(t, err)
+		rd.AddParagraph("text")
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, rd.WriteEPUB(&buf, &docx.EPUBOptions{SplitLevel: 2}))
+
+	_, files := readEPUB(t, buf.Bytes())
+
+	chapters := 0
+	for name := range files {
+		if strings.HasPrefix(name, "OEBPS/chapter") {
+			chapters++
+		}
+	}
+	assert.Equal(t, 4, chapters)
+	assert.Contains(t, files["OEBPS/content.opf"], `<dc:identifier id="pub-id">urn:uuid:`)
+}
+
+func TestWriteEPUB_ContentControl(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+
+	_, err = rd.AddHeading("Terms", 1)
+	require.NoError(t, err)
+	inner := &ctypes.Paragraph{}
+	inner.AddText("Payment within 30 days")
+	ctl := docx.NewContentControl(rd)
+	ctl.GetCT().Contents = []ctypes.TCBlockContent{{Paragraph: inner}}
+	require.NoError(t, rd.AddBlocks(docx.DocumentChild{Control: ctl}))
+
+	var buf bytes.Buffer
+	require.NoError(t, rd.WriteEPUB(&buf, nil))
+
+	_, files := readEPUB(t, buf.Bytes())
+	assert.Contains(t, files["OEBPS/chapter1.xhtml"], "<p>Payment within 30 days</p>")
+}
@@ -0,0 +1,107 @@
+package docx
+
+import (
+	"strings"
+
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// beginsField reports whether a run holds the begin character of a complex field.
+func beginsField(r *ctypes.Run) bool {
+	for _, rc := range r.Children {
+		if rc.FldChar != nil && rc.FldChar.FldCharType == stypes.FldCharTypeBegin {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldAt returns the extent of the complex field that begins at children[i].
+//
+// end is the index of the child holding the field's end character, or the last child if the
+// field is not closed within children. instr is the field instruction and result is the text
+// of the current field result.
+func fieldAt(children []ctypes.ParagraphChild, i int) (end int, instr string, result string) {
+	var (
+		depth     int
+		separated bool
+		ib, rb    strings.Builder
+	)
+
+	for end = i; end < len(children); end++ {
+		child := children[end]
+		if child.Link != nil {
+			if separated {
+				rb.WriteString(paragraphText(&ctypes.Paragraph{Children: []ctypes.ParagraphChild{child}}))
+			}
+			continue
+		}
+
+		if child.Run == nil {
+			continue
+		}
+
+		for _, rc := range child.Run.Children {
+			switch {
+			case rc.FldChar != nil:
+				switch rc.FldChar.FldCharType {
+				case stypes.FldCharTypeBegin:
+					depth++
+				case stypes.FldCharTypeSeparate:
+					if depth == 1 {
+						separated = true
+					}
+				case stypes.FldCharTypeEnd:
+					depth--
+					if depth == 0 {
+						return end, ib.String(), rb.String()
+					}
+				}
+			case rc.InstrText != nil:
+				if depth == 1 && !separated {
+					ib.WriteString(rc.InstrText.Text)
+				}
+			case separated:
+				rb.WriteString(runChildText(rc))
+			}
+		}
+	}
+
+	return len(children) - 1, ib.String(), rb.String()
+}
+
+// fieldRuns returns the runs of a complex field with the given instruction and current result.
+// All runs share the given run properties.
+func fieldRuns(instr, result string, props *ctypes.RunProperty) []ctypes.ParagraphChild {
+	fldRun := func(children ...ctypes.RunChild) ctypes.ParagraphChild {
+		return ctypes.ParagraphChild{Run: &ctypes.Run{Property: copyRunProp(props), Children: children}}
+	}
+
+	runs := []ctypes.ParagraphChild{
+		fldRun(ctypes.RunChild{FldChar: ctypes.NewFldChar(stypes.FldCharTypeBegin)}),
+		fldRun(ctypes.RunChild{InstrText: &ctypes.Text{Text: instr, Space: internal.ToPtr(ctypes.TextSpacePreserve)}}),
+		fldRun(ctypes.RunChild{FldChar: ctypes.NewFldChar(stypes.FldCharTypeSeparate)}),
+	}
+
+	if result != "" {
+		runs = append(runs, fldRun(textRunChildren(result)...))
+	}
+
+	return append(runs, fldRun(ctypes.RunChild{FldChar: ctypes.NewFldChar(stypes.FldCharTypeEnd)}))
+}
+
+// AddField appends a complex field to the paragraph.
+//
+// Parameters:
+//   - instr: The field instruction, for example `PAGE` or `TOC \o "1-3" \h`.
+//   - result: The text shown until the field is updated by the application.
+//
+// Example:
+//
+//	p := document.AddParagraph("Page ")
+//	p.AddField("PAGE", "1")
+func (p *Paragraph) AddField(instr, result string) {
+	p.ct.Children = append(p.ct.Children, fieldRuns(" "+strings.TrimSpace(instr)+" ", result, nil)...)
+}
@@ -0,0 +1,257 @@
+package docx
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// dateLayouts are the layouts tried when a text value is formatted with a date picture.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2 January 2006",
+	"January 2, 2006",
+}
+
+// formatMergeValue converts a merge value to text, applying a numeric or date picture when the
+// value is a number or a date.
+func formatMergeValue(value any, numPic, datePic string) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if datePic == "" {
+			return v.Format("2006-01-02")
+		}
+		return formatDate(v, datePic)
+	case string:
+		if datePic != "" {
+			for _, layout := range dateLayouts {
+				if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+					return formatDate(t, datePic)
+				}
+			}
+		}
+		if numPic != "" {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return formatNumber(f, numPic)
+			}
+		}
+		return v
+	}
+
+	if numPic != "" {
+		if f, ok := toFloat(value); ok {
+			return formatNumber(f, numPic)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// applyTextCase applies the \* format switch of a field.
+func applyTextCase(text, format string) string {
+	switch strings.ToLower(format) {
+	case "upper":
+		return strings.ToUpper(text)
+	case "lower":
+		return strings.ToLower(text)
+	case "firstcap":
+		if text == "" {
+			return ""
+		}
+		r, size := utf8.DecodeRuneInString(text)
+		return string(unicode.ToUpper(r)) + text[size:]
+	case "caps":
+		// Only the first letter of each word changes, so that "McDonald" stays as it is.
+		runes := []rune(text)
+		for i, r := range runes {
+			if i == 0 || unicode.IsSpace(runes[i-1]) {
+				runes[i] = unicode.ToUpper(r)
+			}
+		}
+		return string(runes)
+	}
+	return text
+}
+
+// formatNumber formats a number with a Word numeric picture such as "#,##0.00" or "$0.0".
+// A picture may have a second section, separated by a semicolon, for negative numbers.
+// Text around the digit placeholders is kept, with single quotes removed.
+func formatNumber(v float64, picture string) string {
+	sections := strings.SplitN(picture, ";", 2)
+	pic, negative := sections[0], v < 0
+	if negative && len(sections) == 2 {
+		pic = sections[1]
+	}
+
+	start := strings.IndexAny(pic, "#0")
+	if start < 0 {
+		return strings.ReplaceAll(pic, "'", "")
+	}
+	end := strings.LastIndexAny(pic, "#0") + 1
+	for start > 0 && strings.ContainsAny(pic[start-1:start], ",.") {
+		start--
+	}
+	prefix, digits, suffix := pic[:start], pic[start:end], pic[end:]
+
+	intPic, fracPic := digits, ""
+	if dot := strings.IndexByte(digits, '.'); dot >= 0 {
+		intPic, fracPic = digits[:dot], digits[dot+1:]
+	}
+	minFrac := strings.Count(fracPic, "0")
+	maxFrac := minFrac + strings.Count(fracPic, "#")
+	minInt := strings.Count(intPic, "0")
+
+	scale := math.Pow(10, float64(maxFrac))
+	s := strconv.FormatFloat(math.Round(math.Abs(v)*scale)/scale, 'f', maxFrac, 64)
+	if negative && strings.Trim(s, "0.") == "" {
+		// A negative number rounded to zero is shown as zero.
+		return formatNumber(0, picture)
+	}
+	intPart, fracPart := s, ""
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		intPart, fracPart = s[:dot], s[dot+1:]
+	}
+	for len(fracPart) > minFrac && strings.HasSuffix(fracPart, "0") {
+		fracPart = fracPart[:len(fracPart)-1]
+	}
+
+	intPart = strings.TrimLeft(intPart, "0")
+	for len(intPart) < minInt {
+		intPart = "0" + intPart
+	}
+	if strings.Contains(intPic, ",") {
+		intPart = groupThousands(intPart)
+	}
+
+	var sb strings.Builder
+	if negative && len(sections) == 1 {
+		sb.WriteByte('-')
+	}
+	sb.WriteString(strings.ReplaceAll(prefix, "'", ""))
+	sb.WriteString(intPart)
+	if fracPart != "" {
+		sb.WriteByte('.')
+		sb.WriteString(fracPart)
+	}
+	sb.WriteString(strings.ReplaceAll(suffix, "'", ""))
+	return sb.String()
+}
+
+// groupThousands inserts a comma between every group of three digits.
+func groupThousands(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var sb strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		sb.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if sb.Len() > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(digits[i : i+3])
+	}
+	return sb.String()
+}
+
+// datePictureTokens are the elements of a Word date picture and the Go layouts they map to,
+// longest first so that "MMMM" is not read as two "MM". Hours without a leading zero have no
+// Go layout for the 24-hour clock and are formatted by hour instead.
+var datePictureTokens = []struct {
+	token  string
+	layout string
+	hour   func(t time.Time) int
+}{
+	{token: "yyyy", layout: "2006"}, {token: "yy", layout: "06"},
+	{token: "MMMM", layout: "January"}, {token: "MMM", layout: "Jan"}, {token: "MM", layout: "01"}, {token: "M", layout: "1"},
+	{token: "dddd", layout: "Monday"}, {token: "ddd", layout: "Mon"}, {token: "dd", layout: "02"}, {token: "d", layout: "2"},
+	{token: "HH", layout: "15"}, {token: "H", hour: time.Time.Hour}, {token: "hh", layout: "03"}, {token: "h", hour: hour12},
+	{token: "mm", layout: "04"}, {token: "m", layout: "4"}, {token: "ss", layout: "05"}, {token: "s", layout: "5"},
+	{token: "AM/PM", layout: "PM"}, {token: "am/pm", layout: "pm"},
+}
+
+// hour12 returns the hour of a time on the 12-hour clock.
+func hour12(t time.Time) int {
+	if h := t.Hour() % 12; h != 0 {
+		return h
+	}
+	return 12
+}
+
+// formatDate formats a time with a Word date picture such as "d MMMM yyyy" or "dd/MM/yyyy HH:mm".
+// Text in single quotes is copied as it is.
+func formatDate(t time.Time, picture string) string {
+	var sb strings.Builder
+	for i := 0; i < len(picture); {
+		if picture[i] == '\'' {
+			end := strings.IndexByte(picture[i+1:], '\'')
+			if end < 0 {
+				sb.WriteString(picture[i+1:])
+				break
+			}
+			sb.WriteString(picture[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+
+		matched := false
+		for _, tok := range datePictureTokens {
+			if strings.HasPrefix(picture[i:], tok.token) {
+				if tok.hour != nil {
+					sb.WriteString(strconv.Itoa(tok.hour(t)))
+				} else {
+					sb.WriteString(t.Format(tok.layout))
+				}
+				i += len(tok.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			sb.WriteByte(picture[i])
+			i++
+		}
+	}
+	return sb.String()
+}
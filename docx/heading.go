@@ -3,6 +3,8 @@ package docx
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/gomutex/godocx/wml/ctypes"
 )
@@ -35,3 +37,38 @@ func (rd *RootDoc) AddHeading(text string, level uint) (*Paragraph, error) {
 	p.AddText(text)
 	return p, nil
 }
+
+// HeadingLevel returns the heading level of the paragraph and whether it is a heading. The
+// Title style is level 0 and the Heading1 to Heading9 styles are levels 1 to 9; other
+// paragraphs are headings when they have an outline level.
+func (p *Paragraph) HeadingLevel() (uint, bool) {
+	return headingLevel(p.ct)
+}
+
+// headingLevel reports the heading level of a paragraph.
+// The Title style is level 0 and Heading1..Heading9 are levels 1..9. Paragraphs
+// without a heading style fall back to their outline level, if any.
+func headingLevel(p *ctypes.Paragraph) (uint, bool) {
+	if p == nil || p.Property == nil {
+		return 0, false
+	}
+
+	if p.Property.Style != nil {
+		styleID := p.Property.Style.Val
+		if styleID == "Title" {
+			return 0, true
+		}
+
+		if strings.HasPrefix(styleID, "Heading") {
+			if level, err := strconv.Atoi(strings.TrimPrefix(styleID, "Heading")); err == nil && level >= 1 && level <= 9 {
+				return uint(level), true
+			}
+		}
+	}
+
+	if p.Property.OutlineLvl != nil && p.Property.OutlineLvl.Val >= 0 && p.Property.OutlineLvl.Val < 9 {
+		return uint(p.Property.OutlineLvl.Val + 1), true
+	}
+
+	return 0, false
+}
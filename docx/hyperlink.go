@@ -1,6 +1,8 @@
 package docx
 
 import (
+	"strings"
+
 	"github.com/gomutex/godocx/wml/ctypes"
 	"github.com/gomutex/godocx/wml/stypes"
 )
@@ -187,3 +189,39 @@ func (r *Hyperlink) VerticalAlign(value stypes.VerticalAlignRun) *Hyperlink {
 	r.getProp().VertAlign = ctypes.NewGenSingleStrVal(value)
 	return r
 }
+
+// URL returns the target of an external hyperlink, looked up in the document relationships.
+// It returns "" for links to a bookmark of the document; see Anchor.
+func (r *Hyperlink) URL() string {
+	if r.ct.ID == "" || r.root == nil || r.root.Document == nil {
+		return ""
+	}
+	if rel := r.root.Document.relationByID(r.ct.ID); rel != nil {
+		return rel.Target
+	}
+	return ""
+}
+
+// Anchor returns the bookmark an internal hyperlink points to, or "".
+func (r *Hyperlink) Anchor() string {
+	return r.ct.Anchor
+}
+
+// Text returns the text of the hyperlink.
+func (r *Hyperlink) Text() string {
+	var sb strings.Builder
+	sb.WriteString(runText(r.ct.Run))
+	writeChildrenText(&sb, r.ct.Children)
+	return sb.String()
+}
+
+// Runs returns the runs of the hyperlink.
+func (r *Hyperlink) Runs() []*Run {
+	var runs []*Run
+	for _, child := range hyperlinkChildren(r.ct) {
+		if child.Run != nil {
+			runs = append(runs, newRun(r.root, child.Run))
+		}
+	}
+	return runs
+}
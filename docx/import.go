@@ -0,0 +1,149 @@
+package docx
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/gomutex/godocx/dml"
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+)
+
+// importer copies blocks of the body of one document into another. Relationship IDs used by
+// the copied content are remapped to relationships of the target document, which are created
+// on first use: hyperlinks are linked again and images are stored again.
+type importer struct {
+	src, dst *RootDoc
+	ids      map[string]string // ids maps relationship IDs of src to those of dst.
+}
+
+func newImporter(src, dst *RootDoc) *importer {
+	return &importer{src: src, dst: dst, ids: make(map[string]string)}
+}
+
+// block returns a copy of a block of the source document, owned by the target document.
+func (im *importer) block(child DocumentChild) (DocumentChild, error) {
+	out, paras := copyBlock(im.dst, child)
+	for _, p := range paras {
+		if err := im.paragraph(p); err != nil {
+			return DocumentChild{}, err
+		}
+	}
+	return out, nil
+}
+
+// copyBlock returns a copy of a block owned by root, along with the paragraphs it holds. The
+// relationship IDs used by the copy are left as they are.
+func copyBlock(root *RootDoc, child DocumentChild) (DocumentChild, []*ctypes.Paragraph) {
+	var (
+		out   DocumentChild
+		paras []*ctypes.Paragraph
+	)
+
+	switch {
+	case child.Para != nil:
+		out.Para = newParagraph(root)
+		out.Para.ct = child.Para.ct.Clone()
+		paras = append(paras, out.Para.ct)
+	case child.Table != nil:
+		out.Table = NewTable(root)
+		out.Table.ct = child.Table.ct.Clone()
+		paras = tableParagraphs(nil, out.Table.ct)
+	case child.Control != nil:
+		out.Control = &ContentControl{root: root, ct: internal.DeepCopy(child.Control.ct)}
+		paras = contentParagraphs(nil, out.Control.ct.Contents)
+	}
+	return out, paras
+}
+
+// paragraph remaps the relationship IDs of the hyperlinks and pictures of a copied paragraph.
+func (im *importer) paragraph(p *ctypes.Paragraph) error {
+	links := make(map[*ctypes.Hyperlink]bool)
+	for _, tr := range paragraphRuns(p) {
+		if tr.link != nil && !links[tr.link] {
+			links[tr.link] = true
+			if tr.link.ID != "" {
+				id, err := im.relation(tr.link.ID)
+				if err != nil {
+					return err
+				}
+				tr.link.ID = id
+			}
+		}
+
+		for _, child := range tr.run.Children {
+			if err := im.drawing(child.Drawing); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (im *importer) drawing(d *dml.Drawing) error {
+	if d == nil {
+		return nil
+	}
+
+	relink := func(graphic dml.Graphic) error {
+		if graphic.Data == nil || graphic.Data.Pic == nil || graphic.Data.Pic.BlipFill.Blip == nil {
+			return nil
+		}
+		blip := graphic.Data.Pic.BlipFill.Blip
+		id, err := im.relation(blip.EmbedID)
+		if err != nil {
+			return err
+		}
+		blip.EmbedID = id
+		return nil
+	}
+
+	for _, inline := range d.Inline {
+		if err := relink(inline.Graphic); err != nil {
+			return err
+		}
+	}
+	for _, anchor := range d.Anchor {
+		if anchor == nil {
+			continue
+		}
+		if err := relink(anchor.Graphic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relation returns the ID of the relationship of the target document standing for the
+// relationship rID of the source document.
+func (im *importer) relation(rID string) (string, error) {
+	if id, ok := im.ids[rID]; ok {
+		return id, nil
+	}
+
+	rel := im.src.Document.relationByID(rID)
+	if rel == nil {
+		return "", fmt.Errorf("relationship %s is missing from the source document", rID)
+	}
+
+	var id string
+	switch {
+	case rel.TargetMode == "External" && rel.Type == constants.SourceRelationshipHyperLink:
+		id = im.dst.Document.addLinkRelation(rel.Target)
+	case rel.Type == constants.SourceRelationshipImage:
+		_, data, ok := im.src.imagePart(rID)
+		if !ok {
+			return "", fmt.Errorf("image %s of relationship %s is missing from the source document", rel.Target, rID)
+		}
+		var err error
+		if id, _, err = im.dst.addImagePart(data, path.Ext(rel.Target)); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("relationship %s of type %s cannot be copied", rID, rel.Type)
+	}
+
+	im.ids[rID] = id
+	return id, nil
+}
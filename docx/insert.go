@@ -0,0 +1,181 @@
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Marker tells RootDoc.InsertAt where to insert content. Exactly one field must be set.
+type Marker struct {
+	// Text finds the paragraph whose text is Text, leading and trailing spaces aside, such as
+	// a "{{pricing table}}" placeholder.
+	Text string
+
+	// Bookmark finds the paragraph holding the start of the bookmark with this name.
+	Bookmark string
+
+	// Tag finds the block-level content control with this tag.
+	Tag string
+}
+
+func (m Marker) String() string {
+	switch {
+	case m.Text != "":
+		return fmt.Sprintf("text %q", m.Text)
+	case m.Bookmark != "":
+		return fmt.Sprintf("bookmark %q", m.Bookmark)
+	default:
+		return fmt.Sprintf("content control tag %q", m.Tag)
+	}
+}
+
+// InsertOptions configures RootDoc.InsertAt.
+type InsertOptions struct {
+	// KeepMarker keeps the marker paragraph or content control and inserts the content
+	// after it. By default, the marker is replaced by the content.
+	KeepMarker bool
+}
+
+// errMarkerNotFound is returned by InsertAt when the marker is not in the document.
+var errMarkerNotFound = errors.New("marker not found")
+
+// InsertAt inserts content at a marker of a skeleton document, in place of the marker or
+// after it. Markers are looked for in the body, including table cells and content controls,
+// and then in headers, footers, footnotes, endnotes and comments; the first match is used.
+//
+// Content is made of paragraphs, tables and content controls, such as those created with
+// NewParagraph and NewTable, which may hold pictures. Content taken from another document,
+// such as the Body.Children of a loaded document, is copied along with the images and
+// hyperlinks it uses; its styles and numbering definitions are not copied. Content of this
+// document is moved and must not be part of the document already.
+func (rd *RootDoc) InsertAt(m Marker, content []DocumentChild, opts *InsertOptions) error {
+	if opts == nil {
+		opts = &InsertOptions{}
+	}
+
+	set := 0
+	for _, field := range []string{m.Text, m.Bookmark, m.Tag} {
+		if field != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("insert: exactly one of the marker fields must be set")
+	}
+
+	ref, err := rd.findMarker(m)
+	if err != nil {
+		return err
+	}
+	bl, index, ok := rd.findRef(ref)
+	if !ok {
+		return fmt.Errorf("insert: %s: %w", m, errMarkerNotFound)
+	}
+
+	importers := make(map[*RootDoc]*importer)
+	blocks := make([]DocumentChild, 0, len(content))
+	for _, child := range content {
+		root := childRoot(child)
+		if root != nil && root != rd {
+			if importers[root] == nil {
+				importers[root] = newImporter(root, rd)
+			}
+			if child, err = importers[root].block(child); err != nil {
+				return fmt.Errorf("insert: %w", err)
+			}
+		}
+		blocks = append(blocks, child)
+	}
+
+	for i, child := range blocks {
+		bl.insert(index+1+i, child)
+	}
+	if !opts.KeepMarker {
+		if err := bl.remove(index); err != nil {
+			// Leave the document as it was.
+			for range blocks {
+				_ = bl.remove(index + 1)
+			}
+			return fmt.Errorf("insert: %w", err)
+		}
+	}
+	return nil
+}
+
+// childRoot returns the document a block belongs to.
+func childRoot(child DocumentChild) *RootDoc {
+	switch {
+	case child.Para != nil:
+		return child.Para.root
+	case child.Table != nil:
+		return child.Table.root
+	case child.Control != nil:
+		return child.Control.root
+	}
+	return nil
+}
+
+// findMarker returns the block marked by m.
+func (rd *RootDoc) findMarker(m Marker) (blockRef, error) {
+	var ref blockRef
+	match := func(child DocumentChild) bool {
+		switch {
+		case child.Para != nil && m.Text != "":
+			if strings.TrimSpace(child.Para.Text()) == m.Text {
+				ref.para = child.Para.ct
+			}
+		case child.Para != nil && m.Bookmark != "":
+			for _, pc := range child.Para.ct.Children {
+				if pc.BmkStart != nil && pc.BmkStart.Name == m.Bookmark {
+					ref.para = child.Para.ct
+				}
+			}
+		case child.Control != nil && m.Tag != "":
+			if child.Control.Tag() == m.Tag {
+				ref.sdt = child.Control.ct
+			}
+		}
+		return ref != blockRef{}
+	}
+
+	if eachBlock(rd.Document.Body.Children, match) {
+		return ref, nil
+	}
+
+	stories, err := rd.Stories()
+	if err != nil {
+		return ref, err
+	}
+	for _, s := range stories {
+		if eachBlock(s.Children, match) {
+			return ref, nil
+		}
+	}
+	return ref, fmt.Errorf("insert: %s: %w", m, errMarkerNotFound)
+}
+
+// eachBlock calls fn for the blocks in document order, descending into tables and content
+// controls, until fn returns true. It reports whether fn did.
+func eachBlock(children []DocumentChild, fn func(child DocumentChild) bool) bool {
+	for _, child := range children {
+		if fn(child) {
+			return true
+		}
+
+		if child.Table != nil {
+			for _, row := range child.Table.Rows() {
+				for _, cell := range row.Cells() {
+					if eachBlock(cell.Contents(), fn) {
+						return true
+					}
+				}
+			}
+		}
+
+		if child.Control != nil && eachBlock(child.Control.Contents(), fn) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package docx
+
+import (
+	"encoding/xml"
+	"os"
+	"testing"
+
+	"github.com/gomutex/godocx/common/units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSkeletonXML = `<w:body xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:p><w:r><w:t>Offer</w:t></w:r></w:p>` +
+	`<w:p><w:r><w:t xml:space="preserve"> {{pricing}} </w:t></w:r></w:p>` +
+	`<w:tbl><w:tr><w:tc><w:p><w:bookmarkStart w:id="0" w:name="Chart"/><w:bookmarkEnd w:id="0"/></w:p></w:tc></w:tr></w:tbl>` +
+	`<w:sdt><w:sdtPr><w:tag w:val="terms"/></w:sdtPr><w:sdtContent><w:p><w:r><w:t>Terms go here</w:t></w:r></w:p></w:sdtContent></w:sdt>` +
+	`</w:body>`
+
+func loadSkeleton(t *testing.T) *RootDoc {
+	rd := setupRootDoc(t)
+	body := NewBody(rd)
+	require.NoError(t, xml.Unmarshal([]byte(testSkeletonXML), body))
+	rd.Document.Body = body
+	return rd
+}
+
+func TestInsertAt(t *testing.T) {
+	rd := loadSkeleton(t)
+
+	tbl := NewTable(rd)
+	tbl.AddRow().AddCell().AddParagraph("Qty")
+	note := NewParagraph(rd)
+	note.AddText("Prices are net.")
+	require.NoError(t, rd.InsertAt(Marker{Text: "{{pricing}}"}, []DocumentChild{{Table: tbl}, {Para: note}}, nil))
+	assert.Equal(t, []string{"Offer", "<table>", "Prices are net.", "<table>", "<table>"}, bodyTexts(rd), "the control is listed as a table")
+
+	chart := NewParagraph(rd)
+	chart.AddText("chart")
+	require.NoError(t, rd.InsertAt(Marker{Bookmark: "Chart"}, []DocumentChild{{Para: chart}}, &InsertOptions{KeepMarker: true}))
+	cell := rd.Document.Body.Children[3].Table.Rows()[0].Cells()[0]
+	require.Len(t, cell.Paragraphs(), 2)
+	assert.Same(t, chart.GetCT(), cell.Paragraphs()[1].GetCT())
+
+	terms := NewParagraph(rd)
+	terms.AddText("Payment within 30 days.")
+	require.NoError(t, rd.InsertAt(Marker{Tag: "terms"}, []DocumentChild{{Para: terms}}, nil))
+	assert.Same(t, terms, rd.Document.Body.Children[4].Para, "the content control is replaced")
+
+	err := rd.InsertAt(Marker{Text: "{{pricing}}"}, nil, nil)
+	assert.ErrorIs(t, err, errMarkerNotFound)
+	assert.Error(t, rd.InsertAt(Marker{Text: "a", Tag: "b"}, nil, nil))
+}
+
+func TestInsertAt_LastCellParagraph(t *testing.T) {
+	rd := loadSkeleton(t)
+
+	err := rd.InsertAt(Marker{Bookmark: "Chart"}, []DocumentChild{{Table: NewTable(rd)}}, nil)
+	assert.Error(t, err, "a cell cannot end with a table")
+	cell := rd.Document.Body.Children[2].Table.Rows()[0].Cells()[0]
+	assert.Len(t, cell.Contents(), 1, "the cell is left as it was")
+}
+
+func TestInsertAt_OtherDocument(t *testing.T) {
+	rd := loadSkeleton(t)
+	rd.Document.relativePath = "word/document.xml"
+
+	png, err := os.ReadFile("../godocx.png")
+	require.NoError(t, err)
+	other := setupRootDoc(t)
+	other.Document.relativePath = "word/document.xml"
+	p := other.AddParagraph("See ")
+	p.AddLink("the site", "https://example.com")
+	_, err = p.AddPictureFromBytes(png, "png", units.Inch(1), units.Inch(1))
+	require.NoError(t, err)
+
+	require.NoError(t, rd.InsertAt(Marker{Text: "{{pricing}}"}, other.Document.Body.Children, nil))
+
+	copied := rd.Document.Body.Children[1].Para
+	require.NotSame(t, p, copied)
+	assert.Equal(t, "See the site", copied.Text())
+	assert.Equal(t, "https://example.com", copied.Hyperlinks()[0].URL())
+
+	images := drawingImages(copied.ct.Children[2].Run.Children[0].Drawing)
+	require.Len(t, images, 1)
+	_, data, ok := rd.imagePart(images[0].RelID)
+	require.True(t, ok)
+	assert.Equal(t, png, data)
+
+	assert.Equal(t, "See the site", p.Text(), "the source document is left unchanged")
+}
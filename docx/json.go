@@ -0,0 +1,1269 @@
+package docx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// JSONVersion is the version of the JSON schema produced by RootDoc.MarshalJSON.
+// It is increased whenever the schema changes in a way older readers cannot handle.
+const JSONVersion = 1
+
+// Block types of the JSON schema.
+const (
+	JSONParagraph = "paragraph"
+	JSONTable     = "table"
+)
+
+// Inline types of the JSON schema.
+const (
+	JSONText  = "text"
+	JSONTab   = "tab"
+	JSONBreak = "break"
+	JSONImage = "image"
+	JSONLink  = "link"
+	JSONField = "field"
+)
+
+// JSONDocument is the root of the JSON representation of a document.
+//
+// All lengths are in twentieths of a point (twips) unless stated otherwise.
+type JSONDocument struct {
+	Version  int           `json:"version"`
+	Styles   []JSONStyle   `json:"styles,omitempty"`
+	Sections []JSONSection `json:"sections"`
+}
+
+// JSONSection is a run of blocks sharing the same page setup.
+type JSONSection struct {
+	Page   *JSONPage   `json:"page,omitempty"`
+	Blocks []JSONBlock `json:"blocks"`
+}
+
+// JSONPage describes the page setup of a section.
+type JSONPage struct {
+	Width       uint64       `json:"width,omitempty"`
+	Height      uint64       `json:"height,omitempty"`
+	Orientation string       `json:"orientation,omitempty"` // portrait or landscape
+	Margins     *JSONMargins `json:"margins,omitempty"`
+	Break       string       `json:"break,omitempty"` // nextPage, continuous, evenPage, oddPage or nextColumn
+}
+
+// JSONMargins are the page margins of a section.
+type JSONMargins struct {
+	Top    *int `json:"top,omitempty"`
+	Right  *int `json:"right,omitempty"`
+	Bottom *int `json:"bottom,omitempty"`
+	Left   *int `json:"left,omitempty"`
+	Header *int `json:"header,omitempty"`
+	Footer *int `json:"footer,omitempty"`
+	Gutter *int `json:"gutter,omitempty"`
+}
+
+// JSONBlock is a paragraph or a table.
+type JSONBlock struct {
+	Type  string `json:"type"`
+	Style string `json:"style,omitempty"`
+
+	// Paragraph content
+	Format    *JSONParagraphFormat `json:"format,omitempty"`
+	Numbering *JSONNumbering       `json:"numbering,omitempty"`
+	Inlines   []JSONInline         `json:"inlines,omitempty"`
+
+	// Table content
+	Grid []uint64  `json:"grid,omitempty"`
+	Rows []JSONRow `json:"rows,omitempty"`
+}
+
+// JSONNumbering assigns a paragraph to a list.
+type JSONNumbering struct {
+	ID    int `json:"id"`
+	Level int `json:"level"`
+}
+
+// JSONParagraphFormat holds the direct formatting of a paragraph.
+type JSONParagraphFormat struct {
+	Align           string       `json:"align,omitempty"`
+	Spacing         *JSONSpacing `json:"spacing,omitempty"`
+	Indent          *JSONIndent  `json:"indent,omitempty"`
+	KeepNext        *bool        `json:"keepNext,omitempty"`
+	PageBreakBefore *bool        `json:"pageBreakBefore,omitempty"`
+}
+
+// JSONSpacing is the space above and below a paragraph.
+type JSONSpacing struct {
+	Before *uint64 `json:"before,omitempty"`
+	After  *uint64 `json:"after,omitempty"`
+}
+
+// JSONIndent is the indentation of a paragraph.
+type JSONIndent struct {
+	Left      *int    `json:"left,omitempty"`
+	Right     *int    `json:"right,omitempty"`
+	FirstLine *uint64 `json:"firstLine,omitempty"`
+	Hanging   *uint64 `json:"hanging,omitempty"`
+}
+
+// JSONInline is a piece of paragraph content.
+//
+// Text is the content of text inlines, the current result of field inlines, and a shorthand
+// for the content of link inlines that have no Inlines.
+type JSONInline struct {
+	Type    string         `json:"type"`
+	Text    string         `json:"text,omitempty"`
+	Format  *JSONRunFormat `json:"format,omitempty"`
+	Break   string         `json:"break,omitempty"` // line, page or column
+	URL     string         `json:"url,omitempty"`
+	Anchor  string         `json:"anchor,omitempty"`
+	Inlines []JSONInline   `json:"inlines,omitempty"`
+	Instr   string         `json:"instr,omitempty"`
+	Image   *JSONImageRef  `json:"image,omitempty"`
+}
+
+// JSONImageRef refers to a picture by its media path in the package.
+//
+// When decoding, Media is reused if it names a part in word/media/ of the package; otherwise
+// Data is used to add the picture.
+type JSONImageRef struct {
+	Media  string `json:"media"`
+	Data   []byte `json:"data,omitempty"`
+	Width  uint64 `json:"width"`  // Width in EMUs.
+	Height uint64 `json:"height"` // Height in EMUs.
+	Alt    string `json:"alt,omitempty"`
+}
+
+// JSONRunFormat holds the character formatting of an inline.
+type JSONRunFormat struct {
+	Style     string  `json:"style,omitempty"`
+	Bold      *bool   `json:"bold,omitempty"`
+	Italic    *bool   `json:"italic,omitempty"`
+	Strike    *bool   `json:"strike,omitempty"`
+	Caps      *bool   `json:"caps,omitempty"`
+	SmallCaps *bool   `json:"smallCaps,omitempty"`
+	Underline string  `json:"underline,omitempty"`
+	Color     string  `json:"color,omitempty"`
+	Highlight string  `json:"highlight,omitempty"`
+	Font      string  `json:"font,omitempty"`
+	Size      float64 `json:"size,omitempty"` // Size in points.
+	VertAlign string  `json:"vertAlign,omitempty"`
+}
+
+// JSONRow is a table row.
+type JSONRow struct {
+	Header bool       `json:"header,omitempty"`
+	Cells  []JSONCell `json:"cells"`
+}
+
+// JSONCell is a table cell.
+type JSONCell struct {
+	ColSpan int         `json:"colSpan,omitempty"`
+	VMerge  string      `json:"vMerge,omitempty"` // restart or continue
+	Width   *int        `json:"width,omitempty"`
+	Fill    string      `json:"fill,omitempty"`
+	VAlign  string      `json:"vAlign,omitempty"`
+	Blocks  []JSONBlock `json:"blocks"`
+}
+
+// JSONStyle is a style definition.
+//
+// When decoding, a style with the ID and type of an existing style updates it; the
+// formatting given is applied on top of the existing formatting.
+type JSONStyle struct {
+	ID        string               `json:"id"`
+	Type      string               `json:"type"`
+	Name      string               `json:"name,omitempty"`
+	BasedOn   string               `json:"basedOn,omitempty"`
+	Next      string               `json:"next,omitempty"`
+	Default   bool                 `json:"default,omitempty"`
+	Paragraph *JSONParagraphFormat `json:"paragraph,omitempty"`
+	Run       *JSONRunFormat       `json:"run,omitempty"`
+}
+
+// jsonPathError is a decoding error along with the location in the JSON document it applies to.
+type jsonPathError struct {
+	path string
+	err  error
+}
+
+func (e *jsonPathError) Error() string {
+	return e.path + ": " + e.err.Error()
+}
+
+func (e *jsonPathError) Unwrap() error {
+	return e.err
+}
+
+// atPath prefixes the location of an error with a path segment such as "blocks[2]".
+func atPath(segment string, err error) error {
+	if pe, ok := err.(*jsonPathError); ok {
+		return &jsonPathError{path: segment + "." + pe.path, err: pe.err}
+	}
+	return &jsonPathError{path: segment, err: err}
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the body, sections and
+// styles of the document as a JSONDocument.
+//
+// Content the schema has no representation for, such as bookmarks, comments or
+// revision marks, is not included. Content controls are left out and their content is
+// encoded in their place.
+func (rd *RootDoc) MarshalJSON() ([]byte, error) {
+	doc := JSONDocument{Version: JSONVersion}
+
+	if rd.DocStyles != nil {
+		for i := range rd.DocStyles.StyleList {
+			if st := jsonStyle(&rd.DocStyles.StyleList[i]); st != nil {
+				doc.Styles = append(doc.Styles, *st)
+			}
+		}
+	}
+
+	sec := JSONSection{Blocks: []JSONBlock{}}
+	// The schema has no content controls; their content is encoded in their place.
+	var encode func(children []DocumentChild)
+	encode = func(children []DocumentChild) {
+		for _, child := range children {
+			if child.Table != nil {
+				sec.Blocks = append(sec.Blocks, rd.jsonTable(child.Table.ct))
+			}
+
+			if child.Control != nil {
+				encode(child.Control.Contents())
+			}
+
+			if child.Para == nil {
+				continue
+			}
+
+			sec.Blocks = append(sec.Blocks, rd.jsonParagraph(child.Para.ct))
+			if pPr := child.Para.ct.Property; pPr != nil && pPr.SectPr != nil {
+				sec.Page = jsonPage(pPr.SectPr)
+				doc.Sections = append(doc.Sections, sec)
+				sec = JSONSection{Blocks: []JSONBlock{}}
+			}
+		}
+	}
+	encode(rd.Document.Body.Children)
+	sec.Page = jsonPage(rd.Document.Body.SectPr)
+	doc.Sections = append(doc.Sections, sec)
+
+	return json.Marshal(doc)
+}
+
+// jsonBlocks encodes the paragraphs and tables of a table cell, including those inside
+// content controls.
+func (rd *RootDoc) jsonBlocks(contents []ctypes.TCBlockContent) []JSONBlock {
+	blocks := []JSONBlock{}
+	for _, c := range contents {
+		if c.Paragraph != nil {
+			blocks = append(blocks, rd.jsonParagraph(c.Paragraph))
+		}
+		if c.Table != nil {
+			blocks = append(blocks, rd.jsonTable(c.Table))
+		}
+		if c.SDT != nil {
+			blocks = append(blocks, rd.jsonBlocks(c.SDT.Contents)...)
+		}
+	}
+	return blocks
+}
+
+func (rd *RootDoc) jsonParagraph(p *ctypes.Paragraph) JSONBlock {
+	block := JSONBlock{Type: JSONParagraph}
+
+	if pPr := p.Property; pPr != nil {
+		if pPr.Style != nil {
+			block.Style = pPr.Style.Val
+		}
+		if pPr.NumProp != nil && pPr.NumProp.NumID != nil {
+			block.Numbering = &JSONNumbering{ID: pPr.NumProp.NumID.Val}
+			if pPr.NumProp.ILvl != nil {
+				block.Numbering.Level = pPr.NumProp.ILvl.Val
+			}
+		}
+		block.Format = jsonParagraphFormat(pPr)
+	}
+
+	block.Inlines = rd.jsonInlines(p.Children)
+	return block
+}
+
+// jsonInlines encodes paragraph content, merging adjacent text with the same formatting.
+func (rd *RootDoc) jsonInlines(children []ctypes.ParagraphChild) []JSONInline {
+	var inlines []JSONInline
+	add := func(in JSONInline) {
+		if n := len(inlines); n > 0 && in.Type == JSONText && inlines[n-1].Type == JSONText &&
+			reflect.DeepEqual(inlines[n-1].Format, in.Format) {
+			inlines[n-1].Text += in.Text
+			return
+		}
+		inlines = append(inlines, in)
+	}
+
+	for i := 0; i < len(children); i++ {
+		child := children[i]
+		switch {
+		case child.Link != nil:
+			link := JSONInline{Type: JSONLink, Anchor: child.Link.Anchor}
+			if rel := rd.Document.relationByID(child.Link.ID); rel != nil && rel.TargetMode == "External" {
+				link.URL = rel.Target
+			}
+			link.Inlines = rd.jsonInlines(hyperlinkChildren(child.Link))
+			add(link)
+		case child.Run != nil && beginsField(child.Run):
+			end, instr, result := fieldAt(children, i)
+			add(JSONInline{
+				Type:   JSONField,
+				Instr:  strings.TrimSpace(instr),
+				Text:   result,
+				Format: jsonRunFormat(child.Run.Property),
+			})
+			i = end
+		case child.Run != nil:
+			for _, in := range rd.jsonRunInlines(child.Run) {
+				add(in)
+			}
+		}
+	}
+
+	return inlines
+}
+
+func (rd *RootDoc) jsonRunInlines(r *ctypes.Run) []JSONInline {
+	format := jsonRunFormat(r.Property)
+
+	var inlines []JSONInline
+	for _, rc := range r.Children {
+		switch {
+		case rc.Tab != nil:
+			inlines = append(inlines, JSONInline{Type: JSONTab, Format: format})
+		case rc.Break != nil:
+			brk := "line"
+			if bt := rc.Break.BreakType; bt != nil && *bt != stypes.BreakTypeTextWrapping {
+				brk = string(*bt)
+			}
+			inlines = append(inlines, JSONInline{Type: JSONBreak, Break: brk, Format: format})
+		case rc.Drawing != nil:
+			for _, img := range drawingImages(rc.Drawing) {
+				media, _, ok := rd.imagePart(img.RelID)
+				if !ok {
+					continue
+				}
+				inlines = append(inlines, JSONInline{Type: JSONImage, Image: &JSONImageRef{
+					Media:  media,
+					Width:  img.Width,
+					Height: img.Height,
+					Alt:    img.Description,
+				}})
+			}
+		default:
+			if text := runChildText(rc); text != "" {
+				inlines = append(inlines, JSONInline{Type: JSONText, Text: text, Format: format})
+			}
+		}
+	}
+
+	return inlines
+}
+
+func (rd *RootDoc) jsonTable(tbl *ctypes.Table) JSONBlock {
+	block := JSONBlock{Type: JSONTable}
+	if tbl.TableProp.Style != nil {
+		block.Style = tbl.TableProp.Style.Val
+	}
+
+	for _, col := range tbl.Grid.Col {
+		var w uint64
+		if col.Width != nil {
+			w = *col.Width
+		}
+		block.Grid = append(block.Grid, w)
+	}
+
+	for _, rc := range tbl.RowContents {
+		if rc.Row == nil {
+			continue
+		}
+
+		row := JSONRow{Cells: []JSONCell{}}
+		if rc.Row.Property != nil {
+			row.Header = isOn(rc.Row.Property.Header)
+		}
+
+		for _, cc := range rc.Row.Contents {
+			if cc.Cell == nil {
+				continue
+			}
+			row.Cells = append(row.Cells, rd.jsonCell(cc.Cell))
+		}
+		block.Rows = append(block.Rows, row)
+	}
+
+	return block
+}
+
+func (rd *RootDoc) jsonCell(cell *ctypes.Cell) JSONCell {
+	jc := JSONCell{Blocks: rd.jsonBlocks(cell.Contents)}
+
+	if span := cellGridSpan(cell); span > 1 {
+		jc.ColSpan = span
+	}
+
+	prop := cell.Property
+	if prop == nil {
+		return jc
+	}
+
+	if prop.VMerge != nil {
+		jc.VMerge = string(stypes.MergeCellRestart)
+		if cellMergeContinues(cell) {
+			jc.VMerge = string(stypes.MergeCellContinue)
+		}
+	}
+	if prop.Width != nil && prop.Width.Width != nil && (prop.Width.WidthType == nil || *prop.Width.WidthType == stypes.TableWidthDxa) {
+		w := *prop.Width.Width
+		jc.Width = &w
+	}
+	if prop.Shading != nil && prop.Shading.Fill != nil && *prop.Shading.Fill != "auto" && *prop.Shading.Fill != "FFFFFF" {
+		jc.Fill = *prop.Shading.Fill
+	}
+	if prop.VAlign != nil {
+		jc.VAlign = string(prop.VAlign.Val)
+	}
+
+	return jc
+}
+
+func jsonParagraphFormat(pPr *ctypes.ParagraphProp) *JSONParagraphFormat {
+	if pPr == nil {
+		return nil
+	}
+
+	f := JSONParagraphFormat{
+		KeepNext:        onOffBool(pPr.KeepNext),
+		PageBreakBefore: onOffBool(pPr.PageBreakBefore),
+	}
+	if pPr.Justification != nil {
+		f.Align = string(pPr.Justification.Val)
+	}
+	if s := pPr.Spacing; s != nil && (s.Before != nil || s.After != nil) {
+		f.Spacing = &JSONSpacing{Before: s.Before, After: s.After}
+	}
+	if ind := pPr.Indent; ind != nil && (ind.Left != nil || ind.Right != nil || ind.FirstLine != nil || ind.Hanging != nil) {
+		f.Indent = &JSONIndent{Left: ind.Left, Right: ind.Right, FirstLine: ind.FirstLine, Hanging: ind.Hanging}
+	}
+
+	if f == (JSONParagraphFormat{}) {
+		return nil
+	}
+	return &f
+}
+
+func jsonRunFormat(rp *ctypes.RunProperty) *JSONRunFormat {
+	if rp == nil {
+		return nil
+	}
+
+	f := JSONRunFormat{
+		Bold:      onOffBool(rp.Bold),
+		Italic:    onOffBool(rp.Italic),
+		Strike:    onOffBool(rp.Strike),
+		Caps:      onOffBool(rp.Caps),
+		SmallCaps: onOffBool(rp.SmallCaps),
+	}
+	if rp.Style != nil {
+		f.Style = rp.Style.Val
+	}
+	if rp.Underline != nil {
+		f.Underline = string(rp.Underline.Val)
+	}
+	if rp.Color != nil {
+		f.Color = rp.Color.Val
+	}
+	if rp.Highlight != nil {
+		f.Highlight = rp.Highlight.Val
+	}
+	if rp.Fonts != nil {
+		f.Font = rp.Fonts.Ascii
+	}
+	if rp.Size != nil {
+		f.Size = float64(rp.Size.Value) / 2
+	}
+	if rp.VertAlign != nil {
+		f.VertAlign = string(rp.VertAlign.Val)
+	}
+
+	if f == (JSONRunFormat{}) {
+		return nil
+	}
+	return &f
+}
+
+func jsonPage(sectPr *ctypes.SectionProp) *JSONPage {
+	if sectPr == nil {
+		return nil
+	}
+
+	page := JSONPage{}
+	if ps := sectPr.PageSize; ps != nil {
+		if ps.Width != nil {
+			page.Width = *ps.Width
+		}
+		if ps.Height != nil {
+			page.Height = *ps.Height
+		}
+		page.Orientation = string(ps.Orient)
+	}
+	if pm := sectPr.PageMargin; pm != nil {
+		page.Margins = &JSONMargins{
+			Top:    pm.Top,
+			Right:  pm.Right,
+			Bottom: pm.Bottom,
+			Left:   pm.Left,
+			Header: pm.Header,
+			Footer: pm.Footer,
+			Gutter: pm.Gutter,
+		}
+	}
+	if sectPr.Type != nil {
+		page.Break = string(sectPr.Type.Val)
+	}
+
+	if page == (JSONPage{}) {
+		return nil
+	}
+	return &page
+}
+
+func jsonStyle(st *ctypes.Style) *JSONStyle {
+	if st.ID == nil || st.Type == nil {
+		return nil
+	}
+
+	js := &JSONStyle{
+		ID:        *st.ID,
+		Type:      string(*st.Type),
+		Default:   st.Default != nil && isOn(&ctypes.OnOff{Val: st.Default}),
+		Paragraph: jsonParagraphFormat(st.ParaProp),
+		Run:       jsonRunFormat(st.RunProp),
+	}
+	if st.Name != nil {
+		js.Name = st.Name.Val
+	}
+	if st.BasedOn != nil {
+		js.BasedOn = st.BasedOn.Val
+	}
+	if st.Next != nil {
+		js.Next = st.Next.Val
+	}
+	return js
+}
+
+// onOffBool returns the state of an optional on/off property, or nil if it is not set.
+func onOffBool(o *ctypes.OnOff) *bool {
+	if o == nil {
+		return nil
+	}
+	on := isOn(o)
+	return &on
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, replacing the body of the document
+// with the content of a JSONDocument and adding or updating the styles it defines.
+//
+// The document must have been created with NewDocument or opened from a file beforehand, so
+// that the package parts the content refers to exist. Headers and footers of the final section
+// are kept. Errors report the location of the offending element, for example
+// "sections[0].blocks[2].inlines[1]: unknown inline type "video"".
+// On error the document is left unchanged.
+func (rd *RootDoc) UnmarshalJSON(data []byte) error {
+	var doc JSONDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if doc.Version < 1 || doc.Version > JSONVersion {
+		return fmt.Errorf("json: unsupported schema version %d", doc.Version)
+	}
+	if rd.Document == nil || rd.Document.Body == nil {
+		return errors.New("json: document has no body to decode into")
+	}
+	if len(doc.Sections) == 0 {
+		return errors.New("json: document has no sections")
+	}
+
+	styles := make([]ctypes.Style, 0, len(doc.Styles))
+	for i := range doc.Styles {
+		st, err := rd.styleFromJSON(&doc.Styles[i])
+		if err != nil {
+			return fmt.Errorf("json: %w", atPath(fmt.Sprintf("styles[%d]", i), err))
+		}
+		styles = append(styles, st)
+	}
+
+	var (
+		d        = newJSONDecoder(rd)
+		children []DocumentChild
+		final    *ctypes.SectionProp
+	)
+	for i, sec := range doc.Sections {
+		last := i == len(doc.Sections)-1
+
+		blocks, err := d.blocksFromJSON(sec.Blocks)
+		if err != nil {
+			return fmt.Errorf("json: %w", atPath(fmt.Sprintf("sections[%d]", i), err))
+		}
+
+		var base *ctypes.SectionProp
+		if last {
+			base = rd.Document.Body.SectPr
+		}
+		sectPr, err := sectionFromJSON(sec.Page, base)
+		if err != nil {
+			return fmt.Errorf("json: %w", atPath(fmt.Sprintf("sections[%d].page", i), err))
+		}
+
+		if last {
+			children = append(children, blocks...)
+			final = sectPr
+			continue
+		}
+
+		// The properties of all but the last section are held by their last paragraph
+		if len(blocks) == 0 || blocks[len(blocks)-1].Para == nil {
+			blocks = append(blocks, DocumentChild{Para: newParagraph(rd)})
+		}
+		p := blocks[len(blocks)-1].Para
+		p.ensureProp()
+		if sectPr == nil {
+			sectPr = ctypes.NewSectionProper()
+		}
+		p.ct.Property.SectPr = sectPr
+		children = append(children, blocks...)
+	}
+
+	if err := d.commit(); err != nil {
+		return fmt.Errorf("json: %w", err)
+	}
+	for _, st := range styles {
+		rd.SetStyle(st)
+	}
+	rd.Document.Body.Children = children
+	rd.Document.Body.SectPr = final
+
+	return nil
+}
+
+// jsonDecoder decodes the content of a JSONDocument into a document. The media parts and
+// relationships the content needs, and the IDs and image numbers given to them, are staged,
+// and only added to the document by commit once all of the content decoded, so that an error
+// leaves the document as it was.
+type jsonDecoder struct {
+	*RootDoc
+	rID    int  // rID is the last relationship ID staged.
+	images uint // images is the last image number staged.
+	rels   []*Relationship
+	media  []jsonMedia
+}
+
+// newJSONDecoder returns a decoder staging IDs and image numbers from those of the document.
+func newJSONDecoder(rd *RootDoc) *jsonDecoder {
+	defer rd.Document.lock()()
+	return &jsonDecoder{RootDoc: rd, rID: rd.Document.RID, images: rd.ImageCount}
+}
+
+// jsonMedia is a staged media part.
+type jsonMedia struct {
+	name string
+	ext  string
+	mime string
+	data []byte
+}
+
+// stageRelation stages a relationship of the main document part and returns its ID.
+func (d *jsonDecoder) stageRelation(relType, target, mode string) string {
+	d.rID++
+	rID := "rId" + strconv.Itoa(d.rID)
+	d.rels = append(d.rels, &Relationship{ID: rID, Type: relType, Target: target, TargetMode: mode})
+	return rID
+}
+
+// stageImage stages a picture as a new media part and returns the ID of its relationship and
+// its image number.
+func (d *jsonDecoder) stageImage(data []byte, ext string) (string, uint, error) {
+	ext = strings.TrimPrefix(ext, ".")
+	mime, err := MIMEFromExt(ext)
+	if err != nil {
+		return "", 0, err
+	}
+	if !strings.HasPrefix(mime, "image/") {
+		return "", 0, fmt.Errorf("%q is not a picture", "."+ext)
+	}
+
+	n := d.stageImageNumber()
+	name := fmt.Sprintf("image%d.%s", n, ext)
+	d.media = append(d.media, jsonMedia{name: name, ext: ext, mime: mime, data: data})
+	return d.stageRelation(constants.SourceRelationshipImage, "media/"+name, ""), n, nil
+}
+
+// stageImageNumber stages a new image number and returns it.
+func (d *jsonDecoder) stageImageNumber() uint {
+	d.images++
+	return d.images
+}
+
+// hasMedia reports whether a media part exists in the package or is staged.
+func (d *jsonDecoder) hasMedia(name string) bool {
+	if _, ok := d.FileMap.Load(name); ok {
+		return true
+	}
+	for _, m := range d.media {
+		if constants.MediaPath+m.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// commit adds the staged media parts, relationships, IDs and image numbers to the document.
+func (d *jsonDecoder) commit() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, m := range d.media {
+		if err := d.ContentType.AddExtension(m.ext, m.mime); err != nil {
+			return err
+		}
+		if err := d.ContentType.AddOverride("/"+constants.MediaPath+m.name, m.mime); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range d.media {
+		d.FileMap.Store(constants.MediaPath+m.name, m.data)
+	}
+
+	d.Document.DocRels.Relationships = append(d.Document.DocRels.Relationships, d.rels...)
+	d.Document.RID = d.rID
+	d.ImageCount = d.images
+	return nil
+}
+
+// blocksFromJSON decodes a list of paragraphs and tables.
+func (d *jsonDecoder) blocksFromJSON(blocks []JSONBlock) ([]DocumentChild, error) {
+	children := make([]DocumentChild, 0, len(blocks))
+	for i := range blocks {
+		child, err := d.blockFromJSON(&blocks[i])
+		if err != nil {
+			return nil, atPath(fmt.Sprintf("blocks[%d]", i), err)
+		}
+		children = append(children, child)
+	}
+	return children, nil
+}
+
+func (d *jsonDecoder) blockFromJSON(b *JSONBlock) (DocumentChild, error) {
+	switch b.Type {
+	case JSONParagraph:
+		p, err := d.paragraphFromJSON(b)
+		return DocumentChild{Para: p}, err
+	case JSONTable:
+		t, err := d.tableFromJSON(b)
+		return DocumentChild{Table: t}, err
+	default:
+		return DocumentChild{}, fmt.Errorf("unknown block type %q", b.Type)
+	}
+}
+
+func (d *jsonDecoder) paragraphFromJSON(b *JSONBlock) (*Paragraph, error) {
+	p := newParagraph(d.RootDoc)
+
+	if b.Style != "" {
+		p.Style(b.Style)
+	}
+	if b.Numbering != nil {
+		p.Numbering(b.Numbering.ID, b.Numbering.Level)
+	}
+	if b.Format != nil {
+		p.ensureProp()
+		if err := applyParagraphFormat(p.ct.Property, b.Format); err != nil {
+			return nil, atPath("format", err)
+		}
+	}
+
+	children, err := d.inlinesFromJSON(b.Inlines, false)
+	if err != nil {
+		return nil, err
+	}
+	p.ct.Children = children
+
+	return p, nil
+}
+
+// inlinesFromJSON decodes paragraph content. Text without formatting inside a link gets the
+// hyperlink character style.
+func (d *jsonDecoder) inlinesFromJSON(inlines []JSONInline, inLink bool) ([]ctypes.ParagraphChild, error) {
+	var children []ctypes.ParagraphChild
+	for i := range inlines {
+		inner, err := d.inlineFromJSON(&inlines[i], inLink)
+		if err != nil {
+			return nil, atPath(fmt.Sprintf("inlines[%d]", i), err)
+		}
+		children = append(children, inner...)
+	}
+	return children, nil
+}
+
+func (d *jsonDecoder) inlineFromJSON(in *JSONInline, inLink bool) ([]ctypes.ParagraphChild, error) {
+	props, err := runPropFromJSON(in.Format, inLink)
+	if err != nil {
+		return nil, atPath("format", err)
+	}
+
+	run := func(children ...ctypes.RunChild) []ctypes.ParagraphChild {
+		return []ctypes.ParagraphChild{{Run: &ctypes.Run{Property: props, Children: children}}}
+	}
+
+	switch in.Type {
+	case JSONText:
+		return run(textRunChildren(in.Text)...), nil
+	case JSONTab:
+		return run(ctypes.RunChild{Tab: &ctypes.Empty{}}), nil
+	case JSONBreak:
+		if in.Break == "" || in.Break == "line" {
+			return run(ctypes.RunChild{Break: &ctypes.Break{}}), nil
+		}
+		bt, err := stypes.BreakTypeFromStr(in.Break)
+		if err != nil {
+			return nil, err
+		}
+		return run(ctypes.RunChild{Break: ctypes.NewBreak(bt)}), nil
+	case JSONField:
+		if strings.TrimSpace(in.Instr) == "" {
+			return nil, errors.New("field has no instruction")
+		}
+		return fieldRuns(" "+strings.TrimSpace(in.Instr)+" ", in.Text, props), nil
+	case JSONLink:
+		return d.linkFromJSON(in)
+	case JSONImage:
+		if in.Image == nil {
+			return nil, errors.New("image inline has no image")
+		}
+		r, err := d.imageRunFromJSON(in.Image)
+		if err != nil {
+			return nil, atPath("image", err)
+		}
+		return []ctypes.ParagraphChild{{Run: r}}, nil
+	default:
+		return nil, fmt.Errorf("unknown inline type %q", in.Type)
+	}
+}
+
+func (d *jsonDecoder) linkFromJSON(in *JSONInline) ([]ctypes.ParagraphChild, error) {
+	if in.URL == "" && in.Anchor == "" {
+		return nil, errors.New("link has neither url nor anchor")
+	}
+
+	inlines := in.Inlines
+	if len(inlines) == 0 && in.Text != "" {
+		inlines = []JSONInline{{Type: JSONText, Text: in.Text, Format: in.Format}}
+	}
+
+	children, err := d.inlinesFromJSON(inlines, true)
+	if err != nil {
+		return nil, err
+	}
+
+	link := &ctypes.Hyperlink{Anchor: in.Anchor}
+	if in.URL != "" {
+		link.ID = d.stageRelation(constants.SourceRelationshipHyperLink, in.URL, "External")
+	}
+	if len(children) > 0 && children[0].Run != nil {
+		link.Run = children[0].Run
+		children = children[1:]
+	}
+	link.Children = children
+
+	return []ctypes.ParagraphChild{{Link: link}}, nil
+}
+
+// imageRunFromJSON returns a run holding the referenced picture. Media already in the package
+// is reused; otherwise the picture is staged from the inline data.
+func (d *jsonDecoder) imageRunFromJSON(img *JSONImageRef) (*ctypes.Run, error) {
+	if img.Width == 0 || img.Height == 0 {
+		return nil, errors.New("width and height are required")
+	}
+
+	var (
+		rID string
+		n   uint
+	)
+	if media := path.Clean(img.Media); strings.HasPrefix(media, constants.MediaPath) && d.hasMedia(media) {
+		rID = d.imageRelation(media)
+		n = d.stageImageNumber()
+	} else {
+		if len(img.Data) == 0 {
+			return nil, fmt.Errorf("media %q does not exist in %s and no data is given", img.Media, constants.MediaPath)
+		}
+
+		var err error
+		if rID, n, err = d.stageImage(img.Data, path.Ext(img.Media)); err != nil {
+			return nil, err
+		}
+	}
+
+	r, inline := newDrawingRun(rID, n, units.Emu(img.Width), units.Emu(img.Height))
+	inline.DocProp.Description = img.Alt
+	return r, nil
+}
+
+// imageRelation returns the ID of an image relationship pointing at the given media part,
+// staging the relationship if the document has none.
+func (d *jsonDecoder) imageRelation(media string) string {
+	for _, rels := range [][]*Relationship{d.Document.DocRels.Relationships, d.rels} {
+		for _, rel := range rels {
+			if rel.Type == constants.SourceRelationshipImage && rel.TargetMode != "External" &&
+				d.Document.partPath(rel.Target) == media {
+				return rel.ID
+			}
+		}
+	}
+
+	target := "/" + media
+	if dir := path.Dir(d.Document.relativePath) + "/"; strings.HasPrefix(media, dir) {
+		target = strings.TrimPrefix(media, dir)
+	}
+	return d.stageRelation(constants.SourceRelationshipImage, target, "")
+}
+
+func (d *jsonDecoder) tableFromJSON(b *JSONBlock) (*Table, error) {
+	tbl := &Table{root: d.RootDoc, ct: ctypes.DefaultTable()}
+
+	if b.Style != "" {
+		tbl.Style(b.Style)
+	}
+	tbl.Grid(b.Grid...)
+
+	for i, r := range b.Rows {
+		row := ctypes.DefaultRow()
+		if r.Header {
+			row.Property.Header = &ctypes.OnOff{}
+		}
+
+		for j := range r.Cells {
+			cell, err := d.cellFromJSON(&r.Cells[j])
+			if err != nil {
+				return nil, atPath(fmt.Sprintf("rows[%d].cells[%d]", i, j), err)
+			}
+			row.Contents = append(row.Contents, ctypes.TRCellContent{Cell: cell})
+		}
+
+		tbl.ct.RowContents = append(tbl.ct.RowContents, ctypes.RowContent{Row: row})
+	}
+
+	return tbl, nil
+}
+
+func (d *jsonDecoder) cellFromJSON(c *JSONCell) (*ctypes.Cell, error) {
+	cell := ctypes.DefaultCell()
+	prop := cell.Property
+
+	if c.ColSpan > 1 {
+		prop.GridSpan = ctypes.NewDecimalNum(c.ColSpan)
+	}
+	if c.VMerge != "" {
+		merge, err := stypes.MergeCellFromStr(c.VMerge)
+		if err != nil {
+			return nil, err
+		}
+		prop.VMerge = ctypes.NewGenOptStrVal(merge)
+	}
+	if c.Width != nil {
+		prop.Width = ctypes.NewTableWidth(*c.Width, stypes.TableWidthDxa)
+	}
+	if c.Fill != "" {
+		fill := c.Fill
+		prop.Shading.Fill = &fill
+	}
+	if c.VAlign != "" {
+		align, err := stypes.VerticalJcFromStr(c.VAlign)
+		if err != nil {
+			return nil, err
+		}
+		prop.VAlign = ctypes.NewGenSingleStrVal(align)
+	}
+
+	blocks, err := d.blocksFromJSON(c.Blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	// A cell must end with a paragraph
+	if len(blocks) == 0 || blocks[len(blocks)-1].Para == nil {
+		blocks = append(blocks, DocumentChild{Para: newParagraph(d.RootDoc)})
+	}
+
+	for _, block := range blocks {
+		if block.Para != nil {
+			cell.Contents = append(cell.Contents, ctypes.TCBlockContent{Paragraph: block.Para.ct})
+		}
+		if block.Table != nil {
+			cell.Contents = append(cell.Contents, ctypes.TCBlockContent{Table: block.Table.ct})
+		}
+	}
+
+	return cell, nil
+}
+
+// sectionFromJSON returns section properties for a page setup, starting from a copy of base.
+func sectionFromJSON(page *JSONPage, base *ctypes.SectionProp) (*ctypes.SectionProp, error) {
+	if page == nil {
+		return base, nil
+	}
+
+	sectPr := ctypes.NewSectionProper()
+	if base != nil {
+		*sectPr = *base
+	}
+
+	if page.Width != 0 || page.Height != 0 || page.Orientation != "" {
+		ps := &ctypes.PageSize{}
+		if sectPr.PageSize != nil {
+			*ps = *sectPr.PageSize
+		}
+		if page.Width != 0 {
+			w := page.Width
+			ps.Width = &w
+		}
+		if page.Height != 0 {
+			h := page.Height
+			ps.Height = &h
+		}
+		if page.Orientation != "" {
+			orient, err := stypes.PageOrientFromStr(page.Orientation)
+			if err != nil {
+				return nil, err
+			}
+			ps.Orient = orient
+		}
+		sectPr.PageSize = ps
+	}
+
+	if m := page.Margins; m != nil {
+		sectPr.PageMargin = &ctypes.PageMargin{
+			Top:    m.Top,
+			Right:  m.Right,
+			Bottom: m.Bottom,
+			Left:   m.Left,
+			Header: m.Header,
+			Footer: m.Footer,
+			Gutter: m.Gutter,
+		}
+	}
+
+	if page.Break != "" {
+		mark, err := stypes.SectionMarkFromStr(page.Break)
+		if err != nil {
+			return nil, err
+		}
+		sectPr.Type = ctypes.NewGenSingleStrVal(mark)
+	}
+
+	return sectPr, nil
+}
+
+// styleFromJSON returns the style definition described by st, based on the existing style
+// with the same ID and type if there is one.
+func (rd *RootDoc) styleFromJSON(st *JSONStyle) (ctypes.Style, error) {
+	if st.ID == "" {
+		return ctypes.Style{}, errors.New("style has no id")
+	}
+
+	styleType, err := stypes.StyleTypeFromStr(st.Type)
+	if err != nil {
+		return ctypes.Style{}, err
+	}
+
+	style := ctypes.Style{ID: &st.ID, Type: &styleType}
+	if existing := rd.GetStyleByID(st.ID, styleType); existing != nil {
+		style = *existing
+	}
+
+	if st.Name != "" {
+		style.Name = ctypes.NewCTString(st.Name)
+	}
+	if st.BasedOn != "" {
+		style.BasedOn = ctypes.NewCTString(st.BasedOn)
+	}
+	if st.Next != "" {
+		style.Next = ctypes.NewCTString(st.Next)
+	}
+	if st.Default {
+		on := stypes.OnOffTrue
+		style.Default = &on
+	}
+
+	if st.Paragraph != nil {
+		pPr := &ctypes.ParagraphProp{}
+		if style.ParaProp != nil {
+			*pPr = *style.ParaProp
+		}
+		if err := applyParagraphFormat(pPr, st.Paragraph); err != nil {
+			return ctypes.Style{}, atPath("paragraph", err)
+		}
+		style.ParaProp = pPr
+	}
+
+	if st.Run != nil {
+		rp := copyRunProp(style.RunProp)
+		if rp == nil {
+			rp = &ctypes.RunProperty{}
+		}
+		if err := applyRunFormat(rp, st.Run); err != nil {
+			return ctypes.Style{}, atPath("run", err)
+		}
+		style.RunProp = rp
+	}
+
+	return style, nil
+}
+
+func applyParagraphFormat(pPr *ctypes.ParagraphProp, f *JSONParagraphFormat) error {
+	if f.Align != "" {
+		jc, err := stypes.JustificationFromStr(f.Align)
+		if err != nil {
+			return err
+		}
+		pPr.Justification = ctypes.NewGenSingleStrVal(jc)
+	}
+
+	if f.Spacing != nil {
+		spacing := &ctypes.Spacing{}
+		if pPr.Spacing != nil {
+			*spacing = *pPr.Spacing
+		}
+		if f.Spacing.Before != nil {
+			spacing.Before = f.Spacing.Before
+		}
+		if f.Spacing.After != nil {
+			spacing.After = f.Spacing.After
+		}
+		pPr.Spacing = spacing
+	}
+
+	if f.Indent != nil {
+		indent := &ctypes.Indent{}
+		if pPr.Indent != nil {
+			*indent = *pPr.Indent
+		}
+		if f.Indent.Left != nil {
+			indent.Left = f.Indent.Left
+		}
+		if f.Indent.Right != nil {
+			indent.Right = f.Indent.Right
+		}
+		if f.Indent.FirstLine != nil {
+			indent.FirstLine = f.Indent.FirstLine
+		}
+		if f.Indent.Hanging != nil {
+			indent.Hanging = f.Indent.Hanging
+		}
+		pPr.Indent = indent
+	}
+
+	if f.KeepNext != nil {
+		pPr.KeepNext = ctypes.OnOffFromBool(*f.KeepNext)
+	}
+	if f.PageBreakBefore != nil {
+		pPr.PageBreakBefore = ctypes.OnOffFromBool(*f.PageBreakBefore)
+	}
+
+	return nil
+}
+
+// runPropFromJSON returns the run properties for an inline format.
+func runPropFromJSON(f *JSONRunFormat, inLink bool) (*ctypes.RunProperty, error) {
+	if f == nil {
+		if inLink {
+			return &ctypes.RunProperty{Style: ctypes.NewRunStyle(constants.HyperLinkStyle)}, nil
+		}
+		return nil, nil
+	}
+
+	rp := &ctypes.RunProperty{}
+	if err := applyRunFormat(rp, f); err != nil {
+		return nil, err
+	}
+	return rp, nil
+}
+
+func applyRunFormat(rp *ctypes.RunProperty, f *JSONRunFormat) error {
+	if f.Style != "" {
+		rp.Style = ctypes.NewRunStyle(f.Style)
+	}
+
+	onOffs := []struct {
+		val  *bool
+		prop **ctypes.OnOff
+	}{
+		{f.Bold, &rp.Bold},
+		{f.Italic, &rp.Italic},
+		{f.Strike, &rp.Strike},
+		{f.Caps, &rp.Caps},
+		{f.SmallCaps, &rp.SmallCaps},
+	}
+	for _, o := range onOffs {
+		if o.val != nil {
+			*o.prop = ctypes.OnOffFromBool(*o.val)
+		}
+	}
+
+	if f.Underline != "" {
+		u, err := stypes.UnderlineFromStr(f.Underline)
+		if err != nil {
+			return err
+		}
+		rp.Underline = ctypes.NewGenSingleStrVal(u)
+	}
+	if f.Color != "" {
+		rp.Color = ctypes.NewColor(f.Color)
+	}
+	if f.Highlight != "" {
+		rp.Highlight = ctypes.NewCTString(f.Highlight)
+	}
+	if f.Font != "" {
+		fonts := &ctypes.RunFonts{}
+		if rp.Fonts != nil {
+			*fonts = *rp.Fonts
+		}
+		fonts.Ascii = f.Font
+		fonts.HAnsi = f.Font
+		rp.Fonts = fonts
+	}
+	if f.Size < 0 {
+		return fmt.Errorf("invalid font size %v", f.Size)
+	}
+	if f.Size > 0 {
+		rp.Size = ctypes.NewFontSize(uint64(math.Round(f.Size * 2)))
+	}
+	if f.VertAlign != "" {
+		va, err := stypes.VerticalAlignRunFromStr(f.VertAlign)
+		if err != nil {
+			return err
+		}
+		rp.VertAlign = ctypes.NewGenSingleStrVal(va)
+	}
+
+	return nil
+}
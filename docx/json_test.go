@@ -0,0 +1,291 @@
+package docx_test
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupJSONDoc(t *testing.T) *docx.RootDoc {
+	t.Helper()
+
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+
+	_, err = rd.AddHeading("Overview", 1)
+	require.NoError(t, err)
+
+	p := rd.AddParagraph("Plain ")
+	p.AddText("bold").Bold(true)
+	p.AddText(" end")
+	p.AddLink("site", "https://example.com")
+	p.Justification(stypes.JustificationCenter)
+	p.GetCT().Property.SectPr = &ctypes.SectionProp{
+		Type: ctypes.NewGenSingleStrVal(stypes.SectionMarkNextContinuous),
+	}
+
+	tbl := rd.AddTable()
+	tbl.Grid(2000, 3000)
+	row := tbl.AddRow()
+	row.AddCell().AddParagraph("Qty")
+	row.AddCell().AddParagraph("Item")
+
+	_, err = rd.AddPicture("../godocx.png", units.Inch(1), units.Inch(2))
+	require.NoError(t, err)
+
+	return rd
+}
+
+func TestMarshalJSON(t *testing.T) {
+	rd := setupJSONDoc(t)
+
+	data, err := json.Marshal(rd)
+	require.NoError(t, err)
+
+	var doc docx.JSONDocument
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	assert.Equal(t, docx.JSONVersion, doc.Version)
+	assert.NotEmpty(t, doc.Styles)
+	require.Len(t, doc.Sections, 2)
+
+	first := doc.Sections[0]
+	require.NotNil(t, first.Page)
+	assert.Equal(t, "continuous", first.Page.Break)
+	require.Len(t, first.Blocks, 2)
+	assert.Equal(t, "Heading1", first.Blocks[0].Style)
+
+	para := first.Blocks[1]
+	assert.Equal(t, "center", para.Format.Align)
+	require.Len(t, para.Inlines, 4)
+	assert.Equal(t, docx.JSONInline{Type: "text", Text: "Plain "}, para.Inlines[0])
+	assert.Equal(t, "bold", para.Inlines[1].Text)
+	assert.True(t, *para.Inlines[1].Format.Bold)
+	assert.Equal(t, "link", para.Inlines[3].Type)
+	assert.Equal(t, "https://example.com", para.Inlines[3].URL)
+	assert.Equal(t, "site", para.Inlines[3].Inlines[0].Text)
+
+	second := doc.Sections[1]
+	require.NotNil(t, second.Page)
+	assert.NotZero(t, second.Page.Width)
+
+	tbl := second.Blocks[0]
+	assert.Equal(t, "table", tbl.Type)
+	assert.Equal(t, []uint64{2000, 3000}, tbl.Grid)
+	assert.Equal(t, "Item", tbl.Rows[0].Cells[1].Blocks[0].Inlines[0].Text)
+
+	img := second.Blocks[1].Inlines[0]
+	assert.Equal(t, "image", img.Type)
+	assert.Equal(t, "word/media/image1.png", img.Image.Media)
+	assert.Equal(t, uint64(units.Inch(2).ToEmu()), img.Image.Height)
+	assert.Empty(t, img.Image.Data)
+}
+
+func TestMarshalJSON_ContentControl(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+
+	rd.AddParagraph("Before")
+	inner := &ctypes.Paragraph{}
+	inner.AddText("Inside")
+	tbl := ctypes.DefaultTable()
+	row := ctypes.DefaultRow()
+	cell := ctypes.DefaultCell()
+	cellPara := &ctypes.Paragraph{}
+	cellPara.AddText("In cell")
+	cell.Contents = []ctypes.TCBlockContent{{SDT: &ctypes.SDT{Contents: []ctypes.TCBlockContent{{Paragraph: cellPara}}}}}
+	row.Contents = []ctypes.TRCellContent{{Cell: cell}}
+	tbl.RowContents = []ctypes.RowContent{{Row: row}}
+	ctl := docx.NewContentControl(rd)
+	ctl.GetCT().Contents = []ctypes.TCBlockContent{{Paragraph: inner}, {Table: tbl}}
+	require.NoError(t, rd.AddBlocks(docx.DocumentChild{Control: ctl}))
+	rd.AddParagraph("After")
+
+	data, err := json.Marshal(rd)
+	require.NoError(t, err)
+	var doc docx.JSONDocument
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	blocks := doc.Sections[0].Blocks
+	require.Len(t, blocks, 4)
+	assert.Equal(t, "Before", blocks[0].Inlines[0].Text)
+	assert.Equal(t, "Inside", blocks[1].Inlines[0].Text)
+	assert.Equal(t, "In cell", blocks[2].Rows[0].Cells[0].Blocks[0].Inlines[0].Text)
+	assert.Equal(t, "After", blocks[3].Inlines[0].Text)
+}
+
+func TestUnmarshalJSON_RoundTrip(t *testing.T) {
+	rd := setupJSONDoc(t)
+
+	data, err := json.Marshal(rd)
+	require.NoError(t, err)
+
+	require.NoError(t, json.Unmarshal(data, rd))
+
+	again, err := json.Marshal(rd)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(again))
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	png, err := os.ReadFile("../godocx.png")
+	require.NoError(t, err)
+
+	doc := docx.JSONDocument{
+		Version: docx.JSONVersion,
+		Styles: []docx.JSONStyle{
+			{ID: "Note", Type: "paragraph", Name: "Note", Run: &docx.JSONRunFormat{Italic: boolPtr(true), Size: 9}},
+		},
+		Sections: []docx.JSONSection{
+			{
+				Page: &docx.JSONPage{Orientation: "landscape", Width: 16838, Height: 11906},
+				Blocks: []docx.JSONBlock{
+					{Type: "paragraph", Style: "Note", Inlines: []docx.JSONInline{
+						{Type: "text", Text: "Page "},
+						{Type: "field", Instr: "PAGE", Text: "1"},
+						{Type: "link", Anchor: "intro", Text: "see intro"},
+					}},
+				},
+			},
+			{
+				Blocks: []docx.JSONBlock{
+					{Type: "table", Rows: []docx.JSONRow{{Header: true, Cells: []docx.JSONCell{
+						{ColSpan: 2, Fill: "D9D9D9", VAlign: "center"},
+					}}}},
+					{Type: "paragraph", Inlines: []docx.JSONInline{
+						{Type: "image", Image: &docx.JSONImageRef{Media: "logo.png", Data: png, Width: 914400, Height: 914400, Alt: "Logo"}},
+					}},
+				},
+			},
+		},
+	}
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, rd))
+
+	style := rd.GetStyleByID("Note", stypes.StyleTypeParagraph)
+	require.NotNil(t, style)
+	assert.Equal(t, uint64(18), style.RunProp.Size.Value)
+
+	body := rd.Document.Body.Children
+	require.Len(t, body, 3)
+
+	p := body[0].Para.GetCT()
+	require.NotNil(t, p.Property.SectPr)
+	assert.Equal(t, stypes.PageOrientLandscape, p.Property.SectPr.PageSize.Orient)
+	require.Len(t, p.Children, 7)
+	assert.Equal(t, "intro", p.Children[6].Link.Anchor)
+	assert.Equal(t, "Hyperlink", p.Children[6].Link.Run.Property.Style.Val)
+
+	out, err := json.Marshal(rd)
+	require.NoError(t, err)
+
+	var decoded docx.JSONDocument
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	require.Len(t, decoded.Sections, 2)
+	assert.Equal(t, docx.JSONInline{Type: "field", Instr: "PAGE", Text: "1"}, decoded.Sections[0].Blocks[0].Inlines[1])
+
+	cell := decoded.Sections[1].Blocks[0].Rows[0].Cells[0]
+	assert.Equal(t, 2, cell.ColSpan)
+	assert.Equal(t, "D9D9D9", cell.Fill)
+	assert.Len(t, cell.Blocks, 1)
+
+	img := decoded.Sections[1].Blocks[1].Inlines[0].Image
+	assert.Equal(t, "word/media/image1.png", img.Media)
+	assert.Equal(t, "Logo", img.Alt)
+}
+
+func TestUnmarshalJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		err  string
+	}{
+		{
+			name: "version",
+			json: `{"version": 99, "sections": [{"blocks": []}]}`,
+			err:  "unsupported schema version 99",
+		},
+		{
+			name: "no sections",
+			json: `{"version": 1}`,
+			err:  "document has no sections",
+		},
+		{
+			name: "unknown inline",
+			json: `{"version": 1, "sections": [{"blocks": [{"type": "paragraph", "inlines": [{"type": "text"}, {"type": "video"}]}]}]}`,
+			err:  `sections[0].blocks[0].inlines[1]: unknown inline type "video"`,
+		},
+		{
+			name: "bad cell",
+			json: `{"version": 1, "sections": [{"blocks": [{"type": "table", "rows": [{"cells": [{"vMerge": "down", "blocks": []}]}]}]}]}`,
+			err:  `sections[0].blocks[0].rows[0].cells[0]: invalid MergeCell value`,
+		},
+		{
+			name: "missing media",
+			json: `{"version": 1, "sections": [{"blocks": [{"type": "paragraph", "inlines": [{"type": "image", "image": {"media": "word/media/x.png", "width": 1, "height": 1}}]}]}]}`,
+			err:  `inlines[0].image: media "word/media/x.png" does not exist`,
+		},
+		{
+			name: "media outside word/media",
+			json: `{"version": 1, "sections": [{"blocks": [{"type": "paragraph", "inlines": [{"type": "image", "image": {"media": "word/document.xml", "width": 1, "height": 1}}]}]}]}`,
+			err:  `inlines[0].image: media "word/document.xml" does not exist in word/media/`,
+		},
+		{
+			name: "not a picture",
+			json: `{"version": 1, "sections": [{"blocks": [{"type": "paragraph", "inlines": [{"type": "image", "image": {"media": "notes.txt", "data": "aGk=", "width": 1, "height": 1}}]}]}]}`,
+			err:  `inlines[0].image: ".txt" is not a picture`,
+		},
+		{
+			name: "error after link and image",
+			json: `{"version": 1, "sections": [{"blocks": [{"type": "paragraph", "inlines": [
+				{"type": "link", "url": "https://example.com", "text": "site"},
+				{"type": "image", "image": {"media": "logo.png", "data": "iVBORw0KGgo=", "width": 1, "height": 1}},
+				{"type": "video"}]}]}]}`,
+			err: `inlines[2]: unknown inline type "video"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd, err := godocx.NewDocument()
+			require.NoError(t, err)
+			rd.AddParagraph("kept")
+			rels := len(rd.Document.DocRels.Relationships)
+			overrides := len(rd.ContentType.Override)
+			rID, images := rd.Document.RID, rd.ImageCount
+
+			err = json.Unmarshal([]byte(tt.json), rd)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+			assert.Len(t, rd.Document.Body.Children, 1)
+
+			// Nothing the content needed was added to the package.
+			assert.Len(t, rd.Document.DocRels.Relationships, rels)
+			assert.Len(t, rd.ContentType.Override, overrides)
+			assert.Equal(t, rID, rd.Document.RID)
+			assert.Equal(t, images, rd.ImageCount)
+			rd.FileMap.Range(func(key, _ any) bool {
+				assert.False(t, strings.HasPrefix(key.(string), "word/media/"), key)
+				return true
+			})
+		})
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -1,7 +1,9 @@
 package docx
 
 import (
+	"path"
 	"strconv"
+	"strings"
 
 	"github.com/gomutex/godocx/common/constants"
 )
@@ -17,8 +19,9 @@ import (
 // This function generates a new relationship ID, creates a Relationship object with the specified link as the target,
 // and appends it to the document's relationships collection (DocRels.Relationships). It returns the generated ID of the relationship.
 func (doc *Document) addLinkRelation(link string) string {
+	defer doc.lock()()
 
-	rID := doc.IncRelationID()
+	rID := doc.incRelationID()
 
 	rel := &Relationship{
 		ID:         "rId" + strconv.Itoa(rID),
@@ -44,7 +47,9 @@ func (doc *Document) addLinkRelation(link string) string {
 // This function generates a new relationship ID, creates a Relationship object with the specified type and target,
 // and appends it to the document's relationships collection (DocRels.Relationships). It returns the generated ID of the relationship.
 func (doc *Document) addRelation(relType string, fileName string) string {
-	rID := doc.IncRelationID()
+	defer doc.lock()()
+
+	rID := doc.incRelationID()
 	rel := &Relationship{
 		ID:     "rId" + strconv.Itoa(rID),
 		Type:   relType,
@@ -55,3 +60,23 @@ func (doc *Document) addRelation(relType string, fileName string) string {
 
 	return "rId" + strconv.Itoa(rID)
 }
+
+// relationByID returns the document relationship with the given ID, or nil if it does not exist.
+func (doc *Document) relationByID(rID string) *Relationship {
+	defer doc.lock()()
+
+	for _, rel := range doc.DocRels.Relationships {
+		if rel.ID == rID {
+			return rel
+		}
+	}
+	return nil
+}
+
+// partPath resolves the target of an internal relationship to its path within the package.
+func (doc *Document) partPath(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(doc.relativePath), target)
+}
@@ -0,0 +1,565 @@
+package docx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// MailMergeOptions configures RootDoc.MailMerge.
+type MailMergeOptions struct {
+	// Output, when set, is called once for every merged document instead of merging all records
+	// into a single document. During the call the document holds the merged content of that
+	// document; its original content is restored once all records are merged.
+	Output func(doc *RootDoc, index int) error
+
+	// SectionBreak is the kind of section break between the records of a single merged
+	// document. It defaults to a next page break.
+	SectionBreak stypes.SectionMark
+
+	// Strict makes a MERGEFIELD naming a column that is missing from the record an error
+	// instead of merging it as empty text.
+	Strict bool
+}
+
+// MailMerge executes a mail merge document authored with Word's Mailings tab. For every record
+// the content of the body is copied and its fields are evaluated:
+//
+//   - MERGEFIELD is replaced by the value of the record's column, honouring the \* (Upper,
+//     Lower, FirstCap, Caps), \# (numeric picture), \@ (date picture), \b and \f switches.
+//     Column names are matched case-insensitively.
+//   - IF, NEXTIF and SKIPIF compare two operands with =, <>, <, <=, > or >=, numerically when
+//     both are numbers. = and <> accept the wildcards * and ? in the second operand.
+//   - NEXT and NEXTIF move on to the next record within the same copy, SKIPIF drops the copy
+//     and MERGEREC is replaced by the record number.
+//
+// Other fields are left as they are. A field must begin and end in the same paragraph, and
+// fields in headers and footers are not merged.
+//
+// Without an Output function the body is replaced by the merged copies, each in its own
+// section.
+func (rd *RootDoc) MailMerge(records []map[string]any, opts MailMergeOptions) error {
+	if len(records) == 0 {
+		return errors.New("mail merge has no records")
+	}
+
+	m := &merger{records: make([]map[string]any, len(records)), strict: opts.Strict}
+	for i, rec := range records {
+		m.records[i] = make(map[string]any, len(rec))
+		for k, v := range rec {
+			m.records[i][strings.ToLower(k)] = v
+		}
+	}
+
+	breakType := opts.SectionBreak
+	if breakType == "" {
+		breakType = stypes.SectionMarkNextPage
+	}
+
+	body := rd.Document.Body
+	original := body.Children
+	defer func() {
+		if opts.Output != nil {
+			body.Children = original
+		}
+	}()
+
+	var merged []DocumentChild
+	for n := 0; m.index < len(records); m.index++ {
+		children, skip, err := m.mergeCopy(rd, original)
+		if err != nil {
+			return err
+		}
+		if skip {
+			continue
+		}
+
+		if opts.Output != nil {
+			body.Children = children
+			if err := opts.Output(rd, n); err != nil {
+				return err
+			}
+			n++
+			continue
+		}
+
+		if len(merged) > 0 {
+			merged = endSection(rd, merged, body.SectPr, breakType)
+		}
+		merged = append(merged, children...)
+	}
+
+	if opts.Output == nil {
+		body.Children = merged
+	}
+	return nil
+}
+
+// endSection ends the section at the last paragraph of children, adding an empty paragraph if
+// children end with a table. The section takes the properties of the final section of the body.
+func endSection(rd *RootDoc, children []DocumentChild, sectPr *ctypes.SectionProp, breakType stypes.SectionMark) []DocumentChild {
+	last := children[len(children)-1].Para
+	if last == nil {
+		last = newParagraph(rd)
+		children = append(children, DocumentChild{Para: last})
+	}
+
+	sect := &ctypes.SectionProp{}
+	if sectPr != nil {
+		sect = internal.DeepCopy(sectPr)
+	}
+	sect.Type = ctypes.NewGenSingleStrVal(breakType)
+
+	last.ensureProp()
+	last.ct.Property.SectPr = sect
+	return children
+}
+
+// ReadMergeCSV reads mail merge records from CSV data whose first row holds the column names.
+func ReadMergeCSV(r io.Reader) ([]map[string]any, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("CSV data has no header row")
+	}
+
+	header := rows[0]
+	records := make([]map[string]any, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		rec := make(map[string]any, len(header))
+		for i, name := range header {
+			if i < len(row) {
+				rec[name] = row[i]
+			}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// merger evaluates the fields of a mail merge document.
+type merger struct {
+	records []map[string]any // records have lower case column names.
+	index   int              // index is the current record.
+	strict  bool
+	skip    bool // skip is set by a SKIPIF field whose condition holds.
+}
+
+// mergeCopy returns a copy of children with their fields evaluated, and whether the copy is
+// dropped by a SKIPIF field.
+func (m *merger) mergeCopy(rd *RootDoc, children []DocumentChild) ([]DocumentChild, bool, error) {
+	m.skip = false
+	out := make([]DocumentChild, 0, len(children))
+	for _, child := range children {
+		switch {
+		case child.Para != nil:
+			p := newParagraph(rd)
+			p.ct = child.Para.ct.Clone()
+			if err := m.mergeParagraph(p.ct); err != nil {
+				return nil, false, err
+			}
+			out = append(out, DocumentChild{Para: p})
+		case child.Table != nil:
+			t := NewTable(rd)
+			t.ct = child.Table.ct.Clone()
+			if err := m.mergeTable(t.ct); err != nil {
+				return nil, false, err
+			}
+			out = append(out, DocumentChild{Table: t})
+		case child.Control != nil:
+			c := &ContentControl{root: rd, ct: internal.DeepCopy(child.Control.ct)}
+			if err := m.mergeContents(c.ct.Contents); err != nil {
+				return nil, false, err
+			}
+			out = append(out, DocumentChild{Control: c})
+		}
+	}
+	return out, m.skip, nil
+}
+
+func (m *merger) mergeTable(t *ctypes.Table) error {
+	for _, rc := range t.RowContents {
+		if rc.Row == nil {
+			continue
+		}
+		for _, cc := range rc.Row.Contents {
+			if cc.Cell == nil {
+				continue
+			}
+			if err := m.mergeContents(cc.Cell.Contents); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mergeContents merges the paragraphs, tables and content controls of a cell or content
+// control.
+func (m *merger) mergeContents(contents []ctypes.TCBlockContent) error {
+	for _, bc := range contents {
+		var err error
+		switch {
+		case bc.Paragraph != nil:
+			err = m.mergeParagraph(bc.Paragraph)
+		case bc.Table != nil:
+			err = m.mergeTable(bc.Table)
+		case bc.SDT != nil:
+			err = m.mergeContents(bc.SDT.Contents)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeParagraph replaces the merge fields of a paragraph by their result.
+func (m *merger) mergeParagraph(p *ctypes.Paragraph) error {
+	out := make([]ctypes.ParagraphChild, 0, len(p.Children))
+	for i := 0; i < len(p.Children); i++ {
+		child := p.Children[i]
+		if child.Run == nil || !beginsField(child.Run) {
+			out = append(out, child)
+			continue
+		}
+
+		f, end := parseMergeField(p.Children, i)
+		if f == nil {
+			out = append(out, p.Children[i:]...)
+			break
+		}
+
+		result, ok, err := m.eval(f)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			out = append(out, p.Children[i:end+1]...)
+		} else if result != "" {
+			out = append(out, ctypes.ParagraphChild{Run: &ctypes.Run{
+				Property: copyRunProp(f.props),
+				Children: textRunChildren(result),
+			}})
+		}
+		i = end
+	}
+	p.Children = out
+	return nil
+}
+
+// mergeField is a complex field along with the fields nested in its instruction.
+type mergeField struct {
+	parts  []fieldPart
+	result string              // result is the text of the current field result.
+	props  *ctypes.RunProperty // props is the formatting of the result.
+}
+
+// fieldPart is a piece of field instruction text or a nested field.
+type fieldPart struct {
+	text  string
+	field *mergeField
+}
+
+// parseMergeField parses the complex field that begins at children[i] and returns the index
+// of the child holding its end character. It returns nil if the field does not end within
+// children.
+func parseMergeField(children []ctypes.ParagraphChild, i int) (*mergeField, int) {
+	type frame struct {
+		field     *mergeField
+		separated bool
+	}
+	var stack []*frame
+
+	for end := i; end < len(children); end++ {
+		run := children[end].Run
+		if run == nil {
+			continue
+		}
+
+		for _, rc := range run.Children {
+			var top *frame
+			if len(stack) > 0 {
+				top = stack[len(stack)-1]
+			}
+
+			switch {
+			case rc.FldChar != nil && rc.FldChar.FldCharType == stypes.FldCharTypeBegin:
+				f := &frame{field: &mergeField{}}
+				if top != nil {
+					if top.separated || top.field == nil {
+						// Fields within a result are dropped along with the result.
+						f.field = nil
+					} else {
+						top.field.parts = append(top.field.parts, fieldPart{field: f.field})
+					}
+				}
+				stack = append(stack, f)
+			case top == nil:
+			case rc.FldChar != nil && rc.FldChar.FldCharType == stypes.FldCharTypeSeparate:
+				top.separated = true
+			case rc.FldChar != nil && rc.FldChar.FldCharType == stypes.FldCharTypeEnd:
+				stack = stack[:len(stack)-1]
+				if len(stack) == 0 {
+					return top.field, end
+				}
+			case top.field == nil:
+			case rc.InstrText != nil && !top.separated:
+				top.field.parts = append(top.field.parts, fieldPart{text: rc.InstrText.Text})
+				if top.field.props == nil {
+					top.field.props = run.Property
+				}
+			case top.separated:
+				if top.field.result == "" {
+					top.field.props = run.Property
+				}
+				top.field.result += runChildText(rc)
+			}
+		}
+	}
+	return nil, 0
+}
+
+// eval returns the result of a field, and false if the field is not a mail merge field.
+func (m *merger) eval(f *mergeField) (string, bool, error) {
+	var (
+		sb     strings.Builder
+		quoted bool
+	)
+	for _, part := range f.parts {
+		if part.field == nil {
+			sb.WriteString(part.text)
+			for i := 0; i < len(part.text); i++ {
+				if part.text[i] == '\\' && quoted && i+1 < len(part.text) && part.text[i+1] == '"' {
+					i++
+				} else if part.text[i] == '"' {
+					quoted = !quoted
+				}
+			}
+			continue
+		}
+		result, ok, err := m.eval(part.field)
+		if err != nil {
+			return "", false, err
+		}
+		if !ok {
+			result = part.field.result
+		}
+
+		// The result of a nested field is a single argument, even when it holds white space
+		// or quotes.
+		result = strings.ReplaceAll(result, `"`, `\"`)
+		if !quoted {
+			result = `"` + result + `"`
+		}
+		sb.WriteString(result)
+	}
+
+	instr := sb.String()
+	args := fieldArgs(instr)
+	if len(args) == 0 {
+		return "", false, nil
+	}
+
+	switch strings.ToUpper(args[0]) {
+	case "MERGEFIELD":
+		if len(args) < 2 {
+			return "", false, fmt.Errorf("mail merge: field %q has no column name", strings.TrimSpace(instr))
+		}
+		return m.mergeField(args[1], args[2:])
+	case "MERGEREC":
+		return strconv.Itoa(m.index + 1), true, nil
+	case "NEXT":
+		m.index++
+		return "", true, nil
+	case "NEXTIF", "SKIPIF":
+		ok, err := compareArgs(args[1:], instr)
+		if err != nil {
+			return "", false, err
+		}
+		if ok && strings.EqualFold(args[0], "NEXTIF") {
+			m.index++
+		}
+		if ok && strings.EqualFold(args[0], "SKIPIF") {
+			m.skip = true
+		}
+		return "", true, nil
+	case "IF":
+		if len(args) < 5 {
+			return "", false, fmt.Errorf("mail merge: field %q needs a comparison and a result", strings.TrimSpace(instr))
+		}
+		ok, err := compareArgs(args[1:4], instr)
+		if err != nil {
+			return "", false, err
+		}
+		switch {
+		case ok:
+			return args[4], true, nil
+		case len(args) > 5:
+			return args[5], true, nil
+		}
+		return "", true, nil
+	}
+	return "", false, nil
+}
+
+// mergeField returns the formatted value of a column of the current record.
+func (m *merger) mergeField(name string, switches []string) (string, bool, error) {
+	var value any
+	if m.index < len(m.records) {
+		v, ok := m.records[m.index][strings.ToLower(name)]
+		if !ok && m.strict {
+			return "", false, fmt.Errorf("mail merge: record %d has no column %q", m.index+1, name)
+		}
+		value = v
+	}
+
+	var before, after, textCase, numPic, datePic string
+	for i := 0; i < len(switches); i++ {
+		arg := ""
+		if i+1 < len(switches) {
+			arg = switches[i+1]
+		}
+		switch strings.ToLower(switches[i]) {
+		case `\*`:
+			// MERGEFORMAT and CHARFORMAT tell how to format the result, not its case.
+			if f := strings.ToLower(arg); f != "mergeformat" && f != "charformat" {
+				textCase = arg
+			}
+		case `\#`:
+			numPic = arg
+		case `\@`:
+			datePic = arg
+		case `\b`:
+			before = arg
+		case `\f`:
+			after = arg
+		default:
+			continue
+		}
+		i++
+	}
+
+	text := formatMergeValue(value, numPic, datePic)
+	text = applyTextCase(text, textCase)
+	if text != "" {
+		text = before + text + after
+	}
+	return text, true, nil
+}
+
+// fieldArgs splits a field instruction into its arguments. Quoted arguments may contain
+// white space, and a backslash escapes a quote within them.
+func fieldArgs(instr string) []string {
+	var (
+		args   []string
+		sb     strings.Builder
+		quoted bool
+		inArg  bool
+	)
+
+	for i := 0; i < len(instr); i++ {
+		c := instr[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(instr) && instr[i+1] == '"':
+			sb.WriteByte('"')
+			i++
+		case c == '"':
+			quoted = !quoted
+			inArg = true
+		case !quoted && (c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			if inArg {
+				args = append(args, sb.String())
+				sb.Reset()
+				inArg = false
+			}
+		default:
+			sb.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, sb.String())
+	}
+	return args
+}
+
+// compareArgs evaluates the comparison "operand operator operand" of an IF, NEXTIF or SKIPIF field.
+func compareArgs(args []string, instr string) (bool, error) {
+	if len(args) < 3 {
+		return false, fmt.Errorf("mail merge: field %q needs a comparison", strings.TrimSpace(instr))
+	}
+
+	a, op, b := args[0], args[1], args[2]
+	var cmp int
+	x, errX := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	y, errY := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	switch {
+	case errX == nil && errY == nil:
+		if x < y {
+			cmp = -1
+		} else if x > y {
+			cmp = 1
+		}
+	case op == "=" || op == "<>":
+		if wildcardMatch(b, a) == (op == "=") {
+			return true, nil
+		}
+		return false, nil
+	default:
+		cmp = strings.Compare(a, b)
+	}
+
+	switch op {
+	case "=":
+		return cmp == 0, nil
+	case "<>":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return false, fmt.Errorf("mail merge: unknown operator %q in field %q", op, strings.TrimSpace(instr))
+}
+
+// wildcardMatch reports whether s matches pattern, in which * stands for any text and ? for
+// any single character.
+func wildcardMatch(pattern, s string) bool {
+	p, t := []rune(pattern), []rune(s)
+	star, mark := -1, 0
+	i, j := 0, 0
+	for j < len(t) {
+		switch {
+		case i < len(p) && (p[i] == '?' || p[i] == t[j]):
+			i++
+			j++
+		case i < len(p) && p[i] == '*':
+			star, mark = i, j
+			i++
+		case star >= 0:
+			i = star + 1
+			mark++
+			j = mark
+		default:
+			return false
+		}
+	}
+	for i < len(p) && p[i] == '*' {
+		i++
+	}
+	return i == len(p)
+}
@@ -0,0 +1,235 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addNestedField appends a field such as IF that compares the value of a merge field, as Word
+// writes it.
+func addNestedField(p *Paragraph, kind, column, comparison string) {
+	fld := func(children ...ctypes.RunChild) ctypes.ParagraphChild {
+		return ctypes.ParagraphChild{Run: &ctypes.Run{Children: children}}
+	}
+	instr := func(text string) ctypes.ParagraphChild {
+		return fld(ctypes.RunChild{InstrText: &ctypes.Text{Text: text}})
+	}
+	char := func(typ stypes.FldCharType) ctypes.ParagraphChild {
+		return fld(ctypes.RunChild{FldChar: ctypes.NewFldChar(typ)})
+	}
+
+	p.ct.Children = append(p.ct.Children,
+		char(stypes.FldCharTypeBegin),
+		instr(" "+kind+" "),
+		char(stypes.FldCharTypeBegin),
+		instr(" MERGEFIELD "+column+" "),
+		char(stypes.FldCharTypeSeparate),
+		fld(ctypes.RunChild{Text: ctypes.TextFromString("«" + column + "»")}),
+		char(stypes.FldCharTypeEnd),
+		instr(" "+comparison+" "),
+		char(stypes.FldCharTypeSeparate),
+		fld(ctypes.RunChild{Text: ctypes.TextFromString("result")}),
+		char(stypes.FldCharTypeEnd),
+	)
+}
+
+func setupMergeDoc(t *testing.T) *RootDoc {
+	rd := setupRootDoc(t)
+	rd.Document.Body.SectPr = &ctypes.SectionProp{}
+
+	p := rd.AddParagraph("Dear ")
+	p.AddField(`MERGEFIELD Name \* Upper`, "«Name»")
+	p.AddText(",")
+
+	p = rd.AddParagraph("Salary: ")
+	p.AddField(`MERGEFIELD salary \# "$#,##0.00" \b "is "`, "«salary»")
+	p.AddText(" ")
+	addNestedField(p, "IF", "Salary", `> 50000 "(senior)" "(junior)"`)
+
+	tbl := rd.AddTable()
+	tbl.AddRow().AddCell().AddEmptyPara().AddField(`MERGEFIELD Start \@ "d MMMM yyyy"`, "«Start»")
+
+	return rd
+}
+
+func TestMailMerge(t *testing.T) {
+	rd := setupMergeDoc(t)
+	records := []map[string]any{
+		{"Name": "ada", "Salary": 65000, "Start": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"Name": "bob", "Salary": "42000.5", "Start": "2023-11-20"},
+	}
+
+	require.NoError(t, rd.MailMerge(records, MailMergeOptions{}))
+
+	body := rd.Document.Body.Children
+	require.Len(t, body, 7)
+	assert.Equal(t, "Dear ADA,", paragraphText(body[0].Para.GetCT()))
+	assert.Equal(t, "Salary: is $65,000.00 (senior)", paragraphText(body[1].Para.GetCT()))
+	assert.Equal(t, "1 March 2024", paragraphText(body[2].Table.GetCT().RowContents[0].Row.Contents[0].Cell.Contents[0].Paragraph))
+
+	// A paragraph is added after the table to end the first record's section.
+	sect := body[3].Para.GetCT().Property.SectPr
+	require.NotNil(t, sect)
+	assert.Equal(t, stypes.SectionMarkNextPage, sect.Type.Val)
+
+	assert.Equal(t, "Dear BOB,", paragraphText(body[4].Para.GetCT()))
+	assert.Equal(t, "Salary: is $42,000.50 (junior)", paragraphText(body[5].Para.GetCT()))
+	assert.Equal(t, "20 November 2023", paragraphText(body[6].Table.GetCT().RowContents[0].Row.Contents[0].Cell.Contents[0].Paragraph))
+}
+
+func TestMailMerge_Output(t *testing.T) {
+	rd := setupRootDoc(t)
+	p := rd.AddParagraph("")
+	p.AddField(`MERGEFIELD Name`, "«Name»")
+	p.AddText(" and ")
+	p.AddField(`NEXT`, "")
+	p.AddField(`MERGEFIELD Name`, "«Name»")
+	addNestedField(p, "SKIPIF", "Name", `= "Skip*"`)
+	rd.AddParagraph("Record ").AddField("MERGEREC", "1")
+
+	records, err := ReadMergeCSV(strings.NewReader("name,role\nAda,dev\nBob,ops\nCy,dev\nDee,hr\n"))
+	require.NoError(t, err)
+	records = append(records, map[string]any{"name": "Eve"}, map[string]any{"name": "Skipper"})
+
+	var got []string
+	err = rd.MailMerge(records, MailMergeOptions{Output: func(doc *RootDoc, index int) error {
+		assert.Equal(t, len(got), index)
+		got = append(got, paragraphText(doc.Document.Body.Children[0].Para.GetCT())+" / "+
+			paragraphText(doc.Document.Body.Children[1].Para.GetCT()))
+		return nil
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Ada and Bob / Record 2", "Cy and Dee / Record 4"}, got)
+	require.Len(t, rd.Document.Body.Children, 2)
+	assert.Contains(t, paragraphText(rd.Document.Body.Children[0].Para.GetCT()), "«Name»")
+}
+
+func TestMailMerge_Strict(t *testing.T) {
+	rd := setupRootDoc(t)
+	rd.AddParagraph("").AddField(`MERGEFIELD "First Name"`, "«First Name»")
+
+	err := rd.MailMerge([]map[string]any{{"Name": "Ada"}}, MailMergeOptions{Strict: true})
+	assert.EqualError(t, err, `mail merge: record 1 has no column "First Name"`)
+
+	require.NoError(t, rd.MailMerge([]map[string]any{{"first name": "Ada"}}, MailMergeOptions{Strict: true}))
+	assert.Equal(t, "Ada", paragraphText(rd.Document.Body.Children[0].Para.GetCT()))
+}
+
+func TestMailMerge_FormatSwitch(t *testing.T) {
+	rd := setupRootDoc(t)
+	rd.AddParagraph("").AddField(`MERGEFIELD Name \* Upper \* MERGEFORMAT`, "«Name»")
+	rd.AddParagraph("").AddField(`MERGEFIELD Name \* CHARFORMAT`, "«Name»")
+
+	require.NoError(t, rd.MailMerge([]map[string]any{{"Name": "bob"}}, MailMergeOptions{}))
+	assert.Equal(t, "BOB", paragraphText(rd.Document.Body.Children[0].Para.GetCT()))
+	assert.Equal(t, "bob", paragraphText(rd.Document.Body.Children[1].Para.GetCT()))
+}
+
+func TestMailMerge_NestedValueWithSpaces(t *testing.T) {
+	rd := setupRootDoc(t)
+	addNestedField(rd.AddParagraph(""), "IF", "City", `= "New York" "NYC" "other"`)
+	addNestedField(rd.AddParagraph(""), "IF", "City", `= "Say \"hi\"" "quoted" "other"`)
+
+	require.NoError(t, rd.MailMerge([]map[string]any{{"City": "New York"}, {"City": `Say "hi"`}}, MailMergeOptions{}))
+	body := rd.Document.Body.Children
+	require.Len(t, body, 4)
+	assert.Equal(t, "NYC", paragraphText(body[0].Para.GetCT()))
+	assert.Equal(t, "other", paragraphText(body[1].Para.GetCT()))
+	assert.Equal(t, "other", paragraphText(body[2].Para.GetCT()))
+	assert.Equal(t, "quoted", paragraphText(body[3].Para.GetCT()))
+}
+
+func TestMailMerge_EmptyValue(t *testing.T) {
+	rd := setupRootDoc(t)
+	p := rd.AddParagraph("Hi ")
+	p.AddField(`MERGEFIELD Title \* FirstCap \f " "`, "«Title»")
+	p.AddText("Smith")
+
+	require.NoError(t, rd.MailMerge([]map[string]any{{"Title": ""}}, MailMergeOptions{}))
+	assert.Equal(t, "Hi Smith", paragraphText(rd.Document.Body.Children[0].Para.GetCT()))
+}
+
+func TestFieldArgs(t *testing.T) {
+	assert.Equal(t, []string{"IF", "a b", "=", `say "hi"`, ""},
+		fieldArgs(` IF "a b" = "say \"hi\"" "" `))
+}
+
+func TestCompareArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"10", ">", "9"}, true},
+		{[]string{"10", ">", "abc"}, false},
+		{[]string{"Smith", "=", "Sm*"}, true},
+		{[]string{"Smith", "<>", "S?ith"}, false},
+		{[]string{"b", ">=", "a"}, true},
+	}
+	for _, tt := range tests {
+		got, err := compareArgs(tt.args, "")
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, "%v", tt.args)
+	}
+
+	_, err := compareArgs([]string{"a", "~", "b"}, "IF a ~ b")
+	assert.EqualError(t, err, `mail merge: unknown operator "~" in field "IF a ~ b"`)
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		v       float64
+		picture string
+		want    string
+	}{
+		{1234.5, "#,##0.00", "1,234.50"},
+		{1234.5, "0", "1235"},
+		{0.5, "#,##0.##", "0.5"},
+		{-42, "$#,##0.00;($#,##0.00)", "($42.00)"},
+		{-42, "0.0", "-42.0"},
+		{7, "000", "007"},
+		{12.345, "0.0' units'", "12.3 units"},
+		{-0.001, "0.00", "0.00"},
+		{-0.004, "$0.00;($0.00)", "$0.00"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, formatNumber(tt.v, tt.picture), "%v %q", tt.v, tt.picture)
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	d := time.Date(2024, 3, 5, 14, 7, 0, 0, time.UTC)
+	assert.Equal(t, "05/03/2024 14:07", formatDate(d, "dd/MM/yyyy HH:mm"))
+	assert.Equal(t, "Tuesday, 5 Mar 24", formatDate(d, "dddd, d MMM yy"))
+	assert.Equal(t, "2:07 PM on the 5th", formatDate(d, "h:mm AM/PM 'on the' d'th'"))
+
+	tests := []struct {
+		t       time.Time
+		picture string
+		want    string
+	}{
+		{time.Date(2024, 3, 5, 9, 7, 0, 0, time.UTC), "H:mm", "9:07"},
+		{time.Date(2024, 3, 5, 9, 7, 0, 0, time.UTC), "HH:mm", "09:07"},
+		{time.Date(2024, 3, 5, 0, 7, 0, 0, time.UTC), "H:mm", "0:07"},
+		{time.Date(2024, 3, 5, 0, 7, 0, 0, time.UTC), "h:mm am/pm", "12:07 am"},
+		{time.Date(2024, 3, 5, 21, 7, 0, 0, time.UTC), "h:mm", "9:07"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, formatDate(tt.t, tt.picture), "%v %q", tt.t, tt.picture)
+	}
+}
+
+func TestApplyTextCase(t *testing.T) {
+	assert.Equal(t, "Ada lovelace", applyTextCase("ada lovelace", "FirstCap"))
+	assert.Equal(t, "Ada Lovelace", applyTextCase("ada lovelace", "Caps"))
+	assert.Equal(t, "Ronald McDonald  JR", applyTextCase("ronald McDonald  JR", "Caps"))
+	assert.Equal(t, "ada", applyTextCase("ADA", "Lower"))
+	assert.Equal(t, "Ada", applyTextCase("Ada", "MERGEFORMAT"))
+	assert.Equal(t, "", applyTextCase("", "FirstCap"))
+}
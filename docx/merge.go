@@ -0,0 +1,698 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+)
+
+// MergeOptions configures Merge3.
+type MergeOptions struct {
+	// Author is the author of the tracked changes and comments marking conflicts. It defaults
+	// to "godocx".
+	Author string
+
+	// Date is the date of the tracked changes and comments. They have no date if it is zero.
+	Date time.Time
+
+	// Ours and Theirs name the two sides in the comments marking conflicts. They default to
+	// "ours" and "theirs".
+	Ours, Theirs string
+}
+
+// Conflict is a part of a document that both sides of a merge changed in different ways.
+type Conflict struct {
+	// Location is where the conflict is in the merged document, such as "paragraph 4",
+	// "table 1, row 2, cell 3, paragraph 1" or "style Heading1", counting from 1.
+	Location string `json:"location"`
+
+	Base   string `json:"base,omitempty"`   // Base is the text of the common base.
+	Ours   string `json:"ours,omitempty"`   // Ours is the text of our side.
+	Theirs string `json:"theirs,omitempty"` // Theirs is the text of their side.
+}
+
+// Merge3 merges the changes made to two copies of a base document, ours and theirs, and
+// returns the merged document along with the conflicts found. None of the documents is
+// changed.
+//
+// Paragraphs, tables, table rows and cells, and content controls of both sides are aligned
+// with those of the base. A block changed on one side only takes that side's version, blocks
+// added on either side are added and blocks deleted on one side and left unchanged on the
+// other are deleted. The cells of a table are merged one by one, so that both sides may
+// change different cells of a row. Styles are merged the same way.
+//
+// A block that both sides changed in different ways is a conflict. The merged document holds
+// our version with tracked changes that turn it into theirs, so that accepting the changes
+// takes their version and rejecting them keeps ours, along with a comment explaining the
+// conflict. Content added by both sides at the same place is kept from both, theirs as a
+// tracked insertion. Styles changed by both sides keep our version and are listed only.
+//
+// The merged document is based on ours, numbering definitions included; content taken from
+// theirs is copied along with the images and hyperlinks it uses.
+func Merge3(base, ours, theirs *RootDoc, opts *MergeOptions) (*RootDoc, []Conflict, error) {
+	if opts == nil {
+		opts = &MergeOptions{}
+	}
+
+	merged, err := ours.copyPackage()
+	if err != nil {
+		return nil, nil, fmt.Errorf("merge: %w", err)
+	}
+
+	m := &threeWayMerger{
+		base:   base,
+		ours:   ours,
+		theirs: theirs,
+		im:     newImporter(theirs, merged),
+		c:      newComparer(merged, merged, opts.Author, opts.Date, ours, theirs),
+		anchor: make(map[*ctypes.Paragraph]int),
+	}
+	m.shareRelations()
+
+	blocks, err := m.blocks(bodyRefs(base.Document.Body), bodyRefs(ours.Document.Body), bodyRefs(theirs.Document.Body), "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("merge: %w", err)
+	}
+
+	body := merged.Document.Body
+	var anchors []*ctypes.Paragraph
+	for _, ref := range blocks {
+		switch {
+		case ref.para != nil:
+			p := newParagraph(merged)
+			p.ct = ref.para
+			body.Children = append(body.Children, DocumentChild{Para: p})
+			if i, ok := m.anchor[ref.para]; ok {
+				delete(m.anchor, ref.para)
+				m.anchor[p.ct] = i
+			}
+		case ref.tbl != nil:
+			t := NewTable(merged)
+			t.ct = ref.tbl
+			body.Children = append(body.Children, DocumentChild{Table: t})
+		case ref.sdt != nil:
+			ctl := &ContentControl{root: merged, ct: ref.sdt}
+			body.Children = append(body.Children, DocumentChild{Control: ctl})
+		}
+	}
+	if sectPr := ours.Document.Body.SectPr; sectPr != nil {
+		sp := internal.DeepCopy(*sectPr)
+		body.SectPr = &sp
+	}
+	merged.bookmarkID, merged.bookmarksScanned = m.c.lastID, true
+
+	// Conflicts are commented once the paragraphs are in place, in document order.
+	for _, p := range blockParagraphs(body.Children) {
+		if _, ok := m.anchor[p]; ok {
+			anchors = append(anchors, p)
+		}
+	}
+	ourName, theirName := opts.Ours, opts.Theirs
+	if ourName == "" {
+		ourName = "ours"
+	}
+	if theirName == "" {
+		theirName = "theirs"
+	}
+	for _, p := range anchors {
+		r := &Range{root: merged, spans: []rangeSpan{{para: p, end: len(paragraphText(p))}}}
+		text := fmt.Sprintf("Merge conflict: %s and %s changed this in different ways. Accept the tracked changes to take the version of %s, or reject them to keep the version of %s.",
+			ourName, theirName, theirName, ourName)
+		if _, err := r.AddComment(Comment{Author: m.c.author, Date: opts.Date, Text: text}); err != nil {
+			return nil, nil, fmt.Errorf("merge: %w", err)
+		}
+	}
+
+	m.styles(merged)
+	return merged, m.conflicts, nil
+}
+
+// threeWayMerger merges the content of two documents with a common base.
+type threeWayMerger struct {
+	base, ours, theirs *RootDoc
+
+	im *importer // im copies content of theirs into the merged document.
+	c  *comparer // c marks conflicts with tracked changes.
+
+	conflicts []Conflict
+	anchor    map[*ctypes.Paragraph]int // anchor holds the paragraphs to comment on.
+}
+
+// shareRelations lets content of theirs use the relationships of ours that point at the same
+// targets, which both usually inherit from the base, instead of copying them.
+func (m *threeWayMerger) shareRelations() {
+	for _, rel := range m.theirs.Document.DocRels.Relationships {
+		own := m.ours.Document.relationByID(rel.ID)
+		if own == nil || own.Type != rel.Type || own.Target != rel.Target || own.TargetMode != rel.TargetMode {
+			continue
+		}
+		if own.TargetMode != "External" {
+			_, a, okA := m.ours.imagePart(rel.ID)
+			_, b, okB := m.theirs.imagePart(rel.ID)
+			if !okA || !okB || string(a) != string(b) {
+				continue
+			}
+		}
+		m.im.ids[rel.ID] = rel.ID
+	}
+}
+
+// conflict records a conflict, to be commented on at the paragraph p if it is not nil.
+func (m *threeWayMerger) conflict(c Conflict, p *ctypes.Paragraph) {
+	m.conflicts = append(m.conflicts, c)
+	if p != nil {
+		m.anchor[p] = len(m.conflicts) - 1
+	}
+}
+
+// mergeStep is a step of a three-way merge: an item of the base along with the items of both
+// sides matching it, or the items both sides inserted at a place.
+type mergeStep struct {
+	base         int // base is the index of the base item, or -1 for inserted items.
+	ours, theirs int // ours and theirs are the matching items, or -1 if deleted.
+
+	oursIns, theirsIns []int
+}
+
+// mergeSteps combines the alignments of both sides with the base, which has n items. Items
+// inserted by a side come before the base item following them.
+func mergeSteps(n int, oursOps, theirsOps []alignOp) []mergeStep {
+	split := func(ops []alignOp) ([]int, [][]int) {
+		match := make([]int, n)
+		ins := make([][]int, n+1)
+		next := 0
+		for _, op := range ops {
+			if op.a < 0 {
+				ins[next] = append(ins[next], op.b)
+				continue
+			}
+			match[op.a] = op.b
+			next = op.a + 1
+		}
+		return match, ins
+	}
+	oursMatch, oursIns := split(oursOps)
+	theirsMatch, theirsIns := split(theirsOps)
+
+	var steps []mergeStep
+	for i := 0; i <= n; i++ {
+		if len(oursIns[i]) > 0 || len(theirsIns[i]) > 0 {
+			steps = append(steps, mergeStep{base: -1, ours: -1, theirs: -1, oursIns: oursIns[i], theirsIns: theirsIns[i]})
+		}
+		if i < n {
+			steps = append(steps, mergeStep{base: i, ours: oursMatch[i], theirs: theirsMatch[i]})
+		}
+	}
+	return steps
+}
+
+// alignBlocks aligns blocks of a side with those of the base.
+func alignBlocks(base, side []blockRef) []alignOp {
+	baseKeys, sideKeys := blockKeys(base), blockKeys(side)
+	baseWords, sideWords := blockWords(base), blockWords(side)
+	return diffSequences(len(base), len(side),
+		func(i, j int) bool { return baseKeys[i] == sideKeys[j] },
+		func(i, j int) bool {
+			a, b := base[i], side[j]
+			if (a.para != nil) != (b.para != nil) || (a.tbl != nil) != (b.tbl != nil) {
+				return false
+			}
+			if a.sdt != nil {
+				return b.sdt != nil && sdtTag(a.sdt) == sdtTag(b.sdt)
+			}
+			return similar(baseWords[i], sideWords[j])
+		})
+}
+
+// sameXML reports whether two values are written out the same way.
+func sameXML(a, b interface{}) bool {
+	x, errX := xml.Marshal(a)
+	y, errY := xml.Marshal(b)
+	return errX == nil && errY == nil && string(x) == string(y)
+}
+
+// blockText returns the text of a block.
+func blockText(ref blockRef) string {
+	switch {
+	case ref.para != nil:
+		return paragraphText(ref.para)
+	case ref.tbl != nil:
+		return tableText(ref.tbl)
+	case ref.sdt != nil:
+		return contentsText(ref.sdt.Contents)
+	}
+	return ""
+}
+
+// blocks merges lists of blocks of the three documents. loc is the location of the list, as
+// for comparer.blocks.
+func (m *threeWayMerger) blocks(base, ours, theirs []blockRef, loc string) ([]blockRef, error) {
+	steps := mergeSteps(len(base), alignBlocks(base, ours), alignBlocks(base, theirs))
+
+	var out []blockRef
+	paras, tables, controls := 0, 0, 0
+	// locate returns the location a block gets when it is added next.
+	locate := func(ref blockRef) string {
+		switch {
+		case ref.para != nil:
+			return fmt.Sprintf("%sparagraph %d", loc, paras+1)
+		case ref.tbl != nil:
+			return fmt.Sprintf("%stable %d", loc, tables+1)
+		default:
+			return fmt.Sprintf("%scontent control %d", loc, controls+1)
+		}
+	}
+	emit := func(ref blockRef) string {
+		l := locate(ref)
+		out = append(out, ref)
+		switch {
+		case ref.para != nil:
+			paras++
+		case ref.tbl != nil:
+			tables++
+		default:
+			controls++
+		}
+		return l
+	}
+
+	for _, step := range steps {
+		if step.base < 0 {
+			// Blocks inserted by both sides at the same place are kept from both, unless they
+			// are the same.
+			same := len(step.oursIns) == len(step.theirsIns)
+			for k := 0; same && k < len(step.oursIns); k++ {
+				same = sameBlock(ours[step.oursIns[k]], theirs[step.theirsIns[k]])
+			}
+			for _, j := range step.oursIns {
+				emit(m.ourBlock(ours[j]))
+			}
+			if same && len(step.oursIns) > 0 {
+				continue
+			}
+
+			conflict := len(step.oursIns) > 0
+			for k, j := range step.theirsIns {
+				ref, err := m.theirBlock(theirs[j])
+				if err != nil {
+					return nil, err
+				}
+				if !conflict {
+					emit(ref)
+					continue
+				}
+				if ref, err = m.redline(blockRef{}, ref, locate(ref)); err != nil {
+					return nil, err
+				}
+				l := emit(ref)
+				if k == 0 {
+					var text []string
+					for _, i := range step.oursIns {
+						text = append(text, blockText(ours[i]))
+					}
+					m.conflict(Conflict{Location: l, Ours: strings.Join(text, "\n"), Theirs: blockText(theirs[j])}, firstParagraph(ref))
+				}
+			}
+			continue
+		}
+
+		b := base[step.base]
+		var o, t blockRef
+		if step.ours >= 0 {
+			o = ours[step.ours]
+		}
+		if step.theirs >= 0 {
+			t = theirs[step.theirs]
+		}
+
+		switch {
+		case step.ours < 0 && step.theirs < 0:
+			// Deleted by both sides.
+		case step.ours < 0 || step.theirs < 0:
+			// Deleted by one side: the deletion wins over no change.
+			kept, keptOurs := o, true
+			if step.ours < 0 {
+				kept, keptOurs = t, false
+			}
+			if sameBlock(b, kept) {
+				continue
+			}
+
+			var ref blockRef
+			var err error
+			if keptOurs {
+				ref, err = m.redline(m.ourBlock(o), blockRef{}, locate(o))
+			} else {
+				if ref, err = m.theirBlock(t); err == nil {
+					ref, err = m.redline(blockRef{}, ref, locate(ref))
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+			conflict := Conflict{Location: emit(ref), Base: blockText(b)}
+			if keptOurs {
+				conflict.Ours = blockText(o)
+			} else {
+				conflict.Theirs = blockText(t)
+			}
+			m.conflict(conflict, firstParagraph(ref))
+		default:
+			ref, err := m.block(b, o, t, locate(o))
+			if err != nil {
+				return nil, err
+			}
+			emit(ref)
+		}
+	}
+	return out, nil
+}
+
+// block merges a block of the base with the matching blocks of both sides.
+func (m *threeWayMerger) block(b, o, t blockRef, loc string) (blockRef, error) {
+	switch {
+	case sameBlock(b, t) || sameBlock(o, t):
+		return m.ourBlock(o), nil
+	case sameBlock(b, o):
+		return m.theirBlock(t)
+	}
+
+	switch {
+	case o.tbl != nil:
+		tbl, err := m.table(b.tbl, o.tbl, t.tbl, loc)
+		return blockRef{tbl: tbl}, err
+	case o.sdt != nil:
+		contents, err := m.blocks(contentRefs(b.sdt.Contents), contentRefs(o.sdt.Contents), contentRefs(t.sdt.Contents), loc+", ")
+		if err != nil {
+			return blockRef{}, err
+		}
+		sdt := &ctypes.SDT{Property: internal.DeepCopy(o.sdt.Property), EndProperty: internal.DeepCopy(o.sdt.EndProperty), Contents: refContents(contents)}
+		return blockRef{sdt: sdt}, nil
+	}
+
+	theirs, err := m.theirBlock(t)
+	if err != nil {
+		return blockRef{}, err
+	}
+	ref, err := m.redline(m.ourBlock(o), theirs, loc)
+	if err != nil {
+		return blockRef{}, err
+	}
+	m.conflict(Conflict{Location: loc, Base: blockText(b), Ours: blockText(o), Theirs: blockText(t)}, ref.para)
+	return ref, nil
+}
+
+// table merges a table of the base with the matching tables of both sides, row by row.
+func (m *threeWayMerger) table(b, o, t *ctypes.Table, loc string) (*ctypes.Table, error) {
+	baseRows, ourRows, theirRows := tableRows(b), tableRows(o), tableRows(t)
+	align := func(side []*ctypes.Row) []alignOp {
+		return diffSequences(len(baseRows), len(side),
+			func(i, j int) bool { return rowText(baseRows[i]) == rowText(side[j]) },
+			func(i, j int) bool {
+				return len(baseRows[i].Contents) == len(side[j].Contents) &&
+					similar(words(rowText(baseRows[i])), words(rowText(side[j])))
+			})
+	}
+	steps := mergeSteps(len(baseRows), align(ourRows), align(theirRows))
+
+	out := &ctypes.Table{TableProp: internal.DeepCopy(o.TableProp), Grid: internal.DeepCopy(o.Grid)}
+	next := func() string { return fmt.Sprintf("%s, row %d", loc, len(out.RowContents)+1) }
+	add := func(rows ...*ctypes.Row) {
+		for _, row := range rows {
+			out.RowContents = append(out.RowContents, ctypes.RowContent{Row: row})
+		}
+	}
+	theirRow := func(row *ctypes.Row) (*ctypes.Row, error) {
+		ref, err := m.theirBlock(blockRef{tbl: &ctypes.Table{RowContents: []ctypes.RowContent{{Row: row}}}})
+		if err != nil {
+			return nil, err
+		}
+		return ref.tbl.RowContents[0].Row, nil
+	}
+	// redlineRow marks a row of ours as deleted or one of theirs as inserted.
+	redlineRow := func(ourRow, theirRow *ctypes.Row) (*ctypes.Row, error) {
+		m.c.quiet++
+		defer func() { m.c.quiet-- }()
+		return m.c.row(ourRow, theirRow, next())
+	}
+
+	for _, step := range steps {
+		if step.base < 0 {
+			same := len(step.oursIns) == len(step.theirsIns)
+			for k := 0; same && k < len(step.oursIns); k++ {
+				same = sameXML(ourRows[step.oursIns[k]], theirRows[step.theirsIns[k]])
+			}
+			for _, j := range step.oursIns {
+				add(ourRows[j].Clone())
+			}
+			if same && len(step.oursIns) > 0 {
+				continue
+			}
+
+			conflict := len(step.oursIns) > 0
+			for k, j := range step.theirsIns {
+				row, err := theirRow(theirRows[j])
+				if err != nil {
+					return nil, err
+				}
+				if conflict {
+					l := next()
+					if row, err = redlineRow(nil, row); err != nil {
+						return nil, err
+					}
+					if k == 0 {
+						m.conflict(Conflict{Location: l, Theirs: rowText(theirRows[j])}, firstParagraph(blockRef{tbl: &ctypes.Table{RowContents: []ctypes.RowContent{{Row: row}}}}))
+					}
+				}
+				add(row)
+			}
+			continue
+		}
+
+		br := baseRows[step.base]
+		switch {
+		case step.ours < 0 && step.theirs < 0:
+		case step.ours < 0 || step.theirs < 0:
+			kept, keptOurs := (*ctypes.Row)(nil), step.theirs < 0
+			if keptOurs {
+				kept = ourRows[step.ours]
+			} else {
+				kept = theirRows[step.theirs]
+			}
+			if sameXML(br, kept) {
+				continue
+			}
+
+			l := next()
+			var row *ctypes.Row
+			var err error
+			conflict := Conflict{Location: l, Base: rowText(br)}
+			if keptOurs {
+				row, err = redlineRow(kept.Clone(), nil)
+				conflict.Ours = rowText(kept)
+			} else if row, err = theirRow(kept); err == nil {
+				row, err = redlineRow(nil, row)
+				conflict.Theirs = rowText(kept)
+			}
+			if err != nil {
+				return nil, err
+			}
+			add(row)
+			m.conflict(conflict, firstParagraph(blockRef{tbl: &ctypes.Table{RowContents: []ctypes.RowContent{{Row: row}}}}))
+		default:
+			or, tr := ourRows[step.ours], theirRows[step.theirs]
+			switch {
+			case sameXML(br, tr) || sameXML(or, tr):
+				add(or.Clone())
+			case sameXML(br, or):
+				row, err := theirRow(tr)
+				if err != nil {
+					return nil, err
+				}
+				add(row)
+			case len(br.Contents) == len(or.Contents) && len(br.Contents) == len(tr.Contents):
+				row, err := m.row(br, or, tr, next())
+				if err != nil {
+					return nil, err
+				}
+				add(row)
+			default:
+				// Rows whose cells do not match up conflict as a whole.
+				l := next()
+				deleted, err := redlineRow(or.Clone(), nil)
+				if err != nil {
+					return nil, err
+				}
+				inserted, err := theirRow(tr)
+				if err == nil {
+					inserted, err = redlineRow(nil, inserted)
+				}
+				if err != nil {
+					return nil, err
+				}
+				add(deleted, inserted)
+				m.conflict(Conflict{Location: l, Base: rowText(br), Ours: rowText(or), Theirs: rowText(tr)},
+					firstParagraph(blockRef{tbl: &ctypes.Table{RowContents: []ctypes.RowContent{{Row: inserted}}}}))
+			}
+		}
+	}
+	return out, nil
+}
+
+// row merges a row of the base with the matching rows of both sides, cell by cell.
+func (m *threeWayMerger) row(b, o, t *ctypes.Row, loc string) (*ctypes.Row, error) {
+	out := &ctypes.Row{PropException: internal.DeepCopy(o.PropException), Property: internal.DeepCopy(o.Property)}
+	for i, cc := range o.Contents {
+		if cc.Cell == nil || b.Contents[i].Cell == nil || t.Contents[i].Cell == nil {
+			out.Contents = append(out.Contents, internal.DeepCopy(cc))
+			continue
+		}
+
+		contents, err := m.blocks(contentRefs(b.Contents[i].Cell.Contents), contentRefs(cc.Cell.Contents),
+			contentRefs(t.Contents[i].Cell.Contents), fmt.Sprintf("%s, cell %d, ", loc, i+1))
+		if err != nil {
+			return nil, err
+		}
+		cell := &ctypes.Cell{Property: internal.DeepCopy(cc.Cell.Property), Contents: refContents(contents)}
+		out.Contents = append(out.Contents, ctypes.TRCellContent{Cell: cell})
+	}
+	return out, nil
+}
+
+// redline returns a block of ours (or nothing) with tracked changes turning it into a block of
+// theirs (or nothing), both already in the merged document.
+func (m *threeWayMerger) redline(o, t blockRef, loc string) (blockRef, error) {
+	m.c.quiet++
+	defer func() { m.c.quiet-- }()
+
+	switch {
+	case o.para != nil || t.para != nil:
+		p, err := m.c.paragraph(o.para, t.para, loc)
+		return blockRef{para: p}, err
+	case o.tbl != nil || t.tbl != nil:
+		tbl, err := m.c.table(o.tbl, t.tbl, loc)
+		return blockRef{tbl: tbl}, err
+	default:
+		sdt, err := m.c.control(o.sdt, t.sdt, loc+", ")
+		return blockRef{sdt: sdt}, err
+	}
+}
+
+// ourBlock returns a copy of a block of ours.
+func (m *threeWayMerger) ourBlock(ref blockRef) blockRef {
+	switch {
+	case ref.para != nil:
+		return blockRef{para: ref.para.Clone()}
+	case ref.tbl != nil:
+		return blockRef{tbl: ref.tbl.Clone()}
+	default:
+		sdt := internal.DeepCopy(*ref.sdt)
+		return blockRef{sdt: &sdt}
+	}
+}
+
+// theirBlock returns a copy of a block of theirs, with the images and hyperlinks it uses.
+func (m *threeWayMerger) theirBlock(ref blockRef) (blockRef, error) {
+	out := m.ourBlock(ref)
+	var paras []*ctypes.Paragraph
+	switch {
+	case out.para != nil:
+		paras = []*ctypes.Paragraph{out.para}
+	case out.tbl != nil:
+		paras = tableParagraphs(nil, out.tbl)
+	default:
+		paras = contentParagraphs(nil, out.sdt.Contents)
+	}
+	for _, p := range paras {
+		if err := m.im.paragraph(p); err != nil {
+			return blockRef{}, err
+		}
+	}
+	return out, nil
+}
+
+// sameBlock reports whether two blocks are the same, formatting included.
+func sameBlock(a, b blockRef) bool {
+	switch {
+	case a.para != nil && b.para != nil:
+		return sameXML(a.para, b.para)
+	case a.tbl != nil && b.tbl != nil:
+		return sameXML(a.tbl, b.tbl)
+	case a.sdt != nil && b.sdt != nil:
+		return sameXML(a.sdt, b.sdt)
+	}
+	return false
+}
+
+// firstParagraph returns the first paragraph of a block.
+func firstParagraph(ref blockRef) *ctypes.Paragraph {
+	var paras []*ctypes.Paragraph
+	switch {
+	case ref.para != nil:
+		return ref.para
+	case ref.tbl != nil:
+		paras = tableParagraphs(nil, ref.tbl)
+	case ref.sdt != nil:
+		paras = contentParagraphs(nil, ref.sdt.Contents)
+	}
+	if len(paras) == 0 {
+		return nil
+	}
+	return paras[0]
+}
+
+// styles merges the styles of theirs into the merged document.
+func (m *threeWayMerger) styles(merged *RootDoc) {
+	if m.base.DocStyles == nil || m.theirs.DocStyles == nil || merged.DocStyles == nil {
+		return
+	}
+
+	byID := func(styles *ctypes.Styles) map[string]*ctypes.Style {
+		out := make(map[string]*ctypes.Style)
+		for i := range styles.StyleList {
+			if s := &styles.StyleList[i]; s.ID != nil {
+				out[*s.ID] = s
+			}
+		}
+		return out
+	}
+	base, theirs := byID(m.base.DocStyles), byID(m.theirs.DocStyles)
+	ours := byID(merged.DocStyles)
+
+	var list []ctypes.Style
+	for _, s := range merged.DocStyles.StyleList {
+		if s.ID == nil {
+			list = append(list, s)
+			continue
+		}
+		id := *s.ID
+		b, t := base[id], theirs[id]
+		switch {
+		case b != nil && t == nil:
+			// Deleted by theirs: the deletion wins over no change.
+			if reflect.DeepEqual(*b, s) {
+				continue
+			}
+		case t == nil || reflect.DeepEqual(s, *t):
+		case b != nil && reflect.DeepEqual(*b, *t):
+		case b != nil && reflect.DeepEqual(*b, s):
+			s = internal.DeepCopy(*t)
+		default:
+			m.conflicts = append(m.conflicts, Conflict{Location: "style " + id})
+		}
+		list = append(list, s)
+	}
+
+	// Styles added by theirs.
+	for _, t := range m.theirs.DocStyles.StyleList {
+		if t.ID != nil && base[*t.ID] == nil && ours[*t.ID] == nil {
+			list = append(list, internal.DeepCopy(t))
+		}
+	}
+	merged.DocStyles.StyleList = list
+}
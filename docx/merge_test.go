@@ -0,0 +1,148 @@
+package docx_test
+
+import (
+	"bytes"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mergeVersion is a version of the document used by the merge tests.
+type mergeVersion struct {
+	added               string // added is a paragraph added after the first one, if set.
+	payment, term, law  string // payment, term and law are left out if empty.
+	widget, widgetPrice string
+	gadget              string
+}
+
+var mergeBase = mergeVersion{
+	payment:     "Payment is due within 30 days.",
+	term:        "The term is one year.",
+	law:         "Governing law: Delaware.",
+	widget:      "Widget",
+	widgetPrice: "10",
+	gadget:      "Gadget",
+}
+
+func buildMergeDoc(t *testing.T, v mergeVersion) *docx.RootDoc {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+	rd.AddParagraph("Scope of work.")
+	if v.added != "" {
+		rd.AddParagraph(v.added)
+	}
+	for _, text := range []string{v.payment, v.term, v.law} {
+		if text != "" {
+			rd.AddParagraph(text)
+		}
+	}
+
+	tbl := rd.AddTable()
+	for _, cells := range [][2]string{{"Item", "Price"}, {v.widget, v.widgetPrice}, {v.gadget, "20"}} {
+		row := tbl.AddRow()
+		row.AddCell().AddParagraph(cells[0])
+		row.AddCell().AddParagraph(cells[1])
+	}
+	return rd
+}
+
+func TestMerge3(t *testing.T) {
+	base := buildMergeDoc(t, mergeBase)
+
+	v := mergeBase
+	v.added = "Ours adds this clause."
+	v.payment = "Payment is due within 45 days."
+	v.law = "Governing law: New York."
+	v.widgetPrice = "12"
+	ours := buildMergeDoc(t, v)
+
+	v = mergeBase
+	v.term = "The term is two years."
+	v.law = "Governing law: Texas."
+	v.widget = "Widget Pro"
+	v.gadget = "Gizmo"
+	theirs := buildMergeDoc(t, v)
+	theirs.SetStyle(ctypes.Style{ID: internal.ToPtr("Note"), Type: internal.ToPtr(stypes.StyleTypeParagraph)})
+
+	merged, conflicts, err := docx.Merge3(base, ours, theirs, &docx.MergeOptions{Author: "Merge bot", Ours: "team A", Theirs: "team B"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []docx.Conflict{{
+		Location: "paragraph 5",
+		Base:     "Governing law: Delaware.",
+		Ours:     "Governing law: New York.",
+		Theirs:   "Governing law: Texas.",
+	}}, conflicts)
+
+	children := merged.Document.Body.Children
+	require.Len(t, children, 6)
+	assert.Equal(t, "Ours adds this clause.", children[1].Para.Text())
+	assert.Equal(t, "Payment is due within 45 days.", children[2].Para.Text())
+	assert.Equal(t, "The term is two years.", children[3].Para.Text())
+	assert.Equal(t, "Governing law: Texas.", children[4].Para.Text(), "the conflict reads as theirs with its changes accepted")
+
+	var cells []string
+	for _, row := range children[5].Table.Rows() {
+		for _, cell := range row.Cells() {
+			cells = append(cells, cell.Paragraphs()[0].Text())
+		}
+	}
+	assert.Equal(t, []string{"Item", "Price", "Widget Pro", "12", "Gizmo", "20"}, cells)
+	assert.NotNil(t, merged.GetStyleByID("Note", stypes.StyleTypeParagraph))
+
+	var buf bytes.Buffer
+	require.NoError(t, merged.Write(&buf))
+	files := readZip(t, buf.Bytes())
+	doc := string(files["word/document.xml"])
+	assert.Contains(t, doc, `<w:delText>New York</w:delText>`)
+	assert.Contains(t, doc, `w:author="Merge bot"><w:r><w:t>Texas</w:t></w:r></w:ins>`)
+	assert.Contains(t, doc, `<w:commentRangeStart w:id="0">`)
+	assert.Contains(t, string(files["word/comments.xml"]), "team A and team B changed this in different ways")
+}
+
+func TestMerge3_DeleteAndInsert(t *testing.T) {
+	base := buildMergeDoc(t, mergeBase)
+
+	// Ours deletes a paragraph theirs changes and one theirs leaves alone; both add a paragraph
+	// at the same place.
+	v := mergeBase
+	v.added = "Ours adds this clause."
+	v.term, v.law = "", ""
+	ours := buildMergeDoc(t, v)
+
+	v = mergeBase
+	v.added = "Theirs adds another clause."
+	v.term = "The term is two years."
+	theirs := buildMergeDoc(t, v)
+
+	merged, conflicts, err := docx.Merge3(base, ours, theirs, nil)
+	require.NoError(t, err)
+
+	var texts []string
+	for _, child := range merged.Document.Body.Children {
+		if child.Para != nil {
+			texts = append(texts, child.Para.Text())
+		}
+	}
+	assert.Equal(t, []string{
+		"Scope of work.",
+		"Ours adds this clause.",
+		"Theirs adds another clause.",
+		"Payment is due within 30 days.",
+		"The term is two years.",
+	}, texts)
+	assert.Equal(t, []docx.Conflict{
+		{Location: "paragraph 3", Ours: "Ours adds this clause.", Theirs: "Theirs adds another clause."},
+		{Location: "paragraph 5", Base: "The term is one year.", Theirs: "The term is two years."},
+	}, conflicts)
+
+	_, conflicts, err = docx.Merge3(base, base, theirs, nil)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+}
@@ -120,21 +120,43 @@ func (ni NumInstance) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	return e.EncodeToken(xml.EndElement{Name: start.Name})
 }
 
+// copyFor returns a copy of the manager for root, a copy of the document of the manager.
+func (nm *NumberingManager) copyFor(root *RootDoc) *NumberingManager {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	c := NewNumberingManager(root)
+	for _, inst := range nm.numbering.Instances {
+		instance := *inst
+		c.numbering.Instances = append(c.numbering.Instances, &instance)
+	}
+	c.nextNumId = nm.nextNumId
+	return c
+}
+
 // applyToFileMap injects generated numbering instances into the existing numbering.xml
-// within the root document's file map. It preserves existing abstract numbering definitions
-// from the template and appends only the new w:num instance elements.
+// within the root document's file map.
 func (nm *NumberingManager) applyToFileMap() error {
+	if content, ok := nm.numberingPart(); ok {
+		nm.rootDoc.FileMap.Store(numberingPath, content)
+	}
+	return nil
+}
+
+// numberingPart returns the content of numbering.xml with the generated numbering instances,
+// and false if there are none, leaving the file map as it is. It preserves existing abstract
+// numbering definitions from the template and appends only the new w:num instance elements.
+func (nm *NumberingManager) numberingPart() ([]byte, bool) {
 	nm.mu.Lock()
 	defer nm.mu.Unlock()
 
 	if len(nm.numbering.Instances) == 0 || nm.rootDoc == nil {
-		return nil
+		return nil, false
 	}
 
 	// Determine which instances are already present to avoid duplicates
 	// Collect existing numIds from current numbering.xml content if present
 	existingIDs := make(map[int]struct{})
-	const numberingPath = "word/numbering.xml"
 	if existing, ok := nm.rootDoc.FileMap.Load(numberingPath); ok {
 		content := string(existing.([]byte))
 		idRe := regexp.MustCompile(`w:numId=\"(\d+)\"`)
@@ -171,25 +193,21 @@ func (nm *NumberingManager) applyToFileMap() error {
 		content = nm.ensureMultilevelAbstracts(content)
 		if instancesXML == "" {
 			// Nothing new to add
-			nm.rootDoc.FileMap.Store(numberingPath, []byte(content))
-			return nil
+			return []byte(content), true
 		}
 		if strings.Contains(content, "</w:numbering>") {
 			updated := strings.Replace(content, "</w:numbering>", instancesXML+"</w:numbering>", 1)
-			nm.rootDoc.FileMap.Store(numberingPath, []byte(updated))
-			return nil
+			return []byte(updated), true
 		}
 		// Fallback: if unexpected structure, append instances at end
-		nm.rootDoc.FileMap.Store(numberingPath, []byte(content+instancesXML))
-		return nil
+		return []byte(content + instancesXML), true
 	}
 
 	// If numbering.xml doesn't exist (unlikely with the default template), create a minimal one
 	minimal := `<?xml version="1.0" encoding="UTF-8"?>` +
 		`<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
 		nm.multilevelAbstractsXML() + instancesXML + `</w:numbering>`
-	nm.rootDoc.FileMap.Store(numberingPath, []byte(minimal))
-	return nil
+	return []byte(minimal), true
 }
 
 // normalizeAbstract maps simple ids used by API to internal multilevel abstract ids.
@@ -207,7 +225,6 @@ func (nm *NumberingManager) normalizeAbstract(abstractNumId int) int {
 
 // ensureNextNumIdFromTemplate raises nextNumId above any existing numIds in the template
 func (nm *NumberingManager) ensureNextNumIdFromTemplate() {
-	const numberingPath = "word/numbering.xml"
 	existing, ok := nm.rootDoc.FileMap.Load(numberingPath)
 	if !ok {
 		return
@@ -78,7 +78,7 @@ func TestNumberingResetsBetweenInstancesSameAbstract(t *testing.T) {
 	}
 
 	// Attach numbering to paragraphs to ensure they reference the correct instance ids
-	p1 := &Paragraph{root: root}
+	p1 := newParagraph(root)
 	p1.Numbering(id1, 0)
 	if p1.ct.Property == nil || p1.ct.Property.NumProp == nil || p1.ct.Property.NumProp.NumID == nil {
 		t.Fatalf("Paragraph 1 numbering not set")
@@ -87,7 +87,7 @@ func TestNumberingResetsBetweenInstancesSameAbstract(t *testing.T) {
 		t.Fatalf("Paragraph 1 expected NumID %d, got %d", id1, got)
 	}
 
-	p2 := &Paragraph{root: root}
+	p2 := newParagraph(root)
 	p2.Numbering(id2, 0)
 	if p2.ct.Property == nil || p2.ct.Property.NumProp == nil || p2.ct.Property.NumProp.NumID == nil {
 		t.Fatalf("Paragraph 2 numbering not set")
@@ -116,28 +116,28 @@ func TestNumbering_MultilevelAndBulletsXML(t *testing.T) {
 
 	// Ordered A (abstract 1 → 201)
 	ordA := rd.NewListInstance(1)
-	(&Paragraph{root: rd}).Numbering(ordA, 0)
-	(&Paragraph{root: rd}).Numbering(ordA, 1)
-	(&Paragraph{root: rd}).Numbering(ordA, 1)
-	(&Paragraph{root: rd}).Numbering(ordA, 2)
-	(&Paragraph{root: rd}).Numbering(ordA, 2)
-	(&Paragraph{root: rd}).Numbering(ordA, 3)
-	(&Paragraph{root: rd}).Numbering(ordA, 0)
+	newParagraph(rd).Numbering(ordA, 0)
+	newParagraph(rd).Numbering(ordA, 1)
+	newParagraph(rd).Numbering(ordA, 1)
+	newParagraph(rd).Numbering(ordA, 2)
+	newParagraph(rd).Numbering(ordA, 2)
+	newParagraph(rd).Numbering(ordA, 3)
+	newParagraph(rd).Numbering(ordA, 0)
 
 	// Ordered B (reset numbering)
 	ordB := rd.NewListInstance(1)
-	(&Paragraph{root: rd}).Numbering(ordB, 0)
-	(&Paragraph{root: rd}).Numbering(ordB, 0)
+	newParagraph(rd).Numbering(ordB, 0)
+	newParagraph(rd).Numbering(ordB, 0)
 
 	// Bullets C (abstract 2 → 202)
 	bulC := rd.NewListInstance(2)
-	(&Paragraph{root: rd}).Numbering(bulC, 0)
-	(&Paragraph{root: rd}).Numbering(bulC, 1)
-	(&Paragraph{root: rd}).Numbering(bulC, 1)
-	(&Paragraph{root: rd}).Numbering(bulC, 2)
-	(&Paragraph{root: rd}).Numbering(bulC, 2)
-	(&Paragraph{root: rd}).Numbering(bulC, 3)
-	(&Paragraph{root: rd}).Numbering(bulC, 0)
+	newParagraph(rd).Numbering(bulC, 0)
+	newParagraph(rd).Numbering(bulC, 1)
+	newParagraph(rd).Numbering(bulC, 1)
+	newParagraph(rd).Numbering(bulC, 2)
+	newParagraph(rd).Numbering(bulC, 2)
+	newParagraph(rd).Numbering(bulC, 3)
+	newParagraph(rd).Numbering(bulC, 0)
 
 	// Generate numbering.xml in the FileMap
 	if err := rd.Numbering.applyToFileMap(); err != nil {
@@ -5,7 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
-	"strings"
+	"regexp"
+	"strconv"
 
 	"github.com/gomutex/godocx/common/constants"
 	"github.com/gomutex/godocx/common/units"
@@ -19,8 +20,8 @@ import (
 
 // Paragraph represents a paragraph in a DOCX document.
 type Paragraph struct {
-	root *RootDoc         // root is a reference to the root document.
-	ct   ctypes.Paragraph // ct holds the underlying Paragraph Complex Type.
+	root *RootDoc          // root is a reference to the root document.
+	ct   *ctypes.Paragraph // ct holds the underlying Paragraph Complex Type.
 }
 
 func (p *Paragraph) unmarshalXML(d *xml.Decoder, start xml.StartElement) error {
@@ -42,6 +43,7 @@ type paraOption func(*Paragraph)
 func newParagraph(root *RootDoc, opts ...paraOption) *Paragraph {
 	p := &Paragraph{
 		root: root,
+		ct:   &ctypes.Paragraph{},
 	}
 	for _, opt := range opts {
 		opt(p)
@@ -49,6 +51,11 @@ func newParagraph(root *RootDoc, opts ...paraOption) *Paragraph {
 	return p
 }
 
+// NewParagraph creates an empty paragraph that is not yet part of the document body.
+func NewParagraph(root *RootDoc) *Paragraph {
+	return newParagraph(root)
+}
+
 // paraWithText is an option for adding text to a Paragraph.
 func paraWithText(text string) paraOption {
 	return func(p *Paragraph) {
@@ -64,7 +71,13 @@ func (p *Paragraph) ensureProp() {
 
 // GetCT returns a pointer to the underlying Paragraph Complex Type.
 func (p *Paragraph) GetCT() *ctypes.Paragraph {
-	return &p.ct
+	return p.ct
+}
+
+// Text returns the plain text of the paragraph, including the text of its hyperlinks.
+// Tabs are returned as "\t" and line breaks as "\n".
+func (p *Paragraph) Text() string {
+	return paragraphText(p.ct)
 }
 
 // AddParagraph adds a new paragraph with the specified text to the document.
@@ -201,7 +214,9 @@ func (p *Paragraph) AddText(text string) *Run {
 
 	p.ct.Children = append(p.ct.Children, ctypes.ParagraphChild{Run: run})
 
-	return newRun(p.root, run)
+	r := newRun(p.root, run)
+	r.para = p.ct
+	return r
 }
 
 // AddEmptyParagraph adds a new empty paragraph to the document.
@@ -226,7 +241,9 @@ func (p *Paragraph) AddRun() *Run {
 
 	p.ct.Children = append(p.ct.Children, ctypes.ParagraphChild{Run: run})
 
-	return newRun(p.root, run)
+	r := newRun(p.root, run)
+	r.para = p.ct
+	return r
 }
 
 // GetStyle retrieves the style information applied to the Paragraph.
@@ -284,9 +301,18 @@ func (p *Paragraph) AddLink(text string, link string) *Hyperlink {
 // Returns:
 //   - *dml.Inline: The created Inline instance representing the added drawing.
 func (p *Paragraph) addDrawing(rID string, imgCount uint, width units.Inch, height units.Inch) *dml.Inline {
-	eWidth := width.ToEmu()
-	eHeight := height.ToEmu()
+	return p.addInlineDrawing(rID, imgCount, width.ToEmu(), height.ToEmu())
+}
+
+// addInlineDrawing adds a run holding an inline picture of the given size in EMUs to the Paragraph.
+func (p *Paragraph) addInlineDrawing(rID string, imgCount uint, eWidth units.Emu, eHeight units.Emu) *dml.Inline {
+	run, inline := newDrawingRun(rID, imgCount, eWidth, eHeight)
+	p.ct.Children = append(p.ct.Children, ctypes.ParagraphChild{Run: run})
+	return inline
+}
 
+// newDrawingRun returns a run holding an inline picture, along with the inline object of the run.
+func newDrawingRun(rID string, imgCount uint, eWidth units.Emu, eHeight units.Emu) (*ctypes.Run, *dml.Inline) {
 	inline := dml.NewInline(
 		*dmlct.NewPostvSz2D(eWidth, eHeight),
 		dml.DocProp{
@@ -296,22 +322,14 @@ func (p *Paragraph) addDrawing(rID string, imgCount uint, width units.Inch, heig
 		*dml.NewPicGraphic(dmlpic.NewPic(rID, imgCount, eWidth, eHeight)),
 	)
 
-	runChildren := []ctypes.RunChild{}
 	drawing := &dml.Drawing{}
-
 	drawing.Inline = append(drawing.Inline, inline)
 
-	runChildren = append(runChildren, ctypes.RunChild{
-		Drawing: drawing,
-	})
-
 	run := &ctypes.Run{
-		Children: runChildren,
+		Children: []ctypes.RunChild{{Drawing: drawing}},
 	}
 
-	p.ct.Children = append(p.ct.Children, ctypes.ParagraphChild{Run: run})
-
-	return &inline
+	return run, &drawing.Inline[0]
 }
 
 func (p *Paragraph) AddPicture(path string, width units.Inch, height units.Inch) (*PicMeta, error) {
@@ -321,38 +339,169 @@ func (p *Paragraph) AddPicture(path string, width units.Inch, height units.Inch)
 		return nil, err
 	}
 
-	imgExt := filepath.Ext(path)
-	p.root.ImageCount += 1
-	fileName := fmt.Sprintf("image%d%s", p.root.ImageCount, imgExt)
-	fileIdxPath := fmt.Sprintf("%s%s", constants.MediaPath, fileName)
+	return p.AddPictureFromBytes(imgBytes, filepath.Ext(path), width, height)
+}
 
-	imgExtStripDot := strings.TrimPrefix(imgExt, ".")
-	imgMIME, err := MIMEFromExt(imgExtStripDot)
+// AddPictureFromBytes adds an image held in memory to the Paragraph.
+//
+// Parameters:
+//   - data: The content of the image file.
+//   - ext: The file extension of the image format, with or without the leading dot (e.g. "png" or ".jpg").
+//   - width: The width of the image in inches.
+//   - height: The height of the image in inches.
+//
+// Returns:
+//   - *PicMeta: Metadata about the added picture, including the Paragraph instance and Inline element.
+//   - error: An error, if the image format is not supported.
+func (p *Paragraph) AddPictureFromBytes(data []byte, ext string, width units.Inch, height units.Inch) (*PicMeta, error) {
+	rID, n, err := p.root.addImagePart(data, ext)
 	if err != nil {
 		return nil, err
 	}
 
-	err = p.root.ContentType.AddExtension(imgExtStripDot, imgMIME)
-	if err != nil {
-		return nil, err
+	inline := p.addDrawing(rID, n, width, height)
+
+	return &PicMeta{
+		Para:   p,
+		Inline: inline,
+	}, nil
+}
+
+// AddBookmark makes a bookmark of the given name spanning the content of the Paragraph,
+// which internal hyperlinks can point to. It returns the ID of the bookmark, which is unique
+// within the document.
+func (p *Paragraph) AddBookmark(name string) int {
+	id := p.root.newBookmarkID()
+
+	children := make([]ctypes.ParagraphChild, 0, len(p.ct.Children)+2)
+	children = append(children, ctypes.ParagraphChild{BmkStart: &ctypes.BookmarkStart{ID: id, Name: name}})
+	children = append(children, p.ct.Children...)
+	p.ct.Children = append(children, ctypes.ParagraphChild{BmkEnd: &ctypes.Markup{ID: id}})
+	return id
+}
+
+// newBookmarkID returns an ID for a new bookmark, greater than those of the bookmarks of the
+// body, headers, footers, notes and comments and of the bookmarks added so far.
+func (rd *RootDoc) newBookmarkID() int {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+
+	if !rd.bookmarksScanned {
+		rd.bookmarksScanned = true
+		rd.bookmarkID = rd.maxBookmarkID()
 	}
 
-	overridePart := fmt.Sprintf("/%s%s", constants.MediaPath, fileName)
-	err = p.root.ContentType.AddOverride(overridePart, imgMIME)
-	if err != nil {
-		return nil, err
+	rd.bookmarkID++
+	return rd.bookmarkID
+}
+
+var bookmarkStartID = regexp.MustCompile(`<w:bookmarkStart\b[^>]*\bw:id="(\d+)"`)
+
+// maxBookmarkID returns the greatest ID of the bookmarks of the document. Story parts that
+// have not been loaded are scanned in their XML, so that scanning does not load them.
+func (rd *RootDoc) maxBookmarkID() int {
+	maxID := 0
+	scan := func(blocks []DocumentChild) {
+		for _, para := range blockParagraphs(blocks) {
+			if id := maxChildBookmarkID(para.Children); id > maxID {
+				maxID = id
+			}
+		}
 	}
+	scan(rd.Document.Body.Children)
+
+	if rd.storiesLoaded {
+		for _, part := range rd.storyParts {
+			for _, s := range part.stories {
+				scan(s.Children)
+			}
+		}
+		return maxID
+	}
+
+	for _, rel := range rd.Document.DocRels.Relationships {
+		if _, ok := storyRelTypes[rel.Type]; !ok || rel.TargetMode == "External" {
+			continue
+		}
+		content, ok := rd.FileMap.Load(rd.Document.partPath(rel.Target))
+		if !ok {
+			continue
+		}
+		for _, m := range bookmarkStartID.FindAllSubmatch(content.([]byte), -1) {
+			if id, err := strconv.Atoi(string(m[1])); err == nil && id > maxID {
+				maxID = id
+			}
+		}
+	}
+	return maxID
+}
+
+// maxChildBookmarkID returns the greatest ID of the bookmarks of a list of paragraph
+// children, including those within hyperlinks.
+func maxChildBookmarkID(children []ctypes.ParagraphChild) int {
+	maxID := 0
+	for _, child := range children {
+		id := 0
+		switch {
+		case child.BmkStart != nil:
+			id = child.BmkStart.ID
+		case child.Link != nil:
+			id = maxChildBookmarkID(child.Link.Children)
+		}
+		if id > maxID {
+			maxID = id
+		}
+	}
+	return maxID
+}
 
-	p.root.FileMap.Store(fileIdxPath, imgBytes)
+// StyleID returns the ID of the paragraph style, or "" when the paragraph has none.
+func (p *Paragraph) StyleID() string {
+	if p.ct.Property == nil || p.ct.Property.Style == nil {
+		return ""
+	}
+	return p.ct.Property.Style.Val
+}
 
-	relName := fmt.Sprintf("media/%s", fileName)
+// Alignment returns the justification set on the paragraph, or "" when it is inherited.
+func (p *Paragraph) Alignment() stypes.Justification {
+	if p.ct.Property == nil || p.ct.Property.Justification == nil {
+		return ""
+	}
+	return p.ct.Property.Justification.Val
+}
 
-	rID := p.root.Document.addRelation(constants.SourceRelationshipImage, relName)
+// NumberingID returns the numbering instance set on the paragraph, or 0 when it is not numbered.
+func (p *Paragraph) NumberingID() int {
+	if p.ct.Property == nil || p.ct.Property.NumProp == nil || p.ct.Property.NumProp.NumID == nil {
+		return 0
+	}
+	return p.ct.Property.NumProp.NumID.Val
+}
 
-	inline := p.addDrawing(rID, p.root.ImageCount, width, height)
+// NumberingLevel returns the list level of the paragraph, or -1 when it is not numbered.
+func (p *Paragraph) NumberingLevel() int {
+	if p.NumberingID() == 0 {
+		return -1
+	}
+	if p.ct.Property.NumProp.ILvl == nil {
+		return 0
+	}
+	return p.ct.Property.NumProp.ILvl.Val
+}
 
-	return &PicMeta{
-		Para:   p,
-		Inline: inline,
-	}, nil
+// Hyperlinks returns the hyperlinks of the paragraph, in text order.
+func (p *Paragraph) Hyperlinks() []*Hyperlink {
+	var links []*Hyperlink
+	var collect func(children []ctypes.ParagraphChild)
+	collect = func(children []ctypes.ParagraphChild) {
+		for _, child := range children {
+			if child.Link != nil {
+				links = append(links, newHyperlink(p.root, child.Link))
+				collect(child.Link.Children)
+			}
+		}
+	}
+	collect(p.ct.Children)
+	return links
 }
@@ -1,11 +1,14 @@
 package docx
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/gomutex/godocx/common/constants"
 	"github.com/gomutex/godocx/wml/ctypes"
 	"github.com/gomutex/godocx/wml/stypes"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func assertParaText(t *testing.T, para *Paragraph, expected string) {
@@ -42,7 +45,7 @@ func TestParagraph_Style(t *testing.T) {
 	f := func(styleValue string, expectedStyleValue string) {
 		t.Helper()
 
-		p := &Paragraph{}
+		p := &Paragraph{ct: &ctypes.Paragraph{}}
 
 		p.Style(styleValue)
 
@@ -58,7 +61,7 @@ func TestParagraph_Justification(t *testing.T) {
 	f := func(justificationValue, expectedJustificationValue stypes.Justification) {
 		t.Helper()
 
-		p := &Paragraph{}
+		p := &Paragraph{ct: &ctypes.Paragraph{}}
 
 		p.Justification(justificationValue)
 
@@ -77,7 +80,7 @@ func TestParagraph_Numbering(t *testing.T) {
 	f := func(id int, level int, expectedNumID int, expectedILvl int) {
 		t.Helper()
 
-		p := &Paragraph{}
+		p := &Paragraph{ct: &ctypes.Paragraph{}}
 
 		p.Numbering(id, level)
 
@@ -97,7 +100,7 @@ func TestParagraph_Indent(t *testing.T) {
 	f := func(indentValue, expectedIndentValue ctypes.Indent) {
 		t.Helper()
 
-		p := &Paragraph{}
+		p := &Paragraph{ct: &ctypes.Paragraph{}}
 
 		p.Indent(&indentValue)
 
@@ -130,7 +133,7 @@ func TestParagraph_AddText(t *testing.T) {
 		t.Helper()
 
 		p := &Paragraph{
-			ct: ctypes.Paragraph{
+			ct: &ctypes.Paragraph{
 				Children: []ctypes.ParagraphChild{},
 			},
 		}
@@ -154,7 +157,7 @@ func TestParagraph_AddText(t *testing.T) {
 
 func TestParagraph_AddRun(t *testing.T) {
 	p := &Paragraph{
-		ct: ctypes.Paragraph{
+		ct: &ctypes.Paragraph{
 			Children: []ctypes.ParagraphChild{},
 		},
 	}
@@ -171,3 +174,37 @@ func TestParagraph_AddRun(t *testing.T) {
 
 	assert.Equal(t, 0, len(p.ct.Children[0].Run.Children), "Expected the new Run to have no initial Children")
 }
+
+func TestParagraph_AddBookmark(t *testing.T) {
+	rd := setupRootDoc(t)
+	existing := rd.AddParagraph("Existing")
+	existing.ct.Children = append(existing.ct.Children, ctypes.ParagraphChild{BmkStart: &ctypes.BookmarkStart{ID: 7, Name: "old"}})
+
+	p := rd.AddParagraph("Scope")
+	id := p.AddBookmark("scope")
+	assert.Equal(t, 8, id, "new IDs come after those of the body")
+	assert.Equal(t, 9, rd.AddParagraph("Terms").AddBookmark("terms"))
+
+	assert.Equal(t, "Scope", p.Text())
+	assert.Len(t, p.ct.Children, 3)
+	assert.Equal(t, &ctypes.BookmarkStart{ID: id, Name: "scope"}, p.ct.Children[0].BmkStart)
+	assert.Equal(t, &ctypes.Markup{ID: id}, p.ct.Children[2].BmkEnd)
+}
+
+func TestParagraph_AddBookmark_Stories(t *testing.T) {
+	withBookmark := strings.Replace(testHeaderXML, "<w:p>", `<w:p><w:bookmarkStart w:name="top" w:id="12"/><w:bookmarkEnd w:id="12"/>`, 1)
+
+	rd := setupStoryDoc(t)
+	addTestStoryPart(rd, constants.SourceRelationshipHeader, "header2.xml", withBookmark)
+	assert.Equal(t, 13, rd.AddParagraph("Scope").AddBookmark("scope"), "story parts not loaded are scanned")
+	assert.False(t, rd.storiesLoaded, "scanning does not load the story parts")
+
+	rd = setupStoryDoc(t)
+	stories, err := rd.Stories()
+	require.NoError(t, err)
+	stories[1].Children[0].Para.ct.Children = append(stories[1].Children[0].Para.ct.Children,
+		ctypes.ParagraphChild{Link: &ctypes.Hyperlink{Anchor: "x", Children: []ctypes.ParagraphChild{
+			{BmkStart: &ctypes.BookmarkStart{ID: 20, Name: "note"}},
+		}}})
+	assert.Equal(t, 21, rd.AddParagraph("Scope").AddBookmark("scope"), "loaded story parts are scanned")
+}
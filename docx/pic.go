@@ -1,6 +1,10 @@
 package docx
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/gomutex/godocx/common/constants"
 	"github.com/gomutex/godocx/common/units"
 	"github.com/gomutex/godocx/dml"
 )
@@ -39,3 +43,96 @@ func (rd *RootDoc) AddPicture(path string, width units.Inch, height units.Inch)
 
 	return p.AddPicture(path, width, height)
 }
+
+// addImagePart stores an image in the media folder of the package and returns the ID of
+// the document relationship pointing at it, along with the number of the image.
+func (rd *RootDoc) addImagePart(data []byte, ext string) (string, uint, error) {
+	ext = strings.TrimPrefix(ext, ".")
+	imgMIME, err := MIMEFromExt(ext)
+	if err != nil {
+		return "", 0, err
+	}
+
+	rd.mu.Lock()
+	rd.ImageCount += 1
+	n := rd.ImageCount
+	fileName := fmt.Sprintf("image%d.%s", n, ext)
+
+	if err = rd.ContentType.AddExtension(ext, imgMIME); err == nil {
+		err = rd.ContentType.AddOverride("/"+constants.MediaPath+fileName, imgMIME)
+	}
+	rd.mu.Unlock()
+	if err != nil {
+		return "", 0, err
+	}
+
+	rd.FileMap.Store(constants.MediaPath+fileName, data)
+
+	return rd.Document.addRelation(constants.SourceRelationshipImage, "media/"+fileName), n, nil
+}
+
+// newImageNumber returns a new image number, which identifies the drawing of an image.
+func (rd *RootDoc) newImageNumber() uint {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+
+	rd.ImageCount += 1
+	return rd.ImageCount
+}
+
+// drawingImage describes a picture referenced by a drawing.
+type drawingImage struct {
+	RelID       string // RelID is the relationship ID of the image part.
+	Description string // Description is the alternative text of the drawing.
+	Width       uint64 // Width of the drawing in EMUs.
+	Height      uint64 // Height of the drawing in EMUs.
+}
+
+// drawingImages returns the pictures referenced by the inline and anchored objects of a drawing.
+func drawingImages(d *dml.Drawing) []drawingImage {
+	if d == nil {
+		return nil
+	}
+
+	var images []drawingImage
+	add := func(graphic dml.Graphic, docProp dml.DocProp, width, height uint64) {
+		if graphic.Data == nil || graphic.Data.Pic == nil || graphic.Data.Pic.BlipFill.Blip == nil {
+			return
+		}
+		images = append(images, drawingImage{
+			RelID:       graphic.Data.Pic.BlipFill.Blip.EmbedID,
+			Description: docProp.Description,
+			Width:       width,
+			Height:      height,
+		})
+	}
+
+	for _, inline := range d.Inline {
+		add(inline.Graphic, inline.DocProp, inline.Extent.Width, inline.Extent.Height)
+	}
+
+	for _, anchor := range d.Anchor {
+		if anchor == nil {
+			continue
+		}
+		add(anchor.Graphic, anchor.DocProp, anchor.Extent.Width, anchor.Extent.Height)
+	}
+
+	return images
+}
+
+// imagePart returns the package path and content of the image referenced by the given relationship ID.
+func (rd *RootDoc) imagePart(rID string) (string, []byte, bool) {
+	rel := rd.Document.relationByID(rID)
+	if rel == nil || rel.TargetMode == "External" {
+		return "", nil, false
+	}
+
+	partPath := rd.Document.partPath(rel.Target)
+	content, ok := rd.FileMap.Load(partPath)
+	if !ok {
+		return "", nil, false
+	}
+
+	return partPath, content.([]byte), true
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/xml"
 	"io"
+	"strings"
 
 	"github.com/gomutex/godocx/common/constants"
 )
@@ -178,3 +179,30 @@ func LoadDocProps(fileBytes []byte) (cp *CoreProperties, err error) {
 	}
 	return
 }
+
+// CoreProperties returns the core properties of the document, such as the title, creator and language.
+// The properties are read from the core properties part referenced by the package relationships.
+// An empty CoreProperties is returned if the package has no such part.
+func (rd *RootDoc) CoreProperties() (*CoreProperties, error) {
+	partPath := rd.corePropsPath()
+	if partPath == "" {
+		return &CoreProperties{}, nil
+	}
+
+	content, ok := rd.FileMap.Load(partPath)
+	if !ok {
+		return &CoreProperties{}, nil
+	}
+
+	return LoadDocProps(content.([]byte))
+}
+
+// corePropsPath returns the package path of the core properties part, or an empty string if there is none.
+func (rd *RootDoc) corePropsPath() string {
+	for _, rel := range rd.RootRels.Relationships {
+		if rel.Type == constants.CORE_PROP_TYPE {
+			return strings.TrimPrefix(rel.Target, "/")
+		}
+	}
+	return ""
+}
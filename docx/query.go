@@ -0,0 +1,337 @@
+package docx
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// Query is a selection of the paragraphs and tables of the document body, in document order.
+// Paragraphs and tables inside tables and content controls are included.
+//
+// Queries are built with RootDoc.Find and narrowed down by chaining methods:
+//
+//	scope := rd.Find().Paragraphs().WithStyle("Heading2").Containing("Scope").First()
+//	next := rd.Find().After(scope).Paragraphs().Headings().First()
+//	pricing := rd.Find().After(scope).Until(next).Tables().WithHeader("Qty").First()
+//
+// Each method returns a new query and leaves the one it is called on unchanged. The results
+// are the wrappers of the document content, so editing them edits the document. A query is a
+// snapshot: content added to the document later is not part of it.
+type Query struct {
+	root   *RootDoc
+	blocks []queryBlock
+}
+
+// queryBlock is a paragraph or table with its position in document order.
+type queryBlock struct {
+	para  *Paragraph
+	table *Table
+	pos   int
+}
+
+// Find returns a query selecting every paragraph and table of the document body.
+func (rd *RootDoc) Find() *Query {
+	q := &Query{root: rd}
+	w := &walker{v: Visitor{
+		VisitParagraph: func(ctx *WalkContext, p *Paragraph) WalkAction {
+			q.blocks = append(q.blocks, queryBlock{para: p, pos: len(q.blocks)})
+			return WalkSkipChildren
+		},
+		VisitTable: func(ctx *WalkContext, t *Table) WalkAction {
+			q.blocks = append(q.blocks, queryBlock{table: t, pos: len(q.blocks)})
+			return WalkContinue
+		},
+	}}
+	w.blocks(rd.Document.Body.Children, true)
+	return q
+}
+
+// position returns the position of a paragraph in document order, or -1 if it is nil or not
+// part of the query.
+func (q *Query) position(p *Paragraph) int {
+	if p == nil {
+		return -1
+	}
+	for _, b := range q.blocks {
+		if b.para == p {
+			return b.pos
+		}
+	}
+	return -1
+}
+
+// After selects the blocks that come after the paragraph p. The query is left unchanged if p
+// is nil or not part of it.
+func (q *Query) After(p *Paragraph) *Query {
+	pos := q.position(p)
+	if pos < 0 {
+		return q
+	}
+
+	out := &Query{root: q.root}
+	for _, b := range q.blocks {
+		if b.pos > pos {
+			out.blocks = append(out.blocks, b)
+		}
+	}
+	return out
+}
+
+// Until selects the blocks that come before the paragraph p. The query is left unchanged if p
+// is nil or not part of it, so that Until can be given the result of a search for the next
+// heading, which is nil for the last section.
+func (q *Query) Until(p *Paragraph) *Query {
+	pos := q.position(p)
+	if pos < 0 {
+		return q
+	}
+
+	out := &Query{root: q.root}
+	for _, b := range q.blocks {
+		if b.pos < pos {
+			out.blocks = append(out.blocks, b)
+		}
+	}
+	return out
+}
+
+// Paragraphs selects the paragraphs of the query.
+func (q *Query) Paragraphs() *ParagraphQuery {
+	out := &ParagraphQuery{root: q.root}
+	for _, b := range q.blocks {
+		if b.para != nil {
+			out.items = append(out.items, b.para)
+		}
+	}
+	return out
+}
+
+// Tables selects the tables of the query.
+func (q *Query) Tables() *TableQuery {
+	out := &TableQuery{root: q.root}
+	for _, b := range q.blocks {
+		if b.table != nil {
+			out.items = append(out.items, b.table)
+		}
+	}
+	return out
+}
+
+// Runs selects the runs of the paragraphs of the query, including the runs of hyperlinks.
+func (q *Query) Runs() *RunQuery {
+	return q.Paragraphs().Runs()
+}
+
+// ParagraphQuery is a selection of paragraphs, in document order.
+type ParagraphQuery struct {
+	root  *RootDoc
+	items []*Paragraph
+}
+
+// Where selects the paragraphs for which keep returns true.
+func (q *ParagraphQuery) Where(keep func(p *Paragraph) bool) *ParagraphQuery {
+	out := &ParagraphQuery{root: q.root}
+	for _, p := range q.items {
+		if keep(p) {
+			out.items = append(out.items, p)
+		}
+	}
+	return out
+}
+
+// WithStyle selects the paragraphs with the given paragraph style, given by ID such as
+// "Heading2" or by name such as "heading 2". Paragraphs whose style is based on that style
+// are selected too.
+func (q *ParagraphQuery) WithStyle(style string) *ParagraphQuery {
+	return q.Where(func(p *Paragraph) bool {
+		return q.root.styleMatches(p.StyleID(), style, stypes.StyleTypeParagraph)
+	})
+}
+
+// Headings selects the headings, which are the paragraphs with the Title style, a Heading1
+// to Heading9 style or an outline level.
+func (q *ParagraphQuery) Headings() *ParagraphQuery {
+	return q.Where(func(p *Paragraph) bool {
+		_, ok := headingLevel(p.ct)
+		return ok
+	})
+}
+
+// Containing selects the paragraphs whose text contains text.
+func (q *ParagraphQuery) Containing(text string) *ParagraphQuery {
+	return q.Where(func(p *Paragraph) bool {
+		return strings.Contains(p.Text(), text)
+	})
+}
+
+// Matching selects the paragraphs whose text matches re.
+func (q *ParagraphQuery) Matching(re *regexp.Regexp) *ParagraphQuery {
+	return q.Where(func(p *Paragraph) bool {
+		return re.MatchString(p.Text())
+	})
+}
+
+// Runs selects the runs of the paragraphs, including the runs of hyperlinks.
+func (q *ParagraphQuery) Runs() *RunQuery {
+	out := &RunQuery{root: q.root}
+	for _, p := range q.items {
+		out.items = append(out.items, p.Runs()...)
+	}
+	return out
+}
+
+// All returns the selected paragraphs.
+func (q *ParagraphQuery) All() []*Paragraph {
+	return q.items
+}
+
+// First returns the first selected paragraph, or nil if there is none.
+func (q *ParagraphQuery) First() *Paragraph {
+	if len(q.items) == 0 {
+		return nil
+	}
+	return q.items[0]
+}
+
+// Count returns the number of selected paragraphs.
+func (q *ParagraphQuery) Count() int {
+	return len(q.items)
+}
+
+// TableQuery is a selection of tables, in document order.
+type TableQuery struct {
+	root  *RootDoc
+	items []*Table
+}
+
+// Where selects the tables for which keep returns true.
+func (q *TableQuery) Where(keep func(t *Table) bool) *TableQuery {
+	out := &TableQuery{root: q.root}
+	for _, t := range q.items {
+		if keep(t) {
+			out.items = append(out.items, t)
+		}
+	}
+	return out
+}
+
+// WithHeader selects the tables whose first row has a cell with the given text, leading and
+// trailing spaces aside.
+func (q *TableQuery) WithHeader(text string) *TableQuery {
+	return q.Where(func(t *Table) bool {
+		rows := t.Rows()
+		if len(rows) == 0 {
+			return false
+		}
+		for _, cell := range rows[0].Cells() {
+			if strings.TrimSpace(cell.Text()) == text {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// All returns the selected tables.
+func (q *TableQuery) All() []*Table {
+	return q.items
+}
+
+// First returns the first selected table, or nil if there is none.
+func (q *TableQuery) First() *Table {
+	if len(q.items) == 0 {
+		return nil
+	}
+	return q.items[0]
+}
+
+// Count returns the number of selected tables.
+func (q *TableQuery) Count() int {
+	return len(q.items)
+}
+
+// RunQuery is a selection of runs, in document order.
+type RunQuery struct {
+	root  *RootDoc
+	items []*Run
+}
+
+// Where selects the runs for which keep returns true.
+func (q *RunQuery) Where(keep func(r *Run) bool) *RunQuery {
+	out := &RunQuery{root: q.root}
+	for _, r := range q.items {
+		if keep(r) {
+			out.items = append(out.items, r)
+		}
+	}
+	return out
+}
+
+// Bold selects the runs that are bold.
+func (q *RunQuery) Bold() *RunQuery {
+	return q.Where((*Run).IsBold)
+}
+
+// Italic selects the runs that are italic.
+func (q *RunQuery) Italic() *RunQuery {
+	return q.Where((*Run).IsItalic)
+}
+
+// WithStyle selects the runs with the given character style, given by ID or by name.
+// Runs whose style is based on that style are selected too.
+func (q *RunQuery) WithStyle(style string) *RunQuery {
+	return q.Where(func(r *Run) bool {
+		return q.root.styleMatches(r.StyleID(), style, stypes.StyleTypeCharacter)
+	})
+}
+
+// Containing selects the runs whose text contains text.
+func (q *RunQuery) Containing(text string) *RunQuery {
+	return q.Where(func(r *Run) bool {
+		return strings.Contains(r.Text(), text)
+	})
+}
+
+// All returns the selected runs.
+func (q *RunQuery) All() []*Run {
+	return q.items
+}
+
+// First returns the first selected run, or nil if there is none.
+func (q *RunQuery) First() *Run {
+	if len(q.items) == 0 {
+		return nil
+	}
+	return q.items[0]
+}
+
+// Count returns the number of selected runs.
+func (q *RunQuery) Count() int {
+	return len(q.items)
+}
+
+// styleMatches reports whether the style styleID is the style want, given by ID or by name,
+// or is based on it.
+func (rd *RootDoc) styleMatches(styleID, want string, styleType stypes.StyleType) bool {
+	// The chain of base styles is followed a limited number of times in case it loops.
+	for i := 0; styleID != "" && i < 16; i++ {
+		if styleID == want {
+			return true
+		}
+
+		style := rd.GetStyleByID(styleID, styleType)
+		if style == nil {
+			return false
+		}
+		if style.Name != nil && strings.EqualFold(style.Name.Val, want) {
+			return true
+		}
+		if style.BasedOn == nil {
+			return false
+		}
+		styleID = style.BasedOn.Val
+	}
+	return false
+}
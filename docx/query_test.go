@@ -0,0 +1,88 @@
+package docx
+
+import (
+	"encoding/xml"
+	"regexp"
+	"testing"
+
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testQueryXML = `<w:body xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t>Scope</w:t></w:r></w:p>` +
+	`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Item</w:t></w:r></w:p></w:tc></w:tr></w:tbl>` +
+	`<w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t>Pricing</w:t></w:r></w:p>` +
+	`<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Prices</w:t></w:r><w:r><w:t> are net.</w:t></w:r></w:p>` +
+	`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Item</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t> Qty </w:t></w:r></w:p></w:tc></w:tr></w:tbl>` +
+	`<w:p><w:pPr><w:pStyle w:val="Heading2Numbered"/></w:pPr><w:r><w:t>Terms</w:t></w:r></w:p>` +
+	`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Qty</w:t></w:r></w:p></w:tc></w:tr></w:tbl>` +
+	`</w:body>`
+
+func loadQueryDoc(t *testing.T) *RootDoc {
+	rd := setupRootDoc(t)
+	body := NewBody(rd)
+	require.NoError(t, xml.Unmarshal([]byte(testQueryXML), body))
+	rd.Document.Body = body
+
+	rd.SetStyle(ctypes.Style{
+		ID:   internal.ToPtr("Heading2"),
+		Type: internal.ToPtr(stypes.StyleTypeParagraph),
+		Name: ctypes.NewCTString("heading 2"),
+	})
+	rd.SetStyle(ctypes.Style{
+		ID:      internal.ToPtr("Heading2Numbered"),
+		Type:    internal.ToPtr(stypes.StyleTypeParagraph),
+		BasedOn: ctypes.NewCTString("Heading2"),
+	})
+	return rd
+}
+
+func TestQuery_Paragraphs(t *testing.T) {
+	rd := loadQueryDoc(t)
+
+	headings := rd.Find().Paragraphs().WithStyle("heading 2")
+	require.Equal(t, 3, headings.Count(), "styles based on the style are matched too")
+	assert.Equal(t, "Terms", headings.All()[2].Text())
+
+	scope := rd.Find().Paragraphs().WithStyle("Heading2").Containing("Scope").First()
+	require.NotNil(t, scope)
+	assert.Same(t, rd.Document.Body.Children[0].Para, scope)
+
+	assert.Equal(t, 2, rd.Find().Paragraphs().Containing("Item").Count(), "paragraphs of table cells are included")
+	assert.Equal(t, 1, rd.Find().Paragraphs().Matching(regexp.MustCompile(`net\.$`)).Count())
+	assert.Equal(t, 2, rd.Find().Paragraphs().Headings().Count(), "Heading2Numbered has no heading level of its own")
+	assert.Nil(t, rd.Find().Paragraphs().Containing("missing").First())
+}
+
+func TestQuery_Section(t *testing.T) {
+	rd := loadQueryDoc(t)
+
+	pricing := rd.Find().Paragraphs().Containing("Pricing").First()
+	next := rd.Find().After(pricing).Paragraphs().WithStyle("Heading2").First()
+	assert.Equal(t, "Terms", next.Text())
+
+	section := rd.Find().After(pricing).Until(next)
+	tbl := section.Tables().WithHeader("Qty").First()
+	require.NotNil(t, tbl)
+	assert.Same(t, rd.Document.Body.Children[4].Table, tbl)
+	assert.Equal(t, 2, rd.Find().Tables().WithHeader("Qty").Count())
+	assert.Equal(t, 0, rd.Find().Until(pricing).Tables().WithHeader("Qty").Count())
+
+	// Until a missing heading runs to the end of the document.
+	last := rd.Find().After(next).Until(nil)
+	assert.Equal(t, 1, last.Tables().Count())
+
+	bold := section.Runs().Bold().All()
+	require.Len(t, bold, 1)
+	assert.Equal(t, "Prices", bold[0].Text())
+
+	// Results are live: editing them edits the document.
+	bold[0].Italic(true)
+	assert.Equal(t, 1, rd.Find().Runs().Italic().Count())
+	tbl.Rows()[0].Cells()[0].AddParagraph("each")
+	assert.Equal(t, 1, rd.Find().Paragraphs().Containing("each").Count())
+}
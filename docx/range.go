@@ -0,0 +1,339 @@
+package docx
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/gomutex/godocx/wml/ctypes"
+)
+
+// Range is a span of text in a paragraph or in the document body, addressed by character
+// offsets. Ranges are created with Paragraph.Range and RootDoc.Range.
+//
+// Formatting and other edits split runs where the range starts and ends, so they apply to
+// exactly the characters of the range. A range keeps pointing at the same text across its
+// own edits; other changes to its paragraphs may leave it pointing elsewhere.
+type Range struct {
+	root  *RootDoc
+	spans []rangeSpan
+}
+
+// rangeSpan is the part of a range that lies in a single paragraph, as byte offsets into the
+// paragraph text.
+type rangeSpan struct {
+	para       *ctypes.Paragraph
+	start, end int
+}
+
+// Comment describes a comment added with Range.AddComment.
+type Comment struct {
+	Author   string
+	Initials string
+	Date     time.Time // Date is left out when zero.
+	Text     string
+}
+
+// Range returns the characters from start up to, but not including, end of the paragraph text.
+//
+// Offsets count Unicode characters of the text returned by Text, in which tabs and line
+// breaks are one character each.
+func (p *Paragraph) Range(start, end int) (*Range, error) {
+	text := paragraphText(p.ct)
+	if start < 0 || end < start || end > utf8.RuneCountInString(text) {
+		return nil, fmt.Errorf("range %d-%d is out of bounds for %d characters", start, end, utf8.RuneCountInString(text))
+	}
+
+	return &Range{root: p.root, spans: []rangeSpan{{
+		para:  p.ct,
+		start: byteOffset(text, start),
+		end:   byteOffset(text, end),
+	}}}, nil
+}
+
+// Range returns the characters from start up to, but not including, end of the document text
+// returned by RootDoc.Text. A range may cover several paragraphs; the newlines between them are
+// counted as one character each but are never changed by the range.
+func (rd *RootDoc) Range(start, end int) (*Range, error) {
+	paras := blockParagraphs(rd.Document.Body.Children)
+
+	total := -1
+	for _, p := range paras {
+		total += utf8.RuneCountInString(paragraphText(p)) + 1
+	}
+	if start < 0 || end < start || end > total {
+		return nil, fmt.Errorf("range %d-%d is out of bounds for %d characters", start, end, total)
+	}
+
+	r := &Range{root: rd}
+	pos := 0
+	for _, p := range paras {
+		text := paragraphText(p)
+		n := utf8.RuneCountInString(text)
+		s, e := start-pos, end-pos
+		pos += n + 1
+
+		if e < 0 || s > n {
+			continue
+		}
+		if s < 0 {
+			s = 0
+		}
+		if e > n {
+			e = n
+		}
+
+		// An empty range belongs to the first paragraph it touches; a range covering text
+		// leaves out the paragraphs it only touches at an edge.
+		if s == e && (start != end || len(r.spans) > 0) {
+			continue
+		}
+		r.spans = append(r.spans, rangeSpan{para: p, start: byteOffset(text, s), end: byteOffset(text, e)})
+	}
+
+	return r, nil
+}
+
+// byteOffset returns the byte offset of the n-th character of text.
+func byteOffset(text string, n int) int {
+	for i := range text {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(text)
+}
+
+// Text returns the text of the range. Paragraphs are separated by newlines.
+func (r *Range) Text() string {
+	parts := make([]string, 0, len(r.spans))
+	for _, s := range r.spans {
+		parts = append(parts, paragraphText(s.para)[s.start:s.end])
+	}
+	return strings.Join(parts, "\n")
+}
+
+// eachRun splits runs at the edges of the range and calls fn for every run inside it.
+func (r *Range) eachRun(fn func(run *Run)) {
+	for _, s := range r.spans {
+		if s.start == s.end {
+			continue
+		}
+
+		splitRunsAt(s.para, s.end)
+		splitRunsAt(s.para, s.start)
+		for _, tr := range paragraphRuns(s.para) {
+			if tr.start >= s.start && tr.end <= s.end && tr.start < tr.end {
+				fn(newRun(r.root, tr.run))
+			}
+		}
+	}
+}
+
+// ApplyBold makes the text of the range bold or not bold.
+func (r *Range) ApplyBold(value bool) {
+	r.eachRun(func(run *Run) { run.Bold(value) })
+}
+
+// ApplyItalic makes the text of the range italic or not italic.
+func (r *Range) ApplyItalic(value bool) {
+	r.eachRun(func(run *Run) { run.Italic(value) })
+}
+
+// ApplyColor sets the color of the text of the range, as a hex code such as "FF0000".
+func (r *Range) ApplyColor(colorCode string) {
+	r.eachRun(func(run *Run) { run.Color(colorCode) })
+}
+
+// ApplyHighlight highlights the text of the range with a highlight color such as "yellow".
+func (r *Range) ApplyHighlight(color string) {
+	r.eachRun(func(run *Run) { run.Highlight(color) })
+}
+
+// ApplyStyle applies a character style to the text of the range.
+func (r *Range) ApplyStyle(styleID string) {
+	r.eachRun(func(run *Run) { run.Style(styleID) })
+}
+
+// Delete removes the text of the range, leaving it empty. Paragraphs covered by the range are
+// kept, and so is content without text such as drawings and field characters.
+func (r *Range) Delete() {
+	for i := range r.spans {
+		s := &r.spans[i]
+		if s.start < s.end {
+			replaceText(s.para, s.start, s.end, "", nil)
+			s.end = s.start
+		}
+	}
+}
+
+// InsertBefore inserts text just before the range, with the formatting of the first character
+// of the range. "\t" and "\n" become tabs and line breaks.
+func (r *Range) InsertBefore(text string) {
+	if len(r.spans) == 0 || text == "" {
+		return
+	}
+
+	s := &r.spans[0]
+	insertText(s.para, s.start, text, copyRunProp(textPropAt(s.para, s.start, false)), false)
+	s.start += len(text)
+	s.end += len(text)
+}
+
+// InsertAfter inserts text just after the range, with the formatting of the last character
+// of the range. "\t" and "\n" become tabs and line breaks.
+func (r *Range) InsertAfter(text string) {
+	if len(r.spans) == 0 || text == "" {
+		return
+	}
+
+	s := r.spans[len(r.spans)-1]
+	insertText(s.para, s.end, text, copyRunProp(textPropAt(s.para, s.end, true)), true)
+}
+
+// textPropAt returns the formatting of the character at an offset of the paragraph text, or
+// of the one before it when before is set. The other side is used when there is no such
+// character.
+func textPropAt(p *ctypes.Paragraph, offset int, before bool) *ctypes.RunProperty {
+	if before && offset > 0 {
+		return runPropAt(p, offset-1)
+	}
+	if offset < len(paragraphText(p)) {
+		return runPropAt(p, offset)
+	}
+	if offset > 0 {
+		return runPropAt(p, offset-1)
+	}
+	return nil
+}
+
+// WrapInHyperlink turns the text of the range into a link to url, with one hyperlink per
+// paragraph. It fails if part of the range is already in a hyperlink.
+func (r *Range) WrapInHyperlink(url string) error {
+	for _, s := range r.spans {
+		for _, tr := range paragraphRuns(s.para) {
+			if tr.link != nil && tr.start < s.end && tr.end > s.start {
+				return errors.New("range is already part of a hyperlink")
+			}
+		}
+	}
+
+	for _, s := range r.spans {
+		if s.start == s.end {
+			continue
+		}
+
+		splitRunsAt(s.para, s.end)
+		splitRunsAt(s.para, s.start)
+
+		first, last := -1, -1
+		for _, tr := range paragraphRuns(s.para) {
+			if tr.start >= s.start && tr.end <= s.end && tr.start < tr.end {
+				if first < 0 {
+					first = tr.top
+				}
+				last = tr.top
+			}
+		}
+
+		children := s.para.Children
+		for _, child := range children[first : last+1] {
+			if child.Run == nil {
+				return errors.New("range holds content that cannot be part of a hyperlink")
+			}
+		}
+
+		link := &ctypes.Hyperlink{ID: r.root.Document.addLinkRelation(url)}
+		for _, child := range children[first : last+1] {
+			newRun(r.root, child.Run).Style(constants.HyperLinkStyle)
+			if link.Run == nil {
+				link.Run = child.Run
+			} else {
+				link.Children = append(link.Children, child)
+			}
+		}
+
+		rest := append([]ctypes.ParagraphChild{{Link: link}}, children[last+1:]...)
+		s.para.Children = append(children[:first], rest...)
+	}
+
+	return nil
+}
+
+// AddComment attaches a comment to the range and returns the comment, which can be edited
+// further like any other story. The comments part is created if the document has none.
+//
+// A range that starts or ends inside a hyperlink is widened to the whole hyperlink.
+func (r *Range) AddComment(c Comment) (*Story, error) {
+	if len(r.spans) == 0 {
+		return nil, errors.New("range is empty")
+	}
+
+	part, err := r.root.commentsPart()
+	if err != nil {
+		return nil, err
+	}
+
+	id := 0
+	for _, s := range part.stories {
+		if s.ID >= id {
+			id = s.ID + 1
+		}
+	}
+
+	comment := &Story{root: r.root, Type: StoryComment, Path: part.path, ID: id}
+	comment.attrs = append(comment.attrs, xml.Attr{Name: xml.Name{Local: "w:author"}, Value: c.Author})
+	if !c.Date.IsZero() {
+		comment.attrs = append(comment.attrs, xml.Attr{Name: xml.Name{Local: "w:date"}, Value: c.Date.UTC().Format(time.RFC3339)})
+	}
+	if c.Initials != "" {
+		comment.attrs = append(comment.attrs, xml.Attr{Name: xml.Name{Local: "w:initials"}, Value: c.Initials})
+	}
+
+	para := newParagraph(r.root)
+	para.ct.Children = append(para.ct.Children, ctypes.ParagraphChild{Run: &ctypes.Run{
+		Property: &ctypes.RunProperty{Style: ctypes.NewRunStyle("CommentReference")},
+		Children: []ctypes.RunChild{{AnnotationRef: &ctypes.Empty{}}},
+	}})
+	para.AddText(c.Text)
+	comment.Children = append(comment.Children, DocumentChild{Para: para})
+	part.stories = append(part.stories, comment)
+
+	// The end is marked first so that marking the start does not move it.
+	last := r.spans[len(r.spans)-1]
+	splitRunsAt(last.para, last.end)
+	at := 0
+	for _, tr := range paragraphRuns(last.para) {
+		if tr.end <= last.end && tr.start < tr.end {
+			at = tr.top + 1
+		}
+	}
+	ref := &ctypes.Run{
+		Property: &ctypes.RunProperty{Style: ctypes.NewRunStyle("CommentReference")},
+		Children: []ctypes.RunChild{{CmntRef: &ctypes.Markup{ID: id}}},
+	}
+	insertParagraphChild(&last.para.Children, at, ctypes.ParagraphChild{Run: ref})
+	insertParagraphChild(&last.para.Children, at, ctypes.ParagraphChild{CmntEnd: &ctypes.Markup{ID: id}})
+	end := at
+
+	first := r.spans[0]
+	splitRunsAt(first.para, first.start)
+	at = len(first.para.Children)
+	for _, tr := range paragraphRuns(first.para) {
+		if tr.start >= first.start && tr.start < tr.end {
+			at = tr.top
+			break
+		}
+	}
+	if first.para == last.para && at > end {
+		at = end
+	}
+	insertParagraphChild(&first.para.Children, at, ctypes.ParagraphChild{CmntStart: &ctypes.Markup{ID: id}})
+
+	return comment, nil
+}
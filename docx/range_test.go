@@ -0,0 +1,178 @@
+package docx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParagraphRange(t *testing.T) {
+	rd := setupRootDoc(t)
+	p := rd.AddParagraph("Café owner ")
+	p.AddText("Ada Love").Italic(true)
+	p.AddText("lace joined.")
+
+	r, err := p.Range(11, 24)
+	require.NoError(t, err)
+	assert.Equal(t, "Ada Lovelace ", r.Text())
+
+	r, err = p.Range(11, 23)
+	require.NoError(t, err)
+	r.ApplyBold(true)
+	r.ApplyColor("FF0000")
+
+	children := p.GetCT().Children
+	require.Len(t, children, 4)
+	assert.Equal(t, "Café owner ", runText(children[0].Run))
+	assert.Equal(t, "Ada Love", runText(children[1].Run))
+	assert.Equal(t, "lace", runText(children[2].Run))
+	assert.Equal(t, " joined.", runText(children[3].Run))
+	for _, child := range children[1:3] {
+		assert.NotNil(t, child.Run.Property.Bold)
+		assert.Equal(t, "FF0000", child.Run.Property.Color.Val)
+	}
+	assert.NotNil(t, children[1].Run.Property.Italic, "existing formatting is kept")
+	assert.Nil(t, children[3].Run.Property)
+
+	r.InsertBefore("Dr. ")
+	r.InsertAfter(" (CEO)")
+	assert.Equal(t, "Café owner Dr. Ada Lovelace (CEO) joined.", p.Text())
+	assert.Equal(t, "Ada Lovelace", r.Text())
+
+	r.Delete()
+	assert.Equal(t, "", r.Text())
+	assert.Equal(t, "Café owner Dr.  (CEO) joined.", p.Text())
+
+	_, err = p.Range(3, 100)
+	assert.Error(t, err)
+}
+
+func TestDocumentRange(t *testing.T) {
+	rd := setupRootDoc(t)
+	rd.AddParagraph("First paragraph")
+	cell := rd.AddTable().AddRow().AddCell()
+	cell.AddParagraph("In a cell")
+	rd.AddParagraph("Last")
+
+	assert.Equal(t, "First paragraph\nIn a cell\nLast", rd.Text())
+
+	r, err := rd.Range(6, 20)
+	require.NoError(t, err)
+	assert.Equal(t, "paragraph\nIn a", r.Text())
+	r.ApplyStyle("Strong")
+
+	body := rd.Document.Body.Children
+	assert.Equal(t, "Strong", body[0].Para.GetCT().Children[1].Run.Property.Style.Val)
+	assert.Equal(t, "Strong", cell.ct.Contents[0].Paragraph.Children[0].Run.Property.Style.Val)
+	assert.Equal(t, " cell", runText(cell.ct.Contents[0].Paragraph.Children[1].Run))
+
+	r, err = rd.Range(15, 16)
+	require.NoError(t, err)
+	assert.Equal(t, "", r.Text(), "a range over a paragraph end alone holds no text")
+
+	r, err = rd.Range(26, 30)
+	require.NoError(t, err)
+	assert.Equal(t, "Last", r.Text())
+
+	_, err = rd.Range(26, 31)
+	assert.Error(t, err)
+}
+
+func TestRange_WrapInHyperlink(t *testing.T) {
+	rd := setupRootDoc(t)
+	p := rd.AddParagraph("See the Go website for details.")
+
+	r, err := p.Range(8, 18)
+	require.NoError(t, err)
+	require.NoError(t, r.WrapInHyperlink("https://go.dev"))
+
+	children := p.GetCT().Children
+	require.Len(t, children, 3)
+	link := children[1].Link
+	require.NotNil(t, link)
+	assert.Equal(t, "Go website", runText(link.Run))
+	assert.Equal(t, constants.HyperLinkStyle, link.Run.Property.Style.Val)
+	rel := rd.Document.relationByID(link.ID)
+	require.NotNil(t, rel)
+	assert.Equal(t, "https://go.dev", rel.Target)
+	assert.Equal(t, "See the Go website for details.", p.Text())
+
+	r, err = p.Range(4, 10)
+	require.NoError(t, err)
+	assert.Error(t, r.WrapInHyperlink("https://example.com"))
+}
+
+func TestRange_Insertion(t *testing.T) {
+	rd := setupRootDoc(t)
+	p := rd.AddParagraph("Hello ")
+	addTestInsertion(p, "big ")
+	p.AddText("world")
+
+	r, err := p.Range(10, 15)
+	require.NoError(t, err)
+	assert.Equal(t, "world", r.Text())
+
+	r, err = p.Range(6, 9)
+	require.NoError(t, err)
+	assert.Equal(t, "big", r.Text())
+	r.ApplyBold(true)
+	ins := p.GetCT().Children[1].Ins
+	require.Len(t, ins.Runs, 2)
+	assert.Equal(t, "big", runText(ins.Runs[0]))
+	assert.True(t, isOn(ins.Runs[0].Property.Bold))
+
+	r.InsertAfter("gest")
+	assert.Equal(t, "Hello biggest world", p.Text())
+	assert.Error(t, r.WrapInHyperlink("https://example.com"), "tracked changes cannot be wrapped in a hyperlink")
+	assert.Equal(t, "Hello biggest world", p.Text())
+}
+
+func TestRange_AddComment(t *testing.T) {
+	rd := setupRootDoc(t)
+	rd.Document.relativePath = "word/document.xml"
+	p := rd.AddParagraph("Revenue grew 12% last year.")
+
+	r, err := p.Range(13, 16)
+	require.NoError(t, err)
+	comment, err := r.AddComment(Comment{
+		Author: "Ada",
+		Date:   time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC),
+		Text:   "Source?",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, comment.ID)
+	assert.Equal(t, StoryComment, comment.Type)
+	assert.Equal(t, "word/comments.xml", comment.Path)
+
+	children := p.GetCT().Children
+	require.Len(t, children, 6)
+	assert.Equal(t, "Revenue grew ", runText(children[0].Run))
+	assert.Equal(t, 0, children[1].CmntStart.ID)
+	assert.Equal(t, "12%", runText(children[2].Run))
+	assert.Equal(t, 0, children[3].CmntEnd.ID)
+	assert.Equal(t, 0, children[4].Run.Children[0].CmntRef.ID)
+	assert.Equal(t, " last year.", runText(children[5].Run))
+
+	second, err := r.AddComment(Comment{Author: "Bob", Text: "Checked."})
+	require.NoError(t, err)
+	assert.Equal(t, 1, second.ID)
+
+	stories, err := rd.Stories()
+	require.NoError(t, err)
+	require.Len(t, stories, 2)
+	assert.Same(t, comment, stories[0])
+
+	var found bool
+	for _, o := range rd.ContentType.Override {
+		found = found || (o.PartName == "/word/comments.xml" && o.ContentType == constants.CommentsContentType)
+	}
+	assert.True(t, found, "the comments part is registered in the content types")
+
+	out, err := marshal(rd.storyParts[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `<w:comment w:id="0" w:author="Ada" w:date="2024-05-01T09:30:00Z"><w:p>`)
+	assert.Contains(t, string(out), `<w:annotationRef></w:annotationRef></w:r><w:r><w:t>Source?</w:t></w:r></w:p></w:comment>`)
+}
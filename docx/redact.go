@@ -0,0 +1,706 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/gomutex/godocx/dml"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// RedactMatcher selects the text removed by Redact.
+type RedactMatcher struct {
+	// Name labels the redactions made for the matcher in the audit list, such as "email".
+	Name string
+
+	// Pattern matches the text to redact. Empty matches are ignored.
+	Pattern *regexp.Regexp
+
+	// Literals are texts to redact besides the matches of Pattern, such as customer names.
+	Literals []string
+
+	// IgnoreCase makes Literals match regardless of case. Pattern uses the flags of its
+	// expression instead.
+	IgnoreCase bool
+}
+
+// RedactOptions controls how Redact replaces text.
+type RedactOptions struct {
+	// Placeholder replaces each piece of redacted text. It defaults to "[REDACTED]". The same
+	// placeholder is used whatever the length of the text, so that the length is not given away.
+	Placeholder string
+
+	// BlackBox formats the placeholder in the text of the document as black text on black
+	// shading, the way redacted text is shown on paper.
+	BlackBox bool
+}
+
+// Redaction is an entry of the audit list returned by Redact.
+type Redaction struct {
+	// Matcher is the name of the matcher that matched the text. It is empty for a text box
+	// with no match in it.
+	Matcher string `json:"matcher"`
+
+	// Location tells where the text was: "body", a story such as "header word/header1.xml" or
+	// "footnote 2", a part and the element or relationship holding the text, such as
+	// "docProps/core.xml title", or a text box such as "body text box".
+	Location string `json:"location"`
+
+	// Text is the text redacted. The audit list holds the secrets removed from the document
+	// and must not be shared along with it.
+	Text string `json:"text"`
+}
+
+// Redact removes the text matched by matchers from the document and returns the list of what
+// was redacted, in the order it was found.
+//
+// Text is matched across runs and hyperlinks, but not across paragraphs, in the body, table
+// cells, content controls, headers, footers, footnotes, endnotes and comments, including the
+// text of tracked insertions and deletions and field instructions. A paragraph with tracked
+// changes is matched both as it reads now and as it read before, so that a secret is found
+// even when part of it was inserted or deleted. The alternative text and names of pictures,
+// the authors of comments, the addresses of external hyperlinks and the text of the core,
+// extended and custom properties are redacted too, as is the text of parts the document
+// model does not read, such as the glossary document.
+//
+// Text boxes are not part of the document model and are dropped along with their text when a
+// part is read, so they are missing from the document whether it is redacted or not. Each one
+// is listed in the audit list with its whole text, under the name of the matcher matching it
+// first, or no name when nothing in it matches.
+//
+// Matches of different matchers that overlap are redacted together, under the name of the
+// matcher matching first.
+func (rd *RootDoc) Redact(matchers []RedactMatcher, opts *RedactOptions) ([]Redaction, error) {
+	if opts == nil {
+		opts = &RedactOptions{}
+	}
+	r := &redactor{placeholder: opts.Placeholder, blackBox: opts.BlackBox}
+	if r.placeholder == "" {
+		r.placeholder = "[REDACTED]"
+	}
+
+	if len(matchers) == 0 {
+		return nil, errors.New("redact: no matchers")
+	}
+	for _, m := range matchers {
+		if m.Pattern != nil {
+			r.patterns = append(r.patterns, redactPattern{name: m.Name, re: m.Pattern})
+		}
+		if len(m.Literals) == 0 {
+			if m.Pattern == nil {
+				return nil, fmt.Errorf("redact: matcher %q has neither a pattern nor literals", m.Name)
+			}
+			continue
+		}
+
+		quoted := make([]string, len(m.Literals))
+		for i, lit := range m.Literals {
+			if lit == "" {
+				return nil, fmt.Errorf("redact: matcher %q has an empty literal", m.Name)
+			}
+			quoted[i] = regexp.QuoteMeta(lit)
+		}
+		// Longer literals come first so that they win over their prefixes.
+		sort.SliceStable(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+		pattern := strings.Join(quoted, "|")
+		if m.IgnoreCase {
+			pattern = "(?i)" + pattern
+		}
+		r.patterns = append(r.patterns, redactPattern{name: m.Name, re: regexp.MustCompile(pattern)})
+	}
+
+	err := rd.Walk(Visitor{VisitParagraph: func(ctx *WalkContext, p *Paragraph) WalkAction {
+		r.paragraph(p.ct, storyLocation(ctx.Story))
+		return WalkSkipChildren
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	stories, err := rd.Stories()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stories {
+		if s.Type != StoryComment {
+			continue
+		}
+		for i, a := range s.attrs {
+			// Attributes of comments added by Range.AddComment carry their prefix in the name.
+			name := strings.TrimPrefix(a.Name.Local, "w:")
+			if name == "author" || name == "initials" {
+				s.attrs[i].Value = r.redactString(a.Value, storyLocation(s)+" "+name)
+			}
+		}
+	}
+
+	r.textBoxes(rd.Document.textBoxes, "body")
+	for _, part := range rd.storyParts {
+		r.textBoxes(part.textBoxes, fmt.Sprintf("%s %s", part.typ, part.path))
+	}
+
+	r.relations(rd.Document.DocRels.RelativePath, rd.Document.DocRels.Relationships)
+	if err := r.rawParts(rd); err != nil {
+		return nil, err
+	}
+	return r.audit, nil
+}
+
+// storyLocation returns the location of the text of a story in the audit list.
+func storyLocation(s *Story) string {
+	switch {
+	case s == nil:
+		return "body"
+	case s.Type == StoryHeader || s.Type == StoryFooter:
+		return fmt.Sprintf("%s %s", s.Type, s.Path)
+	default:
+		return fmt.Sprintf("%s %d", s.Type, s.ID)
+	}
+}
+
+type redactPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+type redactor struct {
+	patterns    []redactPattern
+	placeholder string
+	blackBox    bool
+	audit       []Redaction
+}
+
+// redactSpan is a piece of text to redact.
+type redactSpan struct {
+	start, end int
+	matcher    string
+}
+
+// find returns the pieces of text matched by the patterns, in order and without overlaps.
+func (r *redactor) find(text string) []redactSpan {
+	var spans []redactSpan
+	for _, p := range r.patterns {
+		for _, m := range p.re.FindAllStringIndex(text, -1) {
+			if m[0] < m[1] {
+				spans = append(spans, redactSpan{start: m[0], end: m[1], matcher: p.name})
+			}
+		}
+	}
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	// Overlapping matches are merged so that no part of either is left.
+	var merged []redactSpan
+	for _, s := range spans {
+		if n := len(merged); n > 0 && s.start < merged[n-1].end {
+			if s.end > merged[n-1].end {
+				merged[n-1].end = s.end
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// record adds the pieces of text redacted to the audit list.
+func (r *redactor) record(text string, spans []redactSpan, loc string) {
+	for _, s := range spans {
+		r.audit = append(r.audit, Redaction{Matcher: s.matcher, Location: loc, Text: text[s.start:s.end]})
+	}
+}
+
+// textBoxes adds the text boxes dropped from a part to the audit list.
+func (r *redactor) textBoxes(texts []string, loc string) {
+	for _, text := range texts {
+		entry := Redaction{Location: loc + " text box", Text: text}
+		if spans := r.find(text); len(spans) > 0 {
+			entry.Matcher = spans[0].matcher
+		}
+		r.audit = append(r.audit, entry)
+	}
+}
+
+// redactString returns s with the pieces matched by the patterns replaced by the placeholder.
+func (r *redactor) redactString(s, loc string) string {
+	spans := r.find(s)
+	if len(spans) == 0 {
+		return s
+	}
+	r.record(s, spans, loc)
+
+	var sb strings.Builder
+	pos := 0
+	for _, span := range spans {
+		sb.WriteString(s[pos:span.start])
+		sb.WriteString(r.placeholder)
+		pos = span.end
+	}
+	sb.WriteString(s[pos:])
+	return sb.String()
+}
+
+// props returns the properties of the run holding the placeholder that replaces text
+// formatted with base.
+func (r *redactor) props(base *ctypes.RunProperty) *ctypes.RunProperty {
+	props := copyRunProp(base)
+	if !r.blackBox {
+		return props
+	}
+	if props == nil {
+		props = &ctypes.RunProperty{}
+	}
+	black := "000000"
+	props.Color = &ctypes.Color{Val: black}
+	props.Shading = &ctypes.Shading{Val: stypes.ShdClear, Color: &black, Fill: &black}
+	props.Highlight = nil
+	return props
+}
+
+// paragraph redacts the text of a paragraph, of its tracked changes and of the field
+// instructions and pictures of its runs.
+//
+// The text is matched twice: as it reads now, with the tracked insertions, and as it read
+// before, with the tracked deletions, so that a secret partly inserted or deleted is found
+// in the version of the text that shows it whole.
+func (r *redactor) paragraph(p *ctypes.Paragraph, loc string) {
+	r.view(p, loc, false)
+	r.view(p, loc, true)
+
+	forEachRun(p.Children, func(run *ctypes.Run) {
+		for _, child := range run.Children {
+			for _, t := range []*ctypes.Text{child.InstrText, child.DelInstrText} {
+				if t != nil {
+					t.Text = r.redactString(t.Text, loc+" field")
+				}
+			}
+			if child.Drawing != nil {
+				r.drawing(child.Drawing, loc)
+			}
+		}
+	})
+}
+
+// viewRun is a run of a version of a paragraph, along with its offset in the text of that
+// version.
+type viewRun struct {
+	run      *ctypes.Run
+	revision string // revision is "insertion" or "deletion" for the runs of tracked changes.
+	start    int
+	end      int
+}
+
+// viewRuns returns the runs of a list of paragraph children that make up the text as it reads
+// now, or as it read before the tracked changes when deleted is set.
+func viewRuns(children []ctypes.ParagraphChild, deleted bool, runs []viewRun) []viewRun {
+	for _, child := range children {
+		switch {
+		case child.Run != nil:
+			runs = append(runs, viewRun{run: child.Run})
+		case child.Link != nil:
+			if child.Link.Run != nil {
+				runs = append(runs, viewRun{run: child.Link.Run})
+			}
+			runs = viewRuns(child.Link.Children, deleted, runs)
+		case child.Ins != nil && !deleted:
+			for _, run := range child.Ins.Runs {
+				runs = append(runs, viewRun{run: run, revision: "insertion"})
+			}
+		case child.Del != nil && deleted:
+			for _, run := range child.Del.Runs {
+				runs = append(runs, viewRun{run: run, revision: "deletion"})
+			}
+		}
+	}
+	return runs
+}
+
+// view redacts a version of the text of a paragraph, splitting the runs around the
+// placeholders. A match is recorded under the kind of tracked change it falls in, if any.
+func (r *redactor) view(p *ctypes.Paragraph, loc string, deleted bool) {
+	runs := viewRuns(p.Children, deleted, nil)
+	var sb strings.Builder
+	for i := range runs {
+		runs[i].start = sb.Len()
+		for _, child := range runs[i].run.Children {
+			sb.WriteString(revisionChildText(child))
+		}
+		runs[i].end = sb.Len()
+	}
+	text := sb.String()
+	spans := r.find(text)
+	if len(spans) == 0 {
+		return
+	}
+
+	for _, s := range spans {
+		spanLoc := loc
+		for _, vr := range runs {
+			if vr.revision != "" && vr.start < s.end && s.start < vr.end {
+				spanLoc = loc + " " + vr.revision
+				break
+			}
+		}
+		r.record(text, []redactSpan{s}, spanLoc)
+	}
+
+	split := make(map[*ctypes.Run][]*ctypes.Run)
+	for _, vr := range runs {
+		for _, s := range spans {
+			if vr.start < s.end && s.start < vr.end {
+				split[vr.run] = r.splitRun(vr.run, vr.start, spans)
+				break
+			}
+		}
+	}
+	p.Children = replaceRuns(p.Children, split)
+}
+
+// replaceRuns replaces the runs of a list of paragraph children by the runs they were split
+// into, and returns the new list. A hyperlink whose own run is split holds the pieces as its
+// children.
+func replaceRuns(children []ctypes.ParagraphChild, split map[*ctypes.Run][]*ctypes.Run) []ctypes.ParagraphChild {
+	list := func(runs []*ctypes.Run) []*ctypes.Run {
+		var out []*ctypes.Run
+		for _, run := range runs {
+			if pieces, ok := split[run]; ok {
+				out = append(out, pieces...)
+			} else {
+				out = append(out, run)
+			}
+		}
+		return out
+	}
+	asChildren := func(runs []*ctypes.Run) []ctypes.ParagraphChild {
+		out := make([]ctypes.ParagraphChild, 0, len(runs))
+		for _, run := range runs {
+			out = append(out, ctypes.ParagraphChild{Run: run})
+		}
+		return out
+	}
+
+	out := make([]ctypes.ParagraphChild, 0, len(children))
+	for _, child := range children {
+		switch {
+		case child.Run != nil:
+			if pieces, ok := split[child.Run]; ok {
+				out = append(out, asChildren(pieces)...)
+				continue
+			}
+		case child.Link != nil:
+			if pieces, ok := split[child.Link.Run]; ok && child.Link.Run != nil {
+				child.Link.Run = nil
+				child.Link.Children = append(asChildren(pieces), child.Link.Children...)
+			}
+			child.Link.Children = replaceRuns(child.Link.Children, split)
+		case child.Ins != nil:
+			child.Ins.Runs = list(child.Ins.Runs)
+		case child.Del != nil:
+			child.Del.Runs = list(child.Del.Runs)
+		}
+		out = append(out, child)
+	}
+	return out
+}
+
+// revisionChildText returns the text of a run child, including deleted text.
+func revisionChildText(child ctypes.RunChild) string {
+	if child.DelText != nil {
+		return child.DelText.Text
+	}
+	return runChildText(child)
+}
+
+// splitRun returns the runs a run is split into around the placeholders of spans, given the
+// offset pos of the run in the text the spans were found in. The placeholder of a span goes
+// in the run where the span starts; the rest of the text of the span is removed.
+func (r *redactor) splitRun(run *ctypes.Run, pos int, spans []redactSpan) []*ctypes.Run {
+	var out []*ctypes.Run
+	fresh := func() *ctypes.Run {
+		c := *run
+		c.Property = copyRunProp(run.Property)
+		c.Children = nil
+		return &c
+	}
+	cur := fresh()
+	flush := func() {
+		if len(cur.Children) > 0 {
+			out = append(out, cur)
+		}
+		cur = fresh()
+	}
+
+	si := 0
+	for _, child := range run.Children {
+		text := revisionChildText(child)
+		width := len(text)
+		if width == 0 {
+			cur.Children = append(cur.Children, child)
+			continue
+		}
+
+		for off := 0; off < width; {
+			at := pos + off
+			for si < len(spans) && spans[si].end <= at {
+				si++
+			}
+
+			if si < len(spans) && spans[si].start <= at {
+				if at == spans[si].start {
+					flush()
+					placeholder := &ctypes.Run{Property: r.props(run.Property)}
+					if child.DelText != nil {
+						placeholder.Children = []ctypes.RunChild{{DelText: ctypes.TextFromString(r.placeholder)}}
+					} else {
+						placeholder.Children = []ctypes.RunChild{{Text: ctypes.TextFromString(r.placeholder)}}
+					}
+					out = append(out, placeholder)
+				}
+				off = spans[si].end - pos
+				if off > width {
+					off = width
+				}
+				continue
+			}
+
+			next := width
+			if si < len(spans) && spans[si].start-pos < width {
+				next = spans[si].start - pos
+			}
+			switch {
+			case child.Text != nil:
+				cur.Children = append(cur.Children, ctypes.RunChild{Text: ctypes.TextFromString(text[off:next])})
+			case child.DelText != nil:
+				cur.Children = append(cur.Children, ctypes.RunChild{DelText: ctypes.TextFromString(text[off:next])})
+			default:
+				cur.Children = append(cur.Children, child)
+			}
+			off = next
+		}
+		pos += width
+	}
+	flush()
+	return out
+}
+
+// forEachRun calls fn for each run of a list of paragraph children, including the runs of
+// hyperlinks and tracked changes.
+func forEachRun(children []ctypes.ParagraphChild, fn func(*ctypes.Run)) {
+	for _, child := range children {
+		if child.Run != nil {
+			fn(child.Run)
+		}
+		if child.Link != nil {
+			if child.Link.Run != nil {
+				fn(child.Link.Run)
+			}
+			forEachRun(child.Link.Children, fn)
+		}
+		for _, tc := range []*ctypes.RunTrackChange{child.Ins, child.Del} {
+			if tc == nil {
+				continue
+			}
+			for _, run := range tc.Runs {
+				fn(run)
+			}
+		}
+	}
+}
+
+// drawing redacts the alternative text and names of the pictures of a drawing.
+func (r *redactor) drawing(d *dml.Drawing, loc string) {
+	redact := func(docProp *dml.DocProp, graphic *dml.Graphic) {
+		docProp.Description = r.redactString(docProp.Description, loc+" alt text")
+		docProp.Name = r.redactString(docProp.Name, loc+" picture name")
+		if graphic.Data != nil && graphic.Data.Pic != nil {
+			pr := &graphic.Data.Pic.NonVisualPicProp.CNvPr
+			pr.Description = r.redactString(pr.Description, loc+" alt text")
+			pr.Name = r.redactString(pr.Name, loc+" picture name")
+		}
+	}
+	for i := range d.Inline {
+		redact(&d.Inline[i].DocProp, &d.Inline[i].Graphic)
+	}
+	for _, anchor := range d.Anchor {
+		if anchor != nil {
+			redact(&anchor.DocProp, &anchor.Graphic)
+		}
+	}
+}
+
+// relations redacts the addresses of the external relationships of a part, such as the
+// addresses of hyperlinks. The placeholder is escaped to keep the addresses valid.
+func (r *redactor) relations(relsPath string, rels []*Relationship) bool {
+	changed := false
+	for _, rel := range rels {
+		if rel.TargetMode != "External" {
+			continue
+		}
+		target := r.redactString(rel.Target, relsPath+" "+rel.ID)
+		if target != rel.Target {
+			rel.Target = strings.ReplaceAll(target, r.placeholder, url.PathEscape(r.placeholder))
+			changed = true
+		}
+	}
+	return changed
+}
+
+// propertyElements are the elements of the document property parts whose text is redacted,
+// by relationship type. Dates, counts and the like are left out so that the parts stay valid.
+var propertyElements = map[string]map[string]bool{
+	constants.CORE_PROP_TYPE: {
+		"title": true, "subject": true, "creator": true, "keywords": true, "description": true,
+		"lastModifiedBy": true, "category": true, "contentStatus": true, "identifier": true,
+	},
+	constants.EXTENDED_PROP_TYPE: {
+		"Company": true, "Manager": true, "HyperlinkBase": true, "lpstr": true, "lpwstr": true,
+	},
+	constants.CUSTOM_PROP_TYPE: {"lpstr": true, "lpwstr": true, "bstr": true},
+}
+
+// wmlTextElements are the WordprocessingML elements holding text.
+var wmlTextElements = map[string]bool{"t": true, "delText": true, "instrText": true, "delInstrText": true}
+
+// rawParts redacts the parts of the package that are kept as read rather than written from
+// the document model: the document properties, the relationships of parts other than the
+// main document, and WordprocessingML parts such as the glossary document.
+func (r *redactor) rawParts(rd *RootDoc) error {
+	model := rd.modelParts()
+
+	props := make(map[string]map[string]bool)
+	for _, rel := range rd.RootRels.Relationships {
+		if elements, ok := propertyElements[rel.Type]; ok && rel.TargetMode != "External" {
+			props[strings.TrimPrefix(rel.Target, "/")] = elements
+		}
+	}
+
+	var names []string
+	rd.FileMap.Range(func(key, _ any) bool {
+		names = append(names, key.(string))
+		return true
+	})
+	sort.Strings(names)
+
+	for _, name := range names {
+		if model[name] {
+			continue
+		}
+		value, _ := rd.FileMap.Load(name)
+		content := value.([]byte)
+
+		elements, ok := props[name]
+		switch {
+		case ok:
+		case strings.HasSuffix(name, ".rels"):
+			var rels Relationships
+			if err := xml.Unmarshal(content, &rels); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			if !r.relations(name, rels.Relationships) {
+				continue
+			}
+			out, err := marshal(rels)
+			if err != nil {
+				return err
+			}
+			rd.FileMap.Store(name, out)
+			continue
+		case strings.HasPrefix(name, "word/") && path.Ext(name) == ".xml":
+			elements = wmlTextElements
+		default:
+			continue
+		}
+
+		out, err := r.xmlText(content, elements, name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if out != nil {
+			rd.FileMap.Store(name, out)
+		}
+	}
+	return nil
+}
+
+// modelParts returns the paths of the parts written from the document model rather than
+// kept as read.
+func (rd *RootDoc) modelParts() map[string]bool {
+	model := map[string]bool{
+		rd.Document.relativePath:         true,
+		rd.DocStyles.RelativePath:        true,
+		rd.Document.DocRels.RelativePath: true,
+		rd.RootRels.RelativePath:         true,
+	}
+	for _, part := range rd.storyParts {
+		model[part.path] = true
+	}
+	return model
+}
+
+// xmlText redacts the text of the given elements in an XML part, leaving the rest of the part
+// as it is. It returns nil when nothing is redacted.
+func (r *redactor) xmlText(content []byte, elements map[string]bool, name string) ([]byte, error) {
+	type edit struct {
+		start, end int64
+		text       string
+	}
+	var edits []edit
+	var stack []string
+
+	d := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		start := d.InputOffset()
+		tok, err := d.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, tok.Name.Local)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(stack) == 0 || !elements[stack[len(stack)-1]] {
+				continue
+			}
+			text := string(tok)
+			redacted := r.redactString(text, name+" "+stack[len(stack)-1])
+			if redacted == text {
+				continue
+			}
+			var buf bytes.Buffer
+			if err := xml.EscapeText(&buf, []byte(redacted)); err != nil {
+				return nil, err
+			}
+			edits = append(edits, edit{start: start, end: d.InputOffset(), text: buf.String()})
+		}
+	}
+	if len(edits) == 0 {
+		return nil, nil
+	}
+
+	var out bytes.Buffer
+	pos := int64(0)
+	for _, e := range edits {
+		out.Write(content[pos:e.start])
+		out.WriteString(e.text)
+		pos = e.end
+	}
+	out.Write(content[pos:])
+	return out.Bytes(), nil
+}
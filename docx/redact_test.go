@@ -0,0 +1,199 @@
+package docx_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var redactMatchers = []docx.RedactMatcher{
+	{Name: "email", Pattern: regexp.MustCompile(`[\w.]+@[\w.]+\.\w+`)},
+	{Name: "customer", Literals: []string{"John Smith", "Acme"}, IgnoreCase: true},
+}
+
+func buildRedactDoc(t *testing.T) *docx.RootDoc {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+
+	p := rd.AddParagraph("Contact John ")
+	p.AddText("Smith").Bold(true)
+	p.AddText(" at ")
+	p.AddLink("his address", "mailto:john@acme.com")
+	p.AddText(".")
+	p.GetCT().Children = append(p.GetCT().Children, ctypes.ParagraphChild{Del: &ctypes.RunTrackChange{
+		TrackChange: ctypes.TrackChange{ID: 90, Author: "Editor"},
+		Runs: []*ctypes.Run{
+			{Children: []ctypes.RunChild{{DelText: ctypes.TextFromString(" Formerly of JOHN ")}}},
+			{Children: []ctypes.RunChild{{DelText: ctypes.TextFromString("SMITH Ltd.")}}},
+		},
+	}})
+
+	rels := &rd.Document.DocRels.Relationships
+	*rels = append(*rels, &docx.Relationship{ID: "rIdHeader", Type: constants.SourceRelationshipHeader, Target: "header1.xml"})
+	rd.FileMap.Store("word/header1.xml", []byte(`<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`+
+		`<w:p><w:hyperlink r:id="rId1"><w:r><w:t>Acme Corp</w:t></w:r></w:hyperlink></w:p></w:hdr>`))
+	rd.FileMap.Store("word/_rels/header1.xml.rels", []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+		`<Relationship Id="rId1" Type="`+constants.SourceRelationshipHyperLink+`" Target="https://acme.com/" TargetMode="External"/></Relationships>`))
+
+	r, err := p.Range(8, 18)
+	require.NoError(t, err)
+	_, err = r.AddComment(docx.Comment{Author: "John Smith", Initials: "JS", Text: "Ask john@acme.com first."})
+	require.NoError(t, err)
+
+	row := rd.AddTable().AddRow()
+	row.AddCell().AddParagraph("Customer")
+	row.AddCell().AddParagraph("ACME")
+
+	pic, err := rd.AddPicture("../godocx.png", units.Inch(1), units.Inch(1))
+	require.NoError(t, err)
+	pic.Inline.DocProp.Description = "Photo of John Smith"
+
+	rd.FileMap.Store("word/glossary/document.xml", []byte(`<w:glossaryDocument xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+		`<w:docParts><w:docPart><w:docPartBody><w:p><w:r><w:t>Dear John Smith &amp; co</w:t></w:r></w:p></w:docPartBody></w:docPart></w:docParts></w:glossaryDocument>`))
+
+	core, ok := rd.FileMap.Load("docProps/core.xml")
+	require.True(t, ok)
+	rd.FileMap.Store("docProps/core.xml", bytes.Replace(core.([]byte), []byte("<dc:title/>"), []byte("<dc:title>Notes on John Smith</dc:title>"), 1))
+	return rd
+}
+
+func TestRedact(t *testing.T) {
+	rd := buildRedactDoc(t)
+
+	audit, err := rd.Redact(redactMatchers, nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, rd.Write(&buf))
+	for name, content := range readZip(t, buf.Bytes()) {
+		lower := strings.ToLower(string(content))
+		for _, secret := range []string{"john", "acme"} {
+			assert.NotContains(t, lower, secret, "%s still holds %q", name, secret)
+		}
+	}
+
+	assert.Equal(t, "Contact [REDACTED] at his address.", rd.Document.Body.Children[0].Para.Text())
+	core, err := rd.CoreProperties()
+	require.NoError(t, err)
+	assert.Equal(t, "Notes on [REDACTED]", core.Title)
+	assert.Equal(t, "2013-12-23T23:15:00Z", core.Created, "dates are left alone")
+
+	assert.Contains(t, audit, docx.Redaction{Matcher: "customer", Location: "body", Text: "John Smith"})
+	assert.Contains(t, audit, docx.Redaction{Matcher: "customer", Location: "body deletion", Text: "JOHN SMITH"})
+	assert.Contains(t, audit, docx.Redaction{Matcher: "email", Location: "comment 0", Text: "john@acme.com"})
+	assert.Contains(t, audit, docx.Redaction{Matcher: "customer", Location: "comment 0 author", Text: "John Smith"})
+	assert.Contains(t, audit, docx.Redaction{Matcher: "customer", Location: "header word/header1.xml", Text: "Acme"})
+	assert.Contains(t, audit, docx.Redaction{Matcher: "customer", Location: "body alt text", Text: "John Smith"})
+	var link *docx.Relationship
+	for _, rel := range rd.Document.DocRels.Relationships {
+		if rel.Type == constants.SourceRelationshipHyperLink {
+			link = rel
+		}
+	}
+	require.NotNil(t, link)
+	assert.Equal(t, "mailto:%5BREDACTED%5D", link.Target)
+	assert.Contains(t, audit, docx.Redaction{Matcher: "email", Location: "word/_rels/document.xml.rels " + link.ID, Text: "john@acme.com"})
+	assert.Contains(t, audit, docx.Redaction{Matcher: "customer", Location: "word/_rels/header1.xml.rels rId1", Text: "acme"})
+	assert.Contains(t, audit, docx.Redaction{Matcher: "customer", Location: "word/glossary/document.xml t", Text: "John Smith"})
+	assert.Contains(t, audit, docx.Redaction{Matcher: "customer", Location: "docProps/core.xml title", Text: "John Smith"})
+}
+
+func TestRedact_BlackBox(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+	p := rd.AddParagraph("Signed by John Smith.")
+
+	audit, err := rd.Redact(redactMatchers[1:], &docx.RedactOptions{Placeholder: "XXXX", BlackBox: true})
+	require.NoError(t, err)
+	require.Len(t, audit, 1)
+
+	assert.Equal(t, "Signed by XXXX.", p.Text())
+	runs := p.GetCT().Children
+	require.Len(t, runs, 3)
+	props := runs[1].Run.Property
+	require.NotNil(t, props)
+	assert.Equal(t, "000000", props.Color.Val)
+	assert.Equal(t, "000000", *props.Shading.Fill)
+
+	_, err = rd.Redact([]docx.RedactMatcher{{Name: "empty"}}, nil)
+	assert.Error(t, err)
+}
+
+func TestRedact_AcrossRevisions(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+	p := rd.AddParagraph("Call John Sm")
+	p.GetCT().Children = append(p.GetCT().Children,
+		ctypes.ParagraphChild{Ins: &ctypes.RunTrackChange{
+			TrackChange: ctypes.TrackChange{ID: 1, Author: "Jane Roe"},
+			Runs:        []*ctypes.Run{{Children: []ctypes.RunChild{{Text: ctypes.TextFromString("ith today")}}}},
+		}},
+		ctypes.ParagraphChild{Del: &ctypes.RunTrackChange{
+			TrackChange: ctypes.TrackChange{ID: 2, Author: "Jane Roe"},
+			Runs:        []*ctypes.Run{{Children: []ctypes.RunChild{{DelText: ctypes.TextFromString("ith, then Ac")}}}},
+		}},
+		ctypes.ParagraphChild{Run: &ctypes.Run{Children: []ctypes.RunChild{{Text: ctypes.TextFromString("me.")}}}},
+	)
+
+	audit, err := rd.Redact(redactMatchers[1:], nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []docx.Redaction{
+		{Matcher: "customer", Location: "body insertion", Text: "John Smith"},
+		{Matcher: "customer", Location: "body deletion", Text: "Acme"},
+	}, audit)
+	assert.Equal(t, "Call [REDACTED] today.", p.Text())
+
+	var deleted string
+	for _, child := range p.GetCT().Children {
+		if child.Del != nil {
+			for _, run := range child.Del.Runs {
+				for _, rc := range run.Children {
+					deleted += rc.DelText.Text
+				}
+			}
+		}
+	}
+	assert.Equal(t, "ith, then [REDACTED]", deleted)
+}
+
+func TestRedact_TextBoxes(t *testing.T) {
+	const txbx = `<w:txbxContent><w:p><w:r><w:t>Call John Smith</w:t></w:r></w:p><w:p><w:r><w:t>on Monday</w:t></w:r></w:p></w:txbxContent>`
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+	rd.AddParagraph("Intro")
+	rd = rewrite(t, rd, func(files map[string][]byte) {
+		box := `<w:p><w:r><mc:AlternateContent xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006">` +
+			`<mc:Choice Requires="wps"><w:drawing><wp:anchor><a:graphic><a:graphicData><wps:wsp><wps:txbx>` + txbx + `</wps:txbx></wps:wsp></a:graphicData></a:graphic></wp:anchor></w:drawing></mc:Choice>` +
+			`<mc:Fallback><w:pict><v:shape><v:textbox>` + txbx + `</v:textbox></v:shape></w:pict></mc:Fallback>` +
+			`</mc:AlternateContent></w:r></w:p>`
+		files["word/document.xml"] = bytes.Replace(files["word/document.xml"], []byte("</w:body>"), []byte(box+"</w:body>"), 1)
+	})
+
+	rels := &rd.Document.DocRels.Relationships
+	*rels = append(*rels, &docx.Relationship{ID: "rIdHeader", Type: constants.SourceRelationshipHeader, Target: "header1.xml"})
+	rd.FileMap.Store("word/header1.xml", []byte(`<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+		`<w:p><w:r><w:pict><v:shape><v:textbox><w:txbxContent><w:p><w:r><w:t>Draft &amp; notes</w:t></w:r></w:p></w:txbxContent></v:textbox></v:shape></w:pict></w:r></w:p></w:hdr>`))
+
+	audit, err := rd.Redact(redactMatchers, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []docx.Redaction{
+		{Matcher: "customer", Location: "body text box", Text: "Call John Smith\non Monday"},
+		{Location: "header word/header1.xml text box", Text: "Draft & notes"},
+	}, audit)
+
+	var buf bytes.Buffer
+	require.NoError(t, rd.Write(&buf))
+	files := readZip(t, buf.Bytes())
+	assert.NotContains(t, string(files["word/document.xml"]), "John Smith")
+	assert.NotContains(t, string(files["word/header1.xml"]), "Draft")
+}
@@ -0,0 +1,105 @@
+package docx
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/gomutex/godocx/wml/ctypes"
+)
+
+// ReplaceOptions controls how Replace and ReplaceRegexp change the text of a document.
+type ReplaceOptions struct {
+	// Limit is the maximum number of replacements to make. Zero means no limit.
+	Limit int
+
+	// IgnoreCase makes Replace match text regardless of case. ReplaceRegexp uses the flags
+	// of its expression instead.
+	IgnoreCase bool
+
+	// Format is the formatting of the replacement text. When nil, the replacement takes the
+	// formatting of the run where the match starts.
+	Format *ctypes.RunProperty
+}
+
+// Replace replaces each occurrence of old with new and returns the number of replacements.
+//
+// Text is matched across run and hyperlink boundaries, so a phrase that Word has split into
+// several runs is still found, but not across paragraphs. Tabs and line breaks match "\t" and
+// "\n". Matches are replaced in the body, in table cells and in headers, footers, footnotes,
+// endnotes and comments.
+func (rd *RootDoc) Replace(old, new string, opts *ReplaceOptions) (int, error) {
+	if old == "" {
+		return 0, errors.New("replace: empty search text")
+	}
+
+	pattern := regexp.QuoteMeta(old)
+	if opts != nil && opts.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+
+	return rd.replaceMatches(regexp.MustCompile(pattern), opts, func(string, []int) string {
+		return new
+	})
+}
+
+// ReplaceRegexp replaces the matches of re with repl and returns the number of replacements.
+// Inside repl, $ signs are interpreted as in regexp.Regexp.Expand, so $1 stands for the text
+// of the first submatch. Empty matches are ignored.
+//
+// Matching works as described for Replace.
+func (rd *RootDoc) ReplaceRegexp(re *regexp.Regexp, repl string, opts *ReplaceOptions) (int, error) {
+	return rd.replaceMatches(re, opts, func(text string, match []int) string {
+		return string(re.ExpandString(nil, repl, text, match))
+	})
+}
+
+// replaceMatches replaces the matches of re in every paragraph of the document with the text
+// returned by expand.
+func (rd *RootDoc) replaceMatches(re *regexp.Regexp, opts *ReplaceOptions, expand func(text string, match []int) string) (int, error) {
+	if opts == nil {
+		opts = &ReplaceOptions{}
+	}
+
+	paras := blockParagraphs(rd.Document.Body.Children)
+
+	stories, err := rd.Stories()
+	if err != nil {
+		return 0, err
+	}
+	for _, s := range stories {
+		paras = append(paras, blockParagraphs(s.Children)...)
+	}
+
+	count := 0
+	for _, p := range paras {
+		text := paragraphText(p)
+
+		var matches [][]int
+		for _, m := range re.FindAllStringSubmatchIndex(text, -1) {
+			if m[0] == m[1] {
+				continue
+			}
+			if opts.Limit > 0 && count == opts.Limit {
+				break
+			}
+			matches = append(matches, m)
+			count++
+		}
+
+		// Later matches are replaced first so that the offsets of earlier ones stay valid.
+		for i := len(matches) - 1; i >= 0; i-- {
+			m := matches[i]
+			props := opts.Format
+			if props == nil {
+				props = runPropAt(p, m[0])
+			}
+			replaceText(p, m[0], m[1], expand(text, m), copyRunProp(props))
+		}
+
+		if opts.Limit > 0 && count == opts.Limit {
+			break
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,164 @@
+package docx
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCommentsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:comment w:id="0" w:author="Ada" w:date="2024-01-02T00:00:00Z" w:initials="A"><w:p><w:r><w:t>Check ACME figures</w:t></w:r></w:p></w:comment>` +
+	`</w:comments>`
+
+// addTestInsertion appends text to a paragraph as a tracked insertion.
+func addTestInsertion(p *Paragraph, text string) {
+	p.ct.Children = append(p.ct.Children, ctypes.ParagraphChild{Ins: &ctypes.RunTrackChange{
+		TrackChange: ctypes.TrackChange{ID: 1, Author: "Ada"},
+		Runs:        []*ctypes.Run{{Children: []ctypes.RunChild{{Text: ctypes.TextFromString(text)}}}},
+	}})
+}
+
+func TestReplace_AcrossRuns(t *testing.T) {
+	rd := setupRootDoc(t)
+	p := rd.AddParagraph("Dear Mr. Sm")
+	p.AddText("it").Bold(true)
+	p.AddText("h, welcome.")
+
+	count, err := rd.Replace("Mr. Smith", "Ms. Jones", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "Dear Ms. Jones, welcome.", paragraphText(p.GetCT()))
+
+	children := p.GetCT().Children
+	require.Len(t, children, 3)
+	assert.Equal(t, "Dear ", runText(children[0].Run))
+	assert.Equal(t, "Ms. Jones", runText(children[1].Run))
+	assert.Nil(t, children[1].Run.Property, "the replacement takes the formatting of the first matched run")
+	assert.Equal(t, ", welcome.", runText(children[2].Run))
+}
+
+func TestReplace_Hyperlink(t *testing.T) {
+	rd := setupRootDoc(t)
+	p := rd.AddParagraph("Visit our ")
+	p.AddLink("web site", "https://example.com")
+	p.AddText(" today")
+
+	count, err := rd.Replace("our web", "the", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "Visit the site today", paragraphText(p.GetCT()))
+
+	link := p.GetCT().Children[2].Link
+	require.NotNil(t, link)
+	assert.Equal(t, " site", runText(link.Run))
+	assert.Empty(t, link.Children)
+
+	count, err = rd.Replace("site to", "page to", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "Visit the page today", paragraphText(p.GetCT()))
+	assert.Equal(t, "day", runText(p.GetCT().Children[3].Run))
+
+	link = p.GetCT().Children[2].Link
+	require.Len(t, link.Children, 1)
+	assert.Equal(t, "page to", runText(link.Children[0].Run), "text matched from within a link stays in the link")
+	assert.Equal(t, constants.HyperLinkStyle, link.Children[0].Run.Property.Style.Val)
+}
+
+func TestReplace_Options(t *testing.T) {
+	rd := setupRootDoc(t)
+	p := rd.AddParagraph("red, Red and RED")
+
+	format := &ctypes.RunProperty{Bold: ctypes.OnOffFromBool(true)}
+	count, err := rd.Replace("red", "blue", &ReplaceOptions{IgnoreCase: true, Limit: 2, Format: format})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, "blue, blue and RED", paragraphText(p.GetCT()))
+
+	children := p.GetCT().Children
+	require.Len(t, children, 4)
+	assert.NotNil(t, children[0].Run.Property.Bold)
+	assert.Nil(t, children[1].Run.Property)
+	assert.NotNil(t, children[2].Run.Property.Bold)
+	assert.NotSame(t, format, children[2].Run.Property)
+
+	_, err = rd.Replace("", "x", nil)
+	assert.Error(t, err)
+}
+
+func TestReplaceRegexp(t *testing.T) {
+	rd := setupRootDoc(t)
+	rd.AddParagraph("Total:\t42 EUR")
+	tbl := rd.AddTable()
+	cell := tbl.AddRow().AddCell()
+	cell.AddParagraph("Price 7 EUR, tax 1 EUR")
+
+	count, err := rd.ReplaceRegexp(regexp.MustCompile(`(\d+) EUR`), "€$1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	body := rd.Document.Body.Children
+	assert.Equal(t, "Total:\t€42", paragraphText(body[0].Para.GetCT()))
+	assert.Equal(t, "Price €7, tax €1", paragraphText(cell.ct.Contents[0].Paragraph))
+
+	count, err = rd.ReplaceRegexp(regexp.MustCompile(`:\s*`), " = ", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "Total = €42", paragraphText(body[0].Para.GetCT()))
+}
+
+func TestReplace_Stories(t *testing.T) {
+	rd := setupStoryDoc(t)
+	addTestStoryPart(rd, constants.SourceRelationshipComments, "comments.xml", testCommentsXML)
+	rd.AddParagraph("ACME ships worldwide")
+
+	count, err := rd.Replace("appendix", "annex", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = rd.Replace("ACME", "Initech", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	stories, err := rd.Stories()
+	require.NoError(t, err)
+	require.Len(t, stories, 3)
+	assert.Equal(t, " See annex.", paragraphText(stories[1].Children[0].Para.ct))
+
+	comment := stories[2]
+	assert.Equal(t, StoryComment, comment.Type)
+	assert.Equal(t, "Check Initech figures", paragraphText(comment.Children[0].Para.ct))
+
+	out, err := marshal(rd.storyParts[2])
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `<w:comments xmlns:w="`)
+	assert.Contains(t, string(out), `<w:comment w:id="0" w:author="Ada" w:date="2024-01-02T00:00:00Z" w:initials="A">`)
+}
+
+func TestReplace_Insertion(t *testing.T) {
+	rd := setupRootDoc(t)
+	p := rd.AddParagraph("Hello ")
+	addTestInsertion(p, "big ")
+	p.AddText("world")
+
+	count, err := rd.Replace("world", "there", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "Hello big there", p.Text())
+
+	count, err = rd.Replace("big", "small", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "Hello small there", p.Text())
+
+	ins := p.GetCT().Children[1].Ins
+	require.NotNil(t, ins, "text replaced within an insertion stays inserted")
+	require.Len(t, ins.Runs, 2)
+	assert.Equal(t, "small", runText(ins.Runs[0]))
+	assert.Equal(t, " ", runText(ins.Runs[1]))
+}
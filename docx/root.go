@@ -15,12 +15,21 @@ type RootDoc struct {
 	FileMap     sync.Map      // FileMap is a synchronized map for managing files related to the document.
 	RootRels    Relationships // RootRels represents relationships at the root level.
 	ContentType ContentTypes
-	Document    *Document      // Document is the main document structure.
-	DocStyles   *ctypes.Styles // Document styles
+	Document    *Document         // Document is the main document structure.
+	DocStyles   *ctypes.Styles    // Document styles
 	Numbering   *NumberingManager // Numbering manager for list instances
 
 	rID        int // rId is used to generate unique relationship IDs.
 	ImageCount uint
+
+	storyParts    []*storyPart // storyParts are the header, footer and note parts loaded by Stories.
+	storiesLoaded bool
+
+	// mu guards the state shared by content built in several goroutines: the relationship,
+	// image and bookmark counters, the document relationships and the content types.
+	mu               sync.Mutex
+	bookmarkID       int  // bookmarkID is the highest bookmark ID in use.
+	bookmarksScanned bool // bookmarksScanned tells whether bookmarkID accounts for the body.
 }
 
 // NewRootDoc creates a new instance of the RootDoc structure.
@@ -50,6 +59,9 @@ func LoadDocXml(rd *RootDoc, fileName string, fileBytes []byte) (*Document, erro
 	}
 
 	doc.relativePath = fileName
+	if doc.textBoxes, err = textBoxTexts(fileBytes); err != nil {
+		return nil, err
+	}
 	return &doc, nil
 }
 
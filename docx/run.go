@@ -8,6 +8,8 @@ import (
 type Run struct {
 	root *RootDoc    // root is the root document to which this run belongs.
 	ct   *ctypes.Run // ct is the underlying run element from the wml/ctypes package.
+
+	para *ctypes.Paragraph // para is the paragraph holding the run, when known.
 }
 
 func newRun(root *RootDoc, ct *ctypes.Run) *Run {
@@ -205,3 +207,73 @@ func (r *Run) VerticalAlign(value stypes.VerticalAlignRun) *Run {
 	r.getProp().VertAlign = ctypes.NewGenSingleStrVal(value)
 	return r
 }
+
+// Text returns the text of the run. Tabs are returned as "\t" and line breaks as "\n".
+func (r *Run) Text() string {
+	return runText(r.ct)
+}
+
+// StyleID returns the ID of the character style of the run, or "" when it has none.
+func (r *Run) StyleID() string {
+	if r.ct.Property == nil || r.ct.Property.Style == nil {
+		return ""
+	}
+	return r.ct.Property.Style.Val
+}
+
+// IsBold reports whether bold is turned on directly on the run.
+// Formatting inherited from styles is not taken into account.
+func (r *Run) IsBold() bool {
+	return r.ct.Property != nil && isOn(r.ct.Property.Bold)
+}
+
+// IsItalic reports whether italic is turned on directly on the run.
+func (r *Run) IsItalic() bool {
+	return r.ct.Property != nil && isOn(r.ct.Property.Italic)
+}
+
+// IsStrike reports whether strikethrough is turned on directly on the run.
+func (r *Run) IsStrike() bool {
+	return r.ct.Property != nil && isOn(r.ct.Property.Strike)
+}
+
+// GetUnderline returns the underline style of the run, or "" when it has none.
+func (r *Run) GetUnderline() stypes.Underline {
+	if r.ct.Property == nil || r.ct.Property.Underline == nil {
+		return ""
+	}
+	return r.ct.Property.Underline.Val
+}
+
+// FontSize returns the font size of the run in points, or 0 when it is inherited.
+func (r *Run) FontSize() float64 {
+	if r.ct.Property == nil || r.ct.Property.Size == nil {
+		return 0
+	}
+	return float64(r.ct.Property.Size.Value) / 2
+}
+
+// FontName returns the font of the run, or "" when it is inherited.
+func (r *Run) FontName() string {
+	if r.ct.Property == nil || r.ct.Property.Fonts == nil {
+		return ""
+	}
+	return r.ct.Property.Fonts.Ascii
+}
+
+// GetColor returns the text color of the run as a hex code such as "FF0000", or "" when it is
+// inherited.
+func (r *Run) GetColor() string {
+	if r.ct.Property == nil || r.ct.Property.Color == nil {
+		return ""
+	}
+	return r.ct.Property.Color.Val
+}
+
+// GetHighlight returns the highlight color of the run, or "" when it has none.
+func (r *Run) GetHighlight() string {
+	if r.ct.Property == nil || r.ct.Property.Highlight == nil {
+		return ""
+	}
+	return r.ct.Property.Highlight.Val
+}
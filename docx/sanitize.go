@@ -0,0 +1,772 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// SanitizeOptions configures Sanitize. The zero value removes everything Sanitize can remove
+// and accepts the tracked changes.
+type SanitizeOptions struct {
+	// RejectRevisions rejects the tracked changes instead of accepting them: inserted content
+	// is removed and deleted content is restored.
+	RejectRevisions bool
+
+	// KeepComments keeps the comments and their authors.
+	KeepComments bool
+
+	// KeepHiddenText keeps the text formatted as hidden.
+	KeepHiddenText bool
+
+	// KeepCustomXML keeps the custom XML data parts, such as the metadata of a document
+	// management system.
+	KeepCustomXML bool
+}
+
+// SanitizeKind is the kind of information removed by Sanitize.
+type SanitizeKind string
+
+const (
+	SanitizeAuthor           SanitizeKind = "author"            // the author or last editor in the core properties
+	SanitizeRevisionID       SanitizeKind = "revision id"       // revision save IDs (rsid attributes)
+	SanitizeComment          SanitizeKind = "comment"           // a comment
+	SanitizeRevision         SanitizeKind = "revision"          // a tracked change, accepted or rejected
+	SanitizeHiddenText       SanitizeKind = "hidden text"       // text formatted as hidden
+	SanitizeCustomXML        SanitizeKind = "custom xml"        // a custom XML data part
+	SanitizeDocumentVariable SanitizeKind = "document variable" // a document variable of the settings
+	SanitizeThumbnail        SanitizeKind = "thumbnail"         // the thumbnail of the package
+	SanitizeMedia            SanitizeKind = "media"             // an image no part uses
+	SanitizePrinterSettings  SanitizeKind = "printer settings"  // the printer settings of the document
+)
+
+// Removal is an entry of the report returned by Sanitize.
+type Removal struct {
+	Kind SanitizeKind `json:"kind"`
+
+	// Location tells where the information was: "body", a story such as "header word/header1.xml"
+	// or "footnote 2", or a part, followed by the element holding the information for parts
+	// kept as read, such as "docProps/core.xml creator".
+	Location string `json:"location"`
+
+	// Detail describes what was removed, such as the author of the document, the text of a
+	// comment or the name of a document variable. Like the audit list of Redact, the report
+	// holds the information removed and must not be shared along with the document.
+	Detail string `json:"detail"`
+}
+
+// String returns the removal as a single line, such as
+// `comment 0: "Check the figures" by John Smith`.
+func (r Removal) String() string {
+	if r.Detail == "" {
+		return fmt.Sprintf("%s: %s", r.Location, r.Kind)
+	}
+	return fmt.Sprintf("%s: %s", r.Location, r.Detail)
+}
+
+// Sanitize removes personal and hidden information from the document, so that it can be shared
+// outside of the organization it was written in, and returns a report of what was removed.
+//
+// It removes:
+//
+//   - the author and the last editor from the core properties;
+//   - the revision save IDs of paragraphs, runs and styles, and the list of them in the settings;
+//   - the comments, along with their marks in the text and the parts Word keeps beside them;
+//   - the tracked changes of text, paragraph marks, numbering and tables, which are accepted,
+//     or rejected with SanitizeOptions.RejectRevisions;
+//   - the runs formatted as hidden, directly or through their character style;
+//   - the custom XML data parts and the document variables;
+//   - the thumbnail of the package and the printer settings;
+//   - the images that no part uses any longer.
+//
+// Deleted paragraph marks are removed without joining the paragraphs around them. Custom XML
+// markup in the text is not part of the document model and is dropped when a part is read.
+func (rd *RootDoc) Sanitize(opts *SanitizeOptions) ([]Removal, error) {
+	if opts == nil {
+		opts = &SanitizeOptions{}
+	}
+	s := &sanitizer{rd: rd, opts: opts, rsids: make(map[string]int)}
+
+	if err := rd.loadStoryParts(); err != nil {
+		return nil, fmt.Errorf("sanitize: %w", err)
+	}
+	if !opts.KeepComments {
+		s.comments()
+	}
+
+	err := rd.Walk(Visitor{
+		VisitParagraph: func(ctx *WalkContext, p *Paragraph) WalkAction {
+			s.paragraph(p.ct, storyLocation(ctx.Story))
+			return WalkSkipChildren
+		},
+		VisitTable: func(ctx *WalkContext, t *Table) WalkAction {
+			s.table(t.ct, storyLocation(ctx.Story))
+			return WalkContinue
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sanitize: %w", err)
+	}
+
+	for i := range rd.DocStyles.StyleList {
+		if rd.DocStyles.StyleList[i].RevID != nil {
+			rd.DocStyles.StyleList[i].RevID = nil
+			s.countRsids(rd.DocStyles.RelativePath, 1)
+		}
+	}
+	if err := s.settings(); err != nil {
+		return nil, fmt.Errorf("sanitize: %w", err)
+	}
+	if err := s.rawRsids(); err != nil {
+		return nil, fmt.Errorf("sanitize: %w", err)
+	}
+	for _, loc := range s.rsidOrder {
+		s.add(SanitizeRevisionID, loc, fmt.Sprintf("%d revision save IDs", s.rsids[loc]))
+	}
+
+	if err := s.coreProperties(); err != nil {
+		return nil, fmt.Errorf("sanitize: %w", err)
+	}
+	s.parts()
+	if err := s.media(); err != nil {
+		return nil, fmt.Errorf("sanitize: %w", err)
+	}
+	return s.report, nil
+}
+
+type sanitizer struct {
+	rd     *RootDoc
+	opts   *SanitizeOptions
+	report []Removal
+
+	rsids     map[string]int // rsids counts the revision save IDs removed by location.
+	rsidOrder []string       // rsidOrder holds the locations of rsids in the order they were found.
+}
+
+func (s *sanitizer) add(kind SanitizeKind, loc, detail string) {
+	s.report = append(s.report, Removal{Kind: kind, Location: loc, Detail: detail})
+}
+
+func (s *sanitizer) countRsids(loc string, n int) {
+	if n == 0 {
+		return
+	}
+	if _, ok := s.rsids[loc]; !ok {
+		s.rsidOrder = append(s.rsidOrder, loc)
+	}
+	s.rsids[loc] += n
+}
+
+// commentRelTypes are the types of the relationships to the comments part and to the parts
+// Word keeps beside it, such as the people who wrote the comments.
+var commentRelTypes = map[string]bool{
+	constants.SourceRelationshipComments:                                           true,
+	constants.StrictSourceRelationshipComments:                                     true,
+	"http://schemas.microsoft.com/office/2011/relationships/commentsExtended":      true,
+	"http://schemas.microsoft.com/office/2016/09/relationships/commentsIds":        true,
+	"http://schemas.microsoft.com/office/2018/08/relationships/commentsExtensible": true,
+	"http://schemas.microsoft.com/office/2011/relationships/people":                true,
+}
+
+// comments removes the comments part and the parts related to it. The marks of the comments
+// in the text are removed along with the rest of the paragraphs.
+func (s *sanitizer) comments() {
+	var parts []*storyPart
+	for _, part := range s.rd.storyParts {
+		if part.typ != StoryComment {
+			parts = append(parts, part)
+			continue
+		}
+		for _, c := range part.stories {
+			var texts []string
+			for _, p := range blockParagraphs(c.Children) {
+				texts = append(texts, paragraphText(p))
+			}
+			detail := fmt.Sprintf("%q", strings.Join(texts, "\n"))
+			for _, a := range c.attrs {
+				if strings.TrimPrefix(a.Name.Local, "w:") == "author" && a.Value != "" {
+					detail += " by " + a.Value
+				}
+			}
+			s.add(SanitizeComment, storyLocation(c), detail)
+		}
+	}
+	s.rd.storyParts = parts
+	s.rd.removeRelations(commentRelTypes)
+}
+
+// paragraph removes the comment marks, tracked changes, hidden text and revision save IDs of
+// a paragraph.
+func (s *sanitizer) paragraph(p *ctypes.Paragraph, loc string) {
+	if !s.opts.KeepComments {
+		p.Children = dropCommentMarks(p.Children)
+	}
+
+	p.Children = s.revisions(p.Children, loc)
+	if prop := p.Property; prop != nil {
+		if mark := prop.RunProperty; mark != nil {
+			s.trackedContent(&mark.Ins, &mark.Del, loc, "paragraph mark")
+		}
+		if num := prop.NumProp; num != nil {
+			if num.NumChange != nil {
+				s.revision(loc, "numbering change", num.NumChange.Author, "")
+				num.NumChange = nil
+			}
+			if num.Ins != nil {
+				s.revision(loc, "numbering insertion", num.Ins.Author, "")
+				num.Ins = nil
+			}
+		}
+	}
+
+	if !s.opts.KeepHiddenText {
+		p.Children = s.hidden(p.Children, loc)
+	}
+
+	n := 0
+	for _, id := range []**stypes.LongHexNum{&p.RsidRPr, &p.RsidR, &p.RsidDel, &p.RsidP, &p.RsidRDefault} {
+		if *id != nil {
+			*id = nil
+			n++
+		}
+	}
+	forEachRun(p.Children, func(r *ctypes.Run) {
+		for _, id := range []**stypes.LongHexNum{&r.RsidRPr, &r.RsidR, &r.RsidDel} {
+			if *id != nil {
+				*id = nil
+				n++
+			}
+		}
+	})
+	s.countRsids(loc, n)
+}
+
+// dropCommentMarks removes the comment ranges and references from a list of paragraph
+// children. Runs left empty are removed.
+func dropCommentMarks(children []ctypes.ParagraphChild) []ctypes.ParagraphChild {
+	dropRefs := func(r *ctypes.Run) bool {
+		if len(r.Children) == 0 {
+			return true
+		}
+		var kept []ctypes.RunChild
+		for _, child := range r.Children {
+			if child.CmntRef == nil {
+				kept = append(kept, child)
+			}
+		}
+		r.Children = kept
+		return len(kept) > 0
+	}
+
+	out := make([]ctypes.ParagraphChild, 0, len(children))
+	for _, child := range children {
+		switch {
+		case child.CmntStart != nil || child.CmntEnd != nil:
+			continue
+		case child.Run != nil:
+			if !dropRefs(child.Run) {
+				continue
+			}
+		case child.Link != nil:
+			child.Link.Children = dropCommentMarks(child.Link.Children)
+		}
+
+		for _, tc := range []*ctypes.RunTrackChange{child.Ins, child.Del} {
+			if tc == nil {
+				continue
+			}
+			var runs []*ctypes.Run
+			for _, r := range tc.Runs {
+				if dropRefs(r) {
+					runs = append(runs, r)
+				}
+			}
+			tc.Runs = runs
+		}
+		out = append(out, child)
+	}
+	return out
+}
+
+// revision records a tracked change accepted or rejected.
+func (s *sanitizer) revision(loc, what, author, text string) {
+	detail := "accepted " + what
+	if s.opts.RejectRevisions {
+		detail = "rejected " + what
+	}
+	if text != "" {
+		detail += fmt.Sprintf(" %q", text)
+	}
+	if author != "" {
+		detail += " by " + author
+	}
+	s.add(SanitizeRevision, loc, detail)
+}
+
+// revisions accepts or rejects the tracked insertions and deletions of a list of paragraph
+// children and returns the new list.
+func (s *sanitizer) revisions(children []ctypes.ParagraphChild, loc string) []ctypes.ParagraphChild {
+	out := make([]ctypes.ParagraphChild, 0, len(children))
+	for _, child := range children {
+		switch {
+		case child.Ins != nil:
+			s.revision(loc, "insertion", child.Ins.Author, revisionRunsText(child.Ins.Runs))
+			if !s.opts.RejectRevisions {
+				for _, r := range child.Ins.Runs {
+					out = append(out, ctypes.ParagraphChild{Run: r})
+				}
+			}
+			continue
+		case child.Del != nil:
+			s.revision(loc, "deletion", child.Del.Author, revisionRunsText(child.Del.Runs))
+			if s.opts.RejectRevisions {
+				for _, r := range child.Del.Runs {
+					undelete(r)
+					out = append(out, ctypes.ParagraphChild{Run: r})
+				}
+			}
+			continue
+		case child.Link != nil:
+			child.Link.Children = s.revisions(child.Link.Children, loc)
+		}
+		out = append(out, child)
+	}
+	return out
+}
+
+// revisionRunsText returns the text of a list of runs, including deleted text.
+func revisionRunsText(runs []*ctypes.Run) string {
+	var sb strings.Builder
+	for _, r := range runs {
+		for _, child := range r.Children {
+			sb.WriteString(revisionChildText(child))
+		}
+	}
+	return sb.String()
+}
+
+// undelete turns the deleted text and field instructions of a run back into plain ones.
+func undelete(r *ctypes.Run) {
+	for i := range r.Children {
+		child := &r.Children[i]
+		if child.DelText != nil {
+			child.Text, child.DelText = child.DelText, nil
+		}
+		if child.DelInstrText != nil {
+			child.InstrText, child.DelInstrText = child.DelInstrText, nil
+		}
+	}
+}
+
+// trackedContent records and clears the tracked insertion and deletion of a paragraph mark,
+// a table row or a table cell. It reports whether the content goes along with the change.
+func (s *sanitizer) trackedContent(ins, del **ctypes.TrackChange, loc, what string) bool {
+	gone := false
+	if *ins != nil {
+		s.revision(loc, what+" insertion", (*ins).Author, "")
+		gone = s.opts.RejectRevisions
+		*ins = nil
+	}
+	if *del != nil {
+		s.revision(loc, what+" deletion", (*del).Author, "")
+		gone = gone || !s.opts.RejectRevisions
+		*del = nil
+	}
+	return gone
+}
+
+// table accepts or rejects the tracked changes of the grid, rows and cells of a table. The
+// paragraphs of the table are handled as the walk reaches them.
+func (s *sanitizer) table(t *ctypes.Table, loc string) {
+	if t.Grid.GridChange != nil {
+		s.revision(loc, "table grid change", "", "")
+		t.Grid.GridChange = nil
+	}
+
+	var rows []ctypes.RowContent
+	for _, rc := range t.RowContents {
+		row := rc.Row
+		if row == nil {
+			rows = append(rows, rc)
+			continue
+		}
+
+		if prop := row.Property; prop != nil {
+			if s.trackedContent(&prop.Ins, &prop.Del, loc, "table row") {
+				continue
+			}
+			if change := prop.Change; change != nil {
+				s.revision(loc, "table row formatting change", change.Author, "")
+				if s.opts.RejectRevisions {
+					*prop = change.Prop
+				}
+				prop.Change = nil
+			}
+		}
+
+		var cells []ctypes.TRCellContent
+		for _, cc := range row.Contents {
+			if cc.Cell != nil && cc.Cell.Property != nil {
+				prop := cc.Cell.Property
+				if s.trackedContent(&prop.CellInsertion, &prop.CellDeletion, loc, "table cell") {
+					continue
+				}
+				if change := prop.PrChange; change != nil {
+					s.revision(loc, "table cell formatting change", change.Author, "")
+					if s.opts.RejectRevisions {
+						*prop = change.Prop
+					}
+					prop.PrChange = nil
+				}
+			}
+			cells = append(cells, cc)
+		}
+		row.Contents = cells
+		rows = append(rows, rc)
+	}
+	t.RowContents = rows
+}
+
+// hidden removes the runs formatted as hidden from a list of paragraph children and returns
+// the new list. Hyperlinks left empty are removed.
+func (s *sanitizer) hidden(children []ctypes.ParagraphChild, loc string) []ctypes.ParagraphChild {
+	out := make([]ctypes.ParagraphChild, 0, len(children))
+	for _, child := range children {
+		switch {
+		case child.Run != nil && s.hiddenRun(child.Run):
+			s.add(SanitizeHiddenText, loc, runText(child.Run))
+			continue
+		case child.Link != nil:
+			if child.Link.Run != nil && s.hiddenRun(child.Link.Run) {
+				s.add(SanitizeHiddenText, loc, runText(child.Link.Run))
+				child.Link.Run = nil
+			}
+			child.Link.Children = s.hidden(child.Link.Children, loc)
+			if child.Link.Run == nil && len(child.Link.Children) == 0 {
+				continue
+			}
+		}
+		out = append(out, child)
+	}
+	return out
+}
+
+// hiddenRun reports whether a run is formatted as hidden, directly or through its character
+// style and the styles that style is based on.
+func (s *sanitizer) hiddenRun(r *ctypes.Run) bool {
+	if r.Property == nil {
+		return false
+	}
+	if r.Property.Vanish != nil {
+		return isOn(r.Property.Vanish)
+	}
+	if r.Property.Style == nil {
+		return false
+	}
+
+	// The chain of base styles is followed a limited number of times in case it loops.
+	styleID := r.Property.Style.Val
+	for i := 0; styleID != "" && i < 16; i++ {
+		style := s.rd.GetStyleByID(styleID, stypes.StyleTypeCharacter)
+		if style == nil {
+			return false
+		}
+		if style.RunProp != nil && style.RunProp.Vanish != nil {
+			return isOn(style.RunProp.Vanish)
+		}
+		if style.BasedOn == nil {
+			return false
+		}
+		styleID = style.BasedOn.Val
+	}
+	return false
+}
+
+// settings removes the document variables and the list of revision save IDs from the
+// settings part.
+func (s *sanitizer) settings() error {
+	var partPath string
+	for _, rel := range s.rd.Document.DocRels.Relationships {
+		if rel.Type == constants.SourceRelationshipSettings && rel.TargetMode != "External" {
+			partPath = s.rd.Document.partPath(rel.Target)
+		}
+	}
+	content, ok := s.rd.FileMap.Load(partPath)
+	if partPath == "" || !ok {
+		return nil
+	}
+
+	rsids := 0
+	out, err := removeElements(content.([]byte), map[string]bool{"rsids": true, "docVars": true}, func(elem xml.StartElement, _ string) {
+		switch elem.Name.Local {
+		case "rsid", "rsidRoot":
+			rsids++
+		case "docVar":
+			for _, a := range elem.Attr {
+				if a.Name.Local == "name" {
+					s.add(SanitizeDocumentVariable, partPath+" docVar", a.Value)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", partPath, err)
+	}
+	if out != nil {
+		s.rd.FileMap.Store(partPath, out)
+	}
+	if rsids > 0 {
+		s.add(SanitizeRevisionID, partPath+" rsids", fmt.Sprintf("%d revision save IDs", rsids))
+	}
+	return nil
+}
+
+// rsidAttr matches the revision save ID attributes of WordprocessingML elements.
+var rsidAttr = regexp.MustCompile(`\s+w:rsid\w*="[^"]*"`)
+
+// rawRsids removes the revision save IDs from the WordprocessingML parts kept as read, such
+// as the styles with effects and the glossary document.
+func (s *sanitizer) rawRsids() error {
+	model := s.rd.modelParts()
+	var names []string
+	s.rd.FileMap.Range(func(key, _ any) bool {
+		if name := key.(string); strings.HasPrefix(name, "word/") && path.Ext(name) == ".xml" && !model[name] {
+			names = append(names, name)
+		}
+		return true
+	})
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, _ := s.rd.FileMap.Load(name)
+		content := value.([]byte)
+
+		n := len(rsidAttr.FindAllIndex(content, -1))
+		content = rsidAttr.ReplaceAll(content, nil)
+		// Styles hold their revision save ID in an element of their own.
+		out, err := removeElements(content, map[string]bool{"rsid": true}, func(xml.StartElement, string) { n++ })
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if out != nil {
+			content = out
+		}
+		if n > 0 {
+			s.rd.FileMap.Store(name, content)
+			s.countRsids(name, n)
+		}
+	}
+	return nil
+}
+
+// coreProperties removes the author and the last editor from the core properties.
+func (s *sanitizer) coreProperties() error {
+	partPath := s.rd.corePropsPath()
+	content, ok := s.rd.FileMap.Load(partPath)
+	if partPath == "" || !ok {
+		return nil
+	}
+
+	out, err := removeElements(content.([]byte), map[string]bool{"creator": true, "lastModifiedBy": true}, func(elem xml.StartElement, text string) {
+		if text != "" {
+			s.add(SanitizeAuthor, partPath+" "+elem.Name.Local, text)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", partPath, err)
+	}
+	if out != nil {
+		s.rd.FileMap.Store(partPath, out)
+	}
+	return nil
+}
+
+// parts removes the printer settings, the thumbnail and the custom XML data parts.
+func (s *sanitizer) parts() {
+	for _, p := range s.rd.removeRelations(map[string]bool{constants.SourceRelationshipPrinterSettings: true}) {
+		s.add(SanitizePrinterSettings, p, "")
+	}
+
+	var rootRels []*Relationship
+	for _, rel := range s.rd.RootRels.Relationships {
+		if rel.Type != constants.THUMBNAIL_TYPE {
+			rootRels = append(rootRels, rel)
+			continue
+		}
+		if rel.TargetMode != "External" {
+			p := strings.TrimPrefix(rel.Target, "/")
+			s.rd.removePart(p)
+			s.add(SanitizeThumbnail, p, "")
+		}
+	}
+	s.rd.RootRels.Relationships = rootRels
+
+	if s.opts.KeepCustomXML {
+		return
+	}
+	removed := make(map[string]bool)
+	for _, p := range s.rd.removeRelations(map[string]bool{constants.SourceRelationshipCustomXML: true}) {
+		removed[p] = true
+	}
+	// The parts holding the properties of the data parts are related to the data parts only,
+	// and go along with the rest of the folder.
+	s.rd.FileMap.Range(func(key, _ any) bool {
+		if name := key.(string); strings.HasPrefix(name, "customXml/") {
+			removed[name] = true
+		}
+		return true
+	})
+	var names []string
+	for name := range removed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s.rd.removePart(name)
+		if !strings.HasSuffix(name, ".rels") {
+			s.add(SanitizeCustomXML, name, "")
+		}
+	}
+}
+
+// media removes the image relationships the main document no longer uses and the media
+// files no part is related to.
+func (s *sanitizer) media() error {
+	content, err := marshal(s.rd.Document)
+	if err != nil {
+		return err
+	}
+	used := make(map[string]bool)
+	for _, m := range relIDAttr.FindAllSubmatch(content, -1) {
+		used[string(m[1])] = true
+	}
+
+	var kept []*Relationship
+	targeted := make(map[string]bool)
+	for _, rel := range s.rd.Document.DocRels.Relationships {
+		if rel.Type == constants.SourceRelationshipImage && !used[rel.ID] {
+			continue
+		}
+		kept = append(kept, rel)
+		if rel.TargetMode != "External" {
+			targeted[s.rd.Document.partPath(rel.Target)] = true
+		}
+	}
+	s.rd.Document.DocRels.Relationships = kept
+
+	var names []string
+	s.rd.FileMap.Range(func(key, _ any) bool {
+		if name := key.(string); strings.HasPrefix(name, constants.MediaPath) {
+			names = append(names, name)
+		}
+		return true
+	})
+	sort.Strings(names)
+	for _, name := range names {
+		if !targeted[name] && !s.rd.partRelated(name) {
+			s.rd.removePart(name)
+			s.add(SanitizeMedia, name, "")
+		}
+	}
+	return nil
+}
+
+// removeRelations removes the relationships of the main document of the given types, along
+// with the parts they point at and the relationships of those parts. It returns the paths of
+// the parts removed.
+func (rd *RootDoc) removeRelations(types map[string]bool) []string {
+	var (
+		kept    []*Relationship
+		removed []string
+	)
+	for _, rel := range rd.Document.DocRels.Relationships {
+		if !types[rel.Type] {
+			kept = append(kept, rel)
+			continue
+		}
+		if rel.TargetMode == "External" {
+			continue
+		}
+		p := rd.Document.partPath(rel.Target)
+		rd.removePart(p)
+		rd.FileMap.Delete(path.Join(path.Dir(p), "_rels", path.Base(p)+".rels"))
+		removed = append(removed, p)
+	}
+	rd.Document.DocRels.Relationships = kept
+	return removed
+}
+
+// removeElements removes the elements with the given local names from an XML part, leaving
+// the rest of the part as it is. visit is called for each element removed and each element
+// inside one, innermost first, along with the text directly inside it. It returns nil when
+// nothing is removed.
+func removeElements(content []byte, names map[string]bool, visit func(elem xml.StartElement, text string)) ([]byte, error) {
+	type open struct {
+		elem xml.StartElement
+		text strings.Builder
+	}
+	var (
+		spans [][2]int64
+		stack []*open
+		from  int64
+	)
+
+	d := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		start := d.InputOffset()
+		tok, err := d.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if len(stack) == 0 {
+				if !names[tok.Name.Local] {
+					continue
+				}
+				from = start
+			}
+			stack = append(stack, &open{elem: tok.Copy()})
+		case xml.EndElement:
+			if len(stack) == 0 {
+				continue
+			}
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			visit(top.elem, top.text.String())
+			if len(stack) == 0 {
+				spans = append(spans, [2]int64{from, d.InputOffset()})
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(tok)
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return nil, nil
+	}
+
+	var out bytes.Buffer
+	pos := int64(0)
+	for _, span := range spans {
+		out.Write(content[pos:span[0]])
+		pos = span[1]
+	}
+	out.Write(content[pos:])
+	return out.Bytes(), nil
+}
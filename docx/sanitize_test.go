@@ -0,0 +1,141 @@
+package docx_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildSanitizeDoc(t *testing.T) *docx.RootDoc {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+
+	p := rd.AddParagraph("Quarterly figures")
+	p.AddText(" (draft, do not send)").HideText(true)
+	rsid := stypes.LongHexNum("00A1B2C3")
+	p.GetCT().RsidR = &rsid
+	p.GetCT().Children = append(p.GetCT().Children,
+		ctypes.ParagraphChild{Ins: &ctypes.RunTrackChange{
+			TrackChange: ctypes.TrackChange{ID: 91, Author: "Jane Roe"},
+			Runs:        []*ctypes.Run{{Children: []ctypes.RunChild{{Text: ctypes.TextFromString(" are final")}}}},
+		}},
+		ctypes.ParagraphChild{Del: &ctypes.RunTrackChange{
+			TrackChange: ctypes.TrackChange{ID: 92, Author: "Jane Roe"},
+			Runs:        []*ctypes.Run{{RsidDel: &rsid, Children: []ctypes.RunChild{{DelText: ctypes.TextFromString(" are late")}}}},
+		}},
+	)
+
+	r, err := p.Range(0, 9)
+	require.NoError(t, err)
+	_, err = r.AddComment(docx.Comment{Author: "Jane Roe", Initials: "JR", Text: "Ask finance first."})
+	require.NoError(t, err)
+
+	_, err = rd.AddPicture("../godocx.png", units.Inch(1), units.Inch(1))
+	require.NoError(t, err)
+	rd.FileMap.Store("word/media/unused.png", []byte("PNG"))
+
+	rels := &rd.Document.DocRels.Relationships
+	*rels = append(*rels,
+		&docx.Relationship{ID: "rIdPrinter", Type: constants.SourceRelationshipPrinterSettings, Target: "printerSettings/printerSettings1.bin"},
+		&docx.Relationship{ID: "rIdCustom", Type: constants.SourceRelationshipCustomXML, Target: "../customXml/item1.xml"},
+	)
+	rd.FileMap.Store("word/printerSettings/printerSettings1.bin", []byte("\\\\PRINT01\\Finance"))
+	rd.FileMap.Store("customXml/item1.xml", []byte(`<client>Jane Roe</client>`))
+	rd.FileMap.Store("customXml/itemProps1.xml", []byte(`<ds:datastoreItem xmlns:ds="http://schemas.openxmlformats.org/officeDocument/2006/customXml" ds:itemID="{1}"/>`))
+	rd.FileMap.Store("customXml/_rels/item1.xml.rels", []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"/>`))
+
+	rd.RootRels.Relationships = append(rd.RootRels.Relationships,
+		&docx.Relationship{ID: "rIdThumb", Type: constants.THUMBNAIL_TYPE, Target: "docProps/thumbnail.jpeg"})
+	rd.FileMap.Store("docProps/thumbnail.jpeg", []byte("JPEG"))
+
+	settings, ok := rd.FileMap.Load("word/settings.xml")
+	require.True(t, ok)
+	rd.FileMap.Store("word/settings.xml", bytes.Replace(settings.([]byte), []byte("<w:rsids>"),
+		[]byte(`<w:docVars><w:docVar w:name="ClientID" w:val="4711"/></w:docVars><w:rsids>`), 1))
+
+	core, ok := rd.FileMap.Load("docProps/core.xml")
+	require.True(t, ok)
+	rd.FileMap.Store("docProps/core.xml", bytes.Replace(core.([]byte), []byte("<cp:lastModifiedBy/>"), []byte("<cp:lastModifiedBy>Jane Roe</cp:lastModifiedBy>"), 1))
+	return rd
+}
+
+func TestSanitize(t *testing.T) {
+	rd := buildSanitizeDoc(t)
+
+	report, err := rd.Sanitize(nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, rd.Write(&buf))
+	files := readZip(t, buf.Bytes())
+	for name, content := range files {
+		for _, secret := range []string{"Jane Roe", "gomutex", "rsid", "ClientID", "draft", "commentRangeStart"} {
+			assert.NotContains(t, string(content), secret, "%s still holds %q", name, secret)
+		}
+		assert.False(t, strings.HasPrefix(name, "customXml/"), name)
+	}
+	for _, name := range []string{"word/comments.xml", "word/printerSettings/printerSettings1.bin", "docProps/thumbnail.jpeg", "word/media/unused.png"} {
+		assert.NotContains(t, files, name)
+	}
+	assert.Contains(t, files, "word/media/image1.png", "images in use are kept")
+	assert.NotContains(t, string(files["[Content_Types].xml"]), "comments.xml")
+
+	assert.Equal(t, "Quarterly figures are final", rd.Document.Body.Children[0].Para.Text())
+	core, err := rd.CoreProperties()
+	require.NoError(t, err)
+	assert.Empty(t, core.Creator)
+	assert.Empty(t, core.LastModifiedBy)
+	assert.Equal(t, "2013-12-23T23:15:00Z", core.Created)
+
+	for _, want := range []docx.Removal{
+		{Kind: docx.SanitizeComment, Location: "comment 0", Detail: `"Ask finance first." by Jane Roe`},
+		{Kind: docx.SanitizeRevision, Location: "body", Detail: `accepted insertion " are final" by Jane Roe`},
+		{Kind: docx.SanitizeRevision, Location: "body", Detail: `accepted deletion " are late" by Jane Roe`},
+		{Kind: docx.SanitizeHiddenText, Location: "body", Detail: " (draft, do not send)"},
+		{Kind: docx.SanitizeRevisionID, Location: "body", Detail: "1 revision save IDs"},
+		{Kind: docx.SanitizeRevisionID, Location: "word/settings.xml rsids", Detail: "10 revision save IDs"},
+		{Kind: docx.SanitizeDocumentVariable, Location: "word/settings.xml docVar", Detail: "ClientID"},
+		{Kind: docx.SanitizeAuthor, Location: "docProps/core.xml creator", Detail: "gomutex"},
+		{Kind: docx.SanitizeAuthor, Location: "docProps/core.xml lastModifiedBy", Detail: "Jane Roe"},
+		{Kind: docx.SanitizePrinterSettings, Location: "word/printerSettings/printerSettings1.bin"},
+		{Kind: docx.SanitizeThumbnail, Location: "docProps/thumbnail.jpeg"},
+		{Kind: docx.SanitizeCustomXML, Location: "customXml/item1.xml"},
+		{Kind: docx.SanitizeCustomXML, Location: "customXml/itemProps1.xml"},
+		{Kind: docx.SanitizeMedia, Location: "word/media/unused.png"},
+	} {
+		assert.Contains(t, report, want)
+	}
+
+	report, err = rd.Sanitize(nil)
+	require.NoError(t, err)
+	assert.Empty(t, report, "a sanitized document has nothing left to remove")
+}
+
+func TestSanitize_RejectAndKeep(t *testing.T) {
+	rd := buildSanitizeDoc(t)
+
+	report, err := rd.Sanitize(&docx.SanitizeOptions{RejectRevisions: true, KeepComments: true, KeepHiddenText: true, KeepCustomXML: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Quarterly figures (draft, do not send) are late", rd.Document.Body.Children[0].Para.Text())
+	assert.Contains(t, report, docx.Removal{Kind: docx.SanitizeRevision, Location: "body", Detail: `rejected insertion " are final" by Jane Roe`})
+
+	stories, err := rd.Stories()
+	require.NoError(t, err)
+	require.Len(t, stories, 1)
+	assert.Equal(t, docx.StoryComment, stories[0].Type)
+	_, ok := rd.FileMap.Load("customXml/item1.xml")
+	assert.True(t, ok)
+	for _, removal := range report {
+		assert.NotContains(t, []docx.SanitizeKind{docx.SanitizeComment, docx.SanitizeHiddenText, docx.SanitizeCustomXML}, removal.Kind)
+	}
+}
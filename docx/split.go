@@ -0,0 +1,335 @@
+package docx
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// SplitOptions configures RootDoc.Split.
+type SplitOptions struct {
+	// HeadingLevel cuts the document before each heading of this level or a higher one, so
+	// that 1 makes a document of each chapter starting with a Heading1 paragraph. Content
+	// before the first such heading makes a document of its own. By default, the document is
+	// cut at section breaks instead.
+	HeadingLevel uint
+}
+
+// Split returns the parts of the document as separate documents, cut at section breaks or
+// before headings as set by opts. Only headings at the top level of the body are taken into
+// account. The document is left unchanged.
+//
+// Each part is a copy of the document whose body holds the content of the part, with the page
+// setup of the section the part ends in. Styles, numbering definitions, images, hyperlinks,
+// headers and footers that the part does not use are left out of it; other parts of the
+// package, such as settings, fonts, notes and comments, are copied as they are.
+func (rd *RootDoc) Split(opts *SplitOptions) ([]*RootDoc, error) {
+	if opts == nil {
+		opts = &SplitOptions{}
+	}
+
+	children := rd.Document.Body.Children
+
+	// sections[i] is the section properties of the section the block i belongs to.
+	sections := make([]*ctypes.SectionProp, len(children))
+	current := rd.Document.Body.SectPr
+	for i := len(children) - 1; i >= 0; i-- {
+		if sectPr := sectionBreak(children[i]); sectPr != nil {
+			current = sectPr
+		}
+		sections[i] = current
+	}
+
+	var docs []*RootDoc
+	cut := func(from, to int) error {
+		if from >= to {
+			return nil
+		}
+		doc, err := rd.splitPart(children[from:to], sections[to-1])
+		if err != nil {
+			return fmt.Errorf("split: %w", err)
+		}
+		docs = append(docs, doc)
+		return nil
+	}
+
+	start := 0
+	for i, child := range children {
+		if opts.HeadingLevel == 0 {
+			if sectionBreak(child) != nil {
+				if err := cut(start, i+1); err != nil {
+					return nil, err
+				}
+				start = i + 1
+			}
+			continue
+		}
+
+		if child.Para == nil {
+			continue
+		}
+		if level, ok := headingLevel(child.Para.ct); ok && level <= opts.HeadingLevel {
+			if err := cut(start, i); err != nil {
+				return nil, err
+			}
+			start = i
+		}
+	}
+	if err := cut(start, len(children)); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// sectionBreak returns the properties of the section ended by a block, or nil if the block
+// does not end a section.
+func sectionBreak(child DocumentChild) *ctypes.SectionProp {
+	if child.Para == nil || child.Para.ct.Property == nil {
+		return nil
+	}
+	return child.Para.ct.Property.SectPr
+}
+
+// splitPart returns a copy of the document holding the given blocks of its body, in a section
+// with the properties sectPr.
+func (rd *RootDoc) splitPart(blocks []DocumentChild, sectPr *ctypes.SectionProp) (*RootDoc, error) {
+	doc, err := rd.copyPackage()
+	if err != nil {
+		return nil, err
+	}
+
+	body := doc.Document.Body
+	for _, child := range blocks {
+		block, _ := copyBlock(doc, child)
+		body.Children = append(body.Children, block)
+	}
+
+	// The section ending the part is the last section of the new document, whose properties
+	// belong to the body.
+	if last := body.Children[len(body.Children)-1]; sectionBreak(last) != nil {
+		body.SectPr = last.Para.ct.Property.SectPr
+		last.Para.ct.Property.SectPr = nil
+	} else if sectPr != nil {
+		sp := internal.DeepCopy(*sectPr)
+		body.SectPr = &sp
+	}
+
+	if err := doc.pruneRelations(); err != nil {
+		return nil, err
+	}
+	if err := doc.pruneStylesAndNumbering(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// relIDAttr matches the attributes holding relationship IDs in the main document part.
+var relIDAttr = regexp.MustCompile(`\br:(?:id|embed|link|pict)="([^"]*)"`)
+
+// prunedRelTypes are the types of relationships removed from a split part if unused.
+var prunedRelTypes = map[string]bool{
+	constants.SourceRelationshipImage:     true,
+	constants.SourceRelationshipHyperLink: true,
+	constants.SourceRelationshipHeader:    true,
+	constants.SourceRelationshipFooter:    true,
+}
+
+// pruneRelations removes the image, hyperlink, header and footer relationships the main
+// document part does not use, along with the parts they point at.
+func (rd *RootDoc) pruneRelations() error {
+	content, err := marshal(rd.Document)
+	if err != nil {
+		return err
+	}
+	used := make(map[string]bool)
+	for _, m := range relIDAttr.FindAllSubmatch(content, -1) {
+		used[string(m[1])] = true
+	}
+
+	var (
+		kept          []*Relationship
+		parts, images []string
+	)
+	for _, rel := range rd.Document.DocRels.Relationships {
+		if !prunedRelTypes[rel.Type] || used[rel.ID] {
+			kept = append(kept, rel)
+			continue
+		}
+		if rel.TargetMode == "External" {
+			continue
+		}
+		if rel.Type == constants.SourceRelationshipImage {
+			images = append(images, rd.Document.partPath(rel.Target))
+		} else {
+			parts = append(parts, rd.Document.partPath(rel.Target))
+		}
+	}
+	rd.Document.DocRels.Relationships = kept
+
+	targeted := make(map[string]bool)
+	for _, rel := range kept {
+		if rel.TargetMode != "External" {
+			targeted[rd.Document.partPath(rel.Target)] = true
+		}
+	}
+
+	// Headers and footers go first, as images may still be used by those that are kept.
+	for _, p := range parts {
+		if !targeted[p] {
+			rd.removePart(p)
+			rd.FileMap.Delete(path.Join(path.Dir(p), "_rels", path.Base(p)+".rels"))
+		}
+	}
+	for _, p := range images {
+		if !targeted[p] && !rd.partRelated(p) {
+			rd.removePart(p)
+		}
+	}
+	return nil
+}
+
+// partRelated reports whether a relationship part other than that of the main document may
+// point at the part p.
+func (rd *RootDoc) partRelated(p string) bool {
+	related := false
+	rd.FileMap.Range(func(key, content any) bool {
+		name := key.(string)
+		if strings.HasSuffix(name, ".rels") && name != rd.Document.DocRels.RelativePath &&
+			strings.Contains(string(content.([]byte)), path.Base(p)) {
+			related = true
+		}
+		return !related
+	})
+	return related
+}
+
+// removePart removes a part from the package along with its content type override.
+func (rd *RootDoc) removePart(p string) {
+	rd.FileMap.Delete(p)
+
+	var overrides []Override
+	for _, o := range rd.ContentType.Override {
+		if o.PartName != "/"+p {
+			overrides = append(overrides, o)
+		}
+	}
+	rd.ContentType.Override = overrides
+}
+
+// pStyleVal matches the paragraph styles tied to levels of numbering definitions.
+var pStyleVal = regexp.MustCompile(`pStyle\s+\w+:val="([^"]*)"`)
+
+// pruneStylesAndNumbering removes the styles and the numbering definitions that neither the
+// body nor the stories use. Default styles are kept.
+func (rd *RootDoc) pruneStylesAndNumbering() error {
+	blocks := rd.Document.Body.Children
+	stories, err := rd.Stories()
+	if err != nil {
+		return err
+	}
+	for _, s := range stories {
+		blocks = append(blocks[:len(blocks):len(blocks)], s.Children...)
+	}
+
+	var tables []*ctypes.Table
+	eachBlock(blocks, func(child DocumentChild) bool {
+		if child.Table != nil {
+			tables = append(tables, child.Table.ct)
+		}
+		return false
+	})
+	paras := blockParagraphs(blocks)
+
+	if err := rd.Numbering.applyToFileMap(); err != nil {
+		return err
+	}
+	numbering, hasNumbering := rd.FileMap.Load(numberingPath)
+
+	var styles []*ctypes.Style
+	if rd.DocStyles != nil {
+		used := usedStyles(paras, tables)
+		if hasNumbering {
+			// Levels of lists may be tied to paragraph styles.
+			for _, m := range pStyleVal.FindAllSubmatch(numbering.([]byte), -1) {
+				used[string(m[1])] = true
+			}
+		}
+
+		byID := make(map[string]*ctypes.Style)
+		for i := range rd.DocStyles.StyleList {
+			s := &rd.DocStyles.StyleList[i]
+			if s.ID == nil {
+				continue
+			}
+			byID[*s.ID] = s
+			if s.Default != nil && *s.Default != stypes.OnOffZero && *s.Default != stypes.OnOffFalse && *s.Default != stypes.OnOffOff {
+				used[*s.ID] = true
+			}
+		}
+
+		needed := make(map[string]bool)
+		var need func(id string)
+		need = func(id string) {
+			s, ok := byID[id]
+			if !ok || needed[id] {
+				return
+			}
+			needed[id] = true
+			for _, dep := range []*ctypes.CTString{s.BasedOn, s.Link, s.Next} {
+				if dep != nil {
+					need(dep.Val)
+				}
+			}
+		}
+		for id := range used {
+			need(id)
+		}
+
+		var list []ctypes.Style
+		for _, s := range rd.DocStyles.StyleList {
+			if s.ID == nil || needed[*s.ID] {
+				list = append(list, s)
+			}
+		}
+		rd.DocStyles.StyleList = list
+		for i := range rd.DocStyles.StyleList {
+			styles = append(styles, &rd.DocStyles.StyleList[i])
+		}
+	}
+
+	if !hasNumbering {
+		return nil
+	}
+	content := string(numbering.([]byte))
+	abstracts, nums, err := readNumbering([]byte(content))
+	if err != nil {
+		return err
+	}
+
+	usedNums := usedNumbering(paras, styles)
+	usedAbstracts := make(map[int]bool)
+	for _, n := range nums {
+		if usedNums[n.id] {
+			usedAbstracts[n.abstract] = true
+		} else {
+			content = strings.Replace(content, n.raw, "", 1)
+		}
+	}
+	for _, a := range abstracts {
+		if !usedAbstracts[a.id] {
+			content = strings.Replace(content, a.raw, "", 1)
+		}
+	}
+	rd.FileMap.Store(numberingPath, []byte(content))
+
+	// The instances of the manager are in the numbering part now; the unused ones must not
+	// be written again.
+	rd.Numbering = NewNumberingManager(rd)
+	return nil
+}
@@ -0,0 +1,110 @@
+package docx_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit_HeadingLevel(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+	rd.SetStyle(clauseStyle("FF0000"))
+
+	rd.AddParagraph("Front matter")
+	_, err = rd.AddHeading("Chapter 1", 1)
+	require.NoError(t, err)
+	p := rd.AddParagraph("Clause 1")
+	p.Style("Clause")
+	p.Numbering(rd.NewListInstance(1), 0)
+	_, err = rd.AddPicture("../godocx.png", units.Inch(1), units.Inch(1))
+	require.NoError(t, err)
+	_, err = rd.AddHeading("Section 1.1", 2)
+	require.NoError(t, err)
+	_, err = rd.AddHeading("Chapter 2", 1)
+	require.NoError(t, err)
+	rd.AddParagraph("See ").AddLink("the terms", "https://example.com/terms")
+	before := len(rd.Document.Body.Children)
+
+	docs, err := rd.Split(&docx.SplitOptions{HeadingLevel: 1})
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	assert.Len(t, rd.Document.Body.Children, before, "the document is left unchanged")
+
+	assert.Equal(t, "Front matter", docs[0].Document.Body.Children[0].Para.Text())
+	assert.Equal(t, "Chapter 1", docs[1].Document.Body.Children[0].Para.Text())
+	assert.Equal(t, "Section 1.1", docs[1].Document.Body.Children[3].Para.Text())
+	assert.Equal(t, "Chapter 2", docs[2].Document.Body.Children[0].Para.Text())
+
+	path := filepath.Join(t.TempDir(), "chapter1.docx")
+	require.NoError(t, docs[1].SaveTo(path))
+	reopened, err := godocx.OpenDocument(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Clause 1", reopened.Document.Body.Children[1].Para.Text())
+
+	var buf bytes.Buffer
+	require.NoError(t, docs[2].Write(&buf))
+	chapter1, chapter2 := readZip(t, readFile(t, path)), readZip(t, buf.Bytes())
+
+	assert.Contains(t, chapter1, "word/media/image1.png")
+	assert.NotContains(t, chapter2, "word/media/image1.png", "unused media is left out")
+	assert.NotContains(t, string(chapter2["[Content_Types].xml"]), "/word/media/image1.png")
+	assert.NotContains(t, string(chapter1["word/_rels/document.xml.rels"]), "https://example.com/terms")
+	assert.Contains(t, string(chapter2["word/_rels/document.xml.rels"]), "https://example.com/terms")
+
+	assert.Contains(t, string(chapter1["word/styles.xml"]), `w:styleId="Clause"`)
+	assert.NotContains(t, string(chapter2["word/styles.xml"]), `w:styleId="Clause"`, "unused styles are left out")
+	assert.Contains(t, string(chapter2["word/styles.xml"]), `w:styleId="Heading1"`)
+	assert.NotNil(t, docs[2].GetStyleByID("Normal", stypes.StyleTypeParagraph), "styles headings are based on are kept")
+
+	numID := docs[1].Document.Body.Children[1].Para.NumberingID()
+	assert.Contains(t, string(chapter1["word/numbering.xml"]), fmt.Sprintf(`w:numId="%d"`, numID))
+	assert.NotContains(t, string(chapter2["word/numbering.xml"]), fmt.Sprintf(`w:numId="%d"`, numID), "unused numbering is left out")
+}
+
+func TestSplit_Sections(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+
+	rd.AddParagraph("Landscape part")
+	end := rd.AddParagraph("End of part 1")
+	end.GetCT().Property = &ctypes.ParagraphProp{SectPr: &ctypes.SectionProp{
+		PageSize: &ctypes.PageSize{Width: internal.ToPtr(uint64(16838)), Height: internal.ToPtr(uint64(11906))},
+	}}
+	rd.AddParagraph("Portrait part")
+
+	docs, err := rd.Split(nil)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	first := docs[0].Document.Body
+	require.Len(t, first.Children, 2)
+	assert.Nil(t, first.Children[1].Para.GetCT().Property.SectPr, "the last section break becomes the body section")
+	require.NotNil(t, first.SectPr)
+	assert.Equal(t, uint64(16838), *first.SectPr.PageSize.Width)
+	assert.NotNil(t, end.GetCT().Property.SectPr, "the document is left unchanged")
+
+	second := docs[1].Document.Body
+	require.Len(t, second.Children, 1)
+	assert.Equal(t, "Portrait part", second.Children[0].Para.Text())
+	assert.Equal(t, rd.Document.Body.SectPr, second.SectPr)
+}
+
+func readFile(t *testing.T, path string) []byte {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return content
+}
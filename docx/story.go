@@ -0,0 +1,337 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"sort"
+	"strconv"
+
+	"github.com/gomutex/godocx/common/constants"
+)
+
+// StoryType identifies the kind of part a Story belongs to.
+type StoryType string
+
+const (
+	StoryHeader   StoryType = "header"
+	StoryFooter   StoryType = "footer"
+	StoryFootnote StoryType = "footnote"
+	StoryEndnote  StoryType = "endnote"
+	StoryComment  StoryType = "comment"
+)
+
+// storyRelTypes maps the document relationship types that point at story parts to their story type.
+var storyRelTypes = map[string]StoryType{
+	constants.SourceRelationshipHeader:    StoryHeader,
+	constants.SourceRelationshipFooter:    StoryFooter,
+	constants.SourceRelationshipFootnotes: StoryFootnote,
+	constants.SourceRelationshipEndnotes:  StoryEndnote,
+	constants.SourceRelationshipComments:  StoryComment,
+}
+
+// storyOrder is the order in which story types are returned by Stories.
+var storyOrder = map[StoryType]int{
+	StoryHeader:   0,
+	StoryFooter:   1,
+	StoryFootnote: 2,
+	StoryEndnote:  3,
+	StoryComment:  4,
+}
+
+// Story is a flow of block-level content that lives outside the document body:
+// a header, a footer, or a single footnote, endnote or comment.
+type Story struct {
+	root *RootDoc
+
+	Type     StoryType       // Type is the kind of story.
+	Path     string          // Path is the package path of the part holding the story.
+	ID       int             // ID is the note or comment ID, and zero for headers and footers.
+	Children []DocumentChild // Children are the paragraphs and tables of the story.
+
+	noteType string     // noteType is the w:type of special notes such as separators.
+	attrs    []xml.Attr // attrs are other attributes of the note, such as the author of a comment.
+}
+
+// storyPart is a header, footer, footnotes, endnotes or comments part that has been loaded from the package.
+type storyPart struct {
+	typ       StoryType
+	path      string
+	stories   []*Story
+	textBoxes []string // textBoxes are the texts of the text boxes dropped when the part was read.
+}
+
+// Stories returns the headers, footers, footnotes, endnotes and comments of the document, in that order.
+// Separator and continuation notes are not included.
+//
+// Story parts are parsed from the package on first use; any changes made to the returned
+// stories are written back when the document is saved.
+func (rd *RootDoc) Stories() ([]*Story, error) {
+	if err := rd.loadStoryParts(); err != nil {
+		return nil, err
+	}
+
+	var stories []*Story
+	for _, part := range rd.storyParts {
+		for _, s := range part.stories {
+			if s.noteType == "" || s.noteType == "normal" {
+				stories = append(stories, s)
+			}
+		}
+	}
+	return stories, nil
+}
+
+// loadStoryParts parses every story part referenced from the document relationships.
+func (rd *RootDoc) loadStoryParts() error {
+	if rd.storiesLoaded {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var parts []*storyPart
+	for _, rel := range rd.Document.DocRels.Relationships {
+		typ, ok := storyRelTypes[rel.Type]
+		if !ok || rel.TargetMode == "External" {
+			continue
+		}
+
+		partPath := rd.Document.partPath(rel.Target)
+		if seen[partPath] {
+			continue
+		}
+		seen[partPath] = true
+
+		content, ok := rd.FileMap.Load(partPath)
+		if !ok {
+			continue
+		}
+
+		part, err := rd.loadStoryPart(typ, partPath, content.([]byte))
+		if err != nil {
+			return err
+		}
+		parts = append(parts, part)
+	}
+
+	sort.SliceStable(parts, func(i, j int) bool {
+		if parts[i].typ != parts[j].typ {
+			return storyOrder[parts[i].typ] < storyOrder[parts[j].typ]
+		}
+		return parts[i].path < parts[j].path
+	})
+
+	rd.storyParts = parts
+	rd.storiesLoaded = true
+	return nil
+}
+
+// commentsPart returns the comments part of the document, adding an empty one if there is none.
+func (rd *RootDoc) commentsPart() (*storyPart, error) {
+	if err := rd.loadStoryParts(); err != nil {
+		return nil, err
+	}
+
+	for _, part := range rd.storyParts {
+		if part.typ == StoryComment {
+			return part, nil
+		}
+	}
+
+	rd.Document.addRelation(constants.SourceRelationshipComments, "comments.xml")
+	part := &storyPart{typ: StoryComment, path: rd.Document.partPath("comments.xml")}
+	if err := rd.ContentType.AddOverride("/"+part.path, constants.CommentsContentType); err != nil {
+		return nil, err
+	}
+
+	rd.storyParts = append(rd.storyParts, part)
+	return part, nil
+}
+
+// loadStoryPart decodes a single story part.
+func (rd *RootDoc) loadStoryPart(typ StoryType, partPath string, content []byte) (*storyPart, error) {
+	textBoxes, err := textBoxTexts(content)
+	if err != nil {
+		return nil, err
+	}
+
+	part := &storyPart{typ: typ, path: partPath, textBoxes: textBoxes}
+	d := xml.NewDecoder(bytes.NewReader(content))
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := token.(xml.StartElement); !ok {
+			continue
+		}
+
+		if typ == StoryHeader || typ == StoryFooter {
+			s := &Story{root: rd, Type: typ, Path: partPath}
+			if err := s.unmarshalBlocks(d); err != nil {
+				return nil, err
+			}
+			part.stories = append(part.stories, s)
+			return part, nil
+		}
+
+		return part, part.unmarshalNotes(rd, d)
+	}
+}
+
+// unmarshalNotes decodes the w:footnote, w:endnote or w:comment elements of a notes or comments part.
+func (part *storyPart) unmarshalNotes(rd *RootDoc, d *xml.Decoder) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			if elem.Name.Local != string(part.typ) {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+
+			s := &Story{root: rd, Type: part.typ, Path: part.path}
+			for _, attr := range elem.Attr {
+				switch attr.Name.Local {
+				case "id":
+					if s.ID, err = strconv.Atoi(attr.Value); err != nil {
+						return err
+					}
+				case "type":
+					s.noteType = attr.Value
+				default:
+					if attr.Name.Space == constants.WMLNamespace {
+						s.attrs = append(s.attrs, xml.Attr{Name: xml.Name{Local: "w:" + attr.Name.Local}, Value: attr.Value})
+					}
+				}
+			}
+
+			if err := s.unmarshalBlocks(d); err != nil {
+				return err
+			}
+			part.stories = append(part.stories, s)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// unmarshalBlocks decodes the paragraphs, tables and content controls up to the end of the current element.
+func (s *Story) unmarshalBlocks(d *xml.Decoder) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			switch elem.Name.Local {
+			case "p":
+				para := newParagraph(s.root)
+				if err := para.unmarshalXML(d, elem); err != nil {
+					return err
+				}
+				s.Children = append(s.Children, DocumentChild{Para: para})
+			case "tbl":
+				tbl := NewTable(s.root)
+				if err := tbl.unmarshalXML(d, elem); err != nil {
+					return err
+				}
+				s.Children = append(s.Children, DocumentChild{Table: tbl})
+			case "sdt":
+				ctl := NewContentControl(s.root)
+				if err := d.DecodeElement(ctl.ct, &elem); err != nil {
+					return err
+				}
+				s.Children = append(s.Children, DocumentChild{Control: ctl})
+			default:
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// marshalBlocks encodes the paragraphs, tables and content controls of the story.
+func (s *Story) marshalBlocks(e *xml.Encoder) error {
+	for _, child := range s.Children {
+		if child.Para != nil {
+			if err := child.Para.ct.MarshalXML(e, xml.StartElement{}); err != nil {
+				return err
+			}
+		}
+
+		if child.Table != nil {
+			if err := child.Table.ct.MarshalXML(e, xml.StartElement{}); err != nil {
+				return err
+			}
+		}
+
+		if child.Control != nil {
+			if err := child.Control.ct.MarshalXML(e, xml.StartElement{}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalXML implements the xml.Marshaler interface for the storyPart type.
+func (part storyPart) MarshalXML(e *xml.Encoder, start xml.StartElement) (err error) {
+	switch part.typ {
+	case StoryHeader:
+		start.Name.Local = "w:hdr"
+	case StoryFooter:
+		start.Name.Local = "w:ftr"
+	case StoryFootnote:
+		start.Name.Local = "w:footnotes"
+	case StoryComment:
+		start.Name.Local = "w:comments"
+	default:
+		start.Name.Local = "w:endnotes"
+	}
+	start.Attr = append(start.Attr, docAttrs...)
+
+	if err = e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, s := range part.stories {
+		if part.typ == StoryHeader || part.typ == StoryFooter {
+			if err = s.marshalBlocks(e); err != nil {
+				return err
+			}
+			continue
+		}
+
+		note := xml.StartElement{Name: xml.Name{Local: "w:" + string(part.typ)}}
+		if s.noteType != "" {
+			note.Attr = append(note.Attr, xml.Attr{Name: xml.Name{Local: "w:type"}, Value: s.noteType})
+		}
+		note.Attr = append(note.Attr, xml.Attr{Name: xml.Name{Local: "w:id"}, Value: strconv.Itoa(s.ID)})
+		note.Attr = append(note.Attr, s.attrs...)
+
+		if err = e.EncodeToken(note); err != nil {
+			return err
+		}
+		if err = s.marshalBlocks(e); err != nil {
+			return err
+		}
+		if err = e.EncodeToken(note.End()); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
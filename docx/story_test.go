@@ -0,0 +1,78 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testHeaderXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>Product manual</w:t></w:r></w:p></w:hdr>`
+
+	testFootnotesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:footnotes xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:footnote w:type="separator" w:id="-1"><w:p><w:r><w:separator/></w:r></w:p></w:footnote>` +
+		`<w:footnote w:id="1"><w:p><w:r><w:footnoteRef/></w:r><w:r><w:t xml:space="preserve"> See appendix.</w:t></w:r></w:p></w:footnote>` +
+		`</w:footnotes>`
+)
+
+// addTestStoryPart stores a story part in the package and links it from the document.
+func addTestStoryPart(rd *RootDoc, relType, target, content string) {
+	rd.Document.addRelation(relType, target)
+	rd.FileMap.Store(rd.Document.partPath(target), []byte(content))
+}
+
+func setupStoryDoc(t *testing.T) *RootDoc {
+	rd := setupRootDoc(t)
+	rd.Document.relativePath = "word/document.xml"
+	addTestStoryPart(rd, constants.SourceRelationshipFootnotes, "footnotes.xml", testFootnotesXML)
+	addTestStoryPart(rd, constants.SourceRelationshipHeader, "header1.xml", testHeaderXML)
+	addTestStoryPart(rd, constants.SourceRelationshipHyperLink, "https://example.com", "")
+	return rd
+}
+
+func TestStories(t *testing.T) {
+	rd := setupStoryDoc(t)
+
+	stories, err := rd.Stories()
+	require.NoError(t, err)
+	require.Len(t, stories, 2)
+
+	assert.Equal(t, StoryHeader, stories[0].Type)
+	assert.Equal(t, "word/header1.xml", stories[0].Path)
+	assert.Equal(t, "Product manual", paragraphText(stories[0].Children[0].Para.ct))
+
+	assert.Equal(t, StoryFootnote, stories[1].Type)
+	assert.Equal(t, "word/footnotes.xml", stories[1].Path)
+	assert.Equal(t, 1, stories[1].ID)
+	assert.Equal(t, " See appendix.", paragraphText(stories[1].Children[0].Para.ct))
+
+	again, err := rd.Stories()
+	require.NoError(t, err)
+	assert.Same(t, stories[0], again[0])
+}
+
+func TestStories_MarshalParts(t *testing.T) {
+	rd := setupStoryDoc(t)
+
+	stories, err := rd.Stories()
+	require.NoError(t, err)
+	stories[0].Children[0].Para.AddText(" v2")
+
+	require.Len(t, rd.storyParts, 2)
+
+	header, err := marshal(rd.storyParts[0])
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(header), `<?xml`))
+	assert.Contains(t, string(header), `<w:hdr xmlns:w="`)
+	assert.Contains(t, string(header), `<w:t>Product manual</w:t></w:r><w:r><w:t xml:space="preserve"> v2</w:t>`)
+
+	notes, err := marshal(rd.storyParts[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(notes), `<w:footnote w:type="separator" w:id="-1"><w:p><w:r><w:separator></w:separator></w:r></w:p></w:footnote>`)
+	assert.Contains(t, string(notes), `<w:footnote w:id="1"><w:p><w:r><w:footnoteRef></w:footnoteRef></w:r>`)
+}
@@ -32,3 +32,23 @@ func (rd *RootDoc) GetStyleByID(styleID string, styleType stypes.StyleType) *cty
 	}
 	return nil
 }
+
+// SetStyle adds a style definition to the document styles collection.
+//
+// Parameters:
+//   - style: The style to add. Its ID and Type must be set.
+//
+// If a style with the same ID and type already exists, it is replaced.
+func (rd *RootDoc) SetStyle(style ctypes.Style) {
+	if rd.DocStyles == nil {
+		rd.DocStyles = &ctypes.Styles{}
+	}
+
+	for i, existing := range rd.DocStyles.StyleList {
+		if existing.ID != nil && existing.Type != nil && *existing.ID == *style.ID && *existing.Type == *style.Type {
+			rd.DocStyles.StyleList[i] = style
+			return
+		}
+	}
+	rd.DocStyles.StyleList = append(rd.DocStyles.StyleList, style)
+}
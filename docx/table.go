@@ -12,11 +12,15 @@ type Table struct {
 	root *RootDoc
 
 	// Table Complex Type
-	ct ctypes.Table
+	ct *ctypes.Table
 }
 
 func (t *Table) unmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	return t.ct.UnmarshalXML(d, start)
+	if err := t.ct.UnmarshalXML(d, start); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (t *Table) Width(v int, u stypes.TableWidth) *Table {
@@ -62,12 +66,13 @@ func (t *Table) Layout(layout stypes.TableLayout) *Table {
 
 // GetCT returns a pointer to the underlying Table Complex Type.
 func (t *Table) GetCT() *ctypes.Table {
-	return &t.ct
+	return t.ct
 }
 
 func NewTable(root *RootDoc) *Table {
 	return &Table{
 		root: root,
+		ct:   &ctypes.Table{},
 	}
 }
 
@@ -96,7 +101,7 @@ func NewTable(root *RootDoc) *Table {
 func (rd *RootDoc) AddTable() *Table {
 	tbl := Table{
 		root: rd,
-		ct:   *ctypes.DefaultTable(),
+		ct:   ctypes.DefaultTable(),
 	}
 
 	rd.Document.Body.Children = append(rd.Document.Body.Children, DocumentChild{
@@ -117,11 +122,11 @@ func (rd *RootDoc) AddTable() *Table {
 func (t *Table) AddRow() *Row {
 	row := Row{
 		root: t.root,
-		ct:   *ctypes.DefaultRow(),
+		ct:   ctypes.DefaultRow(),
 	}
 
 	t.ct.RowContents = append(t.ct.RowContents, ctypes.RowContent{
-		Row: &row.ct,
+		Row: row.ct,
 	})
 
 	return &row
@@ -193,37 +198,47 @@ type Row struct {
 	root *RootDoc
 
 	// Row Complex Type
-	ct ctypes.Row
+	ct *ctypes.Row
 }
 
 // Add Cell to row and returns Cell
 func (r *Row) AddCell() *Cell {
 	cell := Cell{
 		root: r.root,
-		ct:   *ctypes.DefaultCell(),
+		ct:   ctypes.DefaultCell(),
 	}
 
 	r.ct.Contents = append(r.ct.Contents, ctypes.TRCellContent{
-		Cell: &cell.ct,
+		Cell: cell.ct,
 	})
 
 	return &cell
 }
 
+// Header marks the row as a header row, repeated at the top of each page the table spans and
+// read out by screen readers as the headers of the columns. Header rows must come first.
+func (r *Row) Header() *Row {
+	if r.ct.Property == nil {
+		r.ct.Property = &ctypes.RowProperty{}
+	}
+	r.ct.Property.Header = &ctypes.OnOff{}
+	return r
+}
+
 // Cell Wrapper
 type Cell struct {
 	// Reverse inheriting the Rootdoc into paragraph to access other elements
 	root *RootDoc
 
 	// Cell Complex Type
-	ct ctypes.Cell
+	ct *ctypes.Cell
 }
 
 // Adds paragraph with text and returns Paragraph
 func (c *Cell) AddParagraph(text string) *Paragraph {
 	p := newParagraph(c.root, paraWithText(text))
 	tblContent := ctypes.TCBlockContent{
-		Paragraph: &p.ct,
+		Paragraph: p.ct,
 	}
 
 	c.ct.Contents = append(c.ct.Contents, tblContent)
@@ -235,7 +250,7 @@ func (c *Cell) AddParagraph(text string) *Paragraph {
 func (c *Cell) AddEmptyPara() *Paragraph {
 	p := newParagraph(c.root)
 	tblContent := ctypes.TCBlockContent{
-		Paragraph: &p.ct,
+		Paragraph: p.ct,
 	}
 
 	c.ct.Contents = append(c.ct.Contents, tblContent)
@@ -262,6 +277,15 @@ func (c *Cell) RowSpan() *Cell {
 	return c
 }
 
+// VerticalMerge marks the cell as part of a vertically merged region. The top cell of the region
+// uses stypes.MergeCellRestart and the cells below it stypes.MergeCellContinue.
+func (c *Cell) VerticalMerge(value stypes.MergeCell) *Cell {
+	if c.ct.Property != nil {
+		c.ct.Property.VMerge = ctypes.NewGenOptStrVal(value)
+	}
+	return c
+}
+
 // VerticalAlign sets the vertical alignment of a cell based on the provided string: "top", "center", "middle", or "bottom".
 func (c *Cell) VerticalAlign(valign string) *Cell {
 	if c.ct.Property != nil {
@@ -0,0 +1,193 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// runText returns the plain text held by a run.
+// Tabs are returned as "\t" and line breaks as "\n"; other non-text content is ignored.
+func runText(r *ctypes.Run) string {
+	if r == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, child := range r.Children {
+		sb.WriteString(runChildText(child))
+	}
+	return sb.String()
+}
+
+// runChildText returns the plain text of a single run child, as described for runText.
+func runChildText(child ctypes.RunChild) string {
+	switch {
+	case child.Text != nil:
+		return child.Text.Text
+	case child.Tab != nil:
+		return "\t"
+	case child.CarrRtn != nil:
+		return "\n"
+	case child.Break != nil:
+		if child.Break.BreakType == nil || *child.Break.BreakType == stypes.BreakTypeTextWrapping {
+			return "\n"
+		}
+	}
+	return ""
+}
+
+// textRunChildren returns the run content for a string, turning "\t" into tabs and "\n" into line breaks.
+func textRunChildren(text string) []ctypes.RunChild {
+	var children []ctypes.RunChild
+	start := 0
+	for i, c := range text {
+		if c != '\t' && c != '\n' {
+			continue
+		}
+
+		if i > start {
+			children = append(children, ctypes.RunChild{Text: ctypes.TextFromString(text[start:i])})
+		}
+
+		if c == '\t' {
+			children = append(children, ctypes.RunChild{Tab: &ctypes.Empty{}})
+		} else {
+			children = append(children, ctypes.RunChild{Break: &ctypes.Break{}})
+		}
+		start = i + 1
+	}
+
+	if start < len(text) || len(children) == 0 {
+		children = append(children, ctypes.RunChild{Text: ctypes.TextFromString(text[start:])})
+	}
+	return children
+}
+
+// Text returns the plain text of the document body, with paragraphs separated by newlines.
+// Paragraphs in table cells are included in document order.
+func (rd *RootDoc) Text() string {
+	var parts []string
+	for _, p := range blockParagraphs(rd.Document.Body.Children) {
+		parts = append(parts, paragraphText(p))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// paragraphText returns the plain text of a paragraph, including the text of its hyperlinks.
+func paragraphText(p *ctypes.Paragraph) string {
+	if p == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeChildrenText(&sb, p.Children)
+	return sb.String()
+}
+
+func writeChildrenText(sb *strings.Builder, children []ctypes.ParagraphChild) {
+	for _, child := range children {
+		if child.Run != nil {
+			sb.WriteString(runText(child.Run))
+		}
+
+		if child.Link != nil {
+			sb.WriteString(runText(child.Link.Run))
+			writeChildrenText(sb, child.Link.Children)
+		}
+
+		// Inserted text is part of the text; deleted text is not.
+		if child.Ins != nil {
+			for _, r := range child.Ins.Runs {
+				sb.WriteString(runText(r))
+			}
+		}
+	}
+}
+
+// textBoxTexts returns the text of each text box of a WordprocessingML part, one paragraph a
+// line. Text boxes are not part of the document model, which drops them when a part is read.
+// The copy of a text box that a part holds for older readers, in the fallback of an
+// alternate content, is left out, and so are text boxes nested in another.
+func textBoxTexts(content []byte) ([]string, error) {
+	if !bytes.Contains(content, []byte("txbxContent")) {
+		return nil, nil
+	}
+
+	var texts []string
+	var sb strings.Builder
+	var stack []string
+	depth, fallback, paragraphs := 0, 0, 0
+
+	d := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		tok, err := d.RawToken()
+		if errors.Is(err, io.EOF) {
+			return texts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, tok.Name.Local)
+			switch tok.Name.Local {
+			case "Fallback":
+				fallback++
+			case "txbxContent":
+				if depth == 0 {
+					sb.Reset()
+					paragraphs = 0
+				}
+				depth++
+			case "p":
+				if depth > 0 && fallback == 0 {
+					if paragraphs > 0 {
+						sb.WriteByte('\n')
+					}
+					paragraphs++
+				}
+			}
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			switch tok.Name.Local {
+			case "Fallback":
+				fallback--
+			case "txbxContent":
+				depth--
+				if depth == 0 && fallback == 0 {
+					texts = append(texts, sb.String())
+				}
+			}
+		case xml.CharData:
+			if depth > 0 && fallback == 0 && len(stack) > 0 && stack[len(stack)-1] == "t" {
+				sb.Write(tok)
+			}
+		}
+	}
+}
+
+// isOn reports whether an optional on/off property is set and turned on.
+func isOn(o *ctypes.OnOff) bool {
+	if o == nil {
+		return false
+	}
+
+	if o.Val == nil {
+		return true
+	}
+
+	switch *o.Val {
+	case stypes.OnOffFalse, stypes.OnOffZero, stypes.OnOffOff:
+		return false
+	}
+	return true
+}
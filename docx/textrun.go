@@ -0,0 +1,256 @@
+package docx
+
+import (
+	"github.com/gomutex/godocx/wml/ctypes"
+)
+
+// textRun locates a run of a paragraph and the part of the paragraph text it holds.
+//
+// Offsets are byte offsets into the text returned by paragraphText. A textRun is only valid
+// until the paragraph is changed.
+type textRun struct {
+	run        *ctypes.Run
+	start, end int
+
+	list  *[]ctypes.ParagraphChild // list is the slice holding the run, or nil for the first run of a hyperlink or a run of an insertion.
+	index int                      // index is the position of the run in list, or in the runs of ins.
+	link  *ctypes.Hyperlink        // link is the hyperlink the run belongs to, if any.
+	ins   *ctypes.RunTrackChange   // ins is the tracked insertion the run belongs to, if any.
+	top   int                      // top is the index of the paragraph child holding the run.
+}
+
+// paragraphRuns returns the runs of a paragraph in text order, including those in hyperlinks
+// and tracked insertions, so that they match the text returned by paragraphText. The runs of
+// tracked deletions are not part of the text and are left out.
+func paragraphRuns(p *ctypes.Paragraph) []textRun {
+	var runs []textRun
+	offset := 0
+	collectRuns(&runs, &offset, &p.Children, nil, -1)
+	return runs
+}
+
+// collectRuns adds the runs held by list. top is the index of the paragraph child holding
+// list, or -1 when list is the paragraph's own children.
+func collectRuns(runs *[]textRun, offset *int, list *[]ctypes.ParagraphChild, link *ctypes.Hyperlink, top int) {
+	add := func(tr textRun) {
+		tr.start = *offset
+		tr.end = tr.start + len(runText(tr.run))
+		*offset = tr.end
+		*runs = append(*runs, tr)
+	}
+
+	for i := range *list {
+		child := &(*list)[i]
+		at := top
+		if top < 0 {
+			at = i
+		}
+
+		if child.Run != nil {
+			add(textRun{run: child.Run, list: list, index: i, link: link, top: at})
+		}
+
+		if child.Link != nil {
+			if child.Link.Run != nil {
+				add(textRun{run: child.Link.Run, link: child.Link, top: at})
+			}
+			collectRuns(runs, offset, &child.Link.Children, child.Link, at)
+		}
+
+		if child.Ins != nil {
+			for j, r := range child.Ins.Runs {
+				add(textRun{run: r, index: j, link: link, ins: child.Ins, top: at})
+			}
+		}
+	}
+}
+
+// splitRunsAt makes sure that no run of the paragraph spans the given offset, splitting the run
+// that does in two. Content that takes no space in the text stays with the first half.
+func splitRunsAt(p *ctypes.Paragraph, offset int) {
+	for _, tr := range paragraphRuns(p) {
+		if tr.start < offset && offset < tr.end {
+			tr.insertAfter(splitRun(tr.run, offset-tr.start))
+			return
+		}
+	}
+}
+
+// splitRun cuts a run at an offset into its text. The run keeps the content before the offset
+// and a new run with the same properties and the rest of the content is returned.
+func splitRun(r *ctypes.Run, at int) *ctypes.Run {
+	tail := &ctypes.Run{Property: copyRunProp(r.Property)}
+
+	var head []ctypes.RunChild
+	pos := 0
+	for _, child := range r.Children {
+		width := len(runChildText(child))
+		switch {
+		case pos+width <= at:
+			head = append(head, child)
+		case pos >= at:
+			tail.Children = append(tail.Children, child)
+		default:
+			text := child.Text.Text
+			head = append(head, ctypes.RunChild{Text: ctypes.TextFromString(text[:at-pos])})
+			tail.Children = append(tail.Children, ctypes.RunChild{Text: ctypes.TextFromString(text[at-pos:])})
+		}
+		pos += width
+	}
+
+	r.Children = head
+	return tail
+}
+
+// insertBefore adds a run in front of the located run, inside the same hyperlink and
+// insertion if any.
+func (tr textRun) insertBefore(r *ctypes.Run) {
+	if tr.ins != nil {
+		tr.ins.Runs = insertRun(tr.ins.Runs, tr.index, r)
+		return
+	}
+	if tr.list == nil {
+		tr.link.Children = append([]ctypes.ParagraphChild{{Run: tr.link.Run}}, tr.link.Children...)
+		tr.link.Run = r
+		return
+	}
+	insertParagraphChild(tr.list, tr.index, ctypes.ParagraphChild{Run: r})
+}
+
+// insertAfter adds a run after the located run, inside the same hyperlink and insertion if
+// any.
+func (tr textRun) insertAfter(r *ctypes.Run) {
+	if tr.ins != nil {
+		tr.ins.Runs = insertRun(tr.ins.Runs, tr.index+1, r)
+		return
+	}
+	if tr.list == nil {
+		tr.link.Children = append([]ctypes.ParagraphChild{{Run: r}}, tr.link.Children...)
+		return
+	}
+	insertParagraphChild(tr.list, tr.index+1, ctypes.ParagraphChild{Run: r})
+}
+
+// remove takes the located run out of the paragraph. When the first run of a hyperlink is
+// removed, the next run of the hyperlink takes its place.
+func (tr textRun) remove() {
+	if tr.ins != nil {
+		tr.ins.Runs = append(tr.ins.Runs[:tr.index], tr.ins.Runs[tr.index+1:]...)
+		return
+	}
+	if tr.list != nil {
+		*tr.list = append((*tr.list)[:tr.index], (*tr.list)[tr.index+1:]...)
+		return
+	}
+
+	tr.link.Run = nil
+	if len(tr.link.Children) > 0 && tr.link.Children[0].Run != nil {
+		tr.link.Run = tr.link.Children[0].Run
+		tr.link.Children = tr.link.Children[1:]
+	}
+}
+
+func insertParagraphChild(list *[]ctypes.ParagraphChild, index int, child ctypes.ParagraphChild) {
+	*list = append(*list, ctypes.ParagraphChild{})
+	copy((*list)[index+1:], (*list)[index:])
+	(*list)[index] = child
+}
+
+func insertRun(runs []*ctypes.Run, index int, r *ctypes.Run) []*ctypes.Run {
+	runs = append(runs, nil)
+	copy(runs[index+1:], runs[index:])
+	runs[index] = r
+	return runs
+}
+
+// removeEmptyLinks drops hyperlinks and tracked insertions that no longer hold any run.
+func removeEmptyLinks(list *[]ctypes.ParagraphChild) {
+	kept := (*list)[:0]
+	for _, child := range *list {
+		if child.Link != nil {
+			removeEmptyLinks(&child.Link.Children)
+			if child.Link.Run == nil && len(child.Link.Children) == 0 && child.Run == nil {
+				continue
+			}
+		}
+		if child.Ins != nil && len(child.Ins.Runs) == 0 && child.Run == nil {
+			continue
+		}
+		kept = append(kept, child)
+	}
+	*list = kept
+}
+
+// replaceText replaces the paragraph text between two offsets. The new text is put in a run
+// with the given properties, in front of the first run holding replaced text; content of the
+// replaced runs that has no text, such as field characters and drawings, is kept.
+func replaceText(p *ctypes.Paragraph, start, end int, text string, props *ctypes.RunProperty) {
+	splitRunsAt(p, end)
+	splitRunsAt(p, start)
+
+	if text != "" {
+		insertText(p, start, text, props, false)
+		start, end = start+len(text), end+len(text)
+	}
+
+	runs := paragraphRuns(p)
+	for i := len(runs) - 1; i >= 0; i-- {
+		tr := runs[i]
+		if tr.start < start || tr.end > end || tr.start == tr.end {
+			continue
+		}
+
+		var kept []ctypes.RunChild
+		for _, child := range tr.run.Children {
+			if child.Text == nil && runChildText(child) == "" {
+				kept = append(kept, child)
+			}
+		}
+		tr.run.Children = kept
+		if len(kept) == 0 {
+			tr.remove()
+		}
+	}
+
+	removeEmptyLinks(&p.Children)
+}
+
+// insertText puts text in a new run at an offset of the paragraph text. The run goes after the
+// run ending at the offset when after is set, and before the run starting there otherwise, so
+// that the text joins the hyperlink on that side, if any.
+func insertText(p *ctypes.Paragraph, at int, text string, props *ctypes.RunProperty, after bool) {
+	splitRunsAt(p, at)
+	r := &ctypes.Run{Property: props, Children: textRunChildren(text)}
+
+	runs := paragraphRuns(p)
+	for _, side := range []bool{after, !after} {
+		if side {
+			for i := len(runs) - 1; i >= 0; i-- {
+				if tr := runs[i]; tr.end == at && tr.start < tr.end {
+					tr.insertAfter(r)
+					return
+				}
+			}
+			continue
+		}
+
+		for _, tr := range runs {
+			if tr.start == at && tr.start < tr.end {
+				tr.insertBefore(r)
+				return
+			}
+		}
+	}
+
+	p.Children = append(p.Children, ctypes.ParagraphChild{Run: r})
+}
+
+// runPropAt returns the properties of the run holding the text at an offset, or nil.
+func runPropAt(p *ctypes.Paragraph, offset int) *ctypes.RunProperty {
+	for _, tr := range paragraphRuns(p) {
+		if tr.start <= offset && offset < tr.end {
+			return tr.run.Property
+		}
+	}
+	return nil
+}
@@ -0,0 +1,471 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// Severity tells how Word reacts to an issue found by Validate.
+type Severity string
+
+const (
+	// SeverityError is for issues that make Word report unreadable content, or drop the
+	// content, when opening the document.
+	SeverityError Severity = "error"
+
+	// SeverityWarning is for issues that Word works around silently, such as by falling back
+	// to the default style, but that show in the document differently from what was meant.
+	SeverityWarning Severity = "warning"
+)
+
+// IssueKind is the kind of an issue found by Validate.
+type IssueKind string
+
+const (
+	IssueMissingPart         IssueKind = "missing-part"         // A relationship points at a part that does not exist.
+	IssueMissingContentType  IssueKind = "missing-content-type" // A part has no content type.
+	IssueDuplicateRelationID IssueKind = "duplicate-relation-id"
+	IssueUndefinedRelation   IssueKind = "undefined-relation" // A part uses a relationship ID that is not defined.
+	IssueUnknownStyle        IssueKind = "unknown-style"
+	IssueUnknownNumbering    IssueKind = "unknown-numbering"
+	IssueGridSpan            IssueKind = "grid-span"     // The cells of a table row do not cover the table grid.
+	IssueElementOrder        IssueKind = "element-order" // Elements are not in the order required by the schema.
+)
+
+// Issue is a problem with a document found by Validate.
+type Issue struct {
+	Severity Severity  `json:"severity"`
+	Kind     IssueKind `json:"kind"`
+
+	// Part is the package path of the part with the issue, such as "word/document.xml".
+	Part string `json:"part"`
+
+	// Path is the XPath of the element with the issue within the part, such as
+	// "/w:document/w:body[1]/w:p[3]/w:pPr[1]/w:pStyle[1]", and is empty for issues with the
+	// package.
+	Path string `json:"path,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	where := i.Part
+	if i.Path != "" {
+		where += " " + i.Path
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Severity, where, i.Message)
+}
+
+// Validate checks the package the document would be written as for the problems Word reports
+// as unreadable content or works around silently:
+//
+//   - relationships pointing at parts that do not exist, and relationship IDs used by a part
+//     but not defined, or defined twice;
+//   - parts without a content type;
+//   - paragraphs, runs and tables referencing styles that are not defined, and paragraphs
+//     referencing numbering instances that are not defined;
+//   - table rows whose cells, with their gridSpan, do not cover the columns of the tblGrid;
+//   - elements that are not in the order required by the schema, such as properties after
+//     content.
+//
+// The issues are sorted by part and in document order within a part. The error is for a
+// document that cannot be written or whose parts cannot be read, not for the issues found.
+func (rd *RootDoc) Validate() ([]Issue, error) {
+	files, err := rd.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	v := &validator{rd: rd, files: files}
+	if err := v.numbering(); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if strings.HasSuffix(name, "/") || name == constants.ConentTypeFileIdx {
+			continue
+		}
+		if !v.hasContentType(name) {
+			v.add(SeverityError, IssueMissingContentType, name, "", "the part has no content type")
+		}
+		if strings.HasSuffix(name, ".rels") {
+			if err := v.relations(name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if isXMLContent(files[name]) {
+			if err := v.part(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.SliceStable(v.issues, func(i, j int) bool { return v.issues[i].Part < v.issues[j].Part })
+	return v.issues, nil
+}
+
+type validator struct {
+	rd     *RootDoc
+	files  map[string][]byte
+	issues []Issue
+
+	nums map[int]bool // nums are the IDs of the numbering instances defined.
+}
+
+func (v *validator) add(severity Severity, kind IssueKind, part, xpath, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{
+		Severity: severity,
+		Kind:     kind,
+		Part:     part,
+		Path:     xpath,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// numbering reads the numbering instances defined by the document and checks that the
+// abstract numbering definitions they use are defined.
+func (v *validator) numbering() error {
+	v.nums = make(map[int]bool)
+	content, ok := v.files[numberingPath]
+	if !ok {
+		return nil
+	}
+	abstracts, nums, err := readNumbering(content)
+	if err != nil {
+		return fmt.Errorf("%s: %w", numberingPath, err)
+	}
+
+	defined := make(map[int]bool, len(abstracts))
+	for _, a := range abstracts {
+		defined[a.id] = true
+	}
+	for _, n := range nums {
+		v.nums[n.id] = true
+		if !defined[n.abstract] {
+			v.add(SeverityError, IssueUnknownNumbering, numberingPath, "",
+				"numbering instance %d uses abstract numbering %d, which is not defined", n.id, n.abstract)
+		}
+	}
+	return nil
+}
+
+// hasContentType reports whether a part has a content type, by an override for its name or
+// by a default for its extension.
+func (v *validator) hasContentType(name string) bool {
+	for _, o := range v.rd.ContentType.Override {
+		if strings.TrimPrefix(o.PartName, "/") == name {
+			return true
+		}
+	}
+	ext := strings.TrimPrefix(path.Ext(name), ".")
+	for _, d := range v.rd.ContentType.Default {
+		if strings.EqualFold(d.Extension, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// relationsSource returns the part whose relationships a .rels file holds, which is empty for
+// the relationships of the package.
+func relationsSource(relsPath string) string {
+	dir := path.Dir(path.Dir(relsPath))
+	if dir == "." {
+		dir = ""
+	}
+	return path.Join(dir, strings.TrimSuffix(path.Base(relsPath), ".rels"))
+}
+
+// relations checks the relationships of a .rels file.
+func (v *validator) relations(relsPath string) error {
+	var rels Relationships
+	if err := xml.Unmarshal(v.files[relsPath], &rels); err != nil {
+		return fmt.Errorf("%s: %w", relsPath, err)
+	}
+
+	source := relationsSource(relsPath)
+	seen := make(map[string]bool, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		if seen[rel.ID] {
+			v.add(SeverityError, IssueDuplicateRelationID, relsPath, "", "relationship ID %s is defined more than once", rel.ID)
+		}
+		seen[rel.ID] = true
+
+		if rel.TargetMode == "External" {
+			continue
+		}
+		target := strings.TrimPrefix(rel.Target, "/")
+		if !strings.HasPrefix(rel.Target, "/") {
+			target = path.Join(path.Dir(source), rel.Target)
+		}
+		if _, ok := v.files[target]; !ok {
+			v.add(SeverityError, IssueMissingPart, relsPath, "", "relationship %s points at %s, which does not exist", rel.ID, target)
+		}
+	}
+	return nil
+}
+
+// sequence is the order of the children of an element required by the schema. Children are
+// ranked by their position in the sequence; children that are not listed have the rank other,
+// or are not checked when other is negative.
+type sequence struct {
+	ranks map[string]int
+	other int
+}
+
+// newSequence returns the sequence of the names given in order. Names separated by "|" have
+// the same rank, and "*" stands for all the names not listed.
+func newSequence(names ...string) sequence {
+	s := sequence{ranks: make(map[string]int), other: -1}
+	for rank, name := range names {
+		if name == "*" {
+			s.other = rank
+			continue
+		}
+		for _, n := range strings.Split(name, "|") {
+			s.ranks[n] = rank
+		}
+	}
+	return s
+}
+
+func (s sequence) rank(name string) int {
+	if rank, ok := s.ranks[name]; ok {
+		return rank
+	}
+	return s.other
+}
+
+// sequences are the orders of the children of WordprocessingML elements that are checked by
+// Validate, by the name of the element. Elements whose children may come in any order, such
+// as w:trPr, are not listed.
+var sequences = map[string]sequence{
+	"body": newSequence("*", "sectPr"),
+	"p":    newSequence("pPr", "*"),
+	"r":    newSequence("rPr", "*"),
+	"tbl":  newSequence("tblPr", "tblGrid", "*"),
+	"tr":   newSequence("tblPrEx", "trPr", "*"),
+	"tc":   newSequence("tcPr", "*"),
+	"pPr": newSequence("pStyle", "keepNext", "keepLines", "pageBreakBefore", "framePr",
+		"widowControl", "numPr", "suppressLineNumbers", "pBdr", "shd", "tabs",
+		"suppressAutoHyphens", "kinsoku", "wordWrap", "overflowPunct", "topLinePunct",
+		"autoSpaceDE", "autoSpaceDN", "bidi", "adjustRightInd", "snapToGrid", "spacing", "ind",
+		"contextualSpacing", "mirrorIndents", "suppressOverlap", "jc", "textDirection",
+		"textAlignment", "textboxTightWrap", "outlineLvl", "divId", "cnfStyle", "rPr", "sectPr",
+		"pPrChange"),
+	"rPr": newSequence("ins|del|moveFrom|moveTo", "rStyle", "rFonts", "b", "bCs", "i", "iCs",
+		"caps", "smallCaps", "strike", "dstrike", "outline", "shadow", "emboss", "imprint",
+		"noProof", "snapToGrid", "vanish", "webHidden", "color", "spacing", "w", "kern",
+		"position", "sz", "szCs", "highlight", "u", "effect", "bdr", "shd", "fitText",
+		"vertAlign", "rtl", "cs", "em", "lang", "eastAsianLayout", "specVanish", "oMath",
+		"rPrChange"),
+	"tblPr": newSequence("tblStyle", "tblpPr", "tblOverlap", "bidiVisual",
+		"tblStyleRowBandSize", "tblStyleColBandSize", "tblW", "jc", "tblCellSpacing", "tblInd",
+		"tblBorders", "shd", "tblLayout", "tblCellMar", "tblLook", "tblCaption",
+		"tblDescription", "tblPrChange"),
+	"tcPr": newSequence("cnfStyle", "tcW", "gridSpan", "hMerge", "vMerge", "tcBorders", "shd",
+		"noWrap", "tcMar", "textDirection", "tcFitText", "vAlign", "hideMark", "headers",
+		"cellIns|cellDel|cellMerge", "tcPrChange"),
+	"sectPr": newSequence("headerReference|footerReference", "footnotePr", "endnotePr", "type",
+		"pgSz", "pgMar", "paperSrc", "pgBorders", "lnNumType", "pgNumType", "cols", "formProt",
+		"vAlign", "noEndnote", "titlePg", "textDirection", "bidi", "rtlGutter", "docGrid",
+		"printerSettings", "sectPrChange"),
+	"style": newSequence("name", "aliases", "basedOn", "next", "link", "autoRedefine", "hidden",
+		"uiPriority", "semiHidden", "unhideWhenUsed", "qFormat", "locked", "personal",
+		"personalCompose", "personalReply", "rsid", "pPr", "rPr", "tblPr", "trPr", "tcPr",
+		"tblStylePr"),
+}
+
+// styleRefs are the elements referencing a style, with the type of the style.
+var styleRefs = map[string]stypes.StyleType{
+	"pStyle":   stypes.StyleTypeParagraph,
+	"rStyle":   stypes.StyleTypeCharacter,
+	"tblStyle": stypes.StyleTypeTable,
+}
+
+// element is an open element while a part is scanned.
+type element struct {
+	name   xml.Name
+	path   string
+	counts map[string]int // counts are the numbers of children by name.
+
+	last      string // last is the name of the last child checked against the sequence.
+	lastRank  int
+	misplaced bool // misplaced is set once a child is out of order, which is reported once.
+}
+
+// grid is an open table while a part is scanned.
+type grid struct {
+	cols    int // cols is the number of columns of the table grid.
+	covered int // covered is the number of grid columns covered by the current row.
+	row     string
+}
+
+// part scans an XML part for relationship IDs that are not defined, references to styles
+// and numbering that are not defined, inconsistent table grids and elements out of order.
+func (v *validator) part(name string) error {
+	rels := make(map[string]bool)
+	relsPath := path.Join(path.Dir(name), "_rels", path.Base(name)+".rels")
+	if content, ok := v.files[relsPath]; ok {
+		var r Relationships
+		if err := xml.Unmarshal(content, &r); err != nil {
+			return fmt.Errorf("%s: %w", relsPath, err)
+		}
+		for _, rel := range r.Relationships {
+			rels[rel.ID] = true
+		}
+	}
+
+	var stack []*element
+	var tables []*grid
+	d := xml.NewDecoder(bytes.NewReader(v.files[name]))
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			wml := isWML(tok.Name)
+			local := tok.Name.Local
+			el := &element{name: tok.Name, path: "/" + qualifiedName(tok.Name), lastRank: -1}
+
+			var parent *element
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+				if parent.counts == nil {
+					parent.counts = make(map[string]int)
+				}
+				key := qualifiedName(tok.Name)
+				parent.counts[key]++
+				el.path = parent.path + "/" + key + "[" + strconv.Itoa(parent.counts[key]) + "]"
+
+				if seq, ok := sequences[parent.name.Local]; ok && wml && isWML(parent.name) {
+					if rank := seq.rank(local); rank >= 0 && !parent.misplaced {
+						if rank < parent.lastRank {
+							parent.misplaced = true
+							v.add(SeverityError, IssueElementOrder, name, el.path,
+								"w:%s must come before w:%s in w:%s", local, parent.last, parent.name.Local)
+						} else {
+							parent.last, parent.lastRank = local, rank
+						}
+					}
+				}
+			}
+			stack = append(stack, el)
+
+			for _, a := range tok.Attr {
+				if relationshipsNS[a.Name.Space] && a.Value != "" && !rels[a.Value] {
+					v.add(SeverityError, IssueUndefinedRelation, name, el.path, "relationship %s is not defined", a.Value)
+				}
+			}
+			if !wml {
+				continue
+			}
+
+			val := attrValue(tok, "val")
+			switch local {
+			case "pStyle", "rStyle", "tblStyle":
+				if val != "" && v.rd.GetStyleByID(val, styleRefs[local]) == nil {
+					v.add(SeverityWarning, IssueUnknownStyle, name, el.path, "%s style %q is not defined", styleRefs[local], val)
+				}
+			case "numId":
+				if parent == nil || parent.name.Local != "numPr" {
+					break
+				}
+				id, err := strconv.Atoi(val)
+				if err == nil && id != 0 && !v.nums[id] {
+					v.add(SeverityWarning, IssueUnknownNumbering, name, el.path, "numbering instance %d is not defined", id)
+				}
+			case "tbl":
+				tables = append(tables, &grid{})
+			case "gridCol":
+				// Columns of the tblGrid of a table, leaving out those of a tblGridChange.
+				if len(stack) >= 3 && len(tables) > 0 && stack[len(stack)-2].name.Local == "tblGrid" && stack[len(stack)-3].name.Local == "tbl" {
+					tables[len(tables)-1].cols++
+				}
+			case "tr":
+				if len(tables) > 0 {
+					tables[len(tables)-1].covered, tables[len(tables)-1].row = 0, el.path
+				}
+			case "tc":
+				if len(tables) > 0 {
+					tables[len(tables)-1].covered++
+				}
+			case "gridSpan", "gridBefore", "gridAfter":
+				n, err := strconv.Atoi(val)
+				if err != nil || len(tables) == 0 {
+					break
+				}
+				if local == "gridSpan" {
+					// The cell counts for one column already.
+					n--
+				}
+				tables[len(tables)-1].covered += n
+			}
+
+		case xml.EndElement:
+			el := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !isWML(el.name) || len(tables) == 0 {
+				continue
+			}
+			t := tables[len(tables)-1]
+			switch el.name.Local {
+			case "tr":
+				// Word works out the grid of a table without grid columns from its cells.
+				if t.cols > 0 && t.covered != t.cols {
+					v.add(SeverityWarning, IssueGridSpan, name, t.row,
+						"the cells of the row cover %d grid columns, but the table grid has %d", t.covered, t.cols)
+				}
+			case "tbl":
+				tables = tables[:len(tables)-1]
+			}
+		}
+	}
+}
+
+// isWML reports whether a name is in the WordprocessingML namespace.
+func isWML(name xml.Name) bool {
+	return name.Space == constants.WMLNamespace || name.Space == constants.AltWMLNamespace
+}
+
+// qualifiedName returns the name of an element as used in the paths of issues, with the w
+// prefix for WordprocessingML.
+func qualifiedName(name xml.Name) string {
+	if isWML(name) {
+		return "w:" + name.Local
+	}
+	return name.Local
+}
+
+// attrValue returns the value of the attribute of an element with the given local name.
+func attrValue(start xml.StartElement, local string) string {
+	for _, a := range start.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
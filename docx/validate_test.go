@@ -0,0 +1,79 @@
+package docx_test
+
+import (
+	"bytes"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/docx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+	_, err = rd.AddHeading("Summary", 1)
+	require.NoError(t, err)
+	tbl := rd.AddTable().Grid(2000, 2000)
+	row := tbl.AddRow()
+	row.AddCell().AddParagraph("Region")
+	row.AddCell().AddParagraph("Revenue")
+
+	issues, err := rd.Validate()
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	rd.AddParagraph("Styled").Style("Missing")
+	rd.AddParagraph("Item").Numbering(99, 0)
+	tbl.AddRow().AddCell().AddParagraph("Total")
+
+	rels := &rd.Document.DocRels.Relationships
+	*rels = append(*rels, &docx.Relationship{
+		ID:     (*rels)[0].ID,
+		Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image",
+		Target: "media/missing.png",
+	})
+	rd.FileMap.Store("word/extra.bin", []byte{0})
+	content, ok := rd.FileMap.Load("word/numbering.xml")
+	require.True(t, ok)
+	rd.FileMap.Store("word/numbering.xml", bytes.Replace(content.([]byte), []byte("<w:pPr>"), []byte(`<w:pPr><w:jc w:val="left"/><w:keepNext/>`), 1))
+
+	issues, err = rd.Validate()
+	require.NoError(t, err)
+
+	kinds := make(map[docx.IssueKind][]docx.Issue)
+	for _, issue := range issues {
+		kinds[issue.Kind] = append(kinds[issue.Kind], issue)
+	}
+	assert.Len(t, issues, 7, "issues: %v", issues)
+
+	require.Len(t, kinds[docx.IssueUnknownStyle], 1)
+	assert.Equal(t, docx.Issue{
+		Severity: docx.SeverityWarning,
+		Kind:     docx.IssueUnknownStyle,
+		Part:     "word/document.xml",
+		Path:     "/w:document/w:body[1]/w:p[2]/w:pPr[1]/w:pStyle[1]",
+		Message:  `paragraph style "Missing" is not defined`,
+	}, kinds[docx.IssueUnknownStyle][0])
+
+	require.Len(t, kinds[docx.IssueUnknownNumbering], 1)
+	assert.Equal(t, "numbering instance 99 is not defined", kinds[docx.IssueUnknownNumbering][0].Message)
+
+	require.Len(t, kinds[docx.IssueGridSpan], 1)
+	assert.Equal(t, "/w:document/w:body[1]/w:tbl[1]/w:tr[2]", kinds[docx.IssueGridSpan][0].Path)
+	assert.Equal(t, "the cells of the row cover 1 grid columns, but the table grid has 2", kinds[docx.IssueGridSpan][0].Message)
+
+	require.Len(t, kinds[docx.IssueDuplicateRelationID], 1)
+	require.Len(t, kinds[docx.IssueMissingPart], 1)
+	assert.Equal(t, "word/_rels/document.xml.rels", kinds[docx.IssueMissingPart][0].Part)
+	assert.Contains(t, kinds[docx.IssueMissingPart][0].Message, "word/media/missing.png")
+
+	require.Len(t, kinds[docx.IssueMissingContentType], 1)
+	assert.Equal(t, "word/extra.bin", kinds[docx.IssueMissingContentType][0].Part)
+
+	require.Len(t, kinds[docx.IssueElementOrder], 1)
+	assert.Equal(t, docx.SeverityError, kinds[docx.IssueElementOrder][0].Severity)
+	assert.Equal(t, "word/numbering.xml", kinds[docx.IssueElementOrder][0].Part)
+	assert.Equal(t, "w:keepNext must come before w:jc in w:pPr", kinds[docx.IssueElementOrder][0].Message)
+}
@@ -0,0 +1,278 @@
+package docx
+
+import (
+	"github.com/gomutex/godocx/dml"
+	"github.com/gomutex/godocx/wml/ctypes"
+)
+
+// WalkAction tells RootDoc.Walk how to go on after a callback returns.
+type WalkAction int
+
+const (
+	WalkContinue     WalkAction = iota // visit the children of the element, then the elements after it
+	WalkSkipChildren                   // leave out the children of the element
+	WalkStop                           // end the walk
+)
+
+// Visitor holds the callbacks called by RootDoc.Walk. Callbacks left nil are not called, and
+// the children of their elements are visited.
+type Visitor struct {
+	// VisitParagraph is called for every paragraph. Its children are its runs.
+	VisitParagraph func(ctx *WalkContext, p *Paragraph) WalkAction
+
+	// VisitRun is called for every run, including the runs of hyperlinks and of tracked
+	// insertions and deletions. Its children are its drawings.
+	VisitRun func(ctx *WalkContext, r *Run) WalkAction
+
+	// VisitTable is called for every table, including nested tables. Its children are its
+	// cells.
+	VisitTable func(ctx *WalkContext, t *Table) WalkAction
+
+	// VisitCell is called for every table cell. Its children are its paragraphs and tables.
+	VisitCell func(ctx *WalkContext, c *Cell) WalkAction
+
+	// VisitDrawing is called for every DrawingML object, such as an inline picture.
+	VisitDrawing func(ctx *WalkContext, d *dml.Drawing) WalkAction
+}
+
+// WalkContext tells where in the document the element handed to a callback is. The context
+// is reused during the walk; copy what needs to outlive the callback.
+type WalkContext struct {
+	Story   *Story // Story is the header, footer, note or comment being walked, or nil for the body.
+	Section int    // Section is the index of the body section, counting from 0, and 0 in stories.
+
+	// Tables are the tables holding the element, outermost first, along with the row and
+	// cell holding it. It is empty outside of tables.
+	Tables []TablePosition
+
+	// Headings are the headings the element falls under, outermost first. For a heading
+	// paragraph, these are the headings above it.
+	Headings []*Paragraph
+
+	Control   *ContentControl // Control is the innermost content control holding the element, if any.
+	Paragraph *Paragraph      // Paragraph holds the run or drawing being visited.
+	Hyperlink *Hyperlink      // Hyperlink holds the run or drawing being visited, if any.
+
+	// Insertion and Deletion are the tracked change holding the run or drawing being visited,
+	// if any. The text of a deleted run is not part of the paragraph text and is held as
+	// deleted text, which Run.Text leaves out.
+	Insertion *ctypes.RunTrackChange
+	Deletion  *ctypes.RunTrackChange
+}
+
+// TablePosition is the position of an element inside a table.
+type TablePosition struct {
+	Table *Table
+	Row   int // Row is the index of the row, counting from 0.
+	Cell  int // Cell is the index of the cell in the row, counting from 0.
+}
+
+// Walk visits the document body and then every header, footer, footnote, endnote and
+// comment, calling the callbacks of v in document order. It descends into tables at any
+// depth, hyperlinks and content controls.
+//
+// Story parts are loaded if needed; an error is returned only when that fails.
+func (rd *RootDoc) Walk(v Visitor) error {
+	w := &walker{v: v}
+	if !w.blocks(rd.Document.Body.Children, true) {
+		return nil
+	}
+
+	stories, err := rd.Stories()
+	if err != nil {
+		return err
+	}
+	for _, s := range stories {
+		w.ctx = WalkContext{Story: s}
+		w.levels = nil
+		if !w.blocks(s.Children, false) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// walker holds the state of a walk. Its methods return false once the walk is stopped.
+type walker struct {
+	v      Visitor
+	ctx    WalkContext
+	levels []uint // levels are the heading levels of ctx.Headings.
+}
+
+func (w *walker) blocks(children []DocumentChild, body bool) bool {
+	for _, child := range children {
+		switch {
+		case child.Para != nil:
+			if !w.paragraph(child.Para, body) {
+				return false
+			}
+		case child.Table != nil:
+			if !w.table(child.Table, body) {
+				return false
+			}
+		case child.Control != nil:
+			outer := w.ctx.Control
+			w.ctx.Control = child.Control
+			ok := w.blocks(child.Control.Contents(), body)
+			w.ctx.Control = outer
+			if !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (w *walker) paragraph(p *Paragraph, body bool) bool {
+	level, heading := headingLevel(p.ct)
+	if heading {
+		for len(w.levels) > 0 && w.levels[len(w.levels)-1] >= level {
+			w.levels = w.levels[:len(w.levels)-1]
+			w.ctx.Headings = w.ctx.Headings[:len(w.ctx.Headings)-1]
+		}
+	}
+
+	action := WalkContinue
+	if w.v.VisitParagraph != nil {
+		action = w.v.VisitParagraph(&w.ctx, p)
+	}
+
+	ok := true
+	switch action {
+	case WalkStop:
+		return false
+	case WalkContinue:
+		ok = w.runs(p)
+	}
+
+	if heading {
+		w.levels = append(w.levels, level)
+		w.ctx.Headings = append(w.ctx.Headings[:len(w.ctx.Headings):len(w.ctx.Headings)], p)
+	}
+	// A paragraph holding section properties is the last paragraph of its section.
+	if body && p.ct.Property != nil && p.ct.Property.SectPr != nil {
+		w.ctx.Section++
+	}
+	return ok
+}
+
+func (w *walker) runs(p *Paragraph) bool {
+	defer func() {
+		w.ctx.Paragraph, w.ctx.Hyperlink = nil, nil
+		w.ctx.Insertion, w.ctx.Deletion = nil, nil
+	}()
+
+	w.ctx.Paragraph = p
+	return w.paragraphChildren(p, p.ct.Children)
+}
+
+// paragraphChildren visits the runs of a list of paragraph children in document order.
+func (w *walker) paragraphChildren(p *Paragraph, children []ctypes.ParagraphChild) bool {
+	for _, child := range children {
+		if child.Run != nil && !w.run(p, child.Run) {
+			return false
+		}
+
+		if child.Link != nil {
+			outer := w.ctx.Hyperlink
+			w.ctx.Hyperlink = newHyperlink(p.root, child.Link)
+			if child.Link.Run != nil && !w.run(p, child.Link.Run) {
+				return false
+			}
+			if !w.paragraphChildren(p, child.Link.Children) {
+				return false
+			}
+			w.ctx.Hyperlink = outer
+		}
+
+		if child.Ins != nil {
+			w.ctx.Insertion = child.Ins
+			if !w.trackedRuns(p, child.Ins) {
+				return false
+			}
+			w.ctx.Insertion = nil
+		}
+
+		if child.Del != nil {
+			w.ctx.Deletion = child.Del
+			if !w.trackedRuns(p, child.Del) {
+				return false
+			}
+			w.ctx.Deletion = nil
+		}
+	}
+	return true
+}
+
+func (w *walker) trackedRuns(p *Paragraph, tc *ctypes.RunTrackChange) bool {
+	for _, r := range tc.Runs {
+		if !w.run(p, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *walker) run(p *Paragraph, run *ctypes.Run) bool {
+	action := WalkContinue
+	if w.v.VisitRun != nil {
+		r := newRun(p.root, run)
+		r.para = p.ct
+		action = w.v.VisitRun(&w.ctx, r)
+	}
+
+	switch action {
+	case WalkStop:
+		return false
+	case WalkSkipChildren:
+		return true
+	}
+
+	for _, child := range run.Children {
+		if child.Drawing == nil || w.v.VisitDrawing == nil {
+			continue
+		}
+		if w.v.VisitDrawing(&w.ctx, child.Drawing) == WalkStop {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *walker) table(t *Table, body bool) bool {
+	action := WalkContinue
+	if w.v.VisitTable != nil {
+		action = w.v.VisitTable(&w.ctx, t)
+	}
+	switch action {
+	case WalkStop:
+		return false
+	case WalkSkipChildren:
+		return true
+	}
+
+	outer := w.ctx.Tables
+	defer func() { w.ctx.Tables = outer }()
+
+	for i, row := range t.Rows() {
+		for j, cell := range row.Cells() {
+			w.ctx.Tables = append(outer[:len(outer):len(outer)], TablePosition{Table: t, Row: i, Cell: j})
+
+			action := WalkContinue
+			if w.v.VisitCell != nil {
+				action = w.v.VisitCell(&w.ctx, cell)
+			}
+			switch action {
+			case WalkStop:
+				return false
+			case WalkSkipChildren:
+				continue
+			}
+
+			if !w.blocks(cell.Contents(), body) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gomutex/godocx/dml"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWalkXML = `<w:body xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Intro</w:t></w:r></w:p>` +
+	`<w:p><w:pPr><w:pStyle w:val="Heading2"/><w:sectPr/></w:pPr><w:r><w:t>Scope</w:t></w:r></w:p>` +
+	`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>a</w:t></w:r></w:p></w:tc>` +
+	`<w:tc><w:tbl><w:tr><w:tc><w:p><w:r><w:t>deep</w:t></w:r></w:p></w:tc></w:tr></w:tbl><w:p/></w:tc></w:tr></w:tbl>` +
+	`<w:sdt><w:sdtPr><w:alias w:val="Client"/><w:tag w:val="client"/></w:sdtPr>` +
+	`<w:sdtContent><w:p><w:r><w:t>ACME</w:t></w:r></w:p></w:sdtContent></w:sdt>` +
+	`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Terms</w:t></w:r></w:p>` +
+	`</w:body>`
+
+func loadWalkDoc(t *testing.T) *RootDoc {
+	rd := setupStoryDoc(t)
+	body := NewBody(rd)
+	require.NoError(t, xml.Unmarshal([]byte(testWalkXML), body))
+	rd.Document.Body = body
+	return rd
+}
+
+func TestWalk(t *testing.T) {
+	rd := loadWalkDoc(t)
+	rd.Document.Body.Children[0].Para.ct.Children = append(rd.Document.Body.Children[0].Para.ct.Children,
+		ctypes.ParagraphChild{Run: &ctypes.Run{Children: []ctypes.RunChild{{Drawing: &dml.Drawing{}}}}})
+
+	var events []string
+	err := rd.Walk(Visitor{
+		VisitParagraph: func(ctx *WalkContext, p *Paragraph) WalkAction {
+			var crumbs []string
+			for _, h := range ctx.Headings {
+				crumbs = append(crumbs, h.Text())
+			}
+			where := "body"
+			if ctx.Story != nil {
+				where = string(ctx.Story.Type)
+			}
+			if ctx.Control != nil {
+				where += " " + ctx.Control.Tag()
+			}
+			events = append(events, fmt.Sprintf("p %q %s s%d t%d [%s]", p.Text(), where, ctx.Section, len(ctx.Tables), strings.Join(crumbs, "/")))
+			return WalkSkipChildren
+		},
+		VisitTable: func(ctx *WalkContext, tbl *Table) WalkAction {
+			events = append(events, fmt.Sprintf("table t%d", len(ctx.Tables)))
+			return WalkContinue
+		},
+		VisitCell: func(ctx *WalkContext, c *Cell) WalkAction {
+			pos := ctx.Tables[len(ctx.Tables)-1]
+			events = append(events, fmt.Sprintf("cell %d,%d", pos.Row, pos.Cell))
+			return WalkContinue
+		},
+		VisitDrawing: func(ctx *WalkContext, d *dml.Drawing) WalkAction {
+			events = append(events, "drawing")
+			return WalkContinue
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`p "Intro" body s0 t0 []`,
+		`p "Scope" body s0 t0 [Intro]`,
+		`table t0`,
+		`cell 0,0`,
+		`p "a" body s1 t1 [Intro/Scope]`,
+		`cell 0,1`,
+		`table t1`,
+		`cell 0,0`,
+		`p "deep" body s1 t2 [Intro/Scope]`,
+		`p "" body s1 t1 [Intro/Scope]`,
+		`p "ACME" body client s1 t0 [Intro/Scope]`,
+		`p "Terms" body s1 t0 []`,
+		`p "Product manual" header s0 t0 []`,
+		`p " See appendix." footnote s0 t0 []`,
+	}, events, "skipping the children of paragraphs leaves out the drawing")
+}
+
+func TestWalk_RunsAndStop(t *testing.T) {
+	rd := loadWalkDoc(t)
+	p := rd.Document.Body.Children[0].Para
+	p.AddLink("link", "https://example.com")
+	p.ct.Children = append(p.ct.Children, ctypes.ParagraphChild{Run: &ctypes.Run{Children: []ctypes.RunChild{{Drawing: &dml.Drawing{}}}}})
+
+	var runs []string
+	drawings := 0
+	err := rd.Walk(Visitor{
+		VisitRun: func(ctx *WalkContext, r *Run) WalkAction {
+			text := r.Text()
+			if ctx.Hyperlink != nil {
+				text += " in link"
+			}
+			runs = append(runs, text)
+			if ctx.Paragraph.Text() == "Scope" {
+				return WalkStop
+			}
+			return WalkContinue
+		},
+		VisitDrawing: func(ctx *WalkContext, d *dml.Drawing) WalkAction {
+			drawings++
+			return WalkContinue
+		},
+		VisitTable: func(ctx *WalkContext, tbl *Table) WalkAction {
+			t.Fatal("the walk was stopped before the table")
+			return WalkStop
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Intro", "link in link", "", "Scope"}, runs)
+	assert.Equal(t, 1, drawings)
+}
+
+func TestWalk_Insertion(t *testing.T) {
+	rd := setupRootDoc(t)
+	p := rd.AddParagraph("Hello ")
+	addTestInsertion(p, "big ")
+	p.AddText("world")
+
+	var runs []string
+	require.NoError(t, rd.Walk(Visitor{VisitRun: func(ctx *WalkContext, r *Run) WalkAction {
+		runs = append(runs, r.Text())
+		return WalkContinue
+	}}))
+	assert.Equal(t, []string{"Hello ", "big ", "world"}, runs)
+	assert.Equal(t, p.Text(), strings.Join(runs, ""))
+}
+
+func TestWalk_TrackedChanges(t *testing.T) {
+	rd := setupRootDoc(t)
+	p := rd.AddParagraph("Hello ")
+	del := &ctypes.RunTrackChange{
+		TrackChange: ctypes.TrackChange{ID: 2, Author: "Ada"},
+		Runs:        []*ctypes.Run{{Children: []ctypes.RunChild{{DelText: ctypes.TextFromString("old ")}}}},
+	}
+	p.ct.Children = append(p.ct.Children, ctypes.ParagraphChild{Del: del})
+	addTestInsertion(p, "new ")
+	p.AddText("world")
+	ins := p.ct.Children[2].Ins
+
+	type visit struct {
+		ins, del *ctypes.RunTrackChange
+	}
+	var visits []visit
+	var runs []*ctypes.Run
+	require.NoError(t, rd.Walk(Visitor{VisitRun: func(ctx *WalkContext, r *Run) WalkAction {
+		visits = append(visits, visit{ctx.Insertion, ctx.Deletion})
+		runs = append(runs, r.ct)
+		return WalkContinue
+	}}))
+
+	assert.Equal(t, []visit{{}, {del: del}, {ins: ins}, {}}, visits)
+	require.Len(t, runs, 4)
+	assert.Same(t, del.Runs[0], runs[1])
+	assert.Same(t, ins.Runs[0], runs[2])
+}
+
+func TestContentControl_RoundTrip(t *testing.T) {
+	rd := loadWalkDoc(t)
+	ctl := rd.Document.Body.Children[3].Control
+	require.NotNil(t, ctl)
+	assert.Equal(t, "client", ctl.Tag())
+	assert.Equal(t, "Client", ctl.Alias())
+	assert.Contains(t, rd.Text(), "ACME")
+
+	_, err := ctl.Paragraphs()[0].InsertParagraphAfter("Ltd")
+	require.NoError(t, err)
+
+	out, err := xml.Marshal(rd.Document.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `<w:sdt><w:sdtPr><w:alias w:val="Client"/><w:tag w:val="client"/></w:sdtPr>`+
+		`<w:sdtContent><w:p><w:r><w:t>ACME</w:t></w:r></w:p><w:p><w:r><w:t>Ltd</w:t></w:r></w:p></w:sdtContent></w:sdt>`)
+}
@@ -0,0 +1,75 @@
+package docx
+
+import (
+	"strings"
+
+	"github.com/gomutex/godocx/wml/ctypes"
+)
+
+// Rows returns the rows of the table.
+func (t *Table) Rows() []*Row {
+	var rows []*Row
+	for _, rc := range t.ct.RowContents {
+		if rc.Row != nil {
+			rows = append(rows, &Row{root: t.root, ct: rc.Row})
+		}
+	}
+	return rows
+}
+
+// Cells returns the cells of the row.
+func (r *Row) Cells() []*Cell {
+	var cells []*Cell
+	for _, cc := range r.ct.Contents {
+		if cc.Cell != nil {
+			cells = append(cells, &Cell{root: r.root, ct: cc.Cell})
+		}
+	}
+	return cells
+}
+
+// Contents returns the paragraphs, tables and content controls of the cell.
+func (c *Cell) Contents() []DocumentChild {
+	return wrapBlockContents(c.root, c.ct.Contents)
+}
+
+// wrapBlockContents returns the wrappers of the paragraphs, tables and content controls of a
+// cell or content control.
+func wrapBlockContents(root *RootDoc, contents []ctypes.TCBlockContent) []DocumentChild {
+	var children []DocumentChild
+	for _, content := range contents {
+		if content.Paragraph != nil {
+			children = append(children, DocumentChild{Para: &Paragraph{root: root, ct: content.Paragraph}})
+		}
+		if content.Table != nil {
+			children = append(children, DocumentChild{Table: &Table{root: root, ct: content.Table}})
+		}
+		if content.SDT != nil {
+			children = append(children, DocumentChild{Control: &ContentControl{root: root, ct: content.SDT}})
+		}
+	}
+	return children
+}
+
+// Paragraphs returns the paragraphs of the cell, leaving out nested tables and content
+// controls.
+func (c *Cell) Paragraphs() []*Paragraph {
+	var paras []*Paragraph
+	for _, child := range c.Contents() {
+		if child.Para != nil {
+			paras = append(paras, child.Para)
+		}
+	}
+	return paras
+}
+
+// Text returns the text of the paragraphs of the cell, including those of nested tables,
+// separated by newlines.
+func (c *Cell) Text() string {
+	paras := contentParagraphs(nil, c.ct.Contents)
+	texts := make([]string, 0, len(paras))
+	for _, p := range paras {
+		texts = append(texts, paragraphText(p))
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,104 @@
+package docx
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/gomutex/godocx/wml/stypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBodyXML = `<w:body xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<w:p><w:pPr><w:pStyle w:val="ListNumber"/><w:jc w:val="center"/><w:numPr><w:ilvl w:val="1"/><w:numId w:val="3"/></w:numPr></w:pPr>` +
+	`<w:r><w:rPr><w:rFonts w:ascii="Arial"/><w:b/><w:sz w:val="25"/><w:color w:val="FF0000"/></w:rPr><w:t xml:space="preserve">See </w:t></w:r>` +
+	`<w:hyperlink r:id="rId9"><w:r><w:t>the site</w:t></w:r></w:hyperlink></w:p>` +
+	`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Qty</w:t></w:r></w:p>` +
+	`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>nested</w:t></w:r></w:p></w:tc></w:tr></w:tbl><w:p/></w:tc></w:tr></w:tbl>` +
+	`</w:body>`
+
+func loadTestBody(t *testing.T) *RootDoc {
+	rd := setupRootDoc(t)
+	rd.Document.DocRels.Relationships = append(rd.Document.DocRels.Relationships,
+		&Relationship{ID: "rId9", Type: "hyperlink", Target: "https://example.com", TargetMode: "External"})
+
+	body := NewBody(rd)
+	require.NoError(t, xml.Unmarshal([]byte(testBodyXML), body))
+	rd.Document.Body = body
+	return rd
+}
+
+func TestReadAccessors(t *testing.T) {
+	rd := loadTestBody(t)
+	p := rd.Document.Body.Children[0].Para
+
+	assert.Equal(t, "ListNumber", p.StyleID())
+	assert.Equal(t, stypes.JustificationCenter, p.Alignment())
+	assert.Equal(t, 3, p.NumberingID())
+	assert.Equal(t, 1, p.NumberingLevel())
+	assert.Equal(t, -1, rd.AddParagraph("plain").NumberingLevel())
+
+	runs := p.Runs()
+	require.Len(t, runs, 2)
+	assert.Equal(t, "See ", runs[0].Text())
+	assert.True(t, runs[0].IsBold())
+	assert.False(t, runs[0].IsItalic())
+	assert.Equal(t, 12.5, runs[0].FontSize())
+	assert.Equal(t, "Arial", runs[0].FontName())
+	assert.Equal(t, "FF0000", runs[0].GetColor())
+	assert.Equal(t, "", runs[1].GetColor())
+
+	links := p.Hyperlinks()
+	require.Len(t, links, 1)
+	assert.Equal(t, "https://example.com", links[0].URL())
+	assert.Equal(t, "the site", links[0].Text())
+	assert.Equal(t, "the site", links[0].Runs()[0].Text())
+}
+
+func TestTableWrappers(t *testing.T) {
+	rd := loadTestBody(t)
+	tbl := rd.Document.Body.Children[1].Table
+
+	rows := tbl.Rows()
+	require.Len(t, rows, 1)
+	assert.Same(t, rows[0].ct, tbl.Rows()[0].ct, "wrappers hold the elements of the table")
+
+	cells := rows[0].Cells()
+	require.Len(t, cells, 1)
+	contents := cells[0].Contents()
+	require.Len(t, contents, 3)
+	assert.Equal(t, "Qty", contents[0].Para.Text())
+	assert.Same(t, contents[0].Para.GetCT(), cells[0].Paragraphs()[0].GetCT())
+
+	nested := contents[1].Table
+	require.NotNil(t, nested)
+	assert.Equal(t, "nested", nested.Rows()[0].Cells()[0].Paragraphs()[0].Text())
+
+	// Wrappers edit the loaded table in place.
+	contents[0].Para.AddText(" ordered")
+	_, err := contents[0].Para.InsertParagraphAfter("note")
+	require.NoError(t, err)
+	assert.Equal(t, "Qty ordered\nnote\nnested\n", rd.Text()[len("See the site\n"):])
+
+	added := cells[0].AddParagraph("added")
+	assert.Same(t, added.GetCT(), cells[0].Paragraphs()[3].GetCT())
+}
+
+func TestTableWrappers_ReadOnly(t *testing.T) {
+	rd := loadTestBody(t)
+	tbl := rd.Document.Body.Children[1].Table
+	rowCT := tbl.GetCT().RowContents[0].Row
+	cellCT := rowCT.Contents[0].Cell
+	paraCT := cellCT.Contents[0].Paragraph
+
+	row := tbl.Rows()[0]
+	cell := row.Cells()[0]
+	para := cell.Paragraphs()[0]
+
+	assert.Same(t, rowCT, row.ct)
+	assert.Same(t, rowCT, tbl.GetCT().RowContents[0].Row, "reading the rows leaves the table as it is")
+	assert.Same(t, cellCT, cell.ct)
+	assert.Same(t, cellCT, rowCT.Contents[0].Cell)
+	assert.Same(t, paraCT, para.GetCT())
+	assert.Same(t, paraCT, cellCT.Contents[0].Paragraph)
+}
@@ -46,52 +46,83 @@ func (rd *RootDoc) writeDirectToWriter(w io.Writer) error {
 
 // writeToZip provides a function to write to zip.Writer
 func (rd *RootDoc) writeToZip(zw *zip.Writer) error {
+	snapshot, err := rd.snapshot()
+	if err != nil {
+		return err
+	}
 
-	var (
-		err   error
-		files []string
-	)
+	// Now gather list of paths from snapshot
+	files := make([]string, 0, len(snapshot))
+	for p := range snapshot {
+		files = append(files, p)
+	}
 
+	sort.Strings(files)
+	for _, path := range files {
+		// Use a deterministic timestamp for reproducible archives
+		hdr := &zip.FileHeader{
+			Name:     path,
+			Method:   zip.Deflate,
+			Modified: time.Unix(0, 0).UTC(),
+		}
+		var fi io.Writer
+		if fi, err = zw.CreateHeader(hdr); err != nil {
+			break
+		}
+		_, err = fi.Write(snapshot[path])
+	}
+
+	return err
+}
+
+// snapshot returns the content of the parts of the package as they are written, by path.
+func (rd *RootDoc) snapshot() (map[string][]byte, error) {
 	// Build a local deterministic snapshot rather than mutating rd.FileMap while writing
 	snapshot := make(map[string][]byte)
 
 	ct, err := marshal(rd.ContentType)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	snapshot[constants.ConentTypeFileIdx] = []byte(ct)
 
 	docRelContent, err := marshal(rd.Document.DocRels)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	snapshot[rd.Document.DocRels.RelativePath] = docRelContent
 
 	rootRelContent, err := marshal(rd.RootRels)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	snapshot[rd.RootRels.RelativePath] = rootRelContent
 
 	docContent, err := marshal(rd.Document)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	snapshot[rd.Document.relativePath] = docContent
 
 	docStyleBytes, err := marshal(rd.DocStyles)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	snapshot[rd.DocStyles.RelativePath] = docStyleBytes
 
-	// Persist numbering instances into numbering.xml if any
+	// Story parts that have been loaded replace their original content
+	for _, part := range rd.storyParts {
+		partBytes, err := marshal(part)
+		if err != nil {
+			return nil, err
+		}
+		snapshot[part.path] = partBytes
+	}
+
+	// Numbering instances go into the snapshot's numbering.xml, if any
 	if rd.Numbering != nil {
-		// Apply numbering into a temporary buffer based on either existing or minimal content
-		// by temporarily swapping root to a shallow copy with only the snapshot layer.
-		// Minimal change: read existing numbering.xml if present from rd.FileMap, else let manager create it.
-		if err := rd.Numbering.applyToFileMap(); err != nil {
-			return err
+		if content, ok := rd.Numbering.numberingPart(); ok {
+			snapshot[numberingPath] = content
 		}
 	}
 
@@ -104,27 +135,7 @@ func (rd *RootDoc) writeToZip(zw *zip.Writer) error {
 		return true
 	})
 
-	// Now gather list of paths from snapshot
-	for p := range snapshot {
-		files = append(files, p)
-	}
-
-	sort.Strings(files)
-	for _, path := range files {
-		// Use a deterministic timestamp for reproducible archives
-		hdr := &zip.FileHeader{
-			Name:     path,
-			Method:   zip.Deflate,
-			Modified: time.Unix(0, 0).UTC(),
-		}
-		var fi io.Writer
-		if fi, err = zw.CreateHeader(hdr); err != nil {
-			break
-		}
-		_, err = fi.Write(snapshot[path])
-	}
-
-	return err
+	return snapshot, nil
 }
 
 // Save method saves the RootDoc to the specified file path.
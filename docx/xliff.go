@@ -0,0 +1,886 @@
+package docx
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gomutex/godocx/common/constants"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+const xliffNamespace = "urn:oasis:names:tc:xliff:document:2.0"
+
+// ExportXLIFF writes the translatable text of the document to w as an XLIFF 2.0 file.
+//
+// The main document, every header and footer, and the footnotes and endnotes are exported
+// as separate <file> elements, and every paragraph holding text (including paragraphs in
+// table cells) becomes a <unit> with a single segment.
+//
+// Text is segmented by formatting rather than by run: adjacent runs that look the same are
+// merged whatever their revision IDs. Text formatted differently from the rest of the paragraph
+// and hyperlinks become <pc> codes, while tabs, breaks, pictures, fields and note references
+// become <ph> placeholders.
+func (rd *RootDoc) ExportXLIFF(w io.Writer, srcLang string) error {
+	if srcLang == "" {
+		return errors.New("xliff: source language is empty")
+	}
+
+	files, err := rd.xliffFiles()
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(constants.XMLHeader); err != nil {
+		return err
+	}
+
+	x := &xliffWriter{e: xml.NewEncoder(w)}
+	x.start(0, "xliff",
+		"xmlns", xliffNamespace,
+		"version", "2.0",
+		"srcLang", srcLang)
+
+	for i, f := range files {
+		x.start(1, "file",
+			"id", "f"+strconv.Itoa(i+1),
+			"original", f.original,
+			"xml:space", "preserve")
+
+		for j, p := range f.paras {
+			unit := newXLIFFUnit(rd, p)
+			if !xliffHasText(unit.content) {
+				continue
+			}
+
+			x.start(2, "unit", "id", xliffUnitID(j))
+			x.start(3, "segment")
+			x.start(4, "source")
+			x.inlines(unit, unit.content)
+			x.end("source")
+			x.endLine(3, "segment")
+			x.endLine(2, "unit")
+		}
+
+		x.endLine(1, "file")
+	}
+
+	x.endLine(0, "xliff")
+	x.text("\n")
+
+	if x.err != nil {
+		return x.err
+	}
+	return x.e.Flush()
+}
+
+// ImportXLIFF merges the translations of an XLIFF 2.0 file created by ExportXLIFF back into the document.
+//
+// Units are matched to paragraphs by file and unit ID, so the document must have the same structure
+// as when it was exported. The codes in each translation are mapped back to the formatting, hyperlinks
+// and placeholders of the original paragraph. Units without a target are left untouched, and
+// placeholders that were dropped from a translation are kept at the end of the paragraph.
+func (rd *RootDoc) ImportXLIFF(r io.Reader) error {
+	var doc xliffDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return fmt.Errorf("xliff: %w", err)
+	}
+
+	if doc.Version != "" && !strings.HasPrefix(doc.Version, "2.") {
+		return fmt.Errorf("xliff: unsupported version %q", doc.Version)
+	}
+
+	files, err := rd.xliffFiles()
+	if err != nil {
+		return err
+	}
+
+	byPath := make(map[string]*xliffPart, len(files))
+	for i := range files {
+		byPath[files[i].original] = &files[i]
+	}
+
+	for _, f := range doc.Files {
+		part, ok := byPath[f.Original]
+		if !ok {
+			return fmt.Errorf("xliff: file %q is not part of the document", f.Original)
+		}
+
+		for _, u := range f.Units {
+			content, translated := u.content()
+			if !translated {
+				continue
+			}
+
+			p := part.unit(u.ID)
+			if p == nil {
+				return fmt.Errorf("xliff: file %q has no unit %q", f.Original, u.ID)
+			}
+
+			children, err := newXLIFFUnit(rd, p).rebuild(content)
+			if err != nil {
+				return fmt.Errorf("xliff: unit %q of %q: %w", u.ID, f.Original, err)
+			}
+			p.Children = children
+		}
+	}
+
+	return nil
+}
+
+// xliffPart is a package part whose paragraphs are exported as an XLIFF file.
+type xliffPart struct {
+	original string
+	paras    []*ctypes.Paragraph
+}
+
+func xliffUnitID(i int) string {
+	return "p" + strconv.Itoa(i+1)
+}
+
+// unit returns the paragraph for the given unit ID, or nil if there is none.
+func (part *xliffPart) unit(id string) *ctypes.Paragraph {
+	if !strings.HasPrefix(id, "p") {
+		return nil
+	}
+
+	n, err := strconv.Atoi(id[1:])
+	if err != nil || n < 1 || n > len(part.paras) {
+		return nil
+	}
+	return part.paras[n-1]
+}
+
+// xliffFiles returns the parts to translate and their paragraphs in document order.
+func (rd *RootDoc) xliffFiles() ([]xliffPart, error) {
+	var files []xliffPart
+
+	if rd.Document != nil && rd.Document.Body != nil {
+		files = append(files, xliffPart{
+			original: rd.Document.relativePath,
+			paras:    blockParagraphs(rd.Document.Body.Children),
+		})
+	}
+
+	stories, err := rd.Stories()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range stories {
+		if len(files) == 0 || files[len(files)-1].original != s.Path {
+			files = append(files, xliffPart{original: s.Path})
+		}
+		last := &files[len(files)-1]
+		last.paras = append(last.paras, blockParagraphs(s.Children)...)
+	}
+
+	return files, nil
+}
+
+// blockParagraphs returns the paragraphs of the blocks, descending into tables and content
+// controls.
+func blockParagraphs(blocks []DocumentChild) []*ctypes.Paragraph {
+	var paras []*ctypes.Paragraph
+	for _, child := range blocks {
+		if child.Para != nil {
+			paras = append(paras, child.Para.ct)
+		}
+
+		if child.Table != nil {
+			paras = tableParagraphs(paras, child.Table.ct)
+		}
+
+		if child.Control != nil {
+			paras = contentParagraphs(paras, child.Control.ct.Contents)
+		}
+	}
+	return paras
+}
+
+func tableParagraphs(paras []*ctypes.Paragraph, tbl *ctypes.Table) []*ctypes.Paragraph {
+	for _, rc := range tbl.RowContents {
+		if rc.Row == nil {
+			continue
+		}
+
+		for _, cc := range rc.Row.Contents {
+			if cc.Cell == nil {
+				continue
+			}
+
+			paras = contentParagraphs(paras, cc.Cell.Contents)
+		}
+	}
+	return paras
+}
+
+func contentParagraphs(paras []*ctypes.Paragraph, contents []ctypes.TCBlockContent) []*ctypes.Paragraph {
+	for _, content := range contents {
+		if content.Paragraph != nil {
+			paras = append(paras, content.Paragraph)
+		}
+
+		if content.Table != nil {
+			paras = tableParagraphs(paras, content.Table)
+		}
+
+		if content.SDT != nil {
+			paras = contentParagraphs(paras, content.SDT.Contents)
+		}
+	}
+	return paras
+}
+
+// xliffCodeKind tells what an inline code of a unit stands for.
+type xliffCodeKind int
+
+const (
+	xliffFormat      xliffCodeKind = iota // text formatted differently from its surroundings
+	xliffLink                             // a hyperlink
+	xliffPlaceholder                      // content that is not translated
+)
+
+// xliffCode is an inline code of a unit and the original content it maps back to.
+type xliffCode struct {
+	kind     xliffCodeKind
+	props    *ctypes.RunProperty     // run formatting of the text inside a format or link code
+	link     *ctypes.Hyperlink       // hyperlink of a link code
+	children []ctypes.ParagraphChild // content of a placeholder
+
+	typ, subType, disp, equiv string
+}
+
+// xliffInline is a piece of unit content: either text or a code with its nested content.
+type xliffInline struct {
+	text     string
+	code     string
+	children []xliffInline
+}
+
+// xliffUnit is the inline content of a paragraph as exported to XLIFF.
+type xliffUnit struct {
+	root    *RootDoc
+	codes   map[string]*xliffCode
+	ids     []string
+	props   *ctypes.RunProperty // formatting of text outside of any code
+	content []xliffInline
+}
+
+// newXLIFFUnit splits the content of a paragraph into text and inline codes.
+// The analysis is deterministic, so the codes of an exported unit can be recomputed on import.
+func newXLIFFUnit(root *RootDoc, p *ctypes.Paragraph) *xliffUnit {
+	u := &xliffUnit{root: root, codes: make(map[string]*xliffCode)}
+	u.props, u.content = u.analyse(p.Children)
+	return u
+}
+
+func (u *xliffUnit) add(code *xliffCode) string {
+	id := strconv.Itoa(len(u.ids) + 1)
+	u.codes[id] = code
+	u.ids = append(u.ids, id)
+	return id
+}
+
+// xliffPiece is a run of text with its formatting, a placeholder, or a hyperlink.
+type xliffPiece struct {
+	text  string
+	props *ctypes.RunProperty
+	key   string
+
+	placeholder *xliffCode
+	link        *ctypes.Hyperlink
+}
+
+// analyse returns the formatting of the plain text of the children and their inline content.
+func (u *xliffUnit) analyse(children []ctypes.ParagraphChild) (*ctypes.RunProperty, []xliffInline) {
+	pieces := xliffPieces(children)
+
+	// The formatting carrying most of the text is the base; everything else is a format code.
+	var baseKey string
+	var baseProps *ctypes.RunProperty
+	lengths := make(map[string]int)
+	best := -1
+	for _, pc := range pieces {
+		if pc.placeholder == nil && pc.link == nil {
+			lengths[pc.key] += len(pc.text)
+		}
+	}
+	for _, pc := range pieces {
+		if pc.placeholder == nil && pc.link == nil && lengths[pc.key] > best {
+			best = lengths[pc.key]
+			baseKey, baseProps = pc.key, pc.props
+		}
+	}
+
+	var content []xliffInline
+	addText := func(text string) {
+		if n := len(content); n > 0 && content[n-1].code == "" {
+			content[n-1].text += text
+			return
+		}
+		content = append(content, xliffInline{text: text})
+	}
+
+	for i := 0; i < len(pieces); {
+		pc := pieces[i]
+		switch {
+		case pc.link != nil:
+			props, inner := u.analyse(hyperlinkChildren(pc.link))
+			id := u.add(&xliffCode{
+				kind:  xliffLink,
+				props: props,
+				link:  pc.link,
+				typ:   "link",
+				disp:  u.linkTarget(pc.link),
+			})
+			content = append(content, xliffInline{code: id, children: inner})
+			i++
+		case pc.placeholder != nil:
+			content = append(content, xliffInline{code: u.add(pc.placeholder)})
+			i++
+		default:
+			var sb strings.Builder
+			j := i
+			for ; j < len(pieces) && pieces[j].placeholder == nil && pieces[j].link == nil && pieces[j].key == pc.key; j++ {
+				sb.WriteString(pieces[j].text)
+			}
+			i = j
+
+			if pc.key == baseKey {
+				addText(sb.String())
+				continue
+			}
+
+			id := u.add(&xliffCode{kind: xliffFormat, props: pc.props, typ: "fmt", disp: describeRunProp(pc.props)})
+			content = append(content, xliffInline{code: id, children: []xliffInline{{text: sb.String()}}})
+		}
+	}
+
+	return baseProps, content
+}
+
+// xliffHasText reports whether unit content holds any text other than white space.
+func xliffHasText(content []xliffInline) bool {
+	for _, in := range content {
+		if strings.TrimSpace(in.text) != "" || xliffHasText(in.children) {
+			return true
+		}
+	}
+	return false
+}
+
+// linkTarget returns the URL or bookmark a hyperlink points to.
+func (u *xliffUnit) linkTarget(link *ctypes.Hyperlink) string {
+	if link.ID != "" && u.root != nil && u.root.Document != nil {
+		if rel := u.root.Document.relationByID(link.ID); rel != nil {
+			return rel.Target
+		}
+	}
+
+	if link.Anchor != "" {
+		return "#" + link.Anchor
+	}
+	return ""
+}
+
+// hyperlinkChildren returns all runs of a hyperlink as paragraph children.
+func hyperlinkChildren(link *ctypes.Hyperlink) []ctypes.ParagraphChild {
+	if link.Run == nil {
+		return link.Children
+	}
+	return append([]ctypes.ParagraphChild{{Run: link.Run}}, link.Children...)
+}
+
+// xliffPieces splits paragraph children into text, placeholder and hyperlink pieces.
+// Complex fields are kept together as a single placeholder, from their begin to their end character.
+func xliffPieces(children []ctypes.ParagraphChild) []xliffPiece {
+	var pieces []xliffPiece
+
+	for i := 0; i < len(children); i++ {
+		child := children[i]
+		switch {
+		case child.Link != nil:
+			pieces = append(pieces, xliffPiece{link: child.Link})
+		case child.Run != nil && beginsField(child.Run):
+			end, instr, _ := fieldAt(children, i)
+			pieces = append(pieces, xliffPiece{placeholder: &xliffCode{
+				kind:     xliffPlaceholder,
+				children: children[i : end+1],
+				typ:      "other",
+				disp:     strings.TrimSpace(instr),
+			}})
+			i = end
+		case child.Run != nil:
+			pieces = append(pieces, runPieces(child.Run)...)
+		default:
+			pieces = append(pieces, xliffPiece{placeholder: &xliffCode{
+				kind:     xliffPlaceholder,
+				children: []ctypes.ParagraphChild{child},
+				typ:      "other",
+			}})
+		}
+	}
+
+	return pieces
+}
+
+// runPieces splits a run into text pieces and a placeholder for each non-text child.
+func runPieces(r *ctypes.Run) []xliffPiece {
+	key := runPropKey(r.Property)
+
+	var pieces []xliffPiece
+	for _, rc := range r.Children {
+		switch {
+		case rc.Text != nil:
+			if rc.Text.Text != "" {
+				pieces = append(pieces, xliffPiece{text: rc.Text.Text, props: r.Property, key: key})
+			}
+		case rc.LastRenPgBrk != nil:
+			// Only a layout hint, recomputed by the consuming application
+		default:
+			code := &xliffCode{
+				kind: xliffPlaceholder,
+				children: []ctypes.ParagraphChild{{
+					Run: &ctypes.Run{Property: r.Property, Children: []ctypes.RunChild{rc}},
+				}},
+				typ: "other",
+			}
+			describeRunChild(code, rc)
+			pieces = append(pieces, xliffPiece{placeholder: code})
+		}
+	}
+	return pieces
+}
+
+// describeRunChild fills in the XLIFF type and display hints of a run child placeholder.
+func describeRunChild(code *xliffCode, rc ctypes.RunChild) {
+	switch {
+	case rc.Tab != nil, rc.PTab != nil:
+		code.typ, code.disp, code.equiv = "fmt", "tab", "\t"
+	case rc.CarrRtn != nil:
+		code.typ, code.subType, code.equiv = "fmt", "xlf:lb", "\n"
+	case rc.Break != nil:
+		switch {
+		case rc.Break.BreakType == nil || *rc.Break.BreakType == stypes.BreakTypeTextWrapping:
+			code.typ, code.subType, code.equiv = "fmt", "xlf:lb", "\n"
+		case *rc.Break.BreakType == stypes.BreakTypePage:
+			code.typ, code.subType = "fmt", "xlf:pb"
+		default:
+			code.typ, code.disp = "fmt", string(*rc.Break.BreakType)+" break"
+		}
+	case rc.Drawing != nil:
+		code.typ, code.disp = "image", "image"
+		if images := drawingImages(rc.Drawing); len(images) > 0 && images[0].Description != "" {
+			code.disp = images[0].Description
+		}
+	case rc.Pict != nil:
+		code.typ, code.disp = "image", "image"
+	case rc.FootnoteReference != nil:
+		code.disp = "footnote " + strconv.Itoa(rc.FootnoteReference.ID)
+	case rc.EndnoteReference != nil:
+		code.disp = "endnote " + strconv.Itoa(rc.EndnoteReference.ID)
+	case rc.NoBreakHyphen != nil:
+		code.equiv = "\u2011"
+	case rc.SoftHyphen != nil:
+		code.equiv = "\u00ad"
+	}
+}
+
+// runPropKey returns a key that is equal for run properties that format text the same way.
+func runPropKey(rp *ctypes.RunProperty) string {
+	if rp == nil {
+		return ""
+	}
+
+	out, err := xml.Marshal(rp)
+	if err != nil || string(out) == "<w:rPr></w:rPr>" {
+		return ""
+	}
+	return string(out)
+}
+
+// describeRunProp returns a short description of run formatting for display in translation tools.
+func describeRunProp(rp *ctypes.RunProperty) string {
+	if rp == nil {
+		return "plain"
+	}
+
+	var parts []string
+	if rp.Style != nil {
+		parts = append(parts, rp.Style.Val)
+	}
+
+	flags := []struct {
+		name string
+		on   *ctypes.OnOff
+	}{
+		{"bold", rp.Bold},
+		{"italic", rp.Italic},
+		{"strike", rp.Strike},
+		{"caps", rp.Caps},
+		{"small caps", rp.SmallCaps},
+	}
+	for _, f := range flags {
+		if isOn(f.on) {
+			parts = append(parts, f.name)
+		}
+	}
+
+	if rp.Underline != nil && rp.Underline.Val != stypes.UnderlineNone {
+		parts = append(parts, "underline")
+	}
+
+	if rp.VertAlign != nil && rp.VertAlign.Val != stypes.VerticalAlignRunBaseline {
+		parts = append(parts, string(rp.VertAlign.Val))
+	}
+
+	if len(parts) == 0 {
+		return "format"
+	}
+	return strings.Join(parts, " ")
+}
+
+// rebuild returns new paragraph children for translated unit content.
+func (u *xliffUnit) rebuild(tokens []xliffToken) ([]ctypes.ParagraphChild, error) {
+	type frame struct {
+		code     *xliffCode
+		props    *ctypes.RunProperty
+		children *[]ctypes.ParagraphChild
+		link     *ctypes.Hyperlink
+		last     *ctypes.Run
+	}
+
+	var result []ctypes.ParagraphChild
+	stack := []*frame{{props: u.props, children: &result}}
+	used := make(map[string]bool)
+
+	for _, tok := range tokens {
+		top := stack[len(stack)-1]
+
+		switch tok.kind {
+		case xliffTokenText:
+			if top.last != nil {
+				txt := top.last.Children[0].Text
+				*txt = *ctypes.TextFromString(txt.Text + tok.text)
+				continue
+			}
+
+			top.last = &ctypes.Run{
+				Property: copyRunProp(top.props),
+				Children: []ctypes.RunChild{{Text: ctypes.TextFromString(tok.text)}},
+			}
+			*top.children = append(*top.children, ctypes.ParagraphChild{Run: top.last})
+		case xliffTokenPlaceholder:
+			code, ok := u.codes[tok.id]
+			if !ok || code.kind != xliffPlaceholder {
+				return nil, fmt.Errorf("unknown placeholder %q", tok.id)
+			}
+
+			if used[tok.id] {
+				return nil, fmt.Errorf("placeholder %q is used more than once", tok.id)
+			}
+			used[tok.id] = true
+
+			*top.children = append(*top.children, code.children...)
+			top.last = nil
+		case xliffTokenStart:
+			code, ok := u.codes[tok.id]
+			if !ok || code.kind == xliffPlaceholder {
+				return nil, fmt.Errorf("unknown code %q", tok.id)
+			}
+
+			top.last = nil
+			f := &frame{code: code, props: code.props, children: top.children}
+			if code.kind == xliffLink {
+				f.link = &ctypes.Hyperlink{ID: code.link.ID, Anchor: code.link.Anchor}
+				f.children = &f.link.Children
+				*top.children = append(*top.children, ctypes.ParagraphChild{Link: f.link})
+			}
+			stack = append(stack, f)
+		case xliffTokenEnd:
+			if len(stack) == 1 {
+				return nil, errors.New("unbalanced closing code")
+			}
+
+			if top.link != nil && len(top.link.Children) > 0 && top.link.Children[0].Run != nil {
+				top.link.Run = top.link.Children[0].Run
+				top.link.Children = top.link.Children[1:]
+			}
+
+			stack = stack[:len(stack)-1]
+			stack[len(stack)-1].last = nil
+		}
+	}
+
+	if len(stack) != 1 {
+		return nil, errors.New("unclosed code")
+	}
+
+	for _, id := range u.ids {
+		if code := u.codes[id]; code.kind == xliffPlaceholder && !used[id] {
+			result = append(result, code.children...)
+		}
+	}
+
+	return result, nil
+}
+
+// copyRunProp returns a shallow copy of run properties so that rebuilt runs do not share them.
+func copyRunProp(rp *ctypes.RunProperty) *ctypes.RunProperty {
+	if rp == nil {
+		return nil
+	}
+	c := *rp
+	return &c
+}
+
+// xliffWriter writes XLIFF markup, keeping the first error.
+type xliffWriter struct {
+	e   *xml.Encoder
+	err error
+}
+
+func (x *xliffWriter) token(t xml.Token) {
+	if x.err == nil {
+		x.err = x.e.EncodeToken(t)
+	}
+}
+
+func (x *xliffWriter) text(s string) {
+	x.token(xml.CharData(s))
+}
+
+// start writes an indented start tag with the given attribute name and value pairs.
+func (x *xliffWriter) start(depth int, name string, attrs ...string) {
+	if depth > 0 {
+		x.text("\n" + strings.Repeat("  ", depth))
+	}
+	x.token(xliffStart(name, attrs...))
+}
+
+func (x *xliffWriter) end(name string) {
+	x.token(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+// endLine writes an end tag on its own indented line.
+func (x *xliffWriter) endLine(depth int, name string) {
+	x.text("\n" + strings.Repeat("  ", depth))
+	x.end(name)
+}
+
+// inlines writes unit content, turning codes into <pc> and <ph> elements.
+func (x *xliffWriter) inlines(u *xliffUnit, content []xliffInline) {
+	for _, in := range content {
+		if in.code == "" {
+			x.text(in.text)
+			continue
+		}
+
+		code := u.codes[in.code]
+		if code.kind == xliffPlaceholder {
+			x.token(xliffStart("ph", "id", in.code, "type", code.typ, "subType", code.subType, "disp", code.disp, "equiv", code.equiv))
+			x.end("ph")
+			continue
+		}
+
+		x.token(xliffStart("pc", "id", in.code, "type", code.typ, "dispStart", code.disp))
+		x.inlines(u, in.children)
+		x.end("pc")
+	}
+}
+
+// xliffStart returns a start element with the non-empty attributes of the name and value pairs.
+func xliffStart(name string, attrs ...string) xml.StartElement {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if attrs[i+1] != "" {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: attrs[i]}, Value: attrs[i+1]})
+		}
+	}
+	return start
+}
+
+// xliffDocument is the part of the XLIFF 2.0 structure read by ImportXLIFF.
+type xliffDocument struct {
+	XMLName xml.Name        `xml:"xliff"`
+	Version string          `xml:"version,attr"`
+	Files   []xliffFileElem `xml:"file"`
+}
+
+type xliffFileElem struct {
+	ID       string          `xml:"id,attr"`
+	Original string          `xml:"original,attr"`
+	Units    []xliffUnitElem `xml:"unit"`
+}
+
+// xliffUnitElem holds the segments of a unit, including any <ignorable> parts, in document order.
+type xliffUnitElem struct {
+	ID    string
+	parts []xliffSegment
+}
+
+type xliffSegment struct {
+	source, target []xliffToken
+	hasTarget      bool
+}
+
+// content returns the translated content of the unit, joining its segments.
+// Segments without a target contribute their source; the result is false if no segment was translated.
+func (u xliffUnitElem) content() ([]xliffToken, bool) {
+	var tokens []xliffToken
+	translated := false
+	for _, seg := range u.parts {
+		if seg.hasTarget {
+			translated = true
+			tokens = append(tokens, seg.target...)
+		} else {
+			tokens = append(tokens, seg.source...)
+		}
+	}
+	return tokens, translated
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface for the xliffUnitElem type.
+func (u *xliffUnitElem) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "id" {
+			u.ID = attr.Value
+		}
+	}
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			if elem.Name.Local != "segment" && elem.Name.Local != "ignorable" {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+
+			seg, err := decodeXLIFFSegment(d)
+			if err != nil {
+				return err
+			}
+			u.parts = append(u.parts, seg)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func decodeXLIFFSegment(d *xml.Decoder) (xliffSegment, error) {
+	var seg xliffSegment
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return seg, err
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			switch elem.Name.Local {
+			case "source":
+				if seg.source, err = decodeXLIFFContent(d); err != nil {
+					return seg, err
+				}
+			case "target":
+				if seg.target, err = decodeXLIFFContent(d); err != nil {
+					return seg, err
+				}
+				seg.hasTarget = true
+			default:
+				if err := d.Skip(); err != nil {
+					return seg, err
+				}
+			}
+		case xml.EndElement:
+			return seg, nil
+		}
+	}
+}
+
+// xliffTokenKind is the kind of a piece of inline content read from XLIFF.
+type xliffTokenKind int
+
+const (
+	xliffTokenText xliffTokenKind = iota
+	xliffTokenStart
+	xliffTokenEnd
+	xliffTokenPlaceholder
+)
+
+type xliffToken struct {
+	kind xliffTokenKind
+	text string
+	id   string
+}
+
+// decodeXLIFFContent reads the inline content of a <source> or <target> element.
+// Paired codes may be written as <pc> or as <sc>/<ec>; annotations (<mrk>, <sm>/<em>) are dropped
+// but their content is kept.
+func decodeXLIFFContent(d *xml.Decoder) ([]xliffToken, error) {
+	var tokens []xliffToken
+	depth := 0
+	var pcs []bool // for each open element, whether it is a <pc>
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch elem := token.(type) {
+		case xml.CharData:
+			tokens = append(tokens, xliffToken{kind: xliffTokenText, text: string(elem)})
+		case xml.StartElement:
+			depth++
+			id := xliffAttr(elem, "id")
+			pcs = append(pcs, elem.Name.Local == "pc")
+
+			switch elem.Name.Local {
+			case "pc", "sc":
+				tokens = append(tokens, xliffToken{kind: xliffTokenStart, id: id})
+			case "ec":
+				tokens = append(tokens, xliffToken{kind: xliffTokenEnd})
+			case "ph":
+				tokens = append(tokens, xliffToken{kind: xliffTokenPlaceholder, id: id})
+			case "cp":
+				r, err := strconv.ParseUint(xliffAttr(elem, "hex"), 16, 32)
+				if err != nil {
+					return nil, fmt.Errorf("invalid code point: %w", err)
+				}
+				tokens = append(tokens, xliffToken{kind: xliffTokenText, text: string(rune(r))})
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				return tokens, nil
+			}
+			depth--
+
+			if pcs[len(pcs)-1] {
+				tokens = append(tokens, xliffToken{kind: xliffTokenEnd})
+			}
+			pcs = pcs[:len(pcs)-1]
+		}
+	}
+}
+
+func xliffAttr(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name && attr.Name.Space == "" {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,189 @@
+package docx
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupXLIFFDoc(t *testing.T) *RootDoc {
+	rd := setupStoryDoc(t)
+
+	// Same formatting, split into runs by revision IDs
+	p := rd.AddParagraph("Press ")
+	p.ct.Children[0].Run.RsidR = internal.ToPtr(stypes.LongHexNum("00A1"))
+	p.AddText("the ").ct.RsidR = internal.ToPtr(stypes.LongHexNum("00B2"))
+	p.AddText("power").Bold(true)
+	p.AddText(" button.")
+	tab := p.AddRun()
+	tab.ct.Children = append(tab.ct.Children, ctypes.RunChild{Tab: &ctypes.Empty{}})
+	p.AddLink("Details", "https://example.com/power")
+
+	page := rd.AddParagraph("Page ")
+	page.ct.Children = append(page.ct.Children,
+		ctypes.ParagraphChild{Run: &ctypes.Run{Children: []ctypes.RunChild{{FldChar: ctypes.NewFldChar(stypes.FldCharTypeBegin)}}}},
+		ctypes.ParagraphChild{Run: &ctypes.Run{Children: []ctypes.RunChild{{InstrText: ctypes.TextFromString(" PAGE ")}}}},
+		ctypes.ParagraphChild{Run: &ctypes.Run{Children: []ctypes.RunChild{{FldChar: ctypes.NewFldChar(stypes.FldCharTypeSeparate)}}}},
+		ctypes.ParagraphChild{Run: &ctypes.Run{Children: []ctypes.RunChild{{Text: ctypes.TextFromString("1")}}}},
+		ctypes.ParagraphChild{Run: &ctypes.Run{Children: []ctypes.RunChild{{FldChar: ctypes.NewFldChar(stypes.FldCharTypeEnd)}}}},
+	)
+
+	rd.AddEmptyParagraph()
+
+	tbl := rd.AddTable()
+	tbl.AddRow().AddCell().AddParagraph("Voltage")
+
+	return rd
+}
+
+func TestExportXLIFF(t *testing.T) {
+	rd := setupXLIFFDoc(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, rd.ExportXLIFF(&buf, "en"))
+	out := buf.String()
+
+	assert.Contains(t, out, `<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en">`)
+	assert.Contains(t, out, `<file id="f1" original="word/document.xml" xml:space="preserve">`)
+	assert.Contains(t, out, `<unit id="p1">`)
+	assert.Contains(t, out, `<source>Press the <pc id="1" type="fmt" dispStart="bold">power</pc> button.`+
+		`<ph id="2" type="fmt" disp="tab" equiv="&#x9;"></ph>`+
+		`<pc id="3" type="link" dispStart="https://example.com/power">Details</pc></source>`)
+	assert.Contains(t, out, `<source>Page <ph id="1" type="other" disp="PAGE"></ph></source>`)
+	assert.NotContains(t, out, `<unit id="p3">`)
+	assert.Contains(t, out, `<unit id="p4">`)
+	assert.Contains(t, out, `<source>Voltage</source>`)
+
+	assert.Contains(t, out, `<file id="f2" original="word/header1.xml" xml:space="preserve">`)
+	assert.Contains(t, out, `<source>Product manual</source>`)
+	assert.Contains(t, out, `<file id="f3" original="word/footnotes.xml" xml:space="preserve">`)
+	assert.Contains(t, out, `<source><ph id="1" type="other"></ph> See appendix.</source>`)
+}
+
+// translateXLIFF adds a target to every segment, translating its source with the replacer.
+func translateXLIFF(xliff string, r *strings.Replacer) string {
+	re := regexp.MustCompile(`<source>(.*?)</source>`)
+	return re.ReplaceAllStringFunc(xliff, func(src string) string {
+		inner := re.FindStringSubmatch(src)[1]
+		return src + "<target>" + r.Replace(inner) + "</target>"
+	})
+}
+
+func TestImportXLIFF(t *testing.T) {
+	rd := setupXLIFFDoc(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, rd.ExportXLIFF(&buf, "en"))
+
+	translated := translateXLIFF(buf.String(), strings.NewReplacer(
+		"Press the ", "Drücken Sie die ",
+		"power</pc> button.", "Ein/Aus</pc>-Taste.",
+		"Details", "Einzelheiten",
+		"Page ", "Seite ",
+		"Voltage", "Spannung",
+		"Product manual", "Produkthandbuch",
+		" See appendix.", " Siehe Anhang.",
+	))
+	require.NoError(t, rd.ImportXLIFF(strings.NewReader(translated)))
+
+	body := rd.Document.Body.Children
+	p := body[0].Para.ct
+	assert.Equal(t, "Drücken Sie die Ein/Aus-Taste.\tEinzelheiten", paragraphText(p))
+
+	require.Len(t, p.Children, 5)
+	assert.Nil(t, p.Children[0].Run.RsidR)
+	assert.Nil(t, p.Children[0].Run.Property)
+	assert.Equal(t, "Ein/Aus", runText(p.Children[1].Run))
+	assert.True(t, isOn(p.Children[1].Run.Property.Bold))
+	assert.Equal(t, "-Taste.", runText(p.Children[2].Run))
+	assert.Nil(t, p.Children[2].Run.Property)
+
+	link := p.Children[4].Link
+	require.NotNil(t, link)
+	assert.Equal(t, "https://example.com/power", rd.Document.relationByID(link.ID).Target)
+	assert.Equal(t, "Einzelheiten", runText(link.Run))
+	assert.Equal(t, "Hyperlink", link.Run.Property.Style.Val)
+
+	page := body[1].Para.ct
+	assert.Equal(t, "Seite 1", paragraphText(page))
+	assert.Len(t, page.Children, 6)
+
+	cell := body[3].Table.ct.RowContents[0].Row.Contents[0].Cell.Contents[0].Paragraph
+	assert.Equal(t, "Spannung", paragraphText(cell))
+
+	stories, err := rd.Stories()
+	require.NoError(t, err)
+	assert.Equal(t, "Produkthandbuch", paragraphText(stories[0].Children[0].Para.ct))
+
+	note := stories[1].Children[0].Para.ct
+	assert.Equal(t, " Siehe Anhang.", paragraphText(note))
+	require.NotNil(t, note.Children[0].Run)
+	assert.NotNil(t, note.Children[0].Run.Children[0].FootnoteRef)
+}
+
+func TestImportXLIFF_KeepsDroppedPlaceholders(t *testing.T) {
+	rd := setupXLIFFDoc(t)
+
+	translated := `<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en" trgLang="fr">
+  <file id="f1" original="word/document.xml">
+    <unit id="p2">
+      <segment><source>Page </source><target>Page </target></segment>
+    </unit>
+    <unit id="p4">
+      <segment><source>Voltage</source></segment>
+    </unit>
+  </file>
+</xliff>`
+	require.NoError(t, rd.ImportXLIFF(strings.NewReader(translated)))
+
+	body := rd.Document.Body.Children
+	assert.Equal(t, "Page 1", paragraphText(body[1].Para.ct))
+
+	cell := body[3].Table.ct.RowContents[0].Row.Contents[0].Cell.Contents[0].Paragraph
+	assert.Equal(t, "Voltage", paragraphText(cell))
+}
+
+func TestImportXLIFF_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		units string
+		err   string
+	}{
+		{
+			name:  "unknown unit",
+			units: `<unit id="p99"><segment><source>x</source><target>y</target></segment></unit>`,
+			err:   `has no unit "p99"`,
+		},
+		{
+			name:  "unknown code",
+			units: `<unit id="p1"><segment><source>x</source><target><pc id="9">y</pc></target></segment></unit>`,
+			err:   `unknown code "9"`,
+		},
+		{
+			name:  "duplicate placeholder",
+			units: `<unit id="p1"><segment><source>x</source><target><ph id="2"/><ph id="2"/></target></segment></unit>`,
+			err:   `placeholder "2" is used more than once`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd := setupXLIFFDoc(t)
+
+			input := `<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en">` +
+				`<file id="f1" original="word/document.xml">` + tt.units + `</file></xliff>`
+
+			err := rd.ImportXLIFF(strings.NewReader(input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
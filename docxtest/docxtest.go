@@ -0,0 +1,195 @@
+// Package docxtest provides assertions for tests of code that generates documents.
+//
+// The assertions look at the document tree rather than at the XML it is written as, so that
+// tests do not break when the serialization changes:
+//
+//	rd := report.Build(data)
+//	docxtest.AssertHasHeading(t, rd, 1, "Summary")
+//	docxtest.AssertParagraphText(t, rd, 2, "Revenue grew by 12%.")
+//	docxtest.AssertRunFormatting(t, rd, "12%", docxtest.Format{Bold: docxtest.On})
+//	docxtest.AssertTableCells(t, rd.Find().Tables().First(), [][]string{
+//		{"Region", "Revenue"},
+//		{"North", "1.2M"},
+//	})
+//	docxtest.AssertNoDanglingRelationships(t, rd)
+//
+// AssertGolden compares a readable outline of the whole document, as returned by Dump, with a
+// golden file. Running the tests with the -docxtest.update flag writes the golden files
+// instead:
+//
+//	go test ./report -docxtest.update
+//
+// Assertions report failures with t.Errorf, so that a test goes on after one fails, and
+// return whether they passed.
+package docxtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// AssertParagraphText checks the text of a paragraph at the top level of the body, idx
+// counting the paragraphs of the body from 0 and leaving tables out.
+func AssertParagraphText(t testing.TB, rd *docx.RootDoc, idx int, want string) bool {
+	t.Helper()
+
+	var paras []*docx.Paragraph
+	for _, child := range rd.Document.Body.Children {
+		if child.Para != nil {
+			paras = append(paras, child.Para)
+		}
+	}
+	if idx < 0 || idx >= len(paras) {
+		t.Errorf("paragraph %d: the body has %d paragraphs", idx, len(paras))
+		return false
+	}
+	if got := paras[idx].Text(); got != want {
+		t.Errorf("paragraph %d: text is %q, want %q", idx, got, want)
+		return false
+	}
+	return true
+}
+
+// AssertHasHeading checks that the document has a heading of the given level whose text is
+// text, leading and trailing spaces aside. The Title style is level 0.
+func AssertHasHeading(t testing.TB, rd *docx.RootDoc, level uint, text string) bool {
+	t.Helper()
+
+	var found []string
+	for _, p := range rd.Find().Paragraphs().Headings().All() {
+		l, _ := p.HeadingLevel()
+		got := strings.TrimSpace(p.Text())
+		if l == level && got == text {
+			return true
+		}
+		found = append(found, fmt.Sprintf("%d %q", l, got))
+	}
+	t.Errorf("no heading of level %d with text %q; headings are [%s]", level, text, strings.Join(found, ", "))
+	return false
+}
+
+// AssertTableCells checks the text of the cells of a table, row by row. The text of a cell is
+// the text of its paragraphs separated by newlines.
+func AssertTableCells(t testing.TB, tbl *docx.Table, want [][]string) bool {
+	t.Helper()
+
+	if tbl == nil {
+		t.Errorf("table is nil")
+		return false
+	}
+
+	var got [][]string
+	for _, row := range tbl.Rows() {
+		var cells []string
+		for _, cell := range row.Cells() {
+			var lines []string
+			for _, p := range cell.Paragraphs() {
+				lines = append(lines, p.Text())
+			}
+			cells = append(cells, strings.Join(lines, "\n"))
+		}
+		got = append(got, cells)
+	}
+
+	ok := len(got) == len(want)
+	for i := 0; ok && i < len(got); i++ {
+		ok = len(got[i]) == len(want[i])
+		for j := 0; ok && j < len(got[i]); j++ {
+			ok = got[i][j] == want[i][j]
+		}
+	}
+	if !ok {
+		t.Errorf("table cells are\n%s\nwant\n%s", formatCells(got), formatCells(want))
+	}
+	return ok
+}
+
+func formatCells(rows [][]string) string {
+	var sb strings.Builder
+	for _, row := range rows {
+		quoted := make([]string, len(row))
+		for i, cell := range row {
+			quoted[i] = fmt.Sprintf("%q", cell)
+		}
+		sb.WriteString("\t[" + strings.Join(quoted, ", ") + "]\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// On and Off are the values of the on/off fields of Format.
+var (
+	On  = boolPtr(true)
+	Off = boolPtr(false)
+)
+
+func boolPtr(v bool) *bool { return &v }
+
+// Format is the direct formatting expected of a run by AssertRunFormatting. Fields left nil,
+// empty or zero are not checked; Off checks that an on/off property is not turned on.
+type Format struct {
+	Style     string // Style is the ID of the character style.
+	Bold      *bool
+	Italic    *bool
+	Strike    *bool
+	Underline stypes.Underline
+	Color     string  // Color is a hex code such as "FF0000".
+	Highlight string  // Highlight is a highlight color such as "yellow".
+	Font      string  // Font is the name of the font.
+	Size      float64 // Size is the font size in points.
+}
+
+// AssertRunFormatting checks the direct formatting of the first run, in document order, whose
+// text contains text. Formatting inherited from styles is not taken into account.
+func AssertRunFormatting(t testing.TB, rd *docx.RootDoc, text string, want Format) bool {
+	t.Helper()
+
+	r := rd.Find().Runs().Containing(text).First()
+	if r == nil {
+		t.Errorf("no run contains %q", text)
+		return false
+	}
+
+	var diffs []string
+	check := func(name string, got, want interface{}) {
+		if got != want {
+			diffs = append(diffs, fmt.Sprintf("%s is %v, want %v", name, got, want))
+		}
+	}
+	if want.Style != "" {
+		check("style", r.StyleID(), want.Style)
+	}
+	if want.Bold != nil {
+		check("bold", r.IsBold(), *want.Bold)
+	}
+	if want.Italic != nil {
+		check("italic", r.IsItalic(), *want.Italic)
+	}
+	if want.Strike != nil {
+		check("strike", r.IsStrike(), *want.Strike)
+	}
+	if want.Underline != "" {
+		check("underline", r.GetUnderline(), want.Underline)
+	}
+	if want.Color != "" {
+		check("color", strings.ToUpper(r.GetColor()), strings.ToUpper(want.Color))
+	}
+	if want.Highlight != "" {
+		check("highlight", r.GetHighlight(), want.Highlight)
+	}
+	if want.Font != "" {
+		check("font", r.FontName(), want.Font)
+	}
+	if want.Size != 0 {
+		check("size", r.FontSize(), want.Size)
+	}
+
+	if len(diffs) > 0 {
+		t.Errorf("run %q: %s", r.Text(), strings.Join(diffs, "; "))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package docxtest
+
+import (
+	"fmt"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// recorder records the failures reported by an assertion.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func buildReport(t *testing.T) *docx.RootDoc {
+	rd, err := godocx.NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rd.AddHeading("Quarterly report", 1); err != nil {
+		t.Fatal(err)
+	}
+	p := rd.AddParagraph("Revenue grew by ")
+	p.AddText("12%").Bold(true).Color("FF0000")
+	p.AddText(" compared to ")
+	p.AddLink("last year", "https://example.com/2023")
+	p.AddText(".")
+
+	tbl := rd.AddTable()
+	for _, cells := range [][]string{{"Region", "Revenue"}, {"North", "1.2M"}} {
+		row := tbl.AddRow()
+		for _, text := range cells {
+			row.AddCell().AddParagraph(text)
+		}
+	}
+	if _, err := rd.AddPicture("../godocx.png", units.Inch(1), units.Inch(0.5)); err != nil {
+		t.Fatal(err)
+	}
+	return rd
+}
+
+func TestAssertions(t *testing.T) {
+	rd := buildReport(t)
+	tbl := rd.Find().Tables().First()
+
+	AssertParagraphText(t, rd, 1, "Revenue grew by 12% compared to last year.")
+	AssertHasHeading(t, rd, 1, "Quarterly report")
+	AssertTableCells(t, tbl, [][]string{{"Region", "Revenue"}, {"North", "1.2M"}})
+	AssertRunFormatting(t, rd, "12%", Format{Bold: On, Italic: Off, Color: "ff0000"})
+	AssertNoDanglingRelationships(t, rd)
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB) bool
+		want   string
+	}{
+		{"paragraph text", func(t testing.TB) bool { return AssertParagraphText(t, rd, 0, "Report") },
+			`paragraph 0: text is "Quarterly report", want "Report"`},
+		{"paragraph index", func(t testing.TB) bool { return AssertParagraphText(t, rd, 5, "") },
+			"paragraph 5: the body has 3 paragraphs"},
+		{"heading level", func(t testing.TB) bool { return AssertHasHeading(t, rd, 2, "Quarterly report") },
+			`no heading of level 2 with text "Quarterly report"; headings are [1 "Quarterly report"]`},
+		{"table cells", func(t testing.TB) bool { return AssertTableCells(t, tbl, [][]string{{"Region", "Revenue"}}) },
+			"table cells are\n\t[\"Region\", \"Revenue\"]\n\t[\"North\", \"1.2M\"]\nwant\n\t[\"Region\", \"Revenue\"]"},
+		{"run formatting", func(t testing.TB) bool {
+			return AssertRunFormatting(t, rd, "compared", Format{Bold: On, Underline: stypes.UnderlineSingle})
+		}, `run " compared to ": bold is false, want true; underline is , want single`},
+		{"missing run", func(t testing.TB) bool { return AssertRunFormatting(t, rd, "missing", Format{}) },
+			`no run contains "missing"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			if tt.assert(r) {
+				t.Fatal("assertion passed")
+			}
+			if len(r.errors) != 1 || r.errors[0] != tt.want {
+				t.Errorf("failures are %q, want %q", r.errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssertNoDanglingRelationships(t *testing.T) {
+	rd := buildReport(t)
+	rd.Document.DocRels.Relationships = rd.Document.DocRels.Relationships[:len(rd.Document.DocRels.Relationships)-1]
+
+	r := &recorder{TB: t}
+	if AssertNoDanglingRelationships(r, rd) {
+		t.Fatal("assertion passed")
+	}
+	want := "dangling relationships:\n\tword/document.xml: relationship rId10 is not defined"
+	if len(r.errors) != 1 || r.errors[0] != want {
+		t.Errorf("failures are %q, want %q", r.errors, want)
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	rd := buildReport(t)
+	AssertGolden(t, rd, "testdata/report.golden")
+
+	rd.Find().Paragraphs().Containing("Revenue").First().AddText(" More.")
+	r := &recorder{TB: t}
+	if !*update && AssertGolden(r, rd, "testdata/report.golden") {
+		t.Error("assertion passed for a changed document")
+	}
+}
@@ -0,0 +1,259 @@
+package docxtest
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gomutex/godocx/docx"
+)
+
+var update = flag.Bool("docxtest.update", false, "write the golden files of docxtest.AssertGolden instead of comparing with them")
+
+// AssertGolden compares the outline of the document returned by Dump with the golden file at
+// path. With the -docxtest.update flag, the golden file is written instead, along with the
+// directories holding it.
+func AssertGolden(t testing.TB, rd *docx.RootDoc, path string) bool {
+	t.Helper()
+
+	got, err := Dump(rd)
+	if err != nil {
+		t.Errorf("dumping the document: %v", err)
+		return false
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("writing golden file: %v", err)
+			return false
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Errorf("writing golden file: %v", err)
+			return false
+		}
+		return true
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("reading golden file: %v; run the test with -docxtest.update to create it", err)
+		return false
+	}
+	want := strings.ReplaceAll(string(content), "\r\n", "\n")
+	if got == want {
+		return true
+	}
+
+	gotLines, wantLines := strings.Split(got, "\n"), strings.Split(want, "\n")
+	line := 0
+	for line < len(gotLines) && line < len(wantLines) && gotLines[line] == wantLines[line] {
+		line++
+	}
+	at := func(lines []string) string {
+		if line < len(lines) {
+			return strconv.Quote(lines[line])
+		}
+		return "end of file"
+	}
+	t.Errorf("document differs from golden file %s at line %d:\n\tgot:  %s\n\twant: %s\nrun the test with -docxtest.update to accept the changes",
+		path, line+1, at(gotLines), at(wantLines))
+	return false
+}
+
+// Dump returns a readable outline of the content of the document body, one element per line
+// and indented by nesting, for comparison with golden files:
+//
+//	section page=12240x15840
+//	  paragraph style=Heading1
+//	    text "Summary"
+//	  paragraph
+//	    text "Revenue grew by "
+//	    text "12%" bold
+//	  table
+//	    row
+//	      cell
+//	        paragraph
+//	          text "Region"
+//
+// Paragraphs and runs list their direct formatting, and pictures their size in EMUs and
+// their alternative text. Styles, headers, footers and notes are left out, as is how the
+// document is written in XML.
+func Dump(rd *docx.RootDoc) (string, error) {
+	data, err := json.Marshal(rd)
+	if err != nil {
+		return "", err
+	}
+	var doc docx.JSONDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", err
+	}
+
+	d := &dumper{}
+	for _, section := range doc.Sections {
+		var attrs []string
+		if page := section.Page; page != nil {
+			if page.Width != 0 || page.Height != 0 {
+				attrs = append(attrs, fmt.Sprintf("page=%dx%d", page.Width, page.Height))
+			}
+			attrs = appendAttr(attrs, "orientation", page.Orientation)
+			attrs = appendAttr(attrs, "break", page.Break)
+		}
+		d.line(0, "section", attrs...)
+		d.blocks(1, section.Blocks)
+	}
+	return d.sb.String(), nil
+}
+
+type dumper struct {
+	sb strings.Builder
+}
+
+func (d *dumper) line(depth int, kind string, attrs ...string) {
+	d.sb.WriteString(strings.Repeat("  ", depth))
+	d.sb.WriteString(kind)
+	for _, a := range attrs {
+		d.sb.WriteString(" " + a)
+	}
+	d.sb.WriteString("\n")
+}
+
+func (d *dumper) blocks(depth int, blocks []docx.JSONBlock) {
+	for _, b := range blocks {
+		if b.Type == docx.JSONTable {
+			attrs := appendAttr(nil, "style", b.Style)
+			d.line(depth, "table", attrs...)
+			for _, row := range b.Rows {
+				var rowAttrs []string
+				if row.Header {
+					rowAttrs = append(rowAttrs, "header")
+				}
+				d.line(depth+1, "row", rowAttrs...)
+				for _, cell := range row.Cells {
+					var cellAttrs []string
+					if cell.ColSpan > 1 {
+						cellAttrs = append(cellAttrs, fmt.Sprintf("colSpan=%d", cell.ColSpan))
+					}
+					cellAttrs = appendAttr(cellAttrs, "vMerge", cell.VMerge)
+					cellAttrs = appendAttr(cellAttrs, "fill", cell.Fill)
+					cellAttrs = appendAttr(cellAttrs, "vAlign", cell.VAlign)
+					d.line(depth+2, "cell", cellAttrs...)
+					d.blocks(depth+3, cell.Blocks)
+				}
+			}
+			continue
+		}
+
+		attrs := appendAttr(nil, "style", b.Style)
+		if n := b.Numbering; n != nil {
+			attrs = append(attrs, fmt.Sprintf("list=%d:%d", n.ID, n.Level))
+		}
+		if f := b.Format; f != nil {
+			attrs = appendAttr(attrs, "align", f.Align)
+			if s := f.Spacing; s != nil {
+				attrs = appendUint(attrs, "before", s.Before)
+				attrs = appendUint(attrs, "after", s.After)
+			}
+			if in := f.Indent; in != nil {
+				attrs = appendInt(attrs, "left", in.Left)
+				attrs = appendInt(attrs, "right", in.Right)
+				attrs = appendUint(attrs, "firstLine", in.FirstLine)
+				attrs = appendUint(attrs, "hanging", in.Hanging)
+			}
+			attrs = appendBool(attrs, "keepNext", f.KeepNext)
+			attrs = appendBool(attrs, "pageBreakBefore", f.PageBreakBefore)
+		}
+		d.line(depth, "paragraph", attrs...)
+		d.inlines(depth+1, b.Inlines)
+	}
+}
+
+func (d *dumper) inlines(depth int, inlines []docx.JSONInline) {
+	for _, in := range inlines {
+		var attrs []string
+		switch in.Type {
+		case docx.JSONText:
+			attrs = append(attrs, strconv.Quote(in.Text))
+		case docx.JSONBreak:
+			attrs = appendAttr(attrs, "type", in.Break)
+		case docx.JSONLink:
+			attrs = appendAttr(attrs, "url", in.URL)
+			attrs = appendAttr(attrs, "anchor", in.Anchor)
+			if len(in.Inlines) == 0 {
+				attrs = append(attrs, strconv.Quote(in.Text))
+			}
+		case docx.JSONField:
+			attrs = append(attrs, strconv.Quote(strings.TrimSpace(in.Instr)), strconv.Quote(in.Text))
+		case docx.JSONImage:
+			if img := in.Image; img != nil {
+				attrs = append(attrs, fmt.Sprintf("size=%dx%d", img.Width, img.Height))
+				if img.Alt != "" {
+					attrs = append(attrs, "alt="+strconv.Quote(img.Alt))
+				}
+			}
+		}
+		d.line(depth, in.Type, append(attrs, formatAttrs(in.Format)...)...)
+		d.inlines(depth+1, in.Inlines)
+	}
+}
+
+// formatAttrs returns the attributes listing character formatting.
+func formatAttrs(f *docx.JSONRunFormat) []string {
+	if f == nil {
+		return nil
+	}
+	attrs := appendAttr(nil, "style", f.Style)
+	attrs = appendBool(attrs, "bold", f.Bold)
+	attrs = appendBool(attrs, "italic", f.Italic)
+	attrs = appendBool(attrs, "strike", f.Strike)
+	attrs = appendBool(attrs, "caps", f.Caps)
+	attrs = appendBool(attrs, "smallCaps", f.SmallCaps)
+	attrs = appendAttr(attrs, "underline", f.Underline)
+	attrs = appendAttr(attrs, "color", f.Color)
+	attrs = appendAttr(attrs, "highlight", f.Highlight)
+	attrs = appendAttr(attrs, "font", f.Font)
+	if f.Size != 0 {
+		attrs = append(attrs, "size="+strconv.FormatFloat(f.Size, 'f', -1, 64))
+	}
+	return appendAttr(attrs, "vertAlign", f.VertAlign)
+}
+
+func appendAttr(attrs []string, name, value string) []string {
+	if value == "" {
+		return attrs
+	}
+	if strings.ContainsAny(value, " \"=") {
+		value = strconv.Quote(value)
+	}
+	return append(attrs, name+"="+value)
+}
+
+// appendBool lists an on/off property by its name when it is on.
+func appendBool(attrs []string, name string, value *bool) []string {
+	switch {
+	case value == nil:
+		return attrs
+	case *value:
+		return append(attrs, name)
+	default:
+		return append(attrs, name+"=false")
+	}
+}
+
+func appendInt(attrs []string, name string, value *int) []string {
+	if value == nil {
+		return attrs
+	}
+	return append(attrs, fmt.Sprintf("%s=%d", name, *value))
+}
+
+func appendUint(attrs []string, name string, value *uint64) []string {
+	if value == nil {
+		return attrs
+	}
+	return append(attrs, fmt.Sprintf("%s=%d", name, *value))
+}
@@ -0,0 +1,144 @@
+package docxtest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gomutex/godocx/docx"
+)
+
+// relationshipsNS are the namespaces of the attributes holding relationship IDs.
+var relationshipsNS = map[string]bool{
+	"http://schemas.openxmlformats.org/officeDocument/2006/relationships": true,
+	"http://purl.oclc.org/ooxml/officeDocument/relationships":             true,
+}
+
+// AssertNoDanglingRelationships checks the package the document is written as: every
+// relationship ID used by a part must be defined in the relationships of the part, and every
+// relationship to a part of the package must point at a part that exists.
+func AssertNoDanglingRelationships(t testing.TB, rd *docx.RootDoc) bool {
+	t.Helper()
+
+	problems, err := danglingRelationships(rd)
+	if err != nil {
+		t.Errorf("checking relationships: %v", err)
+		return false
+	}
+	if len(problems) > 0 {
+		t.Errorf("dangling relationships:\n\t%s", strings.Join(problems, "\n\t"))
+		return false
+	}
+	return true
+}
+
+// danglingRelationships returns the problems with the relationships of the package of rd.
+func danglingRelationships(rd *docx.RootDoc) ([]string, error) {
+	var buf bytes.Buffer
+	if err := rd.Write(&buf); err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[f.Name] = content
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		if strings.HasSuffix(name, ".rels") {
+			continue
+		}
+
+		ids := make(map[string]bool)
+		relsPath := path.Join(path.Dir(name), "_rels", path.Base(name)+".rels")
+		if content, ok := files[relsPath]; ok {
+			var rels docx.Relationships
+			if err := xml.Unmarshal(content, &rels); err != nil {
+				return nil, fmt.Errorf("%s: %w", relsPath, err)
+			}
+			for _, rel := range rels.Relationships {
+				ids[rel.ID] = true
+				if rel.TargetMode == "External" {
+					continue
+				}
+				target := strings.TrimPrefix(rel.Target, "/")
+				if !strings.HasPrefix(rel.Target, "/") {
+					target = path.Join(path.Dir(name), rel.Target)
+				}
+				if _, ok := files[target]; !ok {
+					problems = append(problems, fmt.Sprintf("%s: relationship %s points at missing part %s", relsPath, rel.ID, target))
+				}
+			}
+		}
+
+		if !strings.HasSuffix(name, ".xml") {
+			continue
+		}
+		used, err := usedRelationships(files[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for _, id := range used {
+			if !ids[id] {
+				problems = append(problems, fmt.Sprintf("%s: relationship %s is not defined", name, id))
+			}
+		}
+	}
+	return problems, nil
+}
+
+// usedRelationships returns the relationship IDs used by the attributes of an XML part, in
+// the order of their first use.
+func usedRelationships(content []byte) ([]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+
+	d := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return ids, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, a := range start.Attr {
+			if relationshipsNS[a.Name.Space] && a.Value != "" && !seen[a.Value] {
+				seen[a.Value] = true
+				ids = append(ids, a.Value)
+			}
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package template
+
+import (
+	"errors"
+
+	"github.com/gomutex/godocx/wml/ctypes"
+)
+
+// content is a paragraph, a table or a content control of a document body, story, table cell
+// or content control.
+type content struct {
+	para  *ctypes.Paragraph
+	table *ctypes.Table
+	sdt   *ctypes.SDT
+}
+
+// block is a compiled paragraph, table or content control.
+type block struct {
+	para    *paraTemplate
+	table   *tableTemplate
+	control *controlTemplate
+}
+
+// paraTemplate is a paragraph split into items, with its inline if and range tags nested.
+type paraTemplate struct {
+	ct    *ctypes.Paragraph // ct is the paragraph without its children.
+	items []item
+	nodes []node[*item]
+}
+
+// tableTemplate is a table whose rows may be repeated or left out by tags in their cells.
+type tableTemplate struct {
+	ct    *ctypes.Table // ct is the table without its rows.
+	nodes []node[*rowTemplate]
+}
+
+type rowTemplate struct {
+	ct    *ctypes.Row // ct is the row without its cells.
+	cells []*cellTemplate
+}
+
+type cellTemplate struct {
+	ct     *ctypes.Cell // ct is the cell without its content.
+	blocks []node[block]
+}
+
+// controlTemplate is a block-level content control. The tags of its content must be matched
+// within the control.
+type controlTemplate struct {
+	ct     *ctypes.SDT // ct is the content control without its content.
+	blocks []node[block]
+}
+
+// compileBlocks compiles a sequence of paragraphs and tables. A control tag that is alone in its
+// paragraph controls the paragraphs and tables up to its end tag; other control tags must be
+// matched within their paragraph.
+//
+// In a table cell, control tags without a partner in the cell control whole rows of the table.
+// They are removed from the cell and returned.
+func compileBlocks(contents []content, inCell bool) ([]node[block], []*tag, error) {
+	blocks := make([]block, 0, len(contents))
+	var tags []*tag
+	for _, ct := range contents {
+		if ct.table != nil {
+			tt, err := compileTable(ct.table)
+			if err != nil {
+				return nil, nil, err
+			}
+			blocks = append(blocks, block{table: tt})
+			continue
+		}
+		if ct.sdt != nil {
+			nodes, _, err := compileBlocks(blockContents(ct.sdt.Contents), false)
+			if err != nil {
+				return nil, nil, err
+			}
+			sdt := *ct.sdt
+			sdt.Contents = nil
+			blocks = append(blocks, block{control: &controlTemplate{ct: &sdt, blocks: nodes}})
+			continue
+		}
+
+		items, err := tokenizeParagraph(ct.para)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, it := range items {
+			if it.tag != nil && it.tag.isControl() {
+				tags = append(tags, it.tag)
+			}
+		}
+
+		p := *ct.para
+		p.Children = nil
+		blocks = append(blocks, block{para: &paraTemplate{ct: &p, items: items}})
+	}
+
+	unmatched := unmatchedTags(tags)
+	if len(unmatched) > 0 {
+		if !inCell {
+			return nil, nil, unmatchedError(unmatched[0])
+		}
+		blocks = removeTags(blocks, unmatched)
+	}
+
+	tokens := make([]token[block], 0, len(blocks))
+	for _, b := range blocks {
+		if b.para != nil {
+			if t := soleTag(b.para.items); t != nil && t.isControl() {
+				tokens = append(tokens, token[block]{tag: t})
+				continue
+			}
+
+			if err := b.para.compile(); err != nil {
+				return nil, nil, err
+			}
+		}
+		tokens = append(tokens, token[block]{leaf: b})
+	}
+
+	nodes, err := buildTree(tokens)
+	return nodes, unmatched, err
+}
+
+// compile nests the inline control tags of the paragraph.
+func (pt *paraTemplate) compile() error {
+	var tags []*tag
+	tokens := make([]token[*item], len(pt.items))
+	for i := range pt.items {
+		it := &pt.items[i]
+		if it.tag != nil && it.tag.isControl() {
+			tags = append(tags, it.tag)
+			tokens[i] = token[*item]{tag: it.tag}
+		} else {
+			tokens[i] = token[*item]{leaf: it}
+		}
+	}
+
+	if unmatched := unmatchedTags(tags); len(unmatched) > 0 {
+		return &Error{Tag: unmatched[0].text, Err: errors.New("must be alone in its paragraph when its partner tags are in other paragraphs")}
+	}
+
+	nodes, err := buildTree(tokens)
+	if err != nil {
+		return err
+	}
+	pt.nodes = nodes
+	return nil
+}
+
+// removeTags removes tags from the paragraphs holding them. Paragraphs left with nothing but
+// white space are dropped.
+func removeTags(blocks []block, tags []*tag) []block {
+	remove := make(map[*tag]bool, len(tags))
+	for _, t := range tags {
+		remove[t] = true
+	}
+
+	kept := blocks[:0]
+	for _, b := range blocks {
+		if b.para == nil {
+			kept = append(kept, b)
+			continue
+		}
+
+		items := b.para.items[:0]
+		removed := false
+		for _, it := range b.para.items {
+			if it.tag != nil && remove[it.tag] {
+				removed = true
+				continue
+			}
+			items = append(items, it)
+		}
+		b.para.items = items
+
+		if removed && isBlank(items) {
+			continue
+		}
+		kept = append(kept, b)
+	}
+	return kept
+}
+
+// isBlank reports whether items hold nothing but white space.
+func isBlank(items []item) bool {
+	for _, it := range items {
+		if !isSpace(it) {
+			return false
+		}
+	}
+	return true
+}
+
+// compileTable compiles the cells of a table and nests the rows between the control tags that
+// are not matched within a single cell. A row is controlled by the if and range tags of its
+// cells and ends the blocks closed by the end tags of its cells.
+func compileTable(t *ctypes.Table) (*tableTemplate, error) {
+	tbl := *t
+	tbl.RowContents = nil
+	tt := &tableTemplate{ct: &tbl}
+
+	var tokens []token[*rowTemplate]
+	for _, rc := range t.RowContents {
+		if rc.Row == nil {
+			continue
+		}
+
+		row := *rc.Row
+		row.Contents = nil
+		rt := &rowTemplate{ct: &row}
+
+		var opening, closing []token[*rowTemplate]
+		for _, cc := range rc.Row.Contents {
+			if cc.Cell == nil {
+				continue
+			}
+
+			nodes, unmatched, err := compileBlocks(blockContents(cc.Cell.Contents), true)
+			if err != nil {
+				return nil, err
+			}
+
+			cell := *cc.Cell
+			cell.Contents = nil
+			rt.cells = append(rt.cells, &cellTemplate{ct: &cell, blocks: nodes})
+
+			for _, t := range unmatched {
+				if t.kind == tagEnd {
+					closing = append(closing, token[*rowTemplate]{tag: t})
+				} else {
+					opening = append(opening, token[*rowTemplate]{tag: t})
+				}
+			}
+		}
+
+		tokens = append(tokens, opening...)
+		tokens = append(tokens, token[*rowTemplate]{leaf: rt})
+		tokens = append(tokens, closing...)
+	}
+
+	nodes, err := buildTree(tokens)
+	if err != nil {
+		return nil, err
+	}
+	tt.nodes = nodes
+	return tt, nil
+}
+
+// blockContents returns the content of a table cell or content control.
+func blockContents(blocks []ctypes.TCBlockContent) []content {
+	contents := make([]content, 0, len(blocks))
+	for _, bc := range blocks {
+		switch {
+		case bc.Paragraph != nil:
+			contents = append(contents, content{para: bc.Paragraph})
+		case bc.Table != nil:
+			contents = append(contents, content{table: bc.Table})
+		case bc.SDT != nil:
+			contents = append(contents, content{sdt: bc.SDT})
+		}
+	}
+	return contents
+}
+
+func unmatchedError(t *tag) *Error {
+	if t.kind == tagElse || t.kind == tagEnd {
+		return &Error{Tag: t.text, Err: errors.New("has no matching {{if}} or {{range}}")}
+	}
+	return &Error{Tag: t.text, Err: errors.New("has no matching {{end}}")}
+}
@@ -0,0 +1,291 @@
+package template
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"reflect"
+
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// renderer renders the compiled content of a single part of the document.
+type renderer struct {
+	rd   *docx.RootDoc
+	body bool // body is set when rendering the document body, the only part that can hold pictures and links.
+}
+
+// renderBlocks renders compiled paragraphs, tables and content controls.
+func (r *renderer) renderBlocks(nodes []node[block], sc scope) ([]content, error) {
+	var out []content
+	err := expand(nodes, sc, func(b block, sc scope) error {
+		if b.control != nil {
+			sdt, err := r.renderControl(b.control, sc)
+			if err != nil {
+				return err
+			}
+			out = append(out, content{sdt: sdt})
+			return nil
+		}
+		if b.table != nil {
+			t, err := r.renderTable(b.table, sc)
+			if err != nil {
+				return err
+			}
+			out = append(out, content{table: t})
+			return nil
+		}
+
+		c, err := r.renderParagraph(b.para, sc)
+		if err != nil {
+			return err
+		}
+		out = append(out, c)
+		return nil
+	})
+	return out, err
+}
+
+// renderParagraph renders a paragraph. A paragraph holding nothing but a tag whose value is a
+// Table is replaced by the table.
+func (r *renderer) renderParagraph(pt *paraTemplate, sc scope) (content, error) {
+	if t := soleTag(pt.items); t != nil {
+		if v, ok := sc.lookup(t.path); ok && v.IsValid() && v.Type() == reflect.TypeOf(Table{}) {
+			tbl, err := r.tableValue(v.Interface().(Table), t)
+			return content{table: tbl}, err
+		}
+	}
+
+	p := pt.ct.Clone()
+	err := expand(pt.nodes, sc, func(it *item, sc scope) error {
+		if it.tag == nil {
+			p.Children = append(p.Children, internal.DeepCopy(it.child))
+			return nil
+		}
+
+		v, ok := sc.lookup(it.tag.path)
+		if !ok {
+			return &Error{Tag: it.tag.text, Err: errors.New("no value for " + it.tag.path)}
+		}
+		children, err := r.value(v, it.props, it.tag)
+		p.Children = append(p.Children, children...)
+		return err
+	})
+	return content{para: p}, err
+}
+
+// renderTable renders a table, repeating or leaving out the rows controlled by tags.
+func (r *renderer) renderTable(tt *tableTemplate, sc scope) (*ctypes.Table, error) {
+	t := tt.ct.Clone()
+	err := expand(tt.nodes, sc, func(rt *rowTemplate, sc scope) error {
+		row := rt.ct.Clone()
+		for _, ct := range rt.cells {
+			cell := internal.DeepCopy(*ct.ct)
+			contents, err := r.renderBlocks(ct.blocks, sc)
+			if err != nil {
+				return err
+			}
+
+			cell.Contents = append(cell.Contents, blockContent(contents)...)
+			// A cell must end with a paragraph.
+			if n := len(cell.Contents); n == 0 || cell.Contents[n-1].Paragraph == nil {
+				cell.Contents = append(cell.Contents, ctypes.TCBlockContent{Paragraph: &ctypes.Paragraph{}})
+			}
+			row.Contents = append(row.Contents, ctypes.TRCellContent{Cell: &cell})
+		}
+		t.RowContents = append(t.RowContents, ctypes.RowContent{Row: row})
+		return nil
+	})
+	return t, err
+}
+
+// renderControl renders a content control.
+func (r *renderer) renderControl(ct *controlTemplate, sc scope) (*ctypes.SDT, error) {
+	sdt := internal.DeepCopy(*ct.ct)
+	contents, err := r.renderBlocks(ct.blocks, sc)
+	if err != nil {
+		return nil, err
+	}
+	sdt.Contents = blockContent(contents)
+	return &sdt, nil
+}
+
+// blockContent converts rendered content to the content of a table cell or content control.
+func blockContent(contents []content) []ctypes.TCBlockContent {
+	blocks := make([]ctypes.TCBlockContent, 0, len(contents))
+	for _, c := range contents {
+		blocks = append(blocks, ctypes.TCBlockContent{Paragraph: c.para, Table: c.table, SDT: c.sdt})
+	}
+	return blocks
+}
+
+// value returns the paragraph content that replaces a value tag.
+func (r *renderer) value(v reflect.Value, props *ctypes.RunProperty, t *tag) ([]ctypes.ParagraphChild, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	switch x := v.Interface().(type) {
+	case Text:
+		return r.richText(RichText{x}, props, t)
+	case RichText:
+		return r.richText(x, props, t)
+	case []Text:
+		return r.richText(x, props, t)
+	case Image:
+		return r.image(x, t)
+	case Table:
+		return nil, &Error{Tag: t.text, Err: errors.New("a table must be alone in its paragraph")}
+	}
+
+	run := &ctypes.Run{Property: internal.DeepCopy(props), Children: textChildren(fmt.Sprint(v.Interface()))}
+	return []ctypes.ParagraphChild{{Run: run}}, nil
+}
+
+// richText returns a run or hyperlink for every piece of formatted text.
+func (r *renderer) richText(texts RichText, props *ctypes.RunProperty, t *tag) ([]ctypes.ParagraphChild, error) {
+	children := make([]ctypes.ParagraphChild, 0, len(texts))
+	for _, text := range texts {
+		run := &ctypes.Run{Property: formatted(props, text), Children: textChildren(text.Text)}
+		if text.Link == "" {
+			children = append(children, ctypes.ParagraphChild{Run: run})
+			continue
+		}
+
+		if !r.body {
+			return nil, &Error{Tag: t.text, Err: errors.New("links can only be used in the document body")}
+		}
+		p := docx.NewParagraph(r.rd)
+		p.AddLink(text.Text, text.Link)
+		link := p.GetCT().Children[0].Link
+		run.Property.Style = link.Run.Property.Style
+		link.Run = run
+		children = append(children, ctypes.ParagraphChild{Link: link})
+	}
+	return children, nil
+}
+
+// formatted returns a copy of props with the formatting of text applied.
+func formatted(props *ctypes.RunProperty, text Text) *ctypes.RunProperty {
+	p := internal.DeepCopy(props)
+	if p == nil {
+		p = &ctypes.RunProperty{}
+	}
+
+	if text.Bold {
+		p.Bold = ctypes.OnOffFromBool(true)
+	}
+	if text.Italic {
+		p.Italic = ctypes.OnOffFromBool(true)
+	}
+	if text.Underline {
+		p.Underline = ctypes.NewGenSingleStrVal(stypes.UnderlineSingle)
+	}
+	if text.Strike {
+		p.Strike = ctypes.OnOffFromBool(true)
+	}
+	if text.Color != "" {
+		p.Color = ctypes.NewColor(text.Color)
+	}
+	if text.Highlight != "" {
+		p.Highlight = ctypes.NewCTString(text.Highlight)
+	}
+	if text.Size > 0 {
+		p.Size = ctypes.NewFontSize(uint64(text.Size) * 2)
+	}
+	if text.Font != "" {
+		p.Fonts = &ctypes.RunFonts{Ascii: text.Font, HAnsi: text.Font}
+	}
+	return p
+}
+
+// image returns the run holding a picture.
+func (r *renderer) image(img Image, t *tag) ([]ctypes.ParagraphChild, error) {
+	if !r.body {
+		return nil, &Error{Tag: t.text, Err: errors.New("images can only be used in the document body")}
+	}
+
+	width, height, err := img.size()
+	if err != nil {
+		return nil, &Error{Tag: t.text, Err: err}
+	}
+
+	ext := img.Ext
+	if ext == "" {
+		if _, ext, err = image.DecodeConfig(bytes.NewReader(img.Data)); err != nil {
+			return nil, &Error{Tag: t.text, Err: fmt.Errorf("reading image format: %w", err)}
+		}
+	}
+
+	p := docx.NewParagraph(r.rd)
+	pic, err := p.AddPictureFromBytes(img.Data, ext, width, height)
+	if err != nil {
+		return nil, &Error{Tag: t.text, Err: err}
+	}
+	pic.Inline.DocProp.Description = img.Alt
+	return p.GetCT().Children, nil
+}
+
+// tableValue builds the table that replaces a tag.
+func (r *renderer) tableValue(tv Table, t *tag) (*ctypes.Table, error) {
+	tbl := ctypes.DefaultTable()
+	style := tv.Style
+	if style == "" {
+		style = "TableGrid"
+	}
+	tbl.TableProp.Style = ctypes.NewCTString(style)
+	for _, w := range tv.Widths {
+		w := w
+		tbl.Grid.Col = append(tbl.Grid.Col, ctypes.Column{Width: &w})
+	}
+
+	for i, cells := range tv.Rows {
+		row := ctypes.DefaultRow()
+		if tv.Header && i == 0 {
+			row.Property.Header = &ctypes.OnOff{}
+		}
+
+		for _, value := range cells {
+			children, err := r.value(indirect(reflect.ValueOf(value)), nil, t)
+			if err != nil {
+				return nil, err
+			}
+
+			cell := ctypes.DefaultCell()
+			cell.Contents = []ctypes.TCBlockContent{{Paragraph: &ctypes.Paragraph{Children: children}}}
+			row.Contents = append(row.Contents, ctypes.TRCellContent{Cell: cell})
+		}
+		tbl.RowContents = append(tbl.RowContents, ctypes.RowContent{Row: row})
+	}
+	return tbl, nil
+}
+
+// textChildren converts text to run content, turning tabs and line breaks into their elements.
+func textChildren(text string) []ctypes.RunChild {
+	var children []ctypes.RunChild
+	start := 0
+	for i, c := range text {
+		if c != '\t' && c != '\n' {
+			continue
+		}
+
+		if i > start {
+			children = append(children, ctypes.RunChild{Text: ctypes.TextFromString(text[start:i])})
+		}
+		if c == '\t' {
+			children = append(children, ctypes.RunChild{Tab: &ctypes.Empty{}})
+		} else {
+			children = append(children, ctypes.RunChild{Break: &ctypes.Break{}})
+		}
+		start = i + 1
+	}
+
+	if start < len(text) || len(children) == 0 {
+		children = append(children, ctypes.RunChild{Text: ctypes.TextFromString(text[start:])})
+	}
+	return children
+}
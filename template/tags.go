@@ -0,0 +1,218 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/ctypes"
+)
+
+// barrier stands in for run content other than text while tags are searched for, so that a tag
+// cannot span a tab, break or drawing.
+const barrier = "\x00"
+
+var (
+	tagRe  = regexp.MustCompile(`\{\{([^{}\x00]*)\}\}`)
+	pathRe = regexp.MustCompile(`^(\$|\.|\$?(\.[\pL\pN_]+)+)$`)
+)
+
+type tagKind int
+
+const (
+	tagValue tagKind = iota
+	tagIf
+	tagRange
+	tagElse
+	tagEnd
+)
+
+// tag is a parsed template tag.
+type tag struct {
+	kind tagKind
+	path string // path is the data the tag refers to: ".", ".A.B", "$" or "$.A".
+	not  bool   // not negates the condition of {{if not .X}}.
+	text string // text is the tag as written in the document.
+}
+
+// isControl reports whether the tag is an if, range, else or end tag.
+func (t *tag) isControl() bool {
+	return t.kind != tagValue
+}
+
+// parseTag parses the text of a tag, braces included.
+func parseTag(text string) (*tag, error) {
+	t := &tag{text: text}
+	action := strings.Fields(text[2 : len(text)-2])
+	if len(action) == 0 {
+		return nil, &Error{Tag: text, Err: errors.New("empty tag")}
+	}
+
+	args := action[1:]
+	switch action[0] {
+	case "end":
+		t.kind = tagEnd
+	case "else":
+		t.kind = tagElse
+	case "if":
+		t.kind = tagIf
+		if len(args) == 2 && args[0] == "not" {
+			t.not, args = true, args[1:]
+		}
+	case "range":
+		t.kind = tagRange
+	default:
+		t.kind = tagValue
+		args = action
+	}
+
+	switch {
+	case t.kind == tagEnd || t.kind == tagElse:
+		if len(args) != 0 {
+			return nil, &Error{Tag: text, Err: fmt.Errorf("{{%s}} takes no arguments", action[0])}
+		}
+	case len(args) != 1:
+		return nil, &Error{Tag: text, Err: errors.New("expected a single field such as .Name")}
+	case !pathRe.MatchString(args[0]):
+		return nil, &Error{Tag: text, Err: fmt.Errorf("invalid field %q", args[0])}
+	default:
+		t.path = args[0]
+	}
+	return t, nil
+}
+
+// item is a piece of paragraph content: a paragraph child free of tags, or a tag.
+type item struct {
+	child ctypes.ParagraphChild
+	tag   *tag
+	props *ctypes.RunProperty // props is the formatting of the run the tag starts in.
+}
+
+// tokenizeParagraph splits the children of a paragraph into items so that every tag becomes an
+// item of its own, even when Word has spread the tag over several runs. Text around the tags
+// keeps the formatting of the run it was in.
+func tokenizeParagraph(p *ctypes.Paragraph) ([]item, error) {
+	var sb strings.Builder
+	for _, child := range p.Children {
+		if child.Run == nil {
+			sb.WriteString(barrier)
+			continue
+		}
+		for _, rc := range child.Run.Children {
+			if rc.Text != nil {
+				sb.WriteString(rc.Text.Text)
+			} else {
+				sb.WriteString(barrier)
+			}
+		}
+	}
+
+	matches := tagRe.FindAllStringIndex(sb.String(), -1)
+	items := make([]item, 0, len(p.Children))
+	if len(matches) == 0 {
+		for _, child := range p.Children {
+			items = append(items, item{child: child})
+		}
+		return items, nil
+	}
+
+	tags := make([]*tag, len(matches))
+	for i, m := range matches {
+		t, err := parseTag(sb.String()[m[0]:m[1]])
+		if err != nil {
+			return nil, err
+		}
+		tags[i] = t
+	}
+
+	pos, next := 0, 0
+	for _, child := range p.Children {
+		if child.Run == nil {
+			items = append(items, item{child: child})
+			pos++
+			continue
+		}
+
+		run := child.Run
+		piece := pieceOf(run)
+		flush := func() {
+			if len(piece.Children) > 0 {
+				items = append(items, item{child: ctypes.ParagraphChild{Run: piece}})
+				piece = pieceOf(run)
+			}
+		}
+
+		for _, rc := range run.Children {
+			if rc.Text == nil {
+				piece.Children = append(piece.Children, rc)
+				pos++
+				continue
+			}
+
+			var text strings.Builder
+			addText := func() {
+				if text.Len() > 0 {
+					piece.Children = append(piece.Children, ctypes.RunChild{Text: ctypes.TextFromString(text.String())})
+					text.Reset()
+				}
+			}
+
+			for i := 0; i < len(rc.Text.Text); i, pos = i+1, pos+1 {
+				for next < len(matches) && matches[next][1] <= pos {
+					next++
+				}
+				if next == len(matches) || pos < matches[next][0] {
+					text.WriteByte(rc.Text.Text[i])
+					continue
+				}
+				if pos == matches[next][0] {
+					addText()
+					flush()
+					items = append(items, item{tag: tags[next], props: internal.DeepCopy(run.Property)})
+				}
+			}
+			addText()
+		}
+		flush()
+	}
+	return items, nil
+}
+
+// pieceOf returns an empty run with the attributes and formatting of run.
+func pieceOf(run *ctypes.Run) *ctypes.Run {
+	return &ctypes.Run{
+		RsidRPr:  run.RsidRPr,
+		RsidR:    run.RsidR,
+		RsidDel:  run.RsidDel,
+		Property: internal.DeepCopy(run.Property),
+	}
+}
+
+// soleTag returns the tag of a paragraph whose only other content is white space, or nil.
+func soleTag(items []item) *tag {
+	var found *tag
+	for _, it := range items {
+		switch {
+		case it.tag != nil && found == nil:
+			found = it.tag
+		case it.tag != nil || !isSpace(it):
+			return nil
+		}
+	}
+	return found
+}
+
+// isSpace reports whether an item is a run holding nothing but white space.
+func isSpace(it item) bool {
+	if it.tag != nil || it.child.Run == nil {
+		return false
+	}
+	for _, rc := range it.child.Run.Children {
+		if rc.Text == nil || strings.TrimSpace(rc.Text.Text) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func textRun(text string, bold bool) ctypes.ParagraphChild {
+	run := &ctypes.Run{Children: []ctypes.RunChild{{Text: ctypes.TextFromString(text)}}}
+	if bold {
+		run.Property = &ctypes.RunProperty{Bold: ctypes.OnOffFromBool(true)}
+	}
+	return ctypes.ParagraphChild{Run: run}
+}
+
+func TestTokenizeParagraph(t *testing.T) {
+	p := &ctypes.Paragraph{Children: []ctypes.ParagraphChild{
+		textRun("Total: {", true),
+		textRun("{.Sum", false),
+		textRun("}} and {{if .X}}", false),
+		{Run: &ctypes.Run{Children: []ctypes.RunChild{{Tab: &ctypes.Empty{}}}}},
+		textRun("{{end}}", false),
+	}}
+
+	items, err := tokenizeParagraph(p)
+	require.NoError(t, err)
+	require.Len(t, items, 6)
+
+	assert.Equal(t, "Total: ", items[0].child.Run.Children[0].Text.Text)
+	assert.NotNil(t, items[0].child.Run.Property.Bold)
+
+	require.NotNil(t, items[1].tag)
+	assert.Equal(t, tagValue, items[1].tag.kind)
+	assert.Equal(t, ".Sum", items[1].tag.path)
+	assert.Equal(t, "{{.Sum}}", items[1].tag.text)
+	assert.NotNil(t, items[1].props.Bold, "a tag takes the formatting of the run it starts in")
+
+	assert.Equal(t, " and ", items[2].child.Run.Children[0].Text.Text)
+	assert.Equal(t, tagIf, items[3].tag.kind)
+	assert.NotNil(t, items[4].child.Run.Children[0].Tab)
+	assert.Equal(t, tagEnd, items[5].tag.kind)
+}
+
+func TestTokenizeParagraph_Barrier(t *testing.T) {
+	p := &ctypes.Paragraph{Children: []ctypes.ParagraphChild{
+		{Run: &ctypes.Run{Children: []ctypes.RunChild{
+			{Text: ctypes.TextFromString("{{.A")},
+			{Break: &ctypes.Break{}},
+			{Text: ctypes.TextFromString("}}")},
+		}}},
+	}}
+
+	items, err := tokenizeParagraph(p)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Nil(t, items[0].tag)
+	assert.Same(t, p.Children[0].Run, items[0].child.Run)
+}
+
+func TestParseTag(t *testing.T) {
+	tag, err := parseTag("{{ if not $.Paid }}")
+	require.NoError(t, err)
+	assert.Equal(t, tagIf, tag.kind)
+	assert.True(t, tag.not)
+	assert.Equal(t, "$.Paid", tag.path)
+
+	_, err = parseTag("{{range}}")
+	assert.EqualError(t, err, "template: : {{range}}: expected a single field such as .Name")
+}
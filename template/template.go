@@ -0,0 +1,166 @@
+// Package template fills documents that contain template tags with data.
+//
+// Tags are written in the document text, anywhere in the body, headers, footers and notes:
+//
+//	Dear {{.Customer.Name}},
+//	{{if .Overdue}}Your payment is overdue.{{else}}Thank you for your payment.{{end}}
+//
+// A value tag such as {{.Name}} is replaced by its value and takes the formatting of the text it
+// is written in. Values may also be Text, RichText, Image or Table to insert formatted text,
+// pictures and tables. Fields are looked up in maps with string keys and in exported struct
+// fields; {{.}} is the current element of a range and {{$.Name}} looks a field up in the data
+// passed to Execute.
+//
+// {{if .X}} keeps its content when X is set and not false, zero or empty, and {{if not .X}} when
+// it is not; {{range .Items}} repeats its content for every element of a slice, array or map.
+// Both take an optional {{else}} and end at {{end}}. When these tags are written within a
+// single paragraph they control the text between them. A tag alone in its paragraph controls
+// the paragraphs, list items and tables up to its partner tag, which must also be alone in its
+// paragraph. In a table, tags whose partner is in another cell control whole rows: a range that
+// starts in one cell of a row and ends in another repeats that row, and one that starts in a row
+// and ends in a later one repeats all the rows in between.
+//
+// Tags inside a content control must be matched within the control, which is kept with its
+// properties around the rendered content.
+//
+// Word often spreads text typed at once over several runs, for instance when spell checking or
+// revision tracking marks part of it; tags are found regardless. Text in hyperlinks is not
+// searched for tags.
+package template
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/gomutex/godocx/docx"
+)
+
+// Error is a problem with a template tag.
+type Error struct {
+	Part string // Part is the package path of the part holding the tag, such as "word/document.xml".
+	Tag  string // Tag is the tag as written in the document.
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("template: %s: %s: %v", e.Part, e.Tag, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// documentPart is the package path of the document body.
+const documentPart = "word/document.xml"
+
+// Template is a document whose tags have been parsed.
+type Template struct {
+	rd      *docx.RootDoc
+	body    []node[block]
+	stories []storyTemplate
+}
+
+type storyTemplate struct {
+	story  *docx.Story
+	blocks []node[block]
+}
+
+// Parse parses the tags of a document. It returns an *Error for a malformed tag or one without
+// its partner tag.
+func Parse(rd *docx.RootDoc) (*Template, error) {
+	t := &Template{rd: rd}
+
+	var err error
+	if t.body, _, err = compileBlocks(childContents(rd.Document.Body.Children), false); err != nil {
+		return nil, inPart(documentPart, err)
+	}
+
+	stories, err := rd.Stories()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stories {
+		blocks, _, err := compileBlocks(childContents(s.Children), false)
+		if err != nil {
+			return nil, inPart(s.Path, err)
+		}
+		t.stories = append(t.stories, storyTemplate{story: s, blocks: blocks})
+	}
+	return t, nil
+}
+
+// Execute replaces the content of the document with the template filled with data. The parsed
+// template is kept, so Execute can be called again to fill the document with other data.
+// Pictures and links can only be inserted in the document body.
+func (t *Template) Execute(data any) error {
+	v := reflect.ValueOf(data)
+	sc := scope{dot: v, root: v}
+
+	r := &renderer{rd: t.rd, body: true}
+	body, err := r.renderBlocks(t.body, sc)
+	if err != nil {
+		return inPart(documentPart, err)
+	}
+
+	r.body = false
+	stories := make([][]content, len(t.stories))
+	for i, st := range t.stories {
+		if stories[i], err = r.renderBlocks(st.blocks, sc); err != nil {
+			return inPart(st.story.Path, err)
+		}
+	}
+
+	t.rd.Document.Body.Children = t.children(body)
+	for i, st := range t.stories {
+		st.story.Children = t.children(stories[i])
+	}
+	return nil
+}
+
+// children wraps rendered paragraphs, tables and content controls for the document.
+func (t *Template) children(contents []content) []docx.DocumentChild {
+	children := make([]docx.DocumentChild, 0, len(contents))
+	for _, c := range contents {
+		if c.sdt != nil {
+			ctl := docx.NewContentControl(t.rd)
+			*ctl.GetCT() = *c.sdt
+			children = append(children, docx.DocumentChild{Control: ctl})
+			continue
+		}
+		if c.table != nil {
+			tbl := docx.NewTable(t.rd)
+			*tbl.GetCT() = *c.table
+			children = append(children, docx.DocumentChild{Table: tbl})
+			continue
+		}
+		p := docx.NewParagraph(t.rd)
+		*p.GetCT() = *c.para
+		children = append(children, docx.DocumentChild{Para: p})
+	}
+	return children
+}
+
+func childContents(children []docx.DocumentChild) []content {
+	var contents []content
+	for _, child := range children {
+		switch {
+		case child.Para != nil:
+			contents = append(contents, content{para: child.Para.GetCT()})
+		case child.Table != nil:
+			contents = append(contents, content{table: child.Table.GetCT()})
+		case child.Control != nil:
+			contents = append(contents, content{sdt: child.Control.GetCT()})
+		}
+	}
+	return contents
+}
+
+// inPart records the part an *Error occurred in.
+func inPart(part string, err error) error {
+	var e *Error
+	if errors.As(err, &e) && e.Part == "" {
+		e.Part = part
+	}
+	return err
+}
@@ -0,0 +1,279 @@
+package template_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	godocx "github.com/gomutex/godocx"
+	"github.com/gomutex/godocx/common/units"
+	"github.com/gomutex/godocx/docx"
+	"github.com/gomutex/godocx/template"
+	"github.com/gomutex/godocx/wml/ctypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type invoiceLine struct {
+	Item string
+	Qty  int
+}
+
+func setupTemplateDoc(t *testing.T) *docx.RootDoc {
+	t.Helper()
+
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+
+	// Word splits text into runs at spell checking and revision marks.
+	p := rd.AddParagraph("Dear {{.Cus")
+	p.AddText("tomer.Na").Bold(true)
+	p.AddText("me}},")
+
+	rd.AddParagraph("{{if .Paid}}Thank you.{{else}}Please pay by {{.Due}}.{{end}}")
+
+	rd.AddParagraph("{{range .Notes}}")
+	rd.AddParagraph("Note: {{.}}").Style("ListBullet")
+	rd.AddParagraph("{{end}}")
+
+	tbl := rd.AddTable()
+	header := tbl.AddRow()
+	header.AddCell().AddParagraph("Item")
+	header.AddCell().AddParagraph("Qty")
+	row := tbl.AddRow()
+	row.AddCell().AddParagraph("{{range .Lines}}{{.Item}}")
+	row.AddCell().AddParagraph("{{.Qty}}{{end}}")
+
+	rd.AddParagraph("{{.Summary}}")
+	rd.AddParagraph("Signed: {{.Signature}}")
+	rd.AddParagraph("{{if not .Paid}}")
+	rd.AddParagraph("{{.Footer}}")
+	rd.AddParagraph("{{end}}")
+
+	return rd
+}
+
+func paraText(p *ctypes.Paragraph) string {
+	var sb strings.Builder
+	for _, child := range p.Children {
+		run := child.Run
+		if child.Link != nil {
+			run = child.Link.Run
+		}
+		if run == nil {
+			continue
+		}
+		for _, rc := range run.Children {
+			if rc.Text != nil {
+				sb.WriteString(rc.Text.Text)
+			}
+		}
+	}
+	return sb.String()
+}
+
+func cellText(row *ctypes.Row, i int) string {
+	return paraText(row.Contents[i].Cell.Contents[0].Paragraph)
+}
+
+func TestExecute(t *testing.T) {
+	rd := setupTemplateDoc(t)
+	tmpl, err := template.Parse(rd)
+	require.NoError(t, err)
+
+	png, err := os.ReadFile("../godocx.png")
+	require.NoError(t, err)
+
+	data := map[string]any{
+		"Customer": struct{ Name string }{"Ada"},
+		"Paid":     false,
+		"Due":      "1 May",
+		"Notes":    []string{"first", "second"},
+		"Lines": []invoiceLine{
+			{Item: "Pen", Qty: 2},
+			{Item: "Ink", Qty: 5},
+		},
+		"Summary": template.Table{
+			Rows:   [][]any{{"Total", template.Text{Text: "7", Bold: true}}},
+			Widths: []uint64{2000, 1000},
+		},
+		"Signature": template.Image{Data: png, Width: units.Inch(1), Alt: "Signature"},
+		"Footer":    template.RichText{{Text: "Visit "}, {Text: "our site", Link: "https://example.com"}},
+	}
+	require.NoError(t, tmpl.Execute(data))
+
+	body := rd.Document.Body.Children
+	require.Len(t, body, 8)
+
+	greeting := body[0].Para.GetCT()
+	assert.Equal(t, "Dear Ada,", paraText(greeting))
+	require.Len(t, greeting.Children, 3)
+	assert.Equal(t, "Ada", paraText(&ctypes.Paragraph{Children: greeting.Children[1:2]}))
+	assert.Nil(t, greeting.Children[1].Run.Property, "the value takes the formatting of the run the tag starts in")
+
+	assert.Equal(t, "Please pay by 1 May.", paraText(body[1].Para.GetCT()))
+
+	assert.Equal(t, "Note: first", paraText(body[2].Para.GetCT()))
+	assert.Equal(t, "Note: second", paraText(body[3].Para.GetCT()))
+	assert.Equal(t, "ListBullet", body[3].Para.GetCT().Property.Style.Val)
+
+	rows := body[4].Table.GetCT().RowContents
+	require.Len(t, rows, 3)
+	assert.Equal(t, "Item", cellText(rows[0].Row, 0))
+	assert.Equal(t, "Pen", cellText(rows[1].Row, 0))
+	assert.Equal(t, "2", cellText(rows[1].Row, 1))
+	assert.Equal(t, "Ink", cellText(rows[2].Row, 0))
+	assert.Equal(t, "5", cellText(rows[2].Row, 1))
+
+	summary := body[5].Table
+	require.NotNil(t, summary)
+	assert.Equal(t, "TableGrid", summary.GetCT().TableProp.Style.Val)
+	total := summary.GetCT().RowContents[0].Row
+	assert.Equal(t, "7", cellText(total, 1))
+	assert.NotNil(t, total.Contents[1].Cell.Contents[0].Paragraph.Children[0].Run.Property.Bold)
+
+	signed := body[6].Para.GetCT()
+	assert.Equal(t, "Signed: ", paraText(signed))
+	require.Len(t, signed.Children, 2)
+	drawing := signed.Children[1].Run.Children[0].Drawing
+	require.NotNil(t, drawing)
+	assert.Equal(t, "Signature", drawing.Inline[0].DocProp.Description)
+
+	footer := body[7].Para.GetCT()
+	assert.Equal(t, "Visit our site", paraText(footer))
+	require.NotNil(t, footer.Children[1].Link)
+	assert.Equal(t, "Hyperlink", footer.Children[1].Link.Run.Property.Style.Val)
+
+	// The template is kept and can be filled again.
+	data["Paid"] = true
+	data["Lines"] = nil
+	data["Notes"] = nil
+	require.NoError(t, tmpl.Execute(data))
+	body = rd.Document.Body.Children
+	require.Len(t, body, 5)
+	assert.Equal(t, "Thank you.", paraText(body[1].Para.GetCT()))
+	assert.Len(t, body[2].Table.GetCT().RowContents, 1)
+
+	var buf bytes.Buffer
+	require.NoError(t, rd.Write(&buf))
+}
+
+func TestExecute_ContentControl(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+
+	rd.AddParagraph("Dear {{.Name}},")
+	ctl := docx.NewContentControl(rd)
+	ctl.GetCT().Property = &ctypes.SDTProperty{Tag: "terms"}
+	for _, text := range []string{"{{range .Terms}}", "Term: {{.}}", "{{end}}"} {
+		p := &ctypes.Paragraph{}
+		p.AddText(text)
+		ctl.GetCT().Contents = append(ctl.GetCT().Contents, ctypes.TCBlockContent{Paragraph: p})
+	}
+	require.NoError(t, rd.AddBlocks(docx.DocumentChild{Control: ctl}))
+	rd.AddParagraph("Regards")
+
+	tmpl, err := template.Parse(rd)
+	require.NoError(t, err)
+	assert.Contains(t, tmpl.Variables(), template.Variable{Name: "Terms", Kind: template.List})
+	require.NoError(t, tmpl.Execute(map[string]any{"Name": "Ann", "Terms": []string{"Net 30", "No refunds"}}))
+
+	body := rd.Document.Body.Children
+	require.Len(t, body, 3)
+	assert.Equal(t, "Dear Ann,", paraText(body[0].Para.GetCT()))
+	require.NotNil(t, body[1].Control)
+	assert.Equal(t, "terms", body[1].Control.Tag())
+	paras := body[1].Control.Paragraphs()
+	require.Len(t, paras, 2)
+	assert.Equal(t, "Term: Net 30", paras[0].Text())
+	assert.Equal(t, "Term: No refunds", paras[1].Text())
+	assert.Equal(t, "Regards", paraText(body[2].Para.GetCT()))
+
+	var buf bytes.Buffer
+	require.NoError(t, rd.Write(&buf))
+}
+
+func TestVariables(t *testing.T) {
+	tmpl, err := template.Parse(setupTemplateDoc(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, []template.Variable{
+		{Name: "Customer.Name", Kind: template.Value},
+		{Name: "Paid", Kind: template.Condition},
+		{Name: "Due", Kind: template.Value},
+		{Name: "Notes", Kind: template.List},
+		{Name: "Lines", Kind: template.List, Fields: []template.Variable{
+			{Name: "Item", Kind: template.Value},
+			{Name: "Qty", Kind: template.Value},
+		}},
+		{Name: "Summary", Kind: template.Value},
+		{Name: "Signature", Kind: template.Value},
+		{Name: "Footer", Kind: template.Value},
+	}, tmpl.Variables())
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		paras []string
+		err   string
+	}{
+		{
+			name:  "unclosed",
+			paras: []string{"{{range .Items}}", "text"},
+			err:   "template: word/document.xml: {{range .Items}}: has no matching {{end}}",
+		},
+		{
+			name:  "stray end",
+			paras: []string{"text {{end}}"},
+			err:   "{{end}}: has no matching {{if}} or {{range}}",
+		},
+		{
+			name:  "not alone",
+			paras: []string{"Intro {{if .X}}", "{{end}}"},
+			err:   "{{if .X}}: must be alone in its paragraph",
+		},
+		{
+			name:  "bad field",
+			paras: []string{"{{ Name }}"},
+			err:   `{{ Name }}: invalid field "Name"`,
+		},
+		{
+			name:  "second else",
+			paras: []string{"{{if .X}}a{{else}}b{{else}}c{{end}}"},
+			err:   "{{else}}: second {{else}} for {{if .X}}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd, err := godocx.NewDocument()
+			require.NoError(t, err)
+			for _, text := range tt.paras {
+				rd.AddParagraph(text)
+			}
+
+			_, err = template.Parse(rd)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+
+			var tmplErr *template.Error
+			assert.True(t, errors.As(err, &tmplErr))
+		})
+	}
+}
+
+func TestExecute_MissingValue(t *testing.T) {
+	rd, err := godocx.NewDocument()
+	require.NoError(t, err)
+	rd.AddParagraph("Hello {{.Name}}")
+
+	tmpl, err := template.Parse(rd)
+	require.NoError(t, err)
+
+	err = tmpl.Execute(map[string]any{})
+	require.EqualError(t, err, "template: word/document.xml: {{.Name}}: no value for .Name")
+	assert.Equal(t, "Hello {{.Name}}", paraText(rd.Document.Body.Children[0].Para.GetCT()))
+}
@@ -0,0 +1,235 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// token is an element of a sequence that is nested into a tree: a leaf or a control tag.
+type token[T any] struct {
+	leaf T
+	tag  *tag
+}
+
+// node is a leaf, or an if or range tag with the nodes it controls.
+type node[T any] struct {
+	leaf     T
+	tag      *tag
+	body     []node[T]
+	elseBody []node[T]
+}
+
+// buildTree nests the tokens found between if or range tags and their end tag.
+func buildTree[T any](tokens []token[T]) ([]node[T], error) {
+	nodes, i, err := buildNodes(tokens, 0)
+	if err != nil {
+		return nil, err
+	}
+	if i < len(tokens) {
+		return nil, &Error{Tag: tokens[i].tag.text, Err: errors.New("has no matching {{if}} or {{range}}")}
+	}
+	return nodes, nil
+}
+
+// buildNodes builds nodes from tokens[i:] up to the first else or end tag that is not nested,
+// and returns the index of that tag.
+func buildNodes[T any](tokens []token[T], i int) ([]node[T], int, error) {
+	var nodes []node[T]
+	for i < len(tokens) {
+		tk := tokens[i]
+		if tk.tag == nil {
+			nodes = append(nodes, node[T]{leaf: tk.leaf})
+			i++
+			continue
+		}
+		if tk.tag.kind == tagElse || tk.tag.kind == tagEnd {
+			return nodes, i, nil
+		}
+
+		n := node[T]{tag: tk.tag}
+		body, j, err := buildNodes(tokens, i+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		n.body = body
+
+		if j < len(tokens) && tokens[j].tag.kind == tagElse {
+			if n.elseBody, j, err = buildNodes(tokens, j+1); err != nil {
+				return nil, 0, err
+			}
+			if j < len(tokens) && tokens[j].tag.kind == tagElse {
+				return nil, 0, &Error{Tag: tokens[j].tag.text, Err: errors.New("second {{else}} for " + tk.tag.text)}
+			}
+		}
+		if j == len(tokens) {
+			return nil, 0, &Error{Tag: tk.tag.text, Err: errors.New("has no matching {{end}}")}
+		}
+
+		nodes = append(nodes, n)
+		i = j + 1
+	}
+	return nodes, i, nil
+}
+
+// unmatchedTags returns the control tags that have no partner within the sequence.
+func unmatchedTags(tags []*tag) []*tag {
+	var open []*tag
+	var unmatched []*tag
+	for _, t := range tags {
+		switch t.kind {
+		case tagIf, tagRange:
+			open = append(open, t)
+		case tagElse:
+			if len(open) == 0 {
+				unmatched = append(unmatched, t)
+			}
+		case tagEnd:
+			if len(open) == 0 {
+				unmatched = append(unmatched, t)
+			} else {
+				open = open[:len(open)-1]
+			}
+		}
+	}
+	return append(unmatched, open...)
+}
+
+// scope is the data tags are evaluated against: dot is the data of the innermost range.
+type scope struct {
+	dot, root reflect.Value
+}
+
+// lookup resolves a tag path. The second result is false if the data has no such field;
+// a nil value is returned as the zero Value.
+func (sc scope) lookup(path string) (reflect.Value, bool) {
+	v := sc.dot
+	if strings.HasPrefix(path, "$") {
+		v, path = sc.root, strings.TrimPrefix(path, "$")
+	}
+	if path == "" || path == "." {
+		return indirect(v), true
+	}
+
+	for _, name := range strings.Split(path[1:], ".") {
+		v = indirect(v)
+		switch {
+		case !v.IsValid():
+			return reflect.Value{}, false
+		case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		case v.Kind() == reflect.Struct:
+			f, ok := v.Type().FieldByName(name)
+			if !ok || f.PkgPath != "" {
+				return reflect.Value{}, false
+			}
+			v = v.FieldByIndex(f.Index)
+		default:
+			return reflect.Value{}, false
+		}
+		if !v.IsValid() {
+			return reflect.Value{}, false
+		}
+	}
+	return indirect(v), true
+}
+
+// indirect follows pointers and interfaces, returning the zero Value for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// truth reports whether a value counts as true in an if tag: it is true unless it is missing,
+// nil, false, zero or empty.
+func truth(v reflect.Value) bool {
+	if !v.IsValid() {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() != 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() != 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() != 0
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() > 0
+	}
+	return true
+}
+
+// rangeItems returns the elements of a slice or array, or the values of a map in key order.
+func rangeItems(v reflect.Value) ([]reflect.Value, error) {
+	switch {
+	case !v.IsValid():
+		return nil, nil
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		items := make([]reflect.Value, v.Len())
+		for i := range items {
+			items[i] = v.Index(i)
+		}
+		return items, nil
+	case v.Kind() == reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		items := make([]reflect.Value, len(keys))
+		for i, k := range keys {
+			items[i] = v.MapIndex(k)
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("cannot range over %s", v.Type())
+}
+
+// expand walks the nodes with the data in scope and calls leaf for every leaf that is rendered.
+// The leaves of a range are visited once for each element, with the element as dot.
+func expand[T any](nodes []node[T], sc scope, leaf func(T, scope) error) error {
+	for _, n := range nodes {
+		if n.tag == nil {
+			if err := leaf(n.leaf, sc); err != nil {
+				return err
+			}
+			continue
+		}
+
+		v, _ := sc.lookup(n.tag.path)
+		if n.tag.kind == tagIf {
+			body := n.elseBody
+			if truth(v) != n.tag.not {
+				body = n.body
+			}
+			if err := expand(body, sc, leaf); err != nil {
+				return err
+			}
+			continue
+		}
+
+		items, err := rangeItems(v)
+		if err != nil {
+			return &Error{Tag: n.tag.text, Err: err}
+		}
+		if len(items) == 0 {
+			if err := expand(n.elseBody, sc, leaf); err != nil {
+				return err
+			}
+		}
+		for _, it := range items {
+			if err := expand(n.body, scope{dot: it, root: sc.root}, leaf); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package template
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gomutex/godocx/common/units"
+)
+
+// Text is a piece of formatted text. Its formatting is applied on top of the formatting of the
+// tag it replaces.
+type Text struct {
+	Text      string
+	Bold      bool
+	Italic    bool
+	Underline bool
+	Strike    bool
+	Color     string // Color is a hex RGB color such as "FF0000".
+	Highlight string // Highlight is a highlight color such as "yellow".
+	Size      uint   // Size is the font size in points.
+	Font      string
+	Link      string // Link is a URL the text links to.
+}
+
+// RichText is a sequence of formatted texts.
+type RichText []Text
+
+// Image is a picture placed where the tag is. When only one of Width and Height is given, the
+// other follows from the aspect ratio of the picture; when neither is given, the picture is
+// shown at 96 DPI.
+type Image struct {
+	Data   []byte
+	Ext    string // Ext is the file extension of the format of Data, such as "png".
+	Width  units.Inch
+	Height units.Inch
+	Alt    string // Alt is the alternative text of the picture.
+}
+
+// ImageFromFile reads a picture from a file.
+func ImageFromFile(path string, width, height units.Inch) (Image, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Image{}, err
+	}
+	return Image{
+		Data:   data,
+		Ext:    strings.TrimPrefix(filepath.Ext(path), "."),
+		Width:  width,
+		Height: height,
+	}, nil
+}
+
+// size returns the size of the picture, completing the missing dimensions from the image data.
+func (img Image) size() (units.Inch, units.Inch, error) {
+	if img.Width > 0 && img.Height > 0 {
+		return img.Width, img.Height, nil
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(img.Data))
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading image size: %w", err)
+	}
+	if cfg.Width == 0 || cfg.Height == 0 {
+		return 0, 0, errors.New("image has no size")
+	}
+
+	ratio := float64(cfg.Height) / float64(cfg.Width)
+	switch {
+	case img.Width > 0:
+		return img.Width, units.Inch(float64(img.Width) * ratio), nil
+	case img.Height > 0:
+		return units.Inch(float64(img.Height) / ratio), img.Height, nil
+	}
+	return units.Inch(float64(cfg.Width) / 96), units.Inch(float64(cfg.Height) / 96), nil
+}
+
+// Table is a table that replaces a tag standing alone in its paragraph. Cells hold strings, Text,
+// RichText or Image values; any other value is written as formatted by fmt.Sprint.
+type Table struct {
+	Rows   [][]any
+	Style  string   // Style is the table style, TableGrid by default.
+	Widths []uint64 // Widths are the column widths in twips.
+	Header bool     // Header repeats the first row on every page.
+}
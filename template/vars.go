@@ -0,0 +1,144 @@
+package template
+
+import "strings"
+
+// VariableKind tells how a template uses a variable.
+type VariableKind int
+
+const (
+	// Value is a variable whose value is written into the document.
+	Value VariableKind = iota
+	// Condition is a variable tested by {{if}}.
+	Condition
+	// List is a variable repeated by {{range}}.
+	List
+)
+
+func (k VariableKind) String() string {
+	switch k {
+	case Value:
+		return "value"
+	case Condition:
+		return "condition"
+	case List:
+		return "list"
+	}
+	return "unknown"
+}
+
+// Variable is a field of the data a template expects.
+type Variable struct {
+	Name   string       // Name is the path of the field without the leading dot, such as "Customer.Name".
+	Kind   VariableKind // Kind is List if the field is repeated, and Value if it is both written and tested.
+	Fields []Variable   // Fields are the fields used on every element of a List.
+}
+
+// Variables lists the fields of the data the template expects, in order of first use. Fields
+// used inside a range are listed under the List variable of the range, except for those
+// looked up with $.
+func (t *Template) Variables() []Variable {
+	c := &collector{root: &varSet{}}
+	c.blocks(t.body, c.root)
+	for _, st := range t.stories {
+		c.blocks(st.blocks, c.root)
+	}
+	return c.root.variables()
+}
+
+type varNode struct {
+	name   string
+	kind   VariableKind
+	fields *varSet
+}
+
+type varSet struct {
+	nodes []*varNode
+}
+
+// add records the use of a variable and returns it.
+func (s *varSet) add(name string, kind VariableKind) *varNode {
+	for _, n := range s.nodes {
+		if n.name == name {
+			if kind == List || (kind == Value && n.kind == Condition) {
+				n.kind = kind
+			}
+			return n
+		}
+	}
+
+	n := &varNode{name: name, kind: kind, fields: &varSet{}}
+	s.nodes = append(s.nodes, n)
+	return n
+}
+
+func (s *varSet) variables() []Variable {
+	if len(s.nodes) == 0 {
+		return nil
+	}
+	vars := make([]Variable, len(s.nodes))
+	for i, n := range s.nodes {
+		vars[i] = Variable{Name: n.name, Kind: n.kind, Fields: n.fields.variables()}
+	}
+	return vars
+}
+
+// collector gathers the variables used by compiled content.
+type collector struct {
+	root *varSet
+}
+
+// use records the use of path from the current set, and returns the variable or nil for "." and "$".
+func (c *collector) use(path string, kind VariableKind, cur *varSet) *varNode {
+	if strings.HasPrefix(path, "$") {
+		cur, path = c.root, strings.TrimPrefix(path, "$")
+	}
+	if path == "" || path == "." {
+		return nil
+	}
+	return cur.add(path[1:], kind)
+}
+
+func (c *collector) blocks(nodes []node[block], cur *varSet) {
+	collectNodes(c, nodes, cur, func(b block, cur *varSet) {
+		if b.control != nil {
+			c.blocks(b.control.blocks, cur)
+			return
+		}
+		if b.table != nil {
+			collectNodes(c, b.table.nodes, cur, func(rt *rowTemplate, cur *varSet) {
+				for _, cell := range rt.cells {
+					c.blocks(cell.blocks, cur)
+				}
+			})
+			return
+		}
+
+		collectNodes(c, b.para.nodes, cur, func(it *item, cur *varSet) {
+			if it.tag != nil {
+				c.use(it.tag.path, Value, cur)
+			}
+		})
+	})
+}
+
+// collectNodes records the variables of the control tags of nodes and calls leaf for every leaf
+// with the set its variables belong to.
+func collectNodes[T any](c *collector, nodes []node[T], cur *varSet, leaf func(T, *varSet)) {
+	for _, n := range nodes {
+		if n.tag == nil {
+			leaf(n.leaf, cur)
+			continue
+		}
+
+		body := cur
+		if n.tag.kind == tagRange {
+			if v := c.use(n.tag.path, List, cur); v != nil {
+				body = v.fields
+			}
+		} else {
+			c.use(n.tag.path, Condition, cur)
+		}
+		collectNodes(c, n.body, body, leaf)
+		collectNodes(c, n.elseBody, cur, leaf)
+	}
+}
@@ -0,0 +1,22 @@
+package ctypes
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// BookmarkStart marks the start of a bookmark. The bookmark ends at the w:bookmarkEnd with
+// the same ID.
+type BookmarkStart struct {
+	ID   int    `xml:"id,attr"`
+	Name string `xml:"name,attr"`
+}
+
+func (b BookmarkStart) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "w:bookmarkStart"
+	start.Attr = append(start.Attr,
+		xml.Attr{Name: xml.Name{Local: "w:id"}, Value: strconv.Itoa(b.ID)},
+		xml.Attr{Name: xml.Name{Local: "w:name"}, Value: b.Name},
+	)
+	return e.EncodeElement("", start)
+}
@@ -65,28 +65,14 @@ loop:
 				}
 
 				c.Property = &prop
-			case "p":
-				para := Paragraph{}
-				if err = d.DecodeElement(&para, &elem); err != nil {
-					return err
-				}
-
-				c.Contents = append(c.Contents, TCBlockContent{
-					Paragraph: &para,
-				})
-			case "tbl":
-				tbl := Table{}
-				if err = d.DecodeElement(&tbl, &elem); err != nil {
-					return err
-				}
-
-				c.Contents = append(c.Contents, TCBlockContent{
-					Table: &tbl,
-				})
 			default:
-				if err = d.Skip(); err != nil {
+				content, err := unmarshalBlockContent(d, elem)
+				if err != nil {
 					return err
 				}
+				if content != nil {
+					c.Contents = append(c.Contents, *content)
+				}
 			}
 		case xml.EndElement:
 			break loop
@@ -104,6 +90,9 @@ type TCBlockContent struct {
 	//Table
 	//	- ZeroOrMore: Any number of times Table can repeat within cell
 	Table *Table
+	//Block-Level Content Control
+	//	- ZeroOrMore: Any number of times a content control can repeat within cell
+	SDT *SDT
 }
 
 func (t TCBlockContent) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
@@ -115,5 +104,9 @@ func (t TCBlockContent) MarshalXML(e *xml.Encoder, start xml.StartElement) error
 		return t.Table.MarshalXML(e, xml.StartElement{})
 	}
 
+	if t.SDT != nil {
+		return t.SDT.MarshalXML(e, xml.StartElement{})
+	}
+
 	return nil
 }
@@ -0,0 +1,66 @@
+package ctypes
+
+import (
+	"encoding/xml"
+	"strconv"
+
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// FldChar - Complex Field Character
+//
+// A complex field is made of a begin character, the field instructions (w:instrText),
+// an optional separate character followed by the current field result, and an end character.
+type FldChar struct {
+	// Field Character Type
+	FldCharType stypes.FldCharType `xml:"fldCharType,attr"`
+
+	// Field Should Not Be Recalculated
+	FldLock *stypes.OnOff `xml:"fldLock,attr,omitempty"`
+
+	// Field Result Invalidated
+	Dirty *stypes.OnOff `xml:"dirty,attr,omitempty"`
+}
+
+// NewFldChar creates a new field character of the given type.
+func NewFldChar(fldCharType stypes.FldCharType) *FldChar {
+	return &FldChar{FldCharType: fldCharType}
+}
+
+// MarshalXML implements the xml.Marshaler interface for the FldChar type.
+func (f FldChar) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "w:fldChar"
+	start.Attr = []xml.Attr{{Name: xml.Name{Local: "w:fldCharType"}, Value: string(f.FldCharType)}}
+
+	if f.FldLock != nil {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "w:fldLock"}, Value: string(*f.FldLock)})
+	}
+
+	if f.Dirty != nil {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "w:dirty"}, Value: string(*f.Dirty)})
+	}
+
+	return e.EncodeElement("", start)
+}
+
+// FtnEdnRef - Footnote or Endnote Reference
+type FtnEdnRef struct {
+	// Suppress Footnote/Endnote Reference Mark
+	CustomMarkFollows *stypes.OnOff `xml:"customMarkFollows,attr,omitempty"`
+
+	// Footnote/Endnote ID Reference
+	ID int `xml:"id,attr"`
+}
+
+// MarshalXML implements the xml.Marshaler interface for the FtnEdnRef type.
+func (f FtnEdnRef) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = nil
+
+	if f.CustomMarkFollows != nil {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "w:customMarkFollows"}, Value: string(*f.CustomMarkFollows)})
+	}
+
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "w:id"}, Value: strconv.Itoa(f.ID)})
+
+	return e.EncodeElement("", start)
+}
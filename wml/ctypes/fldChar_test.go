@@ -0,0 +1,80 @@
+package ctypes
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+func TestFldChar_MarshalXML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    FldChar
+		expected string
+	}{
+		{
+			name:     "Begin",
+			input:    *NewFldChar(stypes.FldCharTypeBegin),
+			expected: `<w:fldChar w:fldCharType="begin"></w:fldChar>`,
+		},
+		{
+			name:     "Locked and dirty",
+			input:    FldChar{FldCharType: stypes.FldCharTypeEnd, FldLock: internal.ToPtr(stypes.OnOffTrue), Dirty: internal.ToPtr(stypes.OnOffFalse)},
+			expected: `<w:fldChar w:fldCharType="end" w:fldLock="true" w:dirty="false"></w:fldChar>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := xml.Marshal(tt.input)
+			if err != nil {
+				t.Fatalf("Error marshaling XML: %v", err)
+			}
+
+			if strings.TrimSpace(string(output)) != tt.expected {
+				t.Errorf("Expected XML:\n%s\nGot:\n%s", tt.expected, output)
+			}
+
+			var result FldChar
+			if err := xml.Unmarshal(output, &result); err != nil {
+				t.Fatalf("Error unmarshaling XML: %v", err)
+			}
+
+			if !reflect.DeepEqual(result, tt.input) {
+				t.Errorf("Expected %+v but got %+v", tt.input, result)
+			}
+		})
+	}
+}
+
+func TestFtnEdnRef_MarshalXML(t *testing.T) {
+	ref := FtnEdnRef{ID: 4, CustomMarkFollows: internal.ToPtr(stypes.OnOffOne)}
+
+	var buf strings.Builder
+	enc := xml.NewEncoder(&buf)
+	if err := enc.EncodeElement(ref, xml.StartElement{Name: xml.Name{Local: "w:footnoteReference"}}); err != nil {
+		t.Fatalf("Error marshaling XML: %v", err)
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatalf("Error flushing encoder: %v", err)
+	}
+	output := []byte(buf.String())
+
+	expected := `<w:footnoteReference w:customMarkFollows="1" w:id="4"></w:footnoteReference>`
+	if string(output) != expected {
+		t.Errorf("Expected XML:\n%s\nGot:\n%s", expected, output)
+	}
+
+	var result FtnEdnRef
+	if err := xml.Unmarshal(output, &result); err != nil {
+		t.Fatalf("Error unmarshaling XML: %v", err)
+	}
+
+	if !reflect.DeepEqual(result, ref) {
+		t.Errorf("Expected %+v but got %+v", ref, result)
+	}
+}
@@ -0,0 +1,43 @@
+package ctypes
+
+import (
+	"encoding/xml"
+
+	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/stypes"
+)
+
+// simpleField is a w:fldSimple element, a field whose instruction is held in an attribute and
+// whose content is the current field result.
+type simpleField struct {
+	Instr string `xml:"instr,attr"`
+	Runs  []Run  `xml:"r"`
+}
+
+// unmarshalSimpleField reads a w:fldSimple element as the runs of the equivalent complex field,
+// so that code dealing with fields only has to handle one form. The field characters take the
+// formatting of the first run of the result.
+func unmarshalSimpleField(d *xml.Decoder, start xml.StartElement) ([]ParagraphChild, error) {
+	var sf simpleField
+	if err := d.DecodeElement(&sf, &start); err != nil {
+		return nil, err
+	}
+
+	var props *RunProperty
+	if len(sf.Runs) > 0 {
+		props = sf.Runs[0].Property
+	}
+	fldRun := func(child RunChild) ParagraphChild {
+		return ParagraphChild{Run: &Run{Property: internal.DeepCopy(props), Children: []RunChild{child}}}
+	}
+
+	children := []ParagraphChild{
+		fldRun(RunChild{FldChar: NewFldChar(stypes.FldCharTypeBegin)}),
+		fldRun(RunChild{InstrText: &Text{Text: sf.Instr, Space: internal.ToPtr(TextSpacePreserve)}}),
+		fldRun(RunChild{FldChar: NewFldChar(stypes.FldCharTypeSeparate)}),
+	}
+	for i := range sf.Runs {
+		children = append(children, ParagraphChild{Run: &sf.Runs[i]})
+	}
+	return append(children, fldRun(RunChild{FldChar: NewFldChar(stypes.FldCharTypeEnd)})), nil
+}
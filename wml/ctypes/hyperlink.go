@@ -0,0 +1,127 @@
+package ctypes
+
+import (
+	"encoding/xml"
+)
+
+// Hyperlink - w:hyperlink
+//
+// Run holds the first run of the hyperlink and Children any further runs, so that
+// a hyperlink created with a single run and one loaded from a file look the same.
+type Hyperlink struct {
+	XMLName xml.Name `xml:"http://schemas.openxmlformats.org/wordprocessingml/2006/main hyperlink,omitempty"`
+
+	// Relationship ID of the hyperlink target (external links)
+	ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+
+	// Bookmark name of the hyperlink target within the document (internal links)
+	Anchor string `xml:"anchor,attr,omitempty"`
+
+	Run      *Run
+	Children []ParagraphChild
+}
+
+// MarshalXML implements the xml.Marshaler interface for the Hyperlink type.
+func (h Hyperlink) MarshalXML(e *xml.Encoder, start xml.StartElement) (err error) {
+	start.Name = xml.Name{Local: "w:hyperlink"}
+	start.Attr = nil
+
+	if h.ID != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "r:id"}, Value: h.ID})
+	}
+
+	if h.Anchor != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "w:anchor"}, Value: h.Anchor})
+	}
+
+	if err = e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if h.Run != nil {
+		if err = h.Run.MarshalXML(e, xml.StartElement{}); err != nil {
+			return err
+		}
+	}
+
+	for _, child := range h.Children {
+		if child.Run != nil {
+			if err = child.Run.MarshalXML(e, xml.StartElement{}); err != nil {
+				return err
+			}
+		}
+
+		if child.Link != nil {
+			if err = child.Link.MarshalXML(e, xml.StartElement{}); err != nil {
+				return err
+			}
+		}
+
+		if child.Ins != nil {
+			if err = child.Ins.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:ins"}}); err != nil {
+				return err
+			}
+		}
+
+		if child.Del != nil {
+			if err = child.Del.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:del"}}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface for the Hyperlink type.
+func (h *Hyperlink) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "id":
+			h.ID = attr.Value
+		case "anchor":
+			h.Anchor = attr.Value
+		}
+	}
+
+	for {
+		currentToken, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch elem := currentToken.(type) {
+		case xml.StartElement:
+			switch elem.Name.Local {
+			case "r":
+				r := NewRun()
+				if err = d.DecodeElement(r, &elem); err != nil {
+					return err
+				}
+
+				if h.Run == nil {
+					h.Run = r
+				} else {
+					h.Children = append(h.Children, ParagraphChild{Run: r})
+				}
+			case "ins", "del":
+				tc := &RunTrackChange{}
+				if err = d.DecodeElement(tc, &elem); err != nil {
+					return err
+				}
+
+				if elem.Name.Local == "ins" {
+					h.Children = append(h.Children, ParagraphChild{Ins: tc})
+				} else {
+					h.Children = append(h.Children, ParagraphChild{Del: tc})
+				}
+			default:
+				if err = d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package ctypes
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestHyperlink_MarshalXML(t *testing.T) {
+	link := Hyperlink{
+		ID:  "rId7",
+		Run: &Run{Children: []RunChild{{Text: TextFromString("Go")}}},
+		Children: []ParagraphChild{
+			{Run: &Run{Children: []RunChild{{Text: TextFromString("dev")}}}},
+		},
+	}
+
+	var buf strings.Builder
+	if err := xml.NewEncoder(&buf).Encode(link); err != nil {
+		t.Fatalf("Error marshaling XML: %v", err)
+	}
+
+	expected := `<w:hyperlink r:id="rId7"><w:r><w:t>Go</w:t></w:r><w:r><w:t>dev</w:t></w:r></w:hyperlink>`
+	if buf.String() != expected {
+		t.Errorf("Expected XML:\n%s\nGot:\n%s", expected, buf.String())
+	}
+}
+
+func TestHyperlink_UnmarshalXML(t *testing.T) {
+	input := `<w:p xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<w:r><w:t xml:space="preserve">See </w:t></w:r>` +
+		`<w:hyperlink r:id="rId3"><w:r><w:t>the </w:t></w:r><w:proofErr w:type="spellStart"/><w:r><w:t>docs</w:t></w:r></w:hyperlink>` +
+		`<w:hyperlink w:anchor="_Toc1"><w:r><w:t>Intro</w:t></w:r></w:hyperlink>` +
+		`</w:p>`
+
+	var p Paragraph
+	if err := xml.Unmarshal([]byte(input), &p); err != nil {
+		t.Fatalf("Error unmarshaling XML: %v", err)
+	}
+
+	if len(p.Children) != 3 {
+		t.Fatalf("Expected 3 paragraph children, got %d", len(p.Children))
+	}
+
+	link := p.Children[1].Link
+	if link == nil {
+		t.Fatalf("Expected a hyperlink as second child")
+	}
+
+	if link.ID != "rId3" {
+		t.Errorf("Expected ID rId3, got %q", link.ID)
+	}
+
+	if link.Run == nil || link.Run.Children[0].Text.Text != "the " {
+		t.Errorf("Expected first run to hold 'the ', got %+v", link.Run)
+	}
+
+	if len(link.Children) != 1 || link.Children[0].Run.Children[0].Text.Text != "docs" {
+		t.Errorf("Expected one further run holding 'docs', got %+v", link.Children)
+	}
+
+	anchor := p.Children[2].Link
+	if anchor == nil || anchor.Anchor != "_Toc1" || anchor.ID != "" {
+		t.Errorf("Expected internal link to _Toc1, got %+v", anchor)
+	}
+}
@@ -25,15 +25,22 @@ type Paragraph struct {
 }
 
 type ParagraphChild struct {
-	Link *Hyperlink // w:hyperlink
-	Run  *Run       // i.e w:r
+	Link      *Hyperlink // w:hyperlink
+	Run       *Run       // i.e w:r
+	CmntStart *Markup    // w:commentRangeStart
+	CmntEnd   *Markup    // w:commentRangeEnd
+
+	BmkStart *BookmarkStart // w:bookmarkStart
+	BmkEnd   *Markup        // w:bookmarkEnd
+
+	Ins *RunTrackChange // w:ins
+	Del *RunTrackChange // w:del
 }
 
-type Hyperlink struct {
-	XMLName  xml.Name `xml:"http://schemas.openxmlformats.org/wordprocessingml/2006/main hyperlink,omitempty"`
-	ID       string   `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
-	Run      *Run
-	Children []ParagraphChild
+// Clone returns a deep copy of the paragraph.
+func (p *Paragraph) Clone() *Paragraph {
+	c := internal.DeepCopy(*p)
+	return &c
 }
 
 func (p Paragraph) MarshalXML(e *xml.Encoder, start xml.StartElement) (err error) {
@@ -78,9 +85,43 @@ func (p Paragraph) MarshalXML(e *xml.Encoder, start xml.StartElement) (err error
 		}
 
 		if cElem.Link != nil {
-			if err = e.EncodeElement(cElem.Link, xml.StartElement{
-				Name: xml.Name{Local: "w:hyperlink"},
-			}); err != nil {
+			if err = cElem.Link.MarshalXML(e, xml.StartElement{}); err != nil {
+				return err
+			}
+		}
+
+		if cElem.CmntStart != nil {
+			if err = cElem.CmntStart.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:commentRangeStart"}}); err != nil {
+				return err
+			}
+		}
+
+		if cElem.CmntEnd != nil {
+			if err = cElem.CmntEnd.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:commentRangeEnd"}}); err != nil {
+				return err
+			}
+		}
+
+		if cElem.BmkStart != nil {
+			if err = cElem.BmkStart.MarshalXML(e, xml.StartElement{}); err != nil {
+				return err
+			}
+		}
+
+		if cElem.BmkEnd != nil {
+			if err = cElem.BmkEnd.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:bookmarkEnd"}}); err != nil {
+				return err
+			}
+		}
+
+		if cElem.Ins != nil {
+			if err = cElem.Ins.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:ins"}}); err != nil {
+				return err
+			}
+		}
+
+		if cElem.Del != nil {
+			if err = cElem.Del.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:del"}}); err != nil {
 				return err
 			}
 		}
@@ -124,6 +165,56 @@ loop:
 				}
 
 				p.Children = append(p.Children, ParagraphChild{Run: r})
+			case "hyperlink":
+				link := &Hyperlink{}
+				if err = d.DecodeElement(link, &elem); err != nil {
+					return err
+				}
+
+				p.Children = append(p.Children, ParagraphChild{Link: link})
+			case "fldSimple":
+				children, err := unmarshalSimpleField(d, elem)
+				if err != nil {
+					return err
+				}
+
+				p.Children = append(p.Children, children...)
+			case "commentRangeStart", "commentRangeEnd":
+				m := &Markup{}
+				if err = d.DecodeElement(m, &elem); err != nil {
+					return err
+				}
+
+				if elem.Name.Local == "commentRangeStart" {
+					p.Children = append(p.Children, ParagraphChild{CmntStart: m})
+				} else {
+					p.Children = append(p.Children, ParagraphChild{CmntEnd: m})
+				}
+			case "bookmarkStart":
+				b := &BookmarkStart{}
+				if err = d.DecodeElement(b, &elem); err != nil {
+					return err
+				}
+
+				p.Children = append(p.Children, ParagraphChild{BmkStart: b})
+			case "bookmarkEnd":
+				m := &Markup{}
+				if err = d.DecodeElement(m, &elem); err != nil {
+					return err
+				}
+
+				p.Children = append(p.Children, ParagraphChild{BmkEnd: m})
+			case "ins", "del":
+				tc := &RunTrackChange{}
+				if err = d.DecodeElement(tc, &elem); err != nil {
+					return err
+				}
+
+				if elem.Name.Local == "ins" {
+					p.Children = append(p.Children, ParagraphChild{Ins: tc})
+				} else {
+					p.Children = append(p.Children, ParagraphChild{Del: tc})
+				}
 			case "pPr":
 				p.Property = &ParagraphProp{}
 				if err = d.DecodeElement(p.Property, &elem); err != nil {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/xml"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/gomutex/godocx/common/constants"
@@ -48,3 +49,126 @@ func TestParagraphXML(t *testing.T) {
 		t.Errorf("Original and unmarshaled paragraphs are not equal.")
 	}
 }
+
+func TestParagraph_UnmarshalSimpleField(t *testing.T) {
+	input := `<w:p xmlns:w="` + constants.WMLNamespace + `"><w:fldSimple w:instr=" MERGEFIELD Name "><w:r><w:rPr><w:b/></w:rPr><w:t>«Name»</w:t></w:r></w:fldSimple></w:p>`
+
+	var p Paragraph
+	if err := xml.Unmarshal([]byte(input), &p); err != nil {
+		t.Fatalf("Error unmarshaling XML: %v", err)
+	}
+
+	if len(p.Children) != 5 {
+		t.Fatalf("expected 5 runs, got %d", len(p.Children))
+	}
+
+	begin := p.Children[0].Run
+	if begin.Children[0].FldChar == nil || begin.Children[0].FldChar.FldCharType != "begin" {
+		t.Errorf("expected a begin field character, got %+v", begin.Children[0])
+	}
+	if begin.Property == nil || begin.Property.Bold == nil {
+		t.Error("expected field characters to take the formatting of the result")
+	}
+	if got := p.Children[1].Run.Children[0].InstrText.Text; got != " MERGEFIELD Name " {
+		t.Errorf("expected instruction %q, got %q", " MERGEFIELD Name ", got)
+	}
+	if got := p.Children[3].Run.Children[0].Text.Text; got != "«Name»" {
+		t.Errorf("expected result %q, got %q", "«Name»", got)
+	}
+	if p.Children[4].Run.Children[0].FldChar.FldCharType != "end" {
+		t.Error("expected an end field character")
+	}
+}
+
+func TestParagraph_CommentRange(t *testing.T) {
+	input := `<w:p xmlns:w="` + constants.WMLNamespace + `"><w:commentRangeStart w:id="3"/><w:r><w:t>noted</w:t></w:r><w:commentRangeEnd w:id="3"/></w:p>`
+
+	var p Paragraph
+	if err := xml.Unmarshal([]byte(input), &p); err != nil {
+		t.Fatalf("Error unmarshaling XML: %v", err)
+	}
+
+	if len(p.Children) != 3 {
+		t.Fatalf("expected 3 children, got %d", len(p.Children))
+	}
+	if p.Children[0].CmntStart == nil || p.Children[0].CmntStart.ID != 3 {
+		t.Errorf("expected a comment range start with ID 3, got %+v", p.Children[0])
+	}
+	if p.Children[2].CmntEnd == nil || p.Children[2].CmntEnd.ID != 3 {
+		t.Errorf("expected a comment range end with ID 3, got %+v", p.Children[2])
+	}
+
+	output, err := xml.Marshal(p)
+	if err != nil {
+		t.Fatalf("Error marshaling XML: %v", err)
+	}
+	expected := `<w:p><w:commentRangeStart w:id="3"></w:commentRangeStart><w:r><w:t>noted</w:t></w:r><w:commentRangeEnd w:id="3"></w:commentRangeEnd></w:p>`
+	if string(output) != expected {
+		t.Errorf("expected %s, got %s", expected, output)
+	}
+}
+
+func TestParagraph_Bookmark(t *testing.T) {
+	input := `<w:p xmlns:w="` + constants.WMLNamespace + `"><w:bookmarkStart w:id="0" w:name="Slot"/><w:r><w:t>here</w:t></w:r><w:bookmarkEnd w:id="0"/></w:p>`
+
+	var p Paragraph
+	if err := xml.Unmarshal([]byte(input), &p); err != nil {
+		t.Fatalf("Error unmarshaling XML: %v", err)
+	}
+
+	if len(p.Children) != 3 {
+		t.Fatalf("expected 3 children, got %d", len(p.Children))
+	}
+	if p.Children[0].BmkStart == nil || p.Children[0].BmkStart.Name != "Slot" {
+		t.Errorf("expected a bookmark start named Slot, got %+v", p.Children[0])
+	}
+	if p.Children[2].BmkEnd == nil || p.Children[2].BmkEnd.ID != 0 {
+		t.Errorf("expected a bookmark end with ID 0, got %+v", p.Children[2])
+	}
+
+	output, err := xml.Marshal(p)
+	if err != nil {
+		t.Fatalf("Error marshaling XML: %v", err)
+	}
+	expected := `<w:p><w:bookmarkStart w:id="0" w:name="Slot"></w:bookmarkStart><w:r><w:t>here</w:t></w:r><w:bookmarkEnd w:id="0"></w:bookmarkEnd></w:p>`
+	if string(output) != expected {
+		t.Errorf("expected %s, got %s", expected, output)
+	}
+}
+
+func TestParagraph_Revisions(t *testing.T) {
+	input := `<w:p xmlns:w="` + constants.WMLNamespace + `"><w:pPr><w:rPr><w:ins w:id="3" w:author="Ann"/></w:rPr></w:pPr>` +
+		`<w:del w:id="1" w:author="Ann" w:date="2024-01-02T00:00:00Z"><w:r><w:delText>30</w:delText></w:r></w:del>` +
+		`<w:ins w:id="2" w:author="Ann"><w:r><w:rPr><w:b/><w:rPrChange w:id="4" w:author="Ann"><w:rPr></w:rPr></w:rPrChange></w:rPr><w:t>45</w:t></w:r></w:ins></w:p>`
+
+	var p Paragraph
+	if err := xml.Unmarshal([]byte(input), &p); err != nil {
+		t.Fatalf("Error unmarshaling XML: %v", err)
+	}
+
+	if len(p.Children) != 2 || p.Children[0].Del == nil || p.Children[1].Ins == nil {
+		t.Fatalf("expected a deletion and an insertion, got %+v", p.Children)
+	}
+	if del := p.Children[0].Del; del.ID != 1 || del.Author != "Ann" || del.Date == nil || len(del.Runs) != 1 {
+		t.Errorf("unexpected deletion %+v", del)
+	}
+	if ins := p.Children[1].Ins; ins.Runs[0].Property.Change == nil || ins.Runs[0].Property.Change.ID != 4 {
+		t.Errorf("expected a formatting change in the inserted run, got %+v", ins.Runs[0].Property)
+	}
+	if p.Property.RunProperty == nil || p.Property.RunProperty.Ins == nil {
+		t.Errorf("expected an inserted paragraph mark")
+	}
+
+	output, err := xml.Marshal(p)
+	if err != nil {
+		t.Fatalf("Error marshaling XML: %v", err)
+	}
+	expected := `<w:del w:id="1" w:author="Ann" w:date="2024-01-02T00:00:00Z"><w:r><w:delText>30</w:delText></w:r></w:del>` +
+		`<w:ins w:id="2" w:author="Ann"><w:r><w:rPr><w:b></w:b><w:rPrChange w:id="4" w:author="Ann"><w:rPr></w:rPr></w:rPrChange></w:rPr><w:t>45</w:t></w:r></w:ins>`
+	if !strings.Contains(string(output), expected) {
+		t.Errorf("expected %s in %s", expected, output)
+	}
+	if !strings.Contains(string(output), `<w:rPr><w:ins w:id="3" w:author="Ann"></w:ins></w:rPr>`) {
+		t.Errorf("expected the inserted paragraph mark in %s", output)
+	}
+}
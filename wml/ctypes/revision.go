@@ -0,0 +1,50 @@
+package ctypes
+
+import "encoding/xml"
+
+// RunTrackChange is an insertion (w:ins) or a deletion (w:del) of runs. The text of deleted
+// runs is held by w:delText elements.
+type RunTrackChange struct {
+	TrackChange
+	Runs []*Run `xml:"r"`
+}
+
+func (t RunTrackChange) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = t.attrs()
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, r := range t.Runs {
+		if err := r.MarshalXML(e, xml.StartElement{}); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// RunPropChange records a change of the formatting of a run (w:rPrChange), holding the
+// properties the run had before the change.
+type RunPropChange struct {
+	TrackChange
+	Property *RunProperty `xml:"rPr"`
+}
+
+func (c RunPropChange) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "w:rPrChange"
+	start.Attr = c.attrs()
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	prop := c.Property
+	if prop == nil {
+		prop = &RunProperty{}
+	}
+	if err := prop.MarshalXML(e, xml.StartElement{}); err != nil {
+		return err
+	}
+
+	return e.EncodeToken(start.End())
+}
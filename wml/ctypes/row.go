@@ -2,6 +2,8 @@ package ctypes
 
 import (
 	"encoding/xml"
+
+	"github.com/gomutex/godocx/internal"
 )
 
 type Row struct {
@@ -21,6 +23,12 @@ func DefaultRow() *Row {
 	}
 }
 
+// Clone returns a deep copy of the row, including its cells and their content.
+func (r *Row) Clone() *Row {
+	c := internal.DeepCopy(*r)
+	return &c
+}
+
 // TODO  Implement Marshal and Unmarshal properly for all fields
 
 func (r Row) MarshalXML(e *xml.Encoder, start xml.StartElement) (err error) {
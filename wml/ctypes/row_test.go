@@ -0,0 +1,31 @@
+package ctypes
+
+import (
+	"testing"
+)
+
+func TestRow_Clone(t *testing.T) {
+	row := DefaultRow()
+	row.Property.Header = &OnOff{}
+	row.Contents = append(row.Contents, TRCellContent{Cell: &Cell{
+		Contents: []TCBlockContent{{Paragraph: &Paragraph{
+			Children: []ParagraphChild{{Run: &Run{Children: []RunChild{{Text: TextFromString("cell")}}}}},
+		}}},
+	}})
+
+	clone := row.Clone()
+
+	if clone == row || clone.Property == row.Property || clone.Contents[0].Cell == row.Contents[0].Cell {
+		t.Fatal("clone shares pointers with the original row")
+	}
+
+	clone.Contents[0].Cell.Contents[0].Paragraph.Children[0].Run.Children[0].Text.Text = "changed"
+	clone.Property.Header = nil
+
+	if got := row.Contents[0].Cell.Contents[0].Paragraph.Children[0].Run.Children[0].Text.Text; got != "cell" {
+		t.Errorf("original text changed to %q", got)
+	}
+	if row.Property.Header == nil {
+		t.Error("original row property changed")
+	}
+}
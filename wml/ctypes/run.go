@@ -93,13 +93,18 @@ type RunChild struct {
 	// Picture reference
 	Pict *Pict `xml:"pict,omitempty"`
 
+	//Complex Field Character
+	FldChar *FldChar `xml:"fldChar,omitempty"`
+
+	//Footnote Reference
+	FootnoteReference *FtnEdnRef `xml:"footnoteReference,omitempty"`
+
+	//Endnote Reference
+	EndnoteReference *FtnEdnRef `xml:"endnoteReference,omitempty"`
+
 	//TODO:
 	// 	w:object    Inline Embedded Object
-	// w:fldChar    Complex Field Character
 	// w:ruby    Phonetic Guide
-	// w:footnoteReference    Footnote Reference
-	// w:endnoteReference    Endnote Reference
-	// w:commentReference    Comment Content Reference Mark
 
 	//Comment Content Reference Mark
 	CmntRef *Markup `xml:"commentReference,omitempty"`
@@ -182,6 +187,59 @@ loop:
 				}
 
 				r.Children = append(r.Children, RunChild{Text: txt})
+			case "delText", "instrText", "delInstrText":
+				txt := NewText()
+				if err = d.DecodeElement(txt, &elem); err != nil {
+					return err
+				}
+
+				switch elem.Name.Local {
+				case "delText":
+					r.Children = append(r.Children, RunChild{DelText: txt})
+				case "instrText":
+					r.Children = append(r.Children, RunChild{InstrText: txt})
+				default:
+					r.Children = append(r.Children, RunChild{DelInstrText: txt})
+				}
+			case "fldChar":
+				fldChar := &FldChar{}
+				if err = d.DecodeElement(fldChar, &elem); err != nil {
+					return err
+				}
+
+				r.Children = append(r.Children, RunChild{FldChar: fldChar})
+			case "footnoteReference", "endnoteReference":
+				ref := &FtnEdnRef{}
+				if err = d.DecodeElement(ref, &elem); err != nil {
+					return err
+				}
+
+				if elem.Name.Local == "footnoteReference" {
+					r.Children = append(r.Children, RunChild{FootnoteReference: ref})
+				} else {
+					r.Children = append(r.Children, RunChild{EndnoteReference: ref})
+				}
+			case "commentReference":
+				ref := &Markup{}
+				if err = d.DecodeElement(ref, &elem); err != nil {
+					return err
+				}
+
+				r.Children = append(r.Children, RunChild{CmntRef: ref})
+			case "sym":
+				sym := &Sym{}
+				if err = d.DecodeElement(sym, &elem); err != nil {
+					return err
+				}
+
+				r.Children = append(r.Children, RunChild{Sym: sym})
+			case "noBreakHyphen", "softHyphen", "annotationRef", "footnoteRef", "endnoteRef",
+				"separator", "continuationSeparator", "pgNum", "cr", "lastRenderedPageBreak":
+				if err = d.Skip(); err != nil {
+					return err
+				}
+
+				r.Children = append(r.Children, emptyRunChild(elem.Name.Local))
 			case "rPr":
 				r.Property = &RunProperty{}
 				if err = d.DecodeElement(r.Property, &elem); err != nil {
@@ -236,6 +294,33 @@ loop:
 	return nil
 }
 
+// emptyRunChild returns the run child for one of the run content elements that carry no data.
+func emptyRunChild(name string) RunChild {
+	e := &Empty{}
+	switch name {
+	case "noBreakHyphen":
+		return RunChild{NoBreakHyphen: e}
+	case "softHyphen":
+		return RunChild{SoftHyphen: e}
+	case "annotationRef":
+		return RunChild{AnnotationRef: e}
+	case "footnoteRef":
+		return RunChild{FootnoteRef: e}
+	case "endnoteRef":
+		return RunChild{EndnoteRef: e}
+	case "separator":
+		return RunChild{Separator: e}
+	case "continuationSeparator":
+		return RunChild{ContSeparator: e}
+	case "pgNum":
+		return RunChild{PgNumBlock: e}
+	case "cr":
+		return RunChild{CarrRtn: e}
+	default:
+		return RunChild{LastRenPgBrk: e}
+	}
+}
+
 // Sym represents a symbol character in a document.
 type Sym struct {
 	Font *string `xml:"font,attr,omitempty"`
@@ -319,6 +404,12 @@ func (r *Run) MarshalChild(e *xml.Encoder) error {
 			err = child.PTab.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:ptab"}})
 		case child.CmntRef != nil:
 			err = child.CmntRef.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:commentReference"}})
+		case child.FldChar != nil:
+			err = child.FldChar.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:fldChar"}})
+		case child.FootnoteReference != nil:
+			err = child.FootnoteReference.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:footnoteReference"}})
+		case child.EndnoteReference != nil:
+			err = child.EndnoteReference.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:endnoteReference"}})
 
 		}
 
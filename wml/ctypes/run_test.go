@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/gomutex/godocx/internal"
+	"github.com/gomutex/godocx/wml/stypes"
 )
 
 func TestSym_MarshalXML(t *testing.T) {
@@ -98,3 +99,47 @@ func TestSym_UnmarshalXML(t *testing.T) {
 		})
 	}
 }
+
+func TestRun_UnmarshalFieldAndNoteContent(t *testing.T) {
+	input := `<w:r xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:fldChar w:fldCharType="begin"/>` +
+		`<w:instrText xml:space="preserve"> PAGE </w:instrText>` +
+		`<w:fldChar w:fldCharType="end" w:dirty="true"/>` +
+		`<w:footnoteReference w:id="2"/>` +
+		`<w:separator/>` +
+		`<w:sym w:font="Wingdings" w:char="F0E0"/>` +
+		`</w:r>`
+
+	var r Run
+	if err := xml.Unmarshal([]byte(input), &r); err != nil {
+		t.Fatalf("Error unmarshaling XML: %v", err)
+	}
+
+	expected := []RunChild{
+		{FldChar: &FldChar{FldCharType: stypes.FldCharTypeBegin}},
+		{InstrText: &Text{Text: " PAGE ", Space: internal.ToPtr("preserve")}},
+		{FldChar: &FldChar{FldCharType: stypes.FldCharTypeEnd, Dirty: internal.ToPtr(stypes.OnOffTrue)}},
+		{FootnoteReference: &FtnEdnRef{ID: 2}},
+		{Separator: &Empty{}},
+		{Sym: NewSym("Wingdings", "F0E0")},
+	}
+
+	if !reflect.DeepEqual(r.Children, expected) {
+		t.Errorf("Expected children %+v but got %+v", expected, r.Children)
+	}
+
+	var buf strings.Builder
+	if err := xml.NewEncoder(&buf).Encode(r); err != nil {
+		t.Fatalf("Error marshaling XML: %v", err)
+	}
+
+	want := `<w:r><w:fldChar w:fldCharType="begin"></w:fldChar>` +
+		`<w:instrText xml:space="preserve"> PAGE </w:instrText>` +
+		`<w:fldChar w:fldCharType="end" w:dirty="true"></w:fldChar>` +
+		`<w:footnoteReference w:id="2"></w:footnoteReference>` +
+		`<w:separator></w:separator>` +
+		`<w:sym w:font="Wingdings" w:char="F0E0"></w:sym></w:r>`
+	if buf.String() != want {
+		t.Errorf("Expected XML:\n%s\nGot:\n%s", want, buf.String())
+	}
+}
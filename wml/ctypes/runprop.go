@@ -9,6 +9,10 @@ import (
 
 // RunProperty represents the properties of a run of text within a paragraph.
 type RunProperty struct {
+	// Inserted and Deleted Paragraph Mark, set for the properties of paragraph marks only
+	Ins *TrackChange `xml:"ins,omitempty"`
+	Del *TrackChange `xml:"del,omitempty"`
+
 	//1. Referenced Character Style
 	Style *CTString `xml:"rStyle,omitempty"`
 
@@ -125,6 +129,9 @@ type RunProperty struct {
 
 	//39.Office Open XML Math
 	OMath *OnOff `xml:"oMath,omitempty"`
+
+	//40.Revision Information for Run Properties
+	Change *RunPropChange `xml:"rPrChange,omitempty"`
 }
 
 // NewRunProperty creates a new RunProperty with default values.
@@ -145,6 +152,18 @@ func (rp RunProperty) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 		return err
 	}
 
+	// Inserted and Deleted Paragraph Mark
+	if rp.Ins != nil {
+		if err = rp.Ins.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:ins"}}); err != nil {
+			return fmt.Errorf("ins: %w", err)
+		}
+	}
+	if rp.Del != nil {
+		if err = rp.Del.MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:del"}}); err != nil {
+			return fmt.Errorf("del: %w", err)
+		}
+	}
+
 	// 1. Referenced Character Style
 	if rp.Style != nil {
 		if err = rp.Style.MarshalXML(e, xml.StartElement{
@@ -366,5 +385,12 @@ func (rp RunProperty) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 		}
 	}
 
+	//40.Revision Information for Run Properties
+	if rp.Change != nil {
+		if err = rp.Change.MarshalXML(e, xml.StartElement{}); err != nil {
+			return fmt.Errorf("rPrChange: %w", err)
+		}
+	}
+
 	return e.EncodeToken(start.End())
 }
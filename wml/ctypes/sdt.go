@@ -0,0 +1,208 @@
+package ctypes
+
+import (
+	"encoding/xml"
+)
+
+// SDT is a block-level structured document tag, shown by Word as a content control.
+type SDT struct {
+	// 1. Structured Document Tag Properties
+	Property *SDTProperty
+
+	// 2. Structured Document Tag End Character Properties, kept as read.
+	EndProperty *RawElement
+
+	// 3. Block-Level Structured Document Tag Content
+	Contents []TCBlockContent
+}
+
+// SDTProperty holds the properties of a content control.
+//
+// Only the alias and tag are decoded. A property read from a document is written back as it
+// was read, including the parts that are not decoded, unless Inner is cleared; Alias and Tag
+// are written from the fields when Inner is empty.
+type SDTProperty struct {
+	Alias string // w:alias, the friendly name shown by Word
+	Tag   string // w:tag
+
+	// Inner is the XML content of the property element as read from the document.
+	Inner string
+}
+
+// RawElement is an element kept as read, to write it back unchanged.
+type RawElement struct {
+	Inner string `xml:",innerxml"`
+}
+
+func (s SDT) MarshalXML(e *xml.Encoder, start xml.StartElement) (err error) {
+	start.Name.Local = "w:sdt"
+
+	if err = e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	// 1. Structured Document Tag Properties
+	if s.Property != nil {
+		if err = s.Property.MarshalXML(e, xml.StartElement{}); err != nil {
+			return err
+		}
+	}
+
+	// 2. Structured Document Tag End Character Properties
+	if s.EndProperty != nil {
+		if err = e.EncodeElement(s.EndProperty, xml.StartElement{Name: xml.Name{Local: "w:sdtEndPr"}}); err != nil {
+			return err
+		}
+	}
+
+	// 3. Block-Level Structured Document Tag Content
+	content := xml.StartElement{Name: xml.Name{Local: "w:sdtContent"}}
+	if err = e.EncodeToken(content); err != nil {
+		return err
+	}
+	for _, elem := range s.Contents {
+		if err = elem.MarshalXML(e, xml.StartElement{}); err != nil {
+			return err
+		}
+	}
+	if err = e.EncodeToken(content.End()); err != nil {
+		return err
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+func (s *SDT) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+loop:
+	for {
+		currentToken, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch elem := currentToken.(type) {
+		case xml.StartElement:
+			switch elem.Name.Local {
+			case "sdtPr":
+				prop := SDTProperty{}
+				if err = d.DecodeElement(&prop, &elem); err != nil {
+					return err
+				}
+				s.Property = &prop
+			case "sdtEndPr":
+				raw := RawElement{}
+				if err = d.DecodeElement(&raw, &elem); err != nil {
+					return err
+				}
+				s.EndProperty = &raw
+			case "sdtContent":
+				if s.Contents, err = unmarshalBlockContents(d); err != nil {
+					return err
+				}
+			default:
+				if err = d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			break loop
+		}
+	}
+
+	return nil
+}
+
+func (p SDTProperty) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "w:sdtPr"
+
+	if p.Inner != "" {
+		return e.EncodeElement(RawElement{Inner: p.Inner}, start)
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if p.Alias != "" {
+		if err := NewCTString(p.Alias).MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:alias"}}); err != nil {
+			return err
+		}
+	}
+	if p.Tag != "" {
+		if err := NewCTString(p.Tag).MarshalXML(e, xml.StartElement{Name: xml.Name{Local: "w:tag"}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+func (p *SDTProperty) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var prop struct {
+		Alias *CTString `xml:"alias"`
+		Tag   *CTString `xml:"tag"`
+		Inner string    `xml:",innerxml"`
+	}
+	if err := d.DecodeElement(&prop, &start); err != nil {
+		return err
+	}
+
+	if prop.Alias != nil {
+		p.Alias = prop.Alias.Val
+	}
+	if prop.Tag != nil {
+		p.Tag = prop.Tag.Val
+	}
+	p.Inner = prop.Inner
+	return nil
+}
+
+// unmarshalBlockContents decodes the paragraphs, tables and content controls up to the end of
+// the current element.
+func unmarshalBlockContents(d *xml.Decoder) ([]TCBlockContent, error) {
+	var contents []TCBlockContent
+	for {
+		currentToken, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch elem := currentToken.(type) {
+		case xml.StartElement:
+			content, err := unmarshalBlockContent(d, elem)
+			if err != nil {
+				return nil, err
+			}
+			if content != nil {
+				contents = append(contents, *content)
+			}
+		case xml.EndElement:
+			return contents, nil
+		}
+	}
+}
+
+// unmarshalBlockContent decodes a paragraph, table or content control. Other elements are
+// skipped and nil is returned for them.
+func unmarshalBlockContent(d *xml.Decoder, elem xml.StartElement) (*TCBlockContent, error) {
+	switch elem.Name.Local {
+	case "p":
+		para := Paragraph{}
+		if err := d.DecodeElement(&para, &elem); err != nil {
+			return nil, err
+		}
+		return &TCBlockContent{Paragraph: &para}, nil
+	case "tbl":
+		tbl := Table{}
+		if err := d.DecodeElement(&tbl, &elem); err != nil {
+			return nil, err
+		}
+		return &TCBlockContent{Table: &tbl}, nil
+	case "sdt":
+		sdt := SDT{}
+		if err := d.DecodeElement(&sdt, &elem); err != nil {
+			return nil, err
+		}
+		return &TCBlockContent{SDT: &sdt}, nil
+	default:
+		return nil, d.Skip()
+	}
+}
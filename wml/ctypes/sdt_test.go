@@ -0,0 +1,56 @@
+package ctypes
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestSDT_RoundTrip(t *testing.T) {
+	input := `<w:sdt><w:sdtPr><w:alias w:val="Client"/><w:tag w:val="client"/><w:showingPlcHdr/></w:sdtPr>` +
+		`<w:sdtEndPr><w:rPr><w:b/></w:rPr></w:sdtEndPr>` +
+		`<w:sdtContent><w:p><w:r><w:t>ACME</w:t></w:r></w:p><w:tbl><w:tblPr></w:tblPr><w:tblGrid></w:tblGrid></w:tbl></w:sdtContent></w:sdt>`
+
+	var sdt SDT
+	if err := xml.Unmarshal([]byte(input), &sdt); err != nil {
+		t.Fatalf("Error unmarshaling XML: %v", err)
+	}
+
+	if sdt.Property == nil || sdt.Property.Alias != "Client" || sdt.Property.Tag != "client" {
+		t.Fatalf("Unexpected property: %+v", sdt.Property)
+	}
+	if len(sdt.Contents) != 2 || sdt.Contents[0].Paragraph == nil || sdt.Contents[1].Table == nil {
+		t.Fatalf("Unexpected contents: %+v", sdt.Contents)
+	}
+
+	output, err := xml.Marshal(sdt)
+	if err != nil {
+		t.Fatalf("Error marshaling XML: %v", err)
+	}
+	if string(output) != input {
+		t.Errorf("Expected XML:\n%s\nBut got:\n%s", input, output)
+	}
+}
+
+func TestSDTProperty_MarshalXML(t *testing.T) {
+	output, err := xml.Marshal(SDTProperty{Alias: "Client", Tag: "client"})
+	if err != nil {
+		t.Fatalf("Error marshaling XML: %v", err)
+	}
+
+	expected := `<w:sdtPr><w:alias w:val="Client"></w:alias><w:tag w:val="client"></w:tag></w:sdtPr>`
+	if string(output) != expected {
+		t.Errorf("Expected XML:\n%s\nBut got:\n%s", expected, output)
+	}
+}
+
+func TestCell_UnmarshalSDT(t *testing.T) {
+	input := `<w:tc><w:sdt><w:sdtContent><w:p></w:p></w:sdtContent></w:sdt><w:p></w:p></w:tc>`
+
+	var cell Cell
+	if err := xml.Unmarshal([]byte(input), &cell); err != nil {
+		t.Fatalf("Error unmarshaling XML: %v", err)
+	}
+	if len(cell.Contents) != 2 || cell.Contents[0].SDT == nil || cell.Contents[1].Paragraph == nil {
+		t.Fatalf("Unexpected contents: %+v", cell.Contents)
+	}
+}
@@ -2,6 +2,8 @@ package ctypes
 
 import (
 	"encoding/xml"
+
+	"github.com/gomutex/godocx/internal"
 )
 
 // Table
@@ -25,6 +27,12 @@ func DefaultTable() *Table {
 	return &Table{}
 }
 
+// Clone returns a deep copy of the table.
+func (t *Table) Clone() *Table {
+	c := internal.DeepCopy(*t)
+	return &c
+}
+
 func (t Table) MarshalXML(e *xml.Encoder, start xml.StartElement) (err error) {
 	start.Name.Local = "w:tbl"
 
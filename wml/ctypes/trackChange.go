@@ -13,14 +13,18 @@ type TrackChange struct {
 }
 
 func (t TrackChange) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-	start.Attr = []xml.Attr{
+	start.Attr = t.attrs()
+	return e.EncodeElement("", start)
+}
+
+func (t TrackChange) attrs() []xml.Attr {
+	attrs := []xml.Attr{
 		{Name: xml.Name{Local: "w:id"}, Value: strconv.Itoa(t.ID)},
 		{Name: xml.Name{Local: "w:author"}, Value: t.Author},
 	}
 
 	if t.Date != nil {
-		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "w:date"}, Value: *t.Date})
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "w:date"}, Value: *t.Date})
 	}
-
-	return e.EncodeElement("", start)
+	return attrs
 }
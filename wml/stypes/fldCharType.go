@@ -0,0 +1,39 @@
+package stypes
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+// FldCharType - Complex Field Character Type
+type FldCharType string
+
+const (
+	FldCharTypeBegin    FldCharType = "begin"    // Start Character
+	FldCharTypeSeparate FldCharType = "separate" // Separator Character
+	FldCharTypeEnd      FldCharType = "end"      // End Character
+)
+
+func FldCharTypeFromStr(value string) (FldCharType, error) {
+	switch value {
+	case "begin":
+		return FldCharTypeBegin, nil
+	case "separate":
+		return FldCharTypeSeparate, nil
+	case "end":
+		return FldCharTypeEnd, nil
+	default:
+		return "", errors.New("invalid FldCharType value")
+	}
+}
+
+func (f *FldCharType) UnmarshalXMLAttr(attr xml.Attr) error {
+	val, err := FldCharTypeFromStr(attr.Value)
+	if err != nil {
+		return err
+	}
+
+	*f = val
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package stypes
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestFldCharTypeFromStr_ValidValues(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected FldCharType
+	}{
+		{"begin", FldCharTypeBegin},
+		{"separate", FldCharTypeSeparate},
+		{"end", FldCharTypeEnd},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := FldCharTypeFromStr(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("Expected %s but got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFldCharTypeFromStr_InvalidValue(t *testing.T) {
+	input := "invalidValue"
+
+	result, err := FldCharTypeFromStr(input)
+
+	if err == nil {
+		t.Fatalf("Expected error for invalid value %s, but got none. Result: %s", input, result)
+	}
+
+	expectedError := "invalid FldCharType value"
+	if err.Error() != expectedError {
+		t.Errorf("Expected error message '%s' but got '%s'", expectedError, err.Error())
+	}
+}
+
+func TestFldCharType_UnmarshalXMLAttr(t *testing.T) {
+	type Element struct {
+		XMLName xml.Name    `xml:"element"`
+		Val     FldCharType `xml:"val,attr"`
+	}
+
+	var elem Element
+	if err := xml.Unmarshal([]byte(`<element val="separate"></element>`), &elem); err != nil {
+		t.Fatalf("Error unmarshaling XML: %v", err)
+	}
+
+	if elem.Val != FldCharTypeSeparate {
+		t.Errorf("Expected %s but got %s", FldCharTypeSeparate, elem.Val)
+	}
+
+	if err := xml.Unmarshal([]byte(`<element val="middle"></element>`), &elem); err == nil {
+		t.Fatalf("Expected error for invalid value, but got none")
+	}
+}
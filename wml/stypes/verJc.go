@@ -15,6 +15,16 @@ const (
 	VerticalJcBottom VerticalJc = "bottom"
 )
 
+// VerticalJcFromStr converts a string to a VerticalJc.
+func VerticalJcFromStr(value string) (VerticalJc, error) {
+	switch value {
+	case "top", "center", "both", "bottom":
+		return VerticalJc(value), nil
+	default:
+		return "", fmt.Errorf("unexpected value for VerticalJc: %s", value)
+	}
+}
+
 // MarshalXMLAttr marshals the VerticalJc type as an XML attribute.
 func (v VerticalJc) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
 	return xml.Attr{Name: name, Value: string(v)}, nil
@@ -22,11 +32,10 @@ func (v VerticalJc) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
 
 // UnmarshalXMLAttr unmarshals an XML attribute into a VerticalJc type.
 func (v *VerticalJc) UnmarshalXMLAttr(attr xml.Attr) error {
-	switch attr.Value {
-	case "top", "center", "both", "bottom":
-		*v = VerticalJc(attr.Value)
-	default:
-		return fmt.Errorf("unexpected value for VerticalJc: %s", attr.Value)
+	val, err := VerticalJcFromStr(attr.Value)
+	if err != nil {
+		return err
 	}
+	*v = val
 	return nil
 }
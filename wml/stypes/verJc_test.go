@@ -108,3 +108,19 @@ func TestVerticalJc_UnmarshalXMLAttr_InvalidValue(t *testing.T) {
 		t.Errorf("Expected error message '%s' but got '%s'", expectedError, err.Error())
 	}
 }
+
+func TestVerticalJcFromStr(t *testing.T) {
+	for _, value := range []string{"top", "center", "both", "bottom"} {
+		result, err := VerticalJcFromStr(value)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", value, err)
+		}
+		if string(result) != value {
+			t.Errorf("Expected %s but got %s", value, result)
+		}
+	}
+
+	if _, err := VerticalJcFromStr("middle"); err == nil {
+		t.Error("Expected error for invalid value, but got none")
+	}
+}